@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"encoding/json"
+)
+
+// Media type of an RFC 9068 access token, for the typ header
+const AccessTokenType = "at+jwt"
+
+// Claims required by RFC 9068 section 2.2
+var accessTokenRequiredClaims = []string{"iss", "exp", "aud", "sub", "client_id", "iat", "jti"}
+
+// Validate tokens against the JWT Profile for OAuth 2.0 Access Tokens (RFC 9068):
+// typ must be at+jwt, the iss, exp, aud, sub, client_id, iat and jti claims are
+// required, scope must be a space-delimited string, and unsigned tokens are
+// rejected.  If issuer or audience are non-empty, iss must equal issuer and aud
+// must contain audience.
+func WithAccessTokenProfile(issuer, audience string) ParserOption {
+	return func(p *Parser) {
+		p.RequiredType = AccessTokenType
+		p.validators = append(p.validators, func(token *Token) error {
+			return validateAccessTokenProfile(token, issuer, audience)
+		})
+	}
+}
+
+func validateAccessTokenProfile(token *Token, issuer, audience string) error {
+	if token.Method == SigningMethodNone {
+		return NewValidationError("access token must be signed", ValidationErrorSignatureInvalid)
+	}
+
+	claims, err := claimsToMap(token.Claims)
+	if err != nil {
+		return &ValidationError{Inner: err, Errors: ValidationErrorClaimsInvalid}
+	}
+	if err := requireClaims(claims, accessTokenRequiredClaims, "access token"); err != nil {
+		return err
+	}
+
+	for _, name := range []string{"exp", "iat"} {
+		if !isNumericClaim(claims[name]) {
+			return NewValidationError(name+" claim must be a number", ValidationErrorClaimsInvalid)
+		}
+	}
+	for _, name := range []string{"iss", "sub", "client_id", "jti"} {
+		if _, ok := claims[name].(string); !ok {
+			return NewValidationError(name+" claim must be a string", ValidationErrorClaimsInvalid)
+		}
+	}
+	if scope, ok := claims["scope"]; ok {
+		if _, ok := scope.(string); !ok {
+			return NewValidationError("scope claim must be a space-delimited string", ValidationErrorClaimsInvalid)
+		}
+	}
+
+	if issuer != "" && !claims.VerifyIssuer(issuer, true) {
+		return NewValidationError("access token was not issued by "+issuer, ValidationErrorIssuer)
+	}
+	if audience != "" && !claims.VerifyAudience(audience, true) {
+		return NewValidationError("access token was not issued for "+audience, ValidationErrorAudience)
+	}
+	return nil
+}
+
+func isNumericClaim(v interface{}) bool {
+	switch v.(type) {
+	case float64, json.Number:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestAccessTokenProfile(t *testing.T) {
+	valid := func() jwt.MapClaims {
+		now := time.Now()
+		return jwt.MapClaims{
+			"iss":       "https://auth.example.com",
+			"exp":       now.Add(time.Hour).Unix(),
+			"aud":       []string{"https://api.example.com", "https://other.example.com"},
+			"sub":       "5ba552d67",
+			"client_id": "s6BhdRkqt3",
+			"iat":       now.Unix(),
+			"jti":       "dbe39bf3a3ba4238a513f51d6e1691c4",
+			"scope":     "openid profile",
+		}
+	}
+
+	var tests = []struct {
+		name   string
+		typ    string
+		modify func(jwt.MapClaims)
+		errors uint32
+	}{
+		{"valid", "at+jwt", func(jwt.MapClaims) {}, 0},
+		{"application media type", "application/at+jwt", func(jwt.MapClaims) {}, 0},
+		{"wrong typ", "JWT", func(jwt.MapClaims) {}, jwt.ValidationErrorType},
+		{"missing client_id", "at+jwt", func(c jwt.MapClaims) { delete(c, "client_id") }, jwt.ValidationErrorClaimsInvalid},
+		{"missing jti", "at+jwt", func(c jwt.MapClaims) { delete(c, "jti") }, jwt.ValidationErrorClaimsInvalid},
+		{"scope array", "at+jwt", func(c jwt.MapClaims) { c["scope"] = []string{"openid"} }, jwt.ValidationErrorClaimsInvalid},
+		{"wrong issuer", "at+jwt", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, jwt.ValidationErrorIssuer},
+		{"wrong audience", "at+jwt", func(c jwt.MapClaims) { c["aud"] = "https://other.example.com" }, jwt.ValidationErrorAudience},
+	}
+
+	parser := jwt.NewParser(jwt.WithAccessTokenProfile("https://auth.example.com", "https://api.example.com"))
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	for _, data := range tests {
+		claims := valid()
+		data.modify(claims)
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		token.Header["typ"] = data.typ
+		tokenString, _ := token.SignedString(hmacTestKey)
+
+		_, err := parser.Parse(tokenString, keyFunc)
+		if data.errors == 0 {
+			if err != nil {
+				t.Errorf("[%v] Error while verifying token: %v", data.name, err)
+			}
+			continue
+		}
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&data.errors == 0 {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		}
+	}
+
+	// Typed claims are checked the same way
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{Issuer: "https://auth.example.com", Audience: "https://api.example.com", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	token.Header["typ"] = "at+jwt"
+	tokenString, _ := token.SignedString(hmacTestKey)
+	if _, err := parser.ParseWithClaims(tokenString, &jwt.StandardClaims{}, keyFunc); err == nil {
+		t.Errorf("Typed claims missing required claims passed validation")
+	}
+}
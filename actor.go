@@ -0,0 +1,90 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var ErrActorNotAllowed = errors.New("actor is not allowed to act for the subject")
+
+// The act (actor) or may_act claim from RFC 8693 section 4: the party acting
+// on behalf of the token's subject, or allowed to.  Prior actors in a
+// delegation chain are nested in Actor.
+type Actor struct {
+	Subject  string `json:"sub"`
+	Issuer   string `json:"iss,omitempty"`
+	ClientID string `json:"client_id,omitempty"`
+	Actor    *Actor `json:"act,omitempty"`
+}
+
+// The actors in the delegation chain, current actor first
+func (a *Actor) Chain() []*Actor {
+	var chain []*Actor
+	for ; a != nil; a = a.Actor {
+		chain = append(chain, a)
+	}
+	return chain
+}
+
+// Reports whether a identifies the same party as other: the subjects match,
+// and so do the issuers if other has one
+func (a *Actor) Matches(other *Actor) bool {
+	return a.Subject == other.Subject && (other.Issuer == "" || a.Issuer == other.Issuer)
+}
+
+// Returns the token's act claim, or nil if it has none
+func (t *Token) Actor() (*Actor, error) {
+	return actorClaim(t.Claims, "act")
+}
+
+// Returns the token's may_act claim, or nil if it has none
+func (t *Token) MayAct() (*Actor, error) {
+	return actorClaim(t.Claims, "may_act")
+}
+
+func actorClaim(claims Claims, name string) (*Actor, error) {
+	m, err := claimsToMap(claims)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := m[name]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	actor := &Actor{}
+	if err := json.Unmarshal(data, actor); err != nil {
+		return nil, NewValidationError(name+" claim must be an object", ValidationErrorClaimsInvalid)
+	}
+	for _, a := range actor.Chain() {
+		if a.Subject == "" {
+			return nil, NewValidationError(name+" claim must have a sub", ValidationErrorClaimsInvalid)
+		}
+	}
+	return actor, nil
+}
+
+// Check that the party authenticated by actorToken may act for the subject of
+// subjectToken, as an authorization server does during token exchange.  If
+// subjectToken has a may_act claim, actorToken's sub and iss must match it;
+// without one, any actor is allowed.
+func CheckMayAct(subjectToken, actorToken *Token) error {
+	mayAct, err := subjectToken.MayAct()
+	if err != nil || mayAct == nil {
+		return err
+	}
+	m, err := claimsToMap(actorToken.Claims)
+	if err != nil {
+		return err
+	}
+	actor := &Actor{}
+	actor.Subject, _ = m["sub"].(string)
+	actor.Issuer, _ = m["iss"].(string)
+	if !actor.Matches(mayAct) {
+		return &ValidationError{Inner: ErrActorNotAllowed, Errors: ValidationErrorClaimsInvalid}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestTokenActor(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user",
+		"act": map[string]interface{}{"sub": "billing", "act": map[string]interface{}{"sub": "gateway", "iss": "https://idp.example.com"}},
+	})
+	actor, err := token.Actor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := actor.Chain()
+	if len(chain) != 2 || chain[0].Subject != "billing" || chain[1].Subject != "gateway" || chain[1].Issuer != "https://idp.example.com" {
+		t.Errorf("Unexpected chain: %+v", chain)
+	}
+
+	var tests = []struct {
+		name   string
+		claims jwt.MapClaims
+		err    bool
+	}{
+		{"none", jwt.MapClaims{"sub": "user"}, false},
+		{"not an object", jwt.MapClaims{"act": "billing"}, true},
+		{"no sub", jwt.MapClaims{"act": map[string]interface{}{"iss": "x"}}, true},
+		{"nested without sub", jwt.MapClaims{"act": map[string]interface{}{"sub": "a", "act": map[string]interface{}{}}}, true},
+	}
+	for _, data := range tests {
+		actor, err := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).Actor()
+		if (err != nil) != data.err || (!data.err && actor != nil) {
+			t.Errorf("[%v] Unexpected result: %v %v", data.name, actor, err)
+		}
+	}
+}
+
+func TestCheckMayAct(t *testing.T) {
+	subject := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":     "user",
+		"may_act": map[string]interface{}{"sub": "billing", "iss": "https://idp.example.com"},
+	})
+	var tests = []struct {
+		name   string
+		actor  jwt.MapClaims
+		policy jwt.Claims
+		err    bool
+	}{
+		{"allowed", jwt.MapClaims{"sub": "billing", "iss": "https://idp.example.com"}, subject.Claims, false},
+		{"other subject", jwt.MapClaims{"sub": "ledger", "iss": "https://idp.example.com"}, subject.Claims, true},
+		{"other issuer", jwt.MapClaims{"sub": "billing", "iss": "https://evil.example.com"}, subject.Claims, true},
+		{"no may_act", jwt.MapClaims{"sub": "anyone"}, jwt.MapClaims{"sub": "user"}, false},
+		{"issuer optional", jwt.MapClaims{"sub": "billing"}, jwt.MapClaims{"may_act": map[string]interface{}{"sub": "billing"}}, false},
+	}
+	for _, data := range tests {
+		err := jwt.CheckMayAct(jwt.NewWithClaims(jwt.SigningMethodHS256, data.policy), jwt.NewWithClaims(jwt.SigningMethodHS256, data.actor))
+		if (err != nil) != data.err {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		}
+		if ve, ok := err.(*jwt.ValidationError); err != nil && (!ok || ve.Inner != jwt.ErrActorNotAllowed) {
+			t.Errorf("[%v] Expected ErrActorNotAllowed, got %v", data.name, err)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/middleware"
+)
+
+// Media type of an API key token, for the typ header
+const TokenType = "apikey+jwt"
+
+// A hint for how often the token may be used: Requests per Window seconds.
+// The Enforcer passes it to its Limiter, which does the counting.
+type RateLimit struct {
+	Requests int   `json:"requests"`
+	Window   int64 `json:"window"`
+}
+
+// Claims of an API key token.  Empty restrictions allow everything.
+type Claims struct {
+	jwt.StandardClaims
+	AllowedIPs     []string   `json:"allowed_ips,omitempty"`     // CIDR ranges, such as 10.0.0.0/8, or single addresses
+	AllowedMethods []string   `json:"allowed_methods,omitempty"` // HTTP methods, such as GET
+	AllowedPaths   []string   `json:"allowed_paths,omitempty"`   // path.Match patterns; a trailing /** matches everything below
+	RateLimit      *RateLimit `json:"rate_limit,omitempty"`
+}
+
+// Checks the standard claims and that the restrictions are well formed
+func (c *Claims) Valid() error {
+	if err := c.StandardClaims.Valid(); err != nil {
+		return err
+	}
+	if _, err := parseNetworks(c.AllowedIPs); err != nil {
+		return &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorClaimsInvalid}
+	}
+	for _, pattern := range c.AllowedPaths {
+		if _, err := path.Match(pattern, "/"); err != nil {
+			return &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorClaimsInvalid}
+		}
+	}
+	return nil
+}
+
+// Sign an API key token with typ apikey+jwt.  A missing jti or iat is filled
+// in.
+func Issue(method jwt.SigningMethod, key interface{}, claims *Claims) (string, error) {
+	if err := claims.Valid(); err != nil {
+		return "", err
+	}
+	if claims.Id == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return "", err
+		}
+		claims.Id = jwt.EncodeSegment(id)
+	}
+	if claims.IssuedAt == 0 {
+		claims.IssuedAt = jwt.TimeFunc().Unix()
+	}
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["typ"] = TokenType
+	return token.SignedString(key)
+}
+
+// Options making a middleware.Middleware accept only API key tokens,
+// decoded into *Claims
+func MiddlewareOptions() []middleware.Option {
+	return []middleware.Option{
+		middleware.WithParser(jwt.NewParser(jwt.WithType(TokenType))),
+		middleware.WithClaims(func() jwt.Claims { return &Claims{} }),
+	}
+}
+
+// The token's claims as *Claims, converting other claims types through JSON
+func FromToken(token *jwt.Token) (*Claims, error) {
+	if c, ok := token.Claims.(*Claims); ok {
+		return c, nil
+	}
+	data, err := json.Marshal(token.Claims)
+	if err != nil {
+		return nil, err
+	}
+	c := &Claims{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func parseNetworks(ranges []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(ranges))
+	for _, r := range ranges {
+		if !strings.Contains(r, "/") {
+			ip := net.ParseIP(r)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: r}
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(r)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func matchPath(pattern, p string) bool {
+	if prefix := strings.TrimSuffix(pattern, "/**"); prefix != pattern {
+		if prefix == "" {
+			return true
+		}
+		// Try p and each of its parent directories
+		for i := len(p); i > 0; i = strings.LastIndexByte(p[:i], '/') {
+			if ok, _ := path.Match(prefix, p[:i]); ok {
+				return true
+			}
+		}
+		return false
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
+
+// Reports whether method is allowed by the AllowedMethods claim
+func (c *Claims) allowsMethod(method string) bool {
+	if len(c.AllowedMethods) == 0 {
+		return true
+	}
+	for _, m := range c.AllowedMethods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether p is allowed by the AllowedPaths claim.  p is cleaned
+// first, so dot segments can't climb out of an allowed directory.
+func (c *Claims) allowsPath(p string) bool {
+	if len(c.AllowedPaths) == 0 {
+		return true
+	}
+	p = path.Clean("/" + p)
+	for _, pattern := range c.AllowedPaths {
+		if matchPath(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether ip is allowed by the AllowedIPs claim
+func (c *Claims) allowsIP(ip net.IP) bool {
+	if len(c.AllowedIPs) == 0 {
+		return true
+	}
+	networks, err := parseNetworks(c.AllowedIPs)
+	if err != nil || ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// The address of the request's peer, from RemoteAddr
+func RemoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
@@ -0,0 +1,131 @@
+package apikey
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/middleware"
+)
+
+var testKey = []byte("secret")
+
+func TestMatchPath(t *testing.T) {
+	var tests = []struct {
+		pattern, path string
+		match         bool
+	}{
+		{"/orders", "/orders", true},
+		{"/orders/*", "/orders/1", true},
+		{"/orders/*", "/orders/1/items", false},
+		{"/orders/**", "/orders", true},
+		{"/orders/**", "/orders/1/items", true},
+		{"/orders/**", "/ordersx", false},
+		{"/*/items/**", "/orders/items/2", true},
+		{"/**", "/anything/at/all", true},
+	}
+	for _, data := range tests {
+		if match := matchPath(data.pattern, data.path); match != data.match {
+			t.Errorf("[%v %v] Expected %v, got %v", data.pattern, data.path, data.match, match)
+		}
+	}
+}
+
+func TestAllowsPath(t *testing.T) {
+	claims := &Claims{AllowedPaths: []string{"/public/**"}}
+	var tests = []struct {
+		path  string
+		allow bool
+	}{
+		{"/public/docs", true},
+		{"/public/docs/../index", true},
+		{"/public/../admin", false},
+		{"/public/./../admin", false},
+		{"//public/../../admin", false},
+	}
+	for _, data := range tests {
+		if allow := claims.allowsPath(data.path); allow != data.allow {
+			t.Errorf("[%v] Expected %v, got %v", data.path, data.allow, allow)
+		}
+	}
+}
+
+func TestEnforcer(t *testing.T) {
+	claims := &Claims{
+		AllowedIPs:     []string{"10.0.0.0/8", "192.0.2.7"},
+		AllowedMethods: []string{"GET"},
+		AllowedPaths:   []string{"/reports/**"},
+		RateLimit:      &RateLimit{Requests: 2, Window: 60},
+	}
+	claims.Subject = "reporting-service"
+	tokenString, err := Issue(jwt.SigningMethodHS256, testKey, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Id == "" || claims.IssuedAt == 0 {
+		t.Errorf("Expected jti and iat to be set: %+v", claims)
+	}
+
+	counts := map[string]int{}
+	enforcer := &Enforcer{Limiter: func(r *http.Request, c *Claims, limit RateLimit) bool {
+		counts[c.Id]++
+		return counts[c.Id] <= limit.Requests
+	}}
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	auth := middleware.New(keyFunc, MiddlewareOptions()...)
+	handler := auth.Handler(enforcer.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+
+	var tests = []struct {
+		name, method, path, remote string
+		status                     int
+	}{
+		{"allowed", "GET", "/reports/daily", "10.1.2.3:1234", http.StatusOK},
+		{"single address", "GET", "/reports", "192.0.2.7:1234", http.StatusOK},
+		{"other address", "GET", "/reports", "192.0.2.8:1234", http.StatusForbidden},
+		{"method", "DELETE", "/reports/daily", "10.1.2.3:1234", http.StatusForbidden},
+		{"path", "GET", "/users", "10.1.2.3:1234", http.StatusForbidden},
+		{"dot segments", "GET", "/reports/../users", "10.1.2.3:1234", http.StatusForbidden},
+		{"rate limit", "GET", "/reports/daily", "10.1.2.3:1234", http.StatusTooManyRequests},
+	}
+	for _, data := range tests {
+		r := httptest.NewRequest(data.method, data.path, nil)
+		r.RemoteAddr = data.remote
+		r.Header.Set("Authorization", "Bearer "+tokenString)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v, got %v", data.name, data.status, w.Code)
+		}
+	}
+
+	// Plain access tokens are not API keys
+	plain, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(testKey)
+	r := httptest.NewRequest("GET", "/reports", nil)
+	r.Header.Set("Authorization", "Bearer "+plain)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a token without typ apikey+jwt to be rejected, got %v", w.Code)
+	}
+}
+
+func TestEnforcerMapClaims(t *testing.T) {
+	token := &jwt.Token{Claims: jwt.MapClaims{"allowed_methods": []interface{}{"POST"}}}
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(middleware.NewContext(r.Context(), token))
+	if err := new(Enforcer).check(r); err != ErrMethodNotAllowed {
+		t.Errorf("Expected ErrMethodNotAllowed, got %v", err)
+	}
+	if err := new(Enforcer).check(httptest.NewRequest("GET", "/", nil)); err != ErrNoToken {
+		t.Errorf("Expected ErrNoToken, got %v", err)
+	}
+}
+
+func TestIssueInvalidRestrictions(t *testing.T) {
+	for _, claims := range []*Claims{{AllowedIPs: []string{"10.0.0.0/33"}}, {AllowedIPs: []string{"host"}}, {AllowedPaths: []string{"/[a"}}} {
+		if _, err := Issue(jwt.SigningMethodHS256, testKey, claims); err == nil {
+			t.Errorf("Expected an error issuing %+v", claims)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// A profile for long-lived machine tokens used like API keys.
+//
+// Claims carries restrictions alongside the usual claims: the networks the
+// token may be used from, the methods and paths it may call, and a rate
+// limit hint.  Tokens are issued with Issue and checked by an Enforcer
+// placed after the middleware that verifies them:
+//
+//	auth := middleware.New(keyFunc, apikey.MiddlewareOptions()...)
+//	enforcer := &apikey.Enforcer{Limiter: limiter}
+//	http.Handle("/api/", auth.Handler(enforcer.Handler(api)))
+//
+// Long-lived tokens should have a jti so they can be revoked, for example
+// with a tokenstore.Validator; Issue always sets one.
+package apikey
@@ -0,0 +1,97 @@
+package apikey
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/middleware"
+)
+
+var (
+	ErrNoToken           = errors.New("request has no verified token")
+	ErrIPNotAllowed      = errors.New("token may not be used from this address")
+	ErrMethodNotAllowed  = errors.New("token may not be used with this method")
+	ErrPathNotAllowed    = errors.New("token may not be used for this path")
+	ErrRateLimitExceeded = errors.New("token rate limit exceeded")
+)
+
+// Checks requests against the restrictions of the API key token that
+// authenticated them
+type Enforcer struct {
+	// The client's address.  Defaults to RemoteIP; set it to read a trusted
+	// proxy's forwarding header instead.
+	ClientIP func(r *http.Request) net.IP
+
+	// If set, called for tokens with a rate_limit claim.  It should count the
+	// request against the token, keyed by its jti, and return false once the
+	// limit is exceeded.
+	Limiter func(r *http.Request, claims *Claims, limit RateLimit) bool
+
+	// Writes the response when a request is rejected.  Defaults to
+	// DefaultErrorHandler.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// Check r against claims, returning one of the Err values if it is not allowed
+func (e *Enforcer) Check(r *http.Request, claims *Claims) error {
+	clientIP := e.ClientIP
+	if clientIP == nil {
+		clientIP = RemoteIP
+	}
+	if !claims.allowsIP(clientIP(r)) {
+		return ErrIPNotAllowed
+	}
+	if !claims.allowsMethod(r.Method) {
+		return ErrMethodNotAllowed
+	}
+	if !claims.allowsPath(r.URL.Path) {
+		return ErrPathNotAllowed
+	}
+	if claims.RateLimit != nil && e.Limiter != nil && !e.Limiter(r, claims, *claims.RateLimit) {
+		return ErrRateLimitExceeded
+	}
+	return nil
+}
+
+// Wrap next so it only receives requests allowed by the token stored in the
+// request context by middleware.Middleware
+func (e *Enforcer) Handler(next http.Handler) http.Handler {
+	handleError := e.ErrorHandler
+	if handleError == nil {
+		handleError = DefaultErrorHandler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := e.check(r); err != nil {
+			handleError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (e *Enforcer) check(r *http.Request) error {
+	token, ok := middleware.FromContext(r.Context())
+	if !ok {
+		return ErrNoToken
+	}
+	claims, err := FromToken(token)
+	if err != nil {
+		return &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorClaimsInvalid}
+	}
+	return e.Check(r, claims)
+}
+
+// Responds 429 Too Many Requests when the rate limit is exceeded, 403
+// Forbidden for other restrictions, and 401 Unauthorized otherwise
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusUnauthorized
+	switch err {
+	case ErrRateLimitExceeded:
+		status = http.StatusTooManyRequests
+	case ErrIPNotAllowed, ErrMethodNotAllowed, ErrPathNotAllowed:
+		status = http.StatusForbidden
+	}
+	http.Error(w, http.StatusText(status), status)
+}
@@ -0,0 +1,106 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"errors"
+	"time"
+)
+
+var (
+	ErrParentInvalid      = errors.New("only a verified token can be attenuated")
+	ErrParentMissingID    = errors.New("token to attenuate has no jti claim")
+	ErrScopeEscalation    = errors.New("attenuated scopes must be a subset of the parent's")
+	ErrAudienceEscalation = errors.New("attenuated audiences must be a subset of the parent's")
+)
+
+// The restrictions Attenuate adds to a token.  Zero fields leave the parent's
+// claims as they are.
+type Attenuation struct {
+	ExpiresAt time.Time // An earlier exp.  Later times are capped at the parent's exp
+	Scopes    []string  // The scopes to keep; all must be granted by the parent
+	Audience  []string  // The audiences to keep, or to restrict an unrestricted parent to
+}
+
+// Derive a narrower token from parent, in the spirit of macaroon caveats, for
+// passing to another service: it keeps the parent's claims, with exp, scope
+// and aud narrowed as a describes, and can never grant more than the parent.
+// The new token has its own jti and iat, records the parent's jti in the
+// parent_jti claim so delegation chains can be traced and revoked, and is
+// signed with method and key, which are normally the issuing service's own.
+//
+// parent must have been verified, and must have a jti.
+func Attenuate(parent *Token, method SigningMethod, key interface{}, a Attenuation) (string, error) {
+	if !parent.Valid {
+		return "", ErrParentInvalid
+	}
+	parentClaims, err := claimsToMap(parent.Claims)
+	if err != nil {
+		return "", err
+	}
+	parentID, _ := parentClaims["jti"].(string)
+	if parentID == "" {
+		return "", ErrParentMissingID
+	}
+
+	claims := make(MapClaims, len(parentClaims)+1)
+	for k, v := range parentClaims {
+		claims[k] = v
+	}
+
+	if len(a.Scopes) > 0 {
+		if !HasScopes(parent, a.Scopes...) {
+			return "", ErrScopeEscalation
+		}
+		delete(claims, "scp")
+		claims["scope"] = FormatScope(a.Scopes)
+	}
+
+	if len(a.Audience) > 0 {
+		if aud := claimStrings(parentClaims["aud"]); len(aud) > 0 {
+			for _, v := range a.Audience {
+				if !aud.Contains(v) {
+					return "", ErrAudienceEscalation
+				}
+			}
+		}
+		claims["aud"] = ClaimStrings(a.Audience)
+	}
+
+	if !a.ExpiresAt.IsZero() {
+		exp, ok := parent.ExpiresAt()
+		if !ok || a.ExpiresAt.Before(exp) {
+			exp = a.ExpiresAt
+		}
+		claims["exp"] = exp.Unix()
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	claims["jti"] = EncodeSegment(id)
+	claims["iat"] = TimeFunc().Unix()
+	claims["parent_jti"] = parentID
+	return NewWithClaims(method, claims).SignedString(key)
+}
+
+// Decode a string or array of strings claim from a MapClaims value
+func claimStrings(v interface{}) ClaimStrings {
+	switch v := v.(type) {
+	case string:
+		return ClaimStrings{v}
+	case []string:
+		return v
+	case []interface{}:
+		s := make(ClaimStrings, 0, len(v))
+		for _, e := range v {
+			if e, ok := e.(string); ok {
+				s = append(s, e)
+			}
+		}
+		return s
+	case ClaimStrings:
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package jwt_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestAttenuate(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	parentString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"jti":   "parent",
+		"sub":   "user",
+		"scope": "orders:read orders:write",
+		"aud":   []string{"orders", "billing"},
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(hmacTestKey)
+	parent, err := jwt.Parse(parentString, keyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name  string
+		a     jwt.Attenuation
+		err   error
+		scope string
+		aud   interface{}
+		exp   int64
+	}{
+		{"unchanged", jwt.Attenuation{}, nil, "orders:read orders:write", []interface{}{"orders", "billing"}, now.Add(time.Hour).Unix()},
+		{"narrowed", jwt.Attenuation{ExpiresAt: now.Add(time.Minute), Scopes: []string{"orders:read"}, Audience: []string{"orders"}}, nil, "orders:read", "orders", now.Add(time.Minute).Unix()},
+		{"exp capped", jwt.Attenuation{ExpiresAt: now.Add(2 * time.Hour)}, nil, "orders:read orders:write", []interface{}{"orders", "billing"}, now.Add(time.Hour).Unix()},
+		{"scope escalation", jwt.Attenuation{Scopes: []string{"orders:delete"}}, jwt.ErrScopeEscalation, "", nil, 0},
+		{"audience escalation", jwt.Attenuation{Audience: []string{"admin"}}, jwt.ErrAudienceEscalation, "", nil, 0},
+	}
+
+	for _, data := range tests {
+		childString, err := jwt.Attenuate(parent, jwt.SigningMethodHS256, hmacTestKey, data.a)
+		if err != data.err {
+			t.Errorf("[%v] Expected error %v, got %v", data.name, data.err, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		child, err := jwt.Parse(childString, keyFunc)
+		if err != nil {
+			t.Errorf("[%v] Error parsing child: %v", data.name, err)
+			continue
+		}
+		claims := child.Claims.(jwt.MapClaims)
+		if claims["parent_jti"] != "parent" || claims["jti"] == "parent" || claims["sub"] != "user" {
+			t.Errorf("[%v] Unexpected claims: %v", data.name, claims)
+		}
+		if claims["scope"] != data.scope {
+			t.Errorf("[%v] Expected scope %q, got %v", data.name, data.scope, claims["scope"])
+		}
+		if !reflect.DeepEqual(claims["aud"], data.aud) {
+			t.Errorf("[%v] Expected aud %v, got %v", data.name, data.aud, claims["aud"])
+		}
+		if exp, _ := child.ExpiresAt(); exp.Unix() != data.exp {
+			t.Errorf("[%v] Expected exp %v, got %v", data.name, data.exp, exp.Unix())
+		}
+
+		// Children can be attenuated further, but never widened
+		if _, err := jwt.Attenuate(child, jwt.SigningMethodHS256, hmacTestKey, jwt.Attenuation{Scopes: []string{"orders:write"}}); (err == nil) != (data.scope != "orders:read") {
+			t.Errorf("[%v] Unexpected result re-attenuating: %v", data.name, err)
+		}
+	}
+}
+
+func TestAttenuateUnrestrictedParent(t *testing.T) {
+	parent := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"jti": "p"})
+	if _, err := jwt.Attenuate(parent, jwt.SigningMethodHS256, hmacTestKey, jwt.Attenuation{}); err != jwt.ErrParentInvalid {
+		t.Errorf("Expected ErrParentInvalid, got %v", err)
+	}
+
+	parent.Valid = true
+	exp := time.Now().Add(time.Minute).Truncate(time.Second)
+	childString, err := jwt.Attenuate(parent, jwt.SigningMethodHS256, hmacTestKey, jwt.Attenuation{Audience: []string{"orders"}, ExpiresAt: exp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, _ := jwt.ParseUnverified(childString)
+	claims := child.Claims.(jwt.MapClaims)
+	if claims["aud"] != "orders" || int64(claims["exp"].(float64)) != exp.Unix() {
+		t.Errorf("Unexpected claims: %v", claims)
+	}
+
+	parent.Claims = jwt.MapClaims{}
+	if _, err := jwt.Attenuate(parent, jwt.SigningMethodHS256, hmacTestKey, jwt.Attenuation{}); err != jwt.ErrParentMissingID {
+		t.Errorf("Expected ErrParentMissingID, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package benchmarks
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+type algorithm struct {
+	method  jwt.SigningMethod
+	private interface{}
+	public  interface{}
+}
+
+func loadAlgorithms(b *testing.B) []algorithm {
+	hmacKey, err := ioutil.ReadFile("../test/hmacTestKey")
+	if err != nil {
+		b.Fatal(err)
+	}
+	rsaPrivate := test.LoadRSAPrivateKeyFromDisk("../test/sample_key")
+	rsaPublic := test.LoadRSAPublicKeyFromDisk("../test/sample_key.pub")
+
+	algorithms := []algorithm{
+		{jwt.SigningMethodHS256, hmacKey, hmacKey},
+		{jwt.SigningMethodHS384, hmacKey, hmacKey},
+		{jwt.SigningMethodHS512, hmacKey, hmacKey},
+		{jwt.SigningMethodRS256, rsaPrivate, rsaPublic},
+		{jwt.SigningMethodRS384, rsaPrivate, rsaPublic},
+		{jwt.SigningMethodRS512, rsaPrivate, rsaPublic},
+		{jwt.SigningMethodPS256, rsaPrivate, rsaPublic},
+		{jwt.SigningMethodPS384, rsaPrivate, rsaPublic},
+		{jwt.SigningMethodPS512, rsaPrivate, rsaPublic},
+	}
+	for _, ec := range []struct {
+		method jwt.SigningMethod
+		file   string
+	}{
+		{jwt.SigningMethodES256, "ec256"},
+		{jwt.SigningMethodES384, "ec384"},
+		{jwt.SigningMethodES512, "ec512"},
+	} {
+		privateBytes, _ := ioutil.ReadFile("../test/" + ec.file + "-private.pem")
+		private, err := jwt.ParseECPrivateKeyFromPEM(privateBytes)
+		if err != nil {
+			b.Fatal(err)
+		}
+		algorithms = append(algorithms, algorithm{ec.method, private, private.Public()})
+	}
+	return algorithms
+}
+
+func claims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":   "https://auth.example.com",
+		"sub":   "5ba552d67",
+		"aud":   "https://api.example.com",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"scope": "openid profile orders:read",
+	}
+}
+
+func signed(b *testing.B, alg algorithm) string {
+	tokenString, err := jwt.NewWithClaims(alg.method, claims()).SignedString(alg.private)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return tokenString
+}
+
+func BenchmarkSign(b *testing.B) {
+	for _, alg := range loadAlgorithms(b) {
+		b.Run(alg.method.Alg(), func(b *testing.B) {
+			token := jwt.NewWithClaims(alg.method, claims())
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := token.SignedString(alg.private); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Signature verification alone, without decoding or claims validation
+func BenchmarkVerify(b *testing.B) {
+	for _, alg := range loadAlgorithms(b) {
+		b.Run(alg.method.Alg(), func(b *testing.B) {
+			tokenString := signed(b, alg)
+			i := strings.LastIndexByte(tokenString, '.')
+			signingString, signature := tokenString[:i], tokenString[i+1:]
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := alg.method.Verify(signingString, signature, alg.public); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// A full Parse: decoding, signature verification and claims validation
+func BenchmarkParse(b *testing.B) {
+	parser := jwt.NewParser()
+	for _, alg := range loadAlgorithms(b) {
+		b.Run(alg.method.Alg(), func(b *testing.B) {
+			tokenString := signed(b, alg)
+			keyFunc := func(*jwt.Token) (interface{}, error) { return alg.public, nil }
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := parser.Parse(tokenString, keyFunc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Decoding only, which bounds the parser's own overhead
+func BenchmarkParseUnverified(b *testing.B) {
+	tokenString := signed(b, loadAlgorithms(b)[0])
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jwt.ParseUnverified(tokenString); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseInto(b *testing.B) {
+	parser := jwt.NewParser()
+	alg := loadAlgorithms(b)[0]
+	tokenString := signed(b, alg)
+	keyFunc := func(*jwt.Token) (interface{}, error) { return alg.public, nil }
+	var token jwt.Token
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := parser.ParseInto(&token, tokenString, jwt.MapClaims{}, keyFunc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
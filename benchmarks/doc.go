@@ -0,0 +1,9 @@
+// Benchmarks comparing signing, verification and parsing across the
+// supported signing methods.  The package has no code of its own; run it with
+//
+//	go test -run XXX -bench . -benchmem ./benchmarks
+//
+// and compare runs with benchstat to catch regressions.  Each benchmark has a
+// sub-benchmark per algorithm: HS256-512, RS256-512, PS256-512 and ES256-512.
+// There is no EdDSA signing method in this package, so it is not covered.
+package benchmarks
@@ -0,0 +1,48 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"sync"
+)
+
+// Buffers larger than this are not returned to the pool, so one huge token
+// doesn't pin its memory for the life of the process
+const maxPooledBufferSize = 64 << 10
+
+// Scratch buffers for encoding and decoding segments
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 1024)
+		return &b
+	},
+}
+
+func getBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte)
+}
+
+func putBuffer(b *[]byte) {
+	if cap(*b) > maxPooledBufferSize {
+		return
+	}
+	*b = (*b)[:0]
+	bufferPool.Put(b)
+}
+
+// Append the unpadded base64url encoding of src to dst
+func appendEncodeSegment(dst, src []byte) []byte {
+	n := base64.RawURLEncoding.EncodedLen(len(src))
+	dst = grow(dst, n)
+	base64.RawURLEncoding.Encode(dst[len(dst):len(dst)+n], src)
+	return dst[:len(dst)+n]
+}
+
+// Ensure dst has room for n more bytes
+func grow(dst []byte, n int) []byte {
+	if cap(dst)-len(dst) >= n {
+		return dst
+	}
+	grown := make([]byte, len(dst), 2*cap(dst)+n)
+	copy(grown, dst)
+	return grown
+}
@@ -0,0 +1,117 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// A JSONCodec whose Marshal writes objects with their keys in a fixed order,
+// so the same header and claims always produce the same signing string.
+// Top-level keys named in Order come first, in that order; all other keys,
+// including those of nested objects, are sorted.  Set it as a Token's
+// JSONCodec for reproducible signatures in snapshot tests or when comparing
+// cached tokens.  Decoding is delegated to Codec unchanged.
+type CanonicalJSONCodec struct {
+	Codec JSONCodec // Defaults to DefaultJSONCodec
+	Order []string
+}
+
+// Create a CanonicalJSONCodec putting the keys in order first
+func NewCanonicalJSONCodec(order ...string) *CanonicalJSONCodec {
+	return &CanonicalJSONCodec{Order: order}
+}
+
+func (c *CanonicalJSONCodec) codec() JSONCodec {
+	if c.Codec != nil {
+		return c.Codec
+	}
+	return DefaultJSONCodec
+}
+
+func (c *CanonicalJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := c.codec().Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := appendCanonical(&buf, data, c.Order); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *CanonicalJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return c.codec().Unmarshal(data, v)
+}
+
+func (c *CanonicalJSONCodec) NewDecoder(r io.Reader) JSONDecoder {
+	return c.codec().NewDecoder(r)
+}
+
+// Write the JSON value data to buf compactly, with object keys in order and
+// then sorted
+func appendCanonical(buf *bytes.Buffer, data json.RawMessage, order []string) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return json.Compact(buf, data)
+	}
+	switch data[0] {
+	case '{':
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(obj))
+		seen := make(map[string]bool, len(order))
+		for _, k := range order {
+			if _, ok := obj[k]; ok && !seen[k] {
+				keys = append(keys, k)
+				seen[k] = true
+			}
+		}
+		rest := len(keys)
+		for k := range obj {
+			if !seen[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys[rest:])
+
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			name, err := json.Marshal(k)
+			if err != nil {
+				return err
+			}
+			buf.Write(name)
+			buf.WriteByte(':')
+			if err := appendCanonical(buf, obj[k], nil); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case '[':
+		var arr []json.RawMessage
+		if err := json.Unmarshal(data, &arr); err != nil {
+			return err
+		}
+		buf.WriteByte('[')
+		for i, v := range arr {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := appendCanonical(buf, v, nil); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+	return json.Compact(buf, data)
+}
@@ -0,0 +1,77 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type orderedClaims struct {
+	Sub   string                 `json:"sub"`
+	Iss   string                 `json:"iss"`
+	Extra map[string]interface{} `json:"extra"`
+}
+
+func (orderedClaims) Valid() error { return nil }
+
+// Marshals with indentation, as codecs with unusual formatting might
+type indentCodec struct{}
+
+func (indentCodec) Marshal(v interface{}) ([]byte, error) { return json.MarshalIndent(v, "", "  ") }
+
+func (indentCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+func (indentCodec) NewDecoder(r io.Reader) jwt.JSONDecoder { return json.NewDecoder(r) }
+
+func TestCanonicalJSONCodec(t *testing.T) {
+	var tests = []struct {
+		name     string
+		codec    *jwt.CanonicalJSONCodec
+		value    interface{}
+		expected string
+	}{
+		{"sorted struct", jwt.NewCanonicalJSONCodec(), orderedClaims{Sub: "a", Iss: "b", Extra: map[string]interface{}{"z": 1, "a": []interface{}{map[string]interface{}{"y": 1, "x": 2}}}}, `{"extra":{"a":[{"x":2,"y":1}],"z":1},"iss":"b","sub":"a"}`},
+		{"explicit order", jwt.NewCanonicalJSONCodec("typ", "alg", "missing", "typ"), map[string]interface{}{"alg": "HS256", "typ": "JWT", "kid": "k1"}, `{"typ":"JWT","alg":"HS256","kid":"k1"}`},
+		{"order is top-level only", jwt.NewCanonicalJSONCodec("b"), map[string]interface{}{"a": map[string]interface{}{"b": 1, "a": 2}, "b": 1}, `{"b":1,"a":{"a":2,"b":1}}`},
+		{"compacts", &jwt.CanonicalJSONCodec{Codec: indentCodec{}}, orderedClaims{Sub: "a"}, `{"extra":null,"iss":"","sub":"a"}`},
+		{"scalar", jwt.NewCanonicalJSONCodec(), 1.5, `1.5`},
+	}
+
+	for _, data := range tests {
+		out, err := data.codec.Marshal(data.value)
+		if err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+			continue
+		}
+		if string(out) != data.expected {
+			t.Errorf("[%v] Expected %s, got %s", data.name, data.expected, out)
+		}
+	}
+}
+
+func TestCanonicalJSONCodecSigning(t *testing.T) {
+	sign := func() string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, orderedClaims{Sub: "a", Iss: "b"})
+		token.Header["kid"] = "k1"
+		token.JSONCodec = jwt.NewCanonicalJSONCodec("alg", "typ")
+		s, err := token.SignedString(hmacTestKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	tokenString := sign()
+	if sign() != tokenString {
+		t.Errorf("Signatures differ between runs")
+	}
+	header, _ := jwt.DecodeSegment(strings.Split(tokenString, ".")[0])
+	if string(header) != `{"alg":"HS256","typ":"JWT","kid":"k1"}` {
+		t.Errorf("Unexpected header: %s", header)
+	}
+	if _, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }); err != nil {
+		t.Errorf("Error parsing canonical token: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrClaimDecryption = errors.New("encrypted claim could not be decrypted")
+
+// Encrypts and decrypts individual claim values, to hide personal data such
+// as an email address from intermediaries that can read the token, without
+// encrypting the whole token.  The plaintext is the JSON encoding of the
+// claim's value, and the ciphertext is stored in the claim as a string.
+// Implementations should bind the ciphertext to the claim name, so values
+// can't be moved between claims.
+type FieldCipher interface {
+	EncryptClaim(name string, plaintext []byte) (string, error)
+	DecryptClaim(name string, ciphertext string) ([]byte, error)
+}
+
+// A FieldCipher using AES-GCM with a random nonce.  The claim name is
+// authenticated as additional data, and the nonce and ciphertext are stored
+// as one base64url segment.
+type AESFieldCipher struct {
+	aead cipher.AEAD
+}
+
+// Create an AESFieldCipher with a 16, 24 or 32 byte key, for AES-128, AES-192
+// or AES-256
+func NewAESFieldCipher(key []byte) (*AESFieldCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESFieldCipher{aead: aead}, nil
+}
+
+func (c *AESFieldCipher) EncryptClaim(name string, plaintext []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return EncodeSegment(c.aead.Seal(nonce, nonce, plaintext, []byte(name))), nil
+}
+
+func (c *AESFieldCipher) DecryptClaim(name string, ciphertext string) ([]byte, error) {
+	data, err := DecodeSegment(ciphertext)
+	if err != nil || len(data) < c.aead.NonceSize() {
+		return nil, ErrClaimDecryption
+	}
+	nonce, sealed := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, sealed, []byte(name))
+	if err != nil {
+		return nil, ErrClaimDecryption
+	}
+	return plaintext, nil
+}
+
+// Returns a copy of claims with the named claims encrypted by fc, for signing
+// with NewWithClaims.  Claims that are not present are skipped.  Parse the
+// token with WithFieldCipher to decrypt them.
+func EncryptClaims(claims Claims, fc FieldCipher, names ...string) (MapClaims, error) {
+	m, err := claimsToMap(claims)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := make(MapClaims, len(m))
+	for k, v := range m {
+		encrypted[k] = v
+	}
+	for _, name := range names {
+		v, ok := encrypted[name]
+		if !ok {
+			continue
+		}
+		plaintext, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if encrypted[name], err = fc.EncryptClaim(name, plaintext); err != nil {
+			return nil, err
+		}
+	}
+	return encrypted, nil
+}
+
+// Decrypt the parser's EncryptedClaims in the claims JSON, before it is
+// decoded, so claims types see the plaintext values
+func (p *Parser) decryptClaims(data []byte) ([]byte, error) {
+	if p.FieldCipher == nil || len(p.EncryptedClaims) == 0 {
+		return data, nil
+	}
+	// Duplicates would be lost by decoding into a map
+	if p.RejectDuplicateKeys {
+		if err := p.checkDuplicateKeys(data); err != nil {
+			return nil, err
+		}
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range p.EncryptedClaims {
+		raw, ok := fields[name]
+		if !ok {
+			continue
+		}
+		var ciphertext string
+		if err := json.Unmarshal(raw, &ciphertext); err != nil {
+			return nil, fmt.Errorf("encrypted claim %v must be a string", name)
+		}
+		plaintext, err := p.FieldCipher.DecryptClaim(name, ciphertext)
+		if err != nil {
+			return nil, err
+		}
+		if !json.Valid(plaintext) {
+			return nil, ErrClaimDecryption
+		}
+		fields[name] = plaintext
+	}
+	return json.Marshal(fields)
+}
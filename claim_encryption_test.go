@@ -0,0 +1,72 @@
+package jwt_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type piiClaims struct {
+	jwt.StandardClaims
+	Email   string            `json:"email"`
+	Address map[string]string `json:"address"`
+}
+
+func TestFieldCipher(t *testing.T) {
+	fc, err := jwt.NewAESFieldCipher(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := jwt.MapClaims{"sub": "user", "email": "user@example.com", "address": map[string]string{"country": "FI"}}
+	encrypted, err := jwt.EncryptClaims(claims, fc, "email", "address", "phone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims["email"] != "user@example.com" {
+		t.Errorf("EncryptClaims modified the claims")
+	}
+	if s, ok := encrypted["email"].(string); !ok || s == "user@example.com" || encrypted["sub"] != "user" {
+		t.Errorf("Unexpected encrypted claims: %v", encrypted)
+	}
+	tokenString := signHS256(encrypted)
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	parsed := &piiClaims{}
+	token, err := jwt.NewParser(jwt.WithFieldCipher(fc, "email", "address")).ParseWithClaims(tokenString, parsed, keyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Email != "user@example.com" || parsed.Address["country"] != "FI" || parsed.Subject != "user" {
+		t.Errorf("Unexpected claims: %+v", parsed)
+	}
+
+	// RawClaims are the payload as signed, with the ciphertext
+	payload, _ := jwt.DecodeSegment(strings.Split(tokenString, ".")[1])
+	if !bytes.Equal(token.RawClaims, payload) {
+		t.Errorf("RawClaims differ from the payload: %s", token.RawClaims)
+	}
+
+	// Claims mappers see the plaintext
+	var mapped interface{}
+	mapper := func(m jwt.MapClaims) error {
+		mapped = m["email"]
+		return nil
+	}
+	if _, err := jwt.NewParser(jwt.WithFieldCipher(fc, "email", "address"), jwt.WithClaimsMapper(mapper)).ParseWithClaims(tokenString, &piiClaims{}, keyFunc); err != nil || mapped != "user@example.com" {
+		t.Errorf("Unexpected mapped claim %v: %v", mapped, err)
+	}
+
+	// Ciphertext is bound to the claim name
+	swapped := jwt.MapClaims{"email": encrypted["address"], "address": encrypted["email"]}
+	_, err = jwt.NewParser(jwt.WithFieldCipher(fc, "email", "address")).Parse(signHS256(swapped), keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Inner != jwt.ErrClaimDecryption {
+		t.Errorf("Expected ErrClaimDecryption, got %v", err)
+	}
+
+	other, _ := jwt.NewAESFieldCipher(bytes.Repeat([]byte{2}, 32))
+	if _, err := jwt.NewParser(jwt.WithFieldCipher(other, "email")).Parse(tokenString, keyFunc); err == nil {
+		t.Errorf("Expected the wrong key to be rejected")
+	}
+}
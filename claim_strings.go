@@ -0,0 +1,50 @@
+package jwt
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+)
+
+// A claim that may be either a single string or an array of strings, such as
+// aud (RFC 7519 section 4.1.3).  A single value is encoded as a plain string.
+type ClaimStrings []string
+
+func (s *ClaimStrings) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = ClaimStrings{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*s = ClaimStrings(multiple)
+	return nil
+}
+
+func (s ClaimStrings) MarshalJSON() ([]byte, error) {
+	if len(s) == 1 {
+		return json.Marshal(s[0])
+	}
+	return json.Marshal([]string(s))
+}
+
+// Reports whether cmp is one of the values
+func (s ClaimStrings) Contains(cmp string) bool {
+	for _, v := range s {
+		if subtle.ConstantTimeCompare([]byte(v), []byte(cmp)) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Compares the values against cmp.
+// If required is false, this method will return true if cmp is one of the values or none are set
+func (s ClaimStrings) Verify(cmp string, req bool) bool {
+	if len(s) == 0 {
+		return !req
+	}
+	return s.Contains(cmp)
+}
@@ -0,0 +1,48 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestClaimStrings(t *testing.T) {
+	var tests = []struct {
+		json   string
+		values jwt.ClaimStrings
+	}{
+		{`"api"`, jwt.ClaimStrings{"api"}},
+		{`["api","web"]`, jwt.ClaimStrings{"api", "web"}},
+	}
+
+	for _, data := range tests {
+		var values jwt.ClaimStrings
+		if err := json.Unmarshal([]byte(data.json), &values); err != nil {
+			t.Errorf("[%v] Error decoding: %v", data.json, err)
+		}
+		if encoded, _ := json.Marshal(values); string(encoded) != data.json {
+			t.Errorf("[%v] Round trip mismatch: %s", data.json, encoded)
+		}
+		if !values.Contains("api") || values.Contains("other") {
+			t.Errorf("[%v] Contains mismatch", data.json)
+		}
+	}
+
+	var values jwt.ClaimStrings
+	if err := json.Unmarshal([]byte(`42`), &values); err == nil {
+		t.Errorf("Number decoded as ClaimStrings")
+	}
+}
+
+func TestMapClaimsAudienceArray(t *testing.T) {
+	var claims jwt.MapClaims
+	json.Unmarshal([]byte(`{"aud":["api","web"]}`), &claims)
+
+	if !claims.VerifyAudience("web", true) {
+		t.Errorf("Audience in array not matched")
+	}
+	if claims.VerifyAudience("other", false) {
+		t.Errorf("Audience not in array matched")
+	}
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"context"
 	"crypto/subtle"
 	"fmt"
 	"time"
@@ -12,6 +13,14 @@ type Claims interface {
 	Valid() error
 }
 
+// Claims types may implement ClaimsValidator to add their own rules, such as
+// tenant checks.  Validate is called by the parser after the standard checks
+// pass, with the context passed to ParseWithContext (or context.Background()).
+// Errors other than *ValidationError are reported as ValidationErrorClaimsInvalid.
+type ClaimsValidator interface {
+	Validate(ctx context.Context) error
+}
+
 // Structured version of Claims Section, as referenced at
 // https://tools.ietf.org/html/rfc7519#section-4.1
 // See examples for how to use this with your own claim types
@@ -0,0 +1,135 @@
+//go:build go1.18
+
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// A claim could not be converted to the requested type
+type ClaimTypeError struct {
+	Name  string
+	Type  reflect.Type // The requested type
+	Value interface{}  // The decoded claim
+}
+
+func (e *ClaimTypeError) Error() string {
+	return fmt.Sprintf("%v claim %v (%T) cannot be converted to %v", e.Name, e.Value, e.Value, e.Type)
+}
+
+// Returns the claim name of a token's claims as a T.  See ClaimValue.
+func GetClaim[T any](token *Token, name string) (T, error) {
+	return ClaimValue[T](token.Claims, name)
+}
+
+// Returns the claim name as a T, or def if the claim is absent or cannot be
+// converted
+func GetClaimOr[T any](token *Token, name string, def T) T {
+	v, err := ClaimValue[T](token.Claims, name)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Returns the claim name as a T.  Numbers convert to any integer or float
+// type, failing with a *ClaimTypeError if the value has a fraction or is out
+// of range, rather than silently truncating; NumericDate claims convert to
+// time.Time; a single string converts to []string.  Other types are decoded
+// from the claim's JSON, so T may be a struct for object claims.  Returns
+// ErrClaimNotFound if the claim is absent or null.
+func ClaimValue[T any](claims Claims, name string) (T, error) {
+	var zero T
+	m, err := claimsToMap(claims)
+	if err != nil {
+		return zero, err
+	}
+	raw, ok := m[name]
+	if !ok || raw == nil {
+		return zero, ErrClaimNotFound
+	}
+	if v, ok := raw.(T); ok {
+		return v, nil
+	}
+
+	out := reflect.New(reflect.TypeOf((*T)(nil)).Elem()).Elem()
+	if !convertClaim(out, raw) {
+		return zero, &ClaimTypeError{Name: name, Type: out.Type(), Value: raw}
+	}
+	return out.Interface().(T), nil
+}
+
+// Store raw, a decoded claim value, in out
+func convertClaim(out reflect.Value, raw interface{}) bool {
+	if out.Type() == reflect.TypeOf(time.Time{}) {
+		f, ok := claimNumber(raw)
+		if !ok {
+			return false
+		}
+		sec, frac := math.Modf(f)
+		out.Set(reflect.ValueOf(time.Unix(int64(sec), int64(frac*1e9)).UTC()))
+		return true
+	}
+
+	switch out.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Keep the precision of integers beyond 2^53 decoded with UseNumber
+		if n, ok := raw.(json.Number); ok {
+			if i, err := n.Int64(); err == nil && !out.OverflowInt(i) {
+				out.SetInt(i)
+				return true
+			}
+		}
+		f, ok := claimNumber(raw)
+		if !ok || f != math.Trunc(f) || out.OverflowInt(int64(f)) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return false
+		}
+		out.SetInt(int64(f))
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f, ok := claimNumber(raw)
+		if !ok || f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 || out.OverflowUint(uint64(f)) {
+			return false
+		}
+		out.SetUint(uint64(f))
+		return true
+	case reflect.Float32, reflect.Float64:
+		f, ok := claimNumber(raw)
+		if !ok || out.OverflowFloat(f) {
+			return false
+		}
+		out.SetFloat(f)
+		return true
+	case reflect.Slice:
+		if s, ok := raw.(string); ok && out.Type().Elem().Kind() == reflect.String {
+			out.Set(reflect.Append(reflect.MakeSlice(out.Type(), 0, 1), reflect.ValueOf(s).Convert(out.Type().Elem())))
+			return true
+		}
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, out.Addr().Interface()) == nil
+}
+
+// Returns a decoded numeric claim as a float64
+func claimNumber(raw interface{}) (float64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
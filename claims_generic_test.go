@@ -0,0 +1,79 @@
+//go:build go1.18
+
+package jwt_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestGetClaim(t *testing.T) {
+	token := &jwt.Token{Claims: jwt.MapClaims{
+		"sub":     "user",
+		"exp":     float64(1500000000),
+		"age":     float64(30),
+		"ratio":   1.5,
+		"admin":   true,
+		"aud":     "api",
+		"groups":  []interface{}{"a", "b"},
+		"address": map[string]interface{}{"country": "FI"},
+	}}
+
+	if v, err := jwt.GetClaim[string](token, "sub"); v != "user" || err != nil {
+		t.Errorf("Unexpected sub: %v %v", v, err)
+	}
+	if v, err := jwt.GetClaim[int](token, "age"); v != 30 || err != nil {
+		t.Errorf("Unexpected age: %v %v", v, err)
+	}
+	if v, err := jwt.GetClaim[uint8](token, "age"); v != 30 || err != nil {
+		t.Errorf("Unexpected age: %v %v", v, err)
+	}
+	if v, err := jwt.GetClaim[float32](token, "ratio"); v != 1.5 || err != nil {
+		t.Errorf("Unexpected ratio: %v %v", v, err)
+	}
+	if v, err := jwt.GetClaim[time.Time](token, "exp"); !v.Equal(time.Unix(1500000000, 0)) || err != nil {
+		t.Errorf("Unexpected exp: %v %v", v, err)
+	}
+	if v, err := jwt.GetClaim[bool](token, "admin"); !v || err != nil {
+		t.Errorf("Unexpected admin: %v %v", v, err)
+	}
+	if v, err := jwt.GetClaim[[]string](token, "aud"); !reflect.DeepEqual(v, []string{"api"}) || err != nil {
+		t.Errorf("Unexpected aud: %v %v", v, err)
+	}
+	if v, err := jwt.GetClaim[[]string](token, "groups"); !reflect.DeepEqual(v, []string{"a", "b"}) || err != nil {
+		t.Errorf("Unexpected groups: %v %v", v, err)
+	}
+	type address struct {
+		Country string `json:"country"`
+	}
+	if v, err := jwt.GetClaim[address](token, "address"); v.Country != "FI" || err != nil {
+		t.Errorf("Unexpected address: %v %v", v, err)
+	}
+
+	// Lossy conversions fail rather than truncating
+	var typeErr *jwt.ClaimTypeError
+	if _, err := jwt.GetClaim[int](token, "ratio"); !errors.As(err, &typeErr) || typeErr.Name != "ratio" {
+		t.Errorf("Expected a ClaimTypeError, got %v", err)
+	}
+	if _, err := jwt.ClaimValue[int8](jwt.MapClaims{"n": float64(300)}, "n"); err == nil {
+		t.Errorf("Expected an overflow to be rejected")
+	}
+	if _, err := jwt.GetClaim[int](token, "sub"); err == nil {
+		t.Errorf("Expected a string to be rejected as an int")
+	}
+	if _, err := jwt.GetClaim[string](token, "missing"); err != jwt.ErrClaimNotFound {
+		t.Errorf("Expected ErrClaimNotFound, got %v", err)
+	}
+	if v := jwt.GetClaimOr(token, "missing", 42); v != 42 {
+		t.Errorf("Unexpected default: %v", v)
+	}
+
+	// Struct claims and integers beyond 2^53
+	if v, err := jwt.ClaimValue[int64](&jwt.StandardClaims{ExpiresAt: 1<<53 + 1}, "exp"); v != 1<<53+1 || err != nil {
+		t.Errorf("Unexpected exp: %v %v", v, err)
+	}
+}
@@ -0,0 +1,133 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rewrites a claims set after the token has been validated, so code
+// downstream of the parser sees the same claims whichever identity provider
+// issued the token.  A mapper may rename, convert, add or delete claims; an
+// error makes the token invalid.
+type ClaimsMapper func(claims MapClaims) error
+
+// Run the parser's ClaimsMappers on a validated token.  For claims that are
+// not MapClaims, the mappers see every claim in the payload, as MapClaims
+// with json.Number values, and the result is decoded back into the claims
+// value, so mappers can fill struct fields from claims the struct does not
+// know.  Fields whose claims a mapper deletes keep their decoded values.
+func (p *Parser) mapClaims(token *Token) error {
+	if len(p.ClaimsMappers) == 0 {
+		return nil
+	}
+	m, isMap := token.Claims.(MapClaims)
+	if !isMap {
+		data, err := p.decryptClaims(token.RawClaims)
+		if err != nil {
+			return err
+		}
+		m = MapClaims{}
+		dec := p.jsonCodec().NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&m); err != nil {
+			return err
+		}
+	}
+	for _, mapper := range p.ClaimsMappers {
+		if err := mapper(m); err != nil {
+			return err
+		}
+	}
+	if isMap {
+		return nil
+	}
+	data, err := p.jsonCodec().Marshal(m)
+	if err != nil {
+		return err
+	}
+	return p.jsonCodec().Unmarshal(data, token.Claims)
+}
+
+// Move the claim from to the claim to, replacing any value to had.  Does
+// nothing if from is not set.
+func RenameClaim(from, to string) ClaimsMapper {
+	return func(claims MapClaims) error {
+		if v, ok := claims[from]; ok {
+			delete(claims, from)
+			claims[to] = v
+		}
+		return nil
+	}
+}
+
+// Lowercase string claims, such as email, that providers return with
+// inconsistent case.  Claims that are not strings are left as they are.
+func LowercaseClaims(names ...string) ClaimsMapper {
+	return func(claims MapClaims) error {
+		for _, name := range names {
+			if s, ok := claims[name].(string); ok {
+				claims[name] = strings.ToLower(s)
+			}
+		}
+		return nil
+	}
+}
+
+// Convert a claim holding a single string, or an array of strings, to an
+// array of strings, for providers that send single-valued lists as a plain
+// string.  Arrays with values that are not strings are rejected.
+func ListClaim(name string) ClaimsMapper {
+	return func(claims MapClaims) error {
+		switch v := claims[name].(type) {
+		case nil:
+		case string:
+			claims[name] = []interface{}{v}
+		case []interface{}:
+			for _, s := range v {
+				if _, ok := s.(string); !ok {
+					return fmt.Errorf("%v claim must be a list of strings", name)
+				}
+			}
+		default:
+			return fmt.Errorf("%v claim must be a string or a list of strings", name)
+		}
+		return nil
+	}
+}
+
+// Convert a claim holding a number as a string, such as "1500000000", to a
+// number.  Strings that are not numbers are rejected.
+func NumberClaim(name string) ClaimsMapper {
+	return func(claims MapClaims) error {
+		s, ok := claims[name].(string)
+		if !ok {
+			return nil
+		}
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return fmt.Errorf("%v claim must be a number", name)
+		}
+		claims[name] = json.Number(s)
+		return nil
+	}
+}
+
+// Set the claim name to the roles mapper finds, such as mapping a provider's
+// groups to a roles claim.  The claim is deleted if there are none.
+func RolesClaim(name string, mapper RoleMapper) ClaimsMapper {
+	return func(claims MapClaims) error {
+		roles := mapper(claims)
+		if len(roles) == 0 {
+			delete(claims, name)
+			return nil
+		}
+		list := make([]interface{}, len(roles))
+		for i, r := range roles {
+			list[i] = r
+		}
+		claims[name] = list
+		return nil
+	}
+}
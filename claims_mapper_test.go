@@ -0,0 +1,85 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestClaimsMapper(t *testing.T) {
+	tokenString := signHS256(jwt.MapClaims{
+		"sub":         "user",
+		"mail":        "User@Example.COM",
+		"groups":      "admins",
+		"employee_id": "42",
+	})
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	parser := jwt.NewParser(jwt.WithClaimsMapper(
+		jwt.RenameClaim("mail", "email"),
+		jwt.LowercaseClaims("email"),
+		jwt.ListClaim("groups"),
+		jwt.NumberClaim("employee_id"),
+		jwt.RolesClaim("roles", func(claims jwt.MapClaims) []string {
+			var roles []string
+			for _, g := range claims["groups"].([]interface{}) {
+				roles = append(roles, "role:"+g.(string))
+			}
+			return roles
+		}),
+	))
+
+	token, err := parser.Parse(tokenString, keyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	if _, ok := claims["mail"]; ok || claims["email"] != "user@example.com" {
+		t.Errorf("Unexpected email claims: %v", claims)
+	}
+	if !reflect.DeepEqual(claims["groups"], []interface{}{"admins"}) || !reflect.DeepEqual(claims["roles"], []interface{}{"role:admins"}) {
+		t.Errorf("Unexpected list claims: %v", claims)
+	}
+	if claims["employee_id"] != json.Number("42") {
+		t.Errorf("Unexpected employee_id: %#v", claims["employee_id"])
+	}
+
+	// Struct claims are filled from the mapped claims
+	type userClaims struct {
+		jwt.StandardClaims
+		Email string   `json:"email"`
+		Roles []string `json:"roles"`
+	}
+	user := &userClaims{}
+	if _, err := parser.ParseWithClaims(tokenString, user, keyFunc); err != nil {
+		t.Fatal(err)
+	}
+	if user.Subject != "user" || user.Email != "user@example.com" || !reflect.DeepEqual(user.Roles, []string{"role:admins"}) {
+		t.Errorf("Unexpected struct claims: %+v", user)
+	}
+
+	// A failing mapper invalidates the token
+	failing := jwt.NewParser(jwt.WithClaimsMapper(func(jwt.MapClaims) error { return errors.New("unmappable") }))
+	token, err = failing.Parse(tokenString, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors != jwt.ValidationErrorClaimsInvalid || token.Valid {
+		t.Errorf("Expected a claims error, got %v", err)
+	}
+	if _, err := jwt.NewParser(jwt.WithClaimsMapper(jwt.NumberClaim("sub"))).Parse(tokenString, keyFunc); err == nil {
+		t.Errorf("Expected a non-numeric claim to be rejected")
+	}
+}
+
+func TestParseIntoMapsClaims(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	parser := jwt.NewParser(jwt.WithClaimsMapper(jwt.RenameClaim("mail", "email")))
+
+	claims := jwt.MapClaims{}
+	if err := parser.ParseInto(&jwt.Token{}, signHS256(jwt.MapClaims{"mail": "user@example.com"}), claims, keyFunc); err != nil {
+		t.Fatal(err)
+	}
+	if claims["email"] != "user@example.com" {
+		t.Errorf("Unexpected claims: %v", claims)
+	}
+}
@@ -0,0 +1,161 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var errMalformedClaimsJSON = errors.New("claims are not a valid JSON object")
+
+// Returns the JSON of the named top-level claim, found by scanning the raw
+// claims of a parsed token without decoding them.  For hot paths that need
+// one claim from a large token.  Returns ErrClaimNotFound if the claim is
+// absent or null.  Tokens that were not parsed have their claims encoded.
+func GetClaimRaw(token *Token, name string) (json.RawMessage, error) {
+	data := token.RawClaims
+	if data == nil {
+		var err error
+		if data, err = token.jsonCodec().Marshal(token.Claims); err != nil {
+			return nil, err
+		}
+	}
+	return RawClaim(data, name)
+}
+
+// Returns the JSON of the named member of the JSON object in data, without
+// decoding the object.  As with encoding/json, if the name is repeated the
+// last value wins.  Returns ErrClaimNotFound if it is absent or null.
+func RawClaim(data []byte, name string) (json.RawMessage, error) {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return nil, errMalformedClaimsJSON
+	}
+	i = skipSpace(data, i+1)
+	if i < len(data) && data[i] == '}' {
+		return nil, ErrClaimNotFound
+	}
+
+	var found json.RawMessage
+	for {
+		if i >= len(data) || data[i] != '"' {
+			return nil, errMalformedClaimsJSON
+		}
+		end := skipString(data, i)
+		if end < 0 {
+			return nil, errMalformedClaimsJSON
+		}
+		key := data[i:end]
+		i = skipSpace(data, end)
+		if i >= len(data) || data[i] != ':' {
+			return nil, errMalformedClaimsJSON
+		}
+		i = skipSpace(data, i+1)
+		end = skipValue(data, i)
+		if end < 0 {
+			return nil, errMalformedClaimsJSON
+		}
+		if keyEquals(key, name) {
+			found = json.RawMessage(data[i:end])
+		}
+		i = skipSpace(data, end)
+		if i >= len(data) {
+			return nil, errMalformedClaimsJSON
+		}
+		if data[i] == '}' {
+			break
+		}
+		if data[i] != ',' {
+			return nil, errMalformedClaimsJSON
+		}
+		i = skipSpace(data, i+1)
+	}
+
+	if found == nil || string(found) == "null" {
+		return nil, ErrClaimNotFound
+	}
+	return found, nil
+}
+
+// Compares a quoted JSON string with name, unescaping it only if needed
+func keyEquals(quoted []byte, name string) bool {
+	unescaped := true
+	for _, c := range quoted[1 : len(quoted)-1] {
+		if c == '\\' {
+			unescaped = false
+			break
+		}
+	}
+	if unescaped {
+		return string(quoted[1:len(quoted)-1]) == name
+	}
+	var key string
+	return json.Unmarshal(quoted, &key) == nil && key == name
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch data[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// Returns the index after the string starting at data[i], or -1
+func skipString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// Returns the index after the value starting at data[i], or -1.  Values are
+// delimited, not validated; the parser has already decoded the claims.
+func skipValue(data []byte, i int) int {
+	if i >= len(data) {
+		return -1
+	}
+	switch data[i] {
+	case '"':
+		return skipString(data, i)
+	case '{', '[':
+		depth := 0
+		for i < len(data) {
+			switch data[i] {
+			case '"':
+				if i = skipString(data, i); i < 0 {
+					return -1
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			}
+			i++
+		}
+		return -1
+	}
+	start := i
+	for i < len(data) {
+		switch data[i] {
+		case ',', '}', ']', ' ', '\t', '\r', '\n':
+			if i == start {
+				return -1
+			}
+			return i
+		}
+		i++
+	}
+	return -1
+}
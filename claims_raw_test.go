@@ -0,0 +1,57 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestRawClaim(t *testing.T) {
+	data := []byte(` { "sub" : "user", "nested": {"sub": "other", "list": ["}", "\"", 1]}, "n": -1.5e3,` +
+		` "quote\"d": true, "empty": null, "sub2": [ ] } `)
+
+	var tests = []struct {
+		name string
+		want string
+		err  error
+	}{
+		{"sub", `"user"`, nil},
+		{"nested", `{"sub": "other", "list": ["}", "\"", 1]}`, nil},
+		{"n", `-1.5e3`, nil},
+		{`quote"d`, `true`, nil},
+		{"sub2", `[ ]`, nil},
+		{"empty", ``, jwt.ErrClaimNotFound},
+		{"other", ``, jwt.ErrClaimNotFound},
+	}
+	for _, test := range tests {
+		raw, err := jwt.RawClaim(data, test.name)
+		if string(raw) != test.want || err != test.err {
+			t.Errorf("[%v] Expected %v %v, got %s %v", test.name, test.want, test.err, raw, err)
+		}
+	}
+
+	if raw, err := jwt.RawClaim([]byte(`{"a":1,"a":2}`), "a"); string(raw) != "2" || err != nil {
+		t.Errorf("Expected the last value to win, got %s %v", raw, err)
+	}
+	for _, bad := range []string{``, `[]`, `{"a"}`, `{"a":1`, `{"a":1 "b":2}`, `{"a":}`} {
+		if _, err := jwt.RawClaim([]byte(bad), "b"); err == nil || err == jwt.ErrClaimNotFound {
+			t.Errorf("Expected %q to be rejected, got %v", bad, err)
+		}
+	}
+}
+
+func TestGetClaimRaw(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	token, err := jwt.Parse(signHS256(jwt.MapClaims{"sub": "user", "exp": 1 << 62}), keyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, err := jwt.GetClaimRaw(token, "exp"); string(raw) != "4611686018427387904" || err != nil {
+		t.Errorf("Unexpected exp: %s %v", raw, err)
+	}
+
+	created := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{Subject: "user"})
+	if raw, err := jwt.GetClaimRaw(created, "sub"); string(raw) != `"user"` || err != nil {
+		t.Errorf("Unexpected sub: %s %v", raw, err)
+	}
+}
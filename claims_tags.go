@@ -0,0 +1,191 @@
+package jwt
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Checks the constraints declared in jwt struct tags on a claims struct.
+// ParseWithClaims applies them to struct claims automatically, after the
+// other claims checks.  The tag holds comma-separated constraints:
+//
+//	required       the field must not be its zero value
+//	format=email   a string must be a bare email address; also uri and uuid
+//	min=N, max=N   bounds on a number, or on the length of a string or slice
+//
+// For example:
+//
+//	type UserClaims struct {
+//		jwt.StandardClaims
+//		Email string   `json:"email" jwt:"required,format=email,max=254"`
+//		Roles []string `json:"roles" jwt:"max=16"`
+//	}
+//
+// Only required applies to fields left at their zero value, so other
+// constraints describe optional claims when present.  Embedded structs are
+// checked too.  Errors name the claim by its json tag.
+func ValidateClaimTags(claims interface{}) error {
+	v := reflect.ValueOf(claims)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	rules, err := claimRulesFor(v.Type())
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		if err := r.check(v.FieldByIndex(r.index)); err != nil {
+			return NewValidationError(err.Error(), ValidationErrorClaimsInvalid)
+		}
+	}
+	return nil
+}
+
+// The constraints on one struct field
+type claimRule struct {
+	name     string
+	index    []int
+	required bool
+	format   string
+	min, max *float64
+}
+
+var claimRulesCache sync.Map // reflect.Type -> []claimRule
+
+// Returns the rules declared on t's fields, parsing its tags once per type
+func claimRulesFor(t reflect.Type) ([]claimRule, error) {
+	if rules, ok := claimRulesCache.Load(t); ok {
+		return rules.([]claimRule), nil
+	}
+	rules, err := parseClaimRules(t, nil)
+	if err != nil {
+		return nil, err
+	}
+	claimRulesCache.Store(t, rules)
+	return rules, nil
+}
+
+func parseClaimRules(t reflect.Type, index []int) ([]claimRule, error) {
+	var rules []claimRule
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldIndex := append(index[:len(index):len(index)], i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			embedded, err := parseClaimRules(f.Type, fieldIndex)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, embedded...)
+			continue
+		}
+		tag, ok := f.Tag.Lookup("jwt")
+		if !ok || tag == "" {
+			continue
+		}
+
+		r := claimRule{name: f.Name, index: fieldIndex}
+		if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			r.name = name
+		}
+		for _, c := range strings.Split(tag, ",") {
+			key, value := c, ""
+			if i := strings.Index(c, "="); i >= 0 {
+				key, value = c[:i], c[i+1:]
+			}
+			switch key {
+			case "required":
+				r.required = true
+			case "format":
+				if _, ok := claimFormats[value]; !ok {
+					return nil, fmt.Errorf("jwt tag on %v.%v: unknown format %q", t.Name(), f.Name, value)
+				}
+				r.format = value
+			case "min", "max":
+				n, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return nil, fmt.Errorf("jwt tag on %v.%v: %v must be a number", t.Name(), f.Name, key)
+				}
+				if key == "min" {
+					r.min = &n
+				} else {
+					r.max = &n
+				}
+			default:
+				return nil, fmt.Errorf("jwt tag on %v.%v: unknown constraint %q", t.Name(), f.Name, c)
+			}
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// The formats a jwt tag may name, and how to check them
+var claimFormats = map[string]func(string) bool{
+	"email": isEmailAddress,
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	},
+	"uuid": uuidPattern.MatchString,
+}
+
+func (r claimRule) check(v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			if r.required {
+				return fmt.Errorf("token is missing the %v claim", r.name)
+			}
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.IsZero() {
+		if r.required {
+			return fmt.Errorf("token is missing the %v claim", r.name)
+		}
+		return nil
+	}
+
+	if r.format != "" {
+		if v.Kind() != reflect.String || !claimFormats[r.format](v.String()) {
+			return fmt.Errorf("%v claim must be a valid %v", r.name, r.format)
+		}
+	}
+
+	var size float64
+	unit := ""
+	switch v.Kind() {
+	case reflect.String:
+		size, unit = float64(len([]rune(v.String()))), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		size, unit = float64(v.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = v.Float()
+	default:
+		return nil
+	}
+	if r.min != nil && size < *r.min {
+		return fmt.Errorf("%v claim must be at least %v%v", r.name, *r.min, unit)
+	}
+	if r.max != nil && size > *r.max {
+		return fmt.Errorf("%v claim must be at most %v%v", r.name, *r.max, unit)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package jwt
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Reports whether s is a bare email address, such as user@example.com: an
+// RFC 5322 dot-atom local part and domain.  Display names, comments, quoted
+// local parts and domain literals are rejected.  This is checked by hand,
+// rather than with net/mail, so TinyGo builds accept the same addresses.
+func isEmailAddress(s string) bool {
+	at := strings.LastIndexByte(s, '@')
+	if at < 0 || !utf8.ValidString(s) {
+		return false
+	}
+	return isDotAtom(s[:at]) && isDotAtom(s[at+1:])
+}
+
+// Reports whether s is one or more runs of atext separated by single dots
+func isDotAtom(s string) bool {
+	if s == "" || s[0] == '.' || s[len(s)-1] == '.' || strings.Contains(s, "..") {
+		return false
+	}
+	for _, r := range s {
+		if r != '.' && !isAtext(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Letters, digits and the RFC 5322 atext symbols.  Like net/mail, non-ASCII
+// characters are allowed for internationalized addresses.
+func isAtext(r rune) bool {
+	switch {
+	case r >= utf8.RuneSelf:
+		return true
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r)
+}
@@ -0,0 +1,110 @@
+package jwt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type taggedClaims struct {
+	jwt.StandardClaims
+	Email    string   `json:"email" jwt:"required,format=email,max=32"`
+	Website  string   `json:"website,omitempty" jwt:"format=uri"`
+	DeviceID string   `json:"device_id,omitempty" jwt:"format=uuid"`
+	Roles    []string `json:"roles,omitempty" jwt:"max=2"`
+	Level    int      `json:"level,omitempty" jwt:"min=1,max=10"`
+	Tenant   *string  `json:"tenant" jwt:"required"`
+}
+
+func TestValidateClaimTags(t *testing.T) {
+	tenant := "acme"
+	var tests = []struct {
+		name   string
+		claims *taggedClaims
+		err    string
+	}{
+		{"valid", &taggedClaims{Email: "user@example.com", Website: "https://example.com", DeviceID: "123e4567-e89b-12d3-a456-426614174000", Roles: []string{"a"}, Level: 3, Tenant: &tenant}, ""},
+		{"minimal", &taggedClaims{Email: "user@example.com", Tenant: &tenant}, ""},
+		{"missing email", &taggedClaims{Tenant: &tenant}, "token is missing the email claim"},
+		{"missing tenant", &taggedClaims{Email: "user@example.com"}, "token is missing the tenant claim"},
+		{"bad email", &taggedClaims{Email: "User <user@example.com>", Tenant: &tenant}, "email claim must be a valid email"},
+		{"long email", &taggedClaims{Email: strings.Repeat("a", 30) + "@example.com", Tenant: &tenant}, "email claim must be at most 32 characters"},
+		{"bad uri", &taggedClaims{Email: "user@example.com", Website: "example", Tenant: &tenant}, "website claim must be a valid uri"},
+		{"bad uuid", &taggedClaims{Email: "user@example.com", DeviceID: "1234", Tenant: &tenant}, "device_id claim must be a valid uuid"},
+		{"too many roles", &taggedClaims{Email: "user@example.com", Roles: []string{"a", "b", "c"}, Tenant: &tenant}, "roles claim must be at most 2 items"},
+		{"level too high", &taggedClaims{Email: "user@example.com", Level: 11, Tenant: &tenant}, "level claim must be at most 10"},
+	}
+
+	for _, test := range tests {
+		err := jwt.ValidateClaimTags(test.claims)
+		if test.err == "" && err != nil {
+			t.Errorf("[%v] Unexpected error: %v", test.name, err)
+		}
+		if test.err != "" && (err == nil || err.Error() != test.err) {
+			t.Errorf("[%v] Expected error %q, got %v", test.name, test.err, err)
+		}
+	}
+
+	type badTag struct {
+		Name string `jwt:"format=phone"`
+	}
+	if err := jwt.ValidateClaimTags(&badTag{}); err == nil {
+		t.Errorf("Expected an unknown format to be rejected")
+	}
+}
+
+func TestParseEnforcesClaimTags(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	_, err := jwt.ParseWithClaims(signHS256(jwt.MapClaims{"email": "not an address", "tenant": "acme"}), &taggedClaims{}, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorClaimsInvalid == 0 {
+		t.Errorf("Expected a claims error, got %v", err)
+	}
+	if _, err := jwt.ParseWithClaims(signHS256(jwt.MapClaims{"email": "user@example.com", "tenant": "acme"}), &taggedClaims{}, keyFunc); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// The email format is checked without net/mail, so these hold for TinyGo
+// builds too
+func TestClaimTagsEmailFormat(t *testing.T) {
+	var tests = []struct {
+		email string
+		valid bool
+	}{
+		{"user@example.com", true},
+		{"first.last+tag@sub.example.com", true},
+		{"o'brien@example.com", true},
+		{"user@localhost", true},
+		{"jörg@exämple.de", true},
+		{"user", false},
+		{"@example.com", false},
+		{"user@", false},
+		{"user@@example.com", false},
+		{"user@example..com", false},
+		{".user@example.com", false},
+		{"user.@example.com", false},
+		{"user@.example.com", false},
+		{"user@example.com.", false},
+		{"first last@example.com", false},
+		{`"user"@example.com`, false},
+		{"User <user@example.com>", false},
+		{"user@[192.0.2.1]", false},
+		{"user@example.com (comment)", false},
+		{" user@example.com", false},
+		{"user@exa\xffmple.com", false},
+	}
+
+	type emailClaims struct {
+		Email string `jwt:"format=email"`
+	}
+	for _, test := range tests {
+		err := jwt.ValidateClaimTags(&emailClaims{Email: test.email})
+		if test.valid && err != nil {
+			t.Errorf("[%q] Unexpected error: %v", test.email, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("[%q] Expected the address to be rejected", test.email)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type tenantKey struct{}
+
+var errWrongTenant = errors.New("token belongs to another tenant")
+
+type tenantClaims struct {
+	jwt.StandardClaims
+	Tenant string `json:"tenant"`
+}
+
+func (c *tenantClaims) Validate(ctx context.Context) error {
+	if tenant, _ := ctx.Value(tenantKey{}).(string); c.Tenant != tenant {
+		return errWrongTenant
+	}
+	return nil
+}
+
+func TestClaimsValidator(t *testing.T) {
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &tenantClaims{Tenant: "acme"}).SignedString(hmacTestKey)
+	keyFunc := func(context.Context, *jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	if _, err := jwt.ParseWithClaimsContext(ctx, tokenString, &tenantClaims{}, keyFunc); err != nil {
+		t.Errorf("Error while verifying token: %v", err)
+	}
+
+	ctx = context.WithValue(context.Background(), tenantKey{}, "globex")
+	_, err := jwt.ParseWithClaimsContext(ctx, tokenString, &tenantClaims{}, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorClaimsInvalid == 0 || ve.Inner != errWrongTenant {
+		t.Errorf("Expected claims invalid error, got %v", err)
+	}
+
+	// Skipped along with the standard checks
+	if _, err := jwt.NewParser(jwt.WithoutClaimsValidation()).ParseWithClaimsContext(ctx, tokenString, &tenantClaims{}, keyFunc); err != nil {
+		t.Errorf("Validate called with claims validation disabled: %v", err)
+	}
+}
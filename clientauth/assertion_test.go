@@ -0,0 +1,104 @@
+package clientauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func testAssertion(audience string) *ClientAssertion {
+	return &ClientAssertion{
+		ClientID: "orders-service",
+		Audience: audience,
+		Method:   jwt.SigningMethodRS256,
+		Key:      test.LoadRSAPrivateKeyFromDisk("../test/sample_key"),
+		KeyID:    "k1",
+	}
+}
+
+func TestClientAssertion(t *testing.T) {
+	a := testAssertion("https://login.example.com/token")
+	first, err := a.Sign()
+	if err != nil {
+		t.Fatalf("Error signing assertion: %v", err)
+	}
+	second, _ := a.Sign()
+
+	publicKey := test.LoadRSAPublicKeyFromDisk("../test/sample_key.pub")
+	ids := map[string]bool{}
+	for _, assertion := range []string{first, second} {
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(assertion, claims, func(*jwt.Token) (interface{}, error) { return publicKey, nil })
+		if err != nil {
+			t.Fatalf("Error parsing assertion: %v", err)
+		}
+		if token.Header["kid"] != "k1" || claims["iss"] != "orders-service" || claims["sub"] != "orders-service" ||
+			!claims.VerifyAudience("https://login.example.com/token", true) {
+			t.Errorf("Unexpected assertion %v %v", token.Header, claims)
+		}
+		if exp, iat := claims["exp"].(float64), claims["iat"].(float64); exp-iat != 300 {
+			t.Errorf("Expected a 5 minute lifetime, got %vs", exp-iat)
+		}
+		ids[claims["jti"].(string)] = true
+	}
+	if len(ids) != 2 {
+		t.Errorf("Expected each assertion to have a new jti")
+	}
+}
+
+func TestClientCredentials(t *testing.T) {
+	var tests = []struct {
+		name        string
+		bearerGrant bool
+		grantType   string
+		status      int
+		valid       bool
+	}{
+		{"client assertion", false, "client_credentials", http.StatusOK, true},
+		{"bearer grant", true, JWTBearerGrantType, http.StatusOK, true},
+		{"rejected", false, "client_credentials", http.StatusBadRequest, false},
+	}
+
+	for _, data := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			if r.Form.Get("grant_type") != data.grantType || r.Form.Get("scope") != "inventory.read" {
+				t.Errorf("[%v] Unexpected form %v", data.name, r.Form)
+			}
+			assertion := r.Form.Get("client_assertion")
+			if data.bearerGrant {
+				assertion = r.Form.Get("assertion")
+			} else if r.Form.Get("client_assertion_type") != ClientAssertionType {
+				t.Errorf("[%v] Unexpected client_assertion_type %q", data.name, r.Form.Get("client_assertion_type"))
+			}
+			if _, err := jwt.SplitToken(assertion); err != nil {
+				t.Errorf("[%v] Expected an assertion, got %q", data.name, assertion)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			if data.status != http.StatusOK {
+				w.WriteHeader(data.status)
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "at", "token_type": "Bearer", "expires_in": 3600})
+		}))
+
+		config := &ClientCredentials{Assertion: testAssertion(server.URL), TokenURL: server.URL, Scopes: []string{"inventory.read"}, BearerGrant: data.bearerGrant}
+		token, err := config.TokenSource(context.Background()).Token()
+		server.Close()
+
+		if (err == nil) != data.valid {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+			continue
+		}
+		if data.valid && (token.AccessToken != "at" || token.Expiry.IsZero()) {
+			t.Errorf("[%v] Unexpected token %+v", data.name, token)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package clientauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Largest token response ClientCredentials will read
+const maxResponseSize = 1 << 20
+
+// An access token from a token endpoint
+type Token struct {
+	AccessToken     string    `json:"access_token"`
+	TokenType       string    `json:"token_type"`
+	IssuedTokenType string    `json:"issued_token_type,omitempty"` // Set by token exchange, such as AccessTokenType
+	ExpiresIn       int64     `json:"expires_in,omitempty"`
+	Scope           string    `json:"scope,omitempty"`
+	Expiry          time.Time `json:"-"` // Computed from ExpiresIn; zero if the token does not expire
+}
+
+// Supplies access tokens
+type TokenSource interface {
+	Token() (*Token, error)
+}
+
+// Obtains access tokens with the client credentials grant, authenticating
+// with a client assertion
+type ClientCredentials struct {
+	Assertion  *ClientAssertion
+	TokenURL   string
+	Scopes     []string
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+
+	// Send the assertion as a JWT bearer grant rather than as client
+	// authentication, as Google service accounts require
+	BearerGrant bool
+}
+
+// Request a new access token
+func (c *ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	var form url.Values
+	if c.BearerGrant {
+		assertion, err := c.Assertion.Sign()
+		if err != nil {
+			return nil, err
+		}
+		form = url.Values{"grant_type": {JWTBearerGrantType}, "assertion": {assertion}}
+	} else {
+		var err error
+		if form, err = c.Assertion.Form(); err != nil {
+			return nil, err
+		}
+		form.Set("grant_type", "client_credentials")
+	}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", jwt.FormatScope(c.Scopes))
+	}
+	return requestToken(ctx, c.HTTPClient, c.TokenURL, form)
+}
+
+// Post form to a token endpoint and decode the token response
+func requestToken(ctx context.Context, client *http.Client, tokenURL string, form url.Values) (*Token, error) {
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body := io.LimitReader(resp.Body, maxResponseSize)
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		json.NewDecoder(body).Decode(&e)
+		io.Copy(ioutil.Discard, body)
+		if e.Error != "" {
+			return nil, fmt.Errorf("requesting token: %v: %v", e.Error, e.Description)
+		}
+		return nil, fmt.Errorf("requesting token: unexpected status %v", resp.Status)
+	}
+	token := &Token{}
+	if err := json.NewDecoder(body).Decode(token); err != nil {
+		return nil, fmt.Errorf("decoding token response: %v", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = jwt.TimeFunc().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// A TokenSource requesting tokens with ctx.  Each call requests a new token;
+// wrap it with OAuth2TokenSource to reuse them until they expire.
+func (c *ClientCredentials) TokenSource(ctx context.Context) TokenSource {
+	return &clientCredentialsSource{ctx, c}
+}
+
+type clientCredentialsSource struct {
+	ctx    context.Context
+	config *ClientCredentials
+}
+
+func (s *clientCredentialsSource) Token() (*Token, error) {
+	return s.config.Token(s.ctx)
+}
@@ -0,0 +1,45 @@
+// Client-side token helpers for service-to-service authentication.
+//
+// ClientAssertion signs RFC 7523 client authentication assertions, the
+// private_key_jwt method, in place of a client secret.  ClientCredentials uses
+// one to obtain access tokens from a token endpoint:
+//
+//	config := &clientauth.ClientCredentials{
+//		Assertion: &clientauth.ClientAssertion{
+//			ClientID: "orders-service",
+//			Audience: "https://login.example.com/oauth2/token",
+//			Method:   jwt.SigningMethodRS256,
+//			Key:      privateKey,
+//			KeyID:    "2024-01",
+//		},
+//		TokenURL: "https://login.example.com/oauth2/token",
+//		Scopes:   []string{"inventory.read"},
+//	}
+//	token, err := config.Token(ctx)
+//
+// SelfSigned mints tokens signed with the service's own key, for internal
+// services that verify each other directly rather than through an
+// authorization server:
+//
+//	src := &clientauth.SelfSigned{
+//		Method: jwt.SigningMethodES256,
+//		Key:    privateKey,
+//		Claims: jwt.MapClaims{"iss": "orders-service", "aud": "inventory"},
+//	}
+//	client := oauth2.NewClient(ctx, clientauth.OAuth2TokenSource(src))
+//
+// Or sign requests with Transport, which sets each token's aud to the target
+// host:
+//
+//	client := &http.Client{Transport: &clientauth.Transport{Source: src}}
+//
+// TokenExchange trades a caller's token for one to pass downstream, with
+// the RFC 8693 token exchange grant:
+//
+//	exchange := &clientauth.TokenExchange{TokenURL: tokenURL, Assertion: assertion, Audience: []string{"billing"}}
+//	token, err := exchange.Exchange(ctx, callerToken, "")
+//
+// OAuth2TokenSource adapts these sources for golang.org/x/oauth2 transports.
+// It depends on golang.org/x/oauth2 and is only built with the oauth2 build
+// tag.
+package clientauth
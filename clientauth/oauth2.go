@@ -0,0 +1,25 @@
+//go:build oauth2
+
+package clientauth
+
+import (
+	"golang.org/x/oauth2"
+)
+
+// Adapt src for golang.org/x/oauth2, reusing each token until shortly
+// before it expires
+func OAuth2TokenSource(src TokenSource) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(nil, oauth2Source{src})
+}
+
+type oauth2Source struct {
+	src TokenSource
+}
+
+func (s oauth2Source) Token() (*oauth2.Token, error) {
+	token, err := s.src.Token()
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{AccessToken: token.AccessToken, TokenType: token.TokenType, Expiry: token.Expiry}, nil
+}
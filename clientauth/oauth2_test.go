@@ -0,0 +1,31 @@
+//go:build oauth2
+
+package clientauth
+
+import (
+	"testing"
+	"time"
+)
+
+type countingSource struct {
+	calls int
+}
+
+func (s *countingSource) Token() (*Token, error) {
+	s.calls++
+	return &Token{AccessToken: "at", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}, nil
+}
+
+func TestOAuth2TokenSource(t *testing.T) {
+	src := &countingSource{}
+	ts := OAuth2TokenSource(src)
+	for i := 0; i < 3; i++ {
+		token, err := ts.Token()
+		if err != nil || token.AccessToken != "at" || token.TokenType != "Bearer" {
+			t.Fatalf("Unexpected token %+v, %v", token, err)
+		}
+	}
+	if src.calls != 1 {
+		t.Errorf("Expected the token to be reused, got %v requests", src.calls)
+	}
+}
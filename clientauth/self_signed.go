@@ -0,0 +1,114 @@
+package clientauth
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// A TokenSource minting short-lived tokens signed with the service's own key,
+// for internal services that trust each other's keys.  Each token is reused
+// until shortly before it expires, then a new one is signed.  Safe for
+// concurrent use.
+type SelfSigned struct {
+	Method jwt.SigningMethod
+	Key    interface{}
+	KeyID  string // If set, sent as the kid header
+
+	// Claims copied into every token, such as iss, sub and aud.  iat, exp
+	// and jti are set per token.
+	Claims jwt.MapClaims
+
+	Lifetime      time.Duration // Defaults to 5 minutes
+	RefreshBefore time.Duration // How long before expiry a new token is signed.  Defaults to a fifth of Lifetime
+
+	mu     sync.Mutex
+	cached map[string]*Token // By audience; "" for the template's own
+}
+
+// Returns the cached token, or signs a new one if it is close to expiry
+func (s *SelfSigned) Token() (*Token, error) {
+	return s.TokenForAudience("")
+}
+
+// Token, with aud set to audience.  Tokens are cached per audience.  An empty
+// audience keeps the template's aud.
+func (s *SelfSigned) TokenForAudience(audience string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token := s.cached[audience]; token != nil && jwt.TimeFunc().Before(token.Expiry.Add(-s.refreshBefore())) {
+		return token, nil
+	}
+	var overrides jwt.MapClaims
+	if audience != "" {
+		overrides = jwt.MapClaims{"aud": audience}
+	}
+	token, err := s.mint(overrides)
+	if err != nil {
+		return nil, err
+	}
+	if s.cached == nil {
+		s.cached = make(map[string]*Token)
+	}
+	s.cached[audience] = token
+	return token, nil
+}
+
+// Sign a new token with overrides applied on top of the template, bypassing
+// the cache
+func (s *SelfSigned) TokenWithClaims(overrides jwt.MapClaims) (*Token, error) {
+	return s.mint(overrides)
+}
+
+// Sign a new token from the template, with overrides applied on top
+func (s *SelfSigned) mint(overrides jwt.MapClaims) (*Token, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	now := jwt.TimeFunc()
+	expiry := now.Add(s.lifetime())
+
+	claims := jwt.MapClaims{}
+	for k, v := range s.Claims {
+		claims[k] = v
+	}
+	for k, v := range overrides {
+		claims[k] = v
+	}
+	claims["jti"] = jwt.EncodeSegment(id)
+	claims["iat"] = now.Unix()
+	claims["exp"] = expiry.Unix()
+
+	token := jwt.NewWithClaims(s.Method, claims)
+	if s.KeyID != "" {
+		token.Header["kid"] = s.KeyID
+	}
+	signed, err := token.SignedString(s.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &Token{
+		AccessToken: signed,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.lifetime() / time.Second),
+		Expiry:      time.Unix(expiry.Unix(), 0),
+	}, nil
+}
+
+func (s *SelfSigned) lifetime() time.Duration {
+	if s.Lifetime == 0 {
+		return 5 * time.Minute
+	}
+	return s.Lifetime
+}
+
+func (s *SelfSigned) refreshBefore() time.Duration {
+	if s.RefreshBefore == 0 {
+		return s.lifetime() / 5
+	}
+	return s.RefreshBefore
+}
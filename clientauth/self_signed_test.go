@@ -0,0 +1,55 @@
+package clientauth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestSelfSigned(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	src := &SelfSigned{
+		Method:   jwt.SigningMethodHS256,
+		Key:      []byte("secret"),
+		Claims:   jwt.MapClaims{"iss": "orders", "aud": "inventory"},
+		Lifetime: 10 * time.Minute,
+	}
+
+	first, err := src.Token()
+	if err != nil {
+		t.Fatalf("Error minting token: %v", err)
+	}
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(first.AccessToken, claims, func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil }); err != nil {
+		t.Fatalf("Error parsing token: %v", err)
+	}
+	if claims["iss"] != "orders" || claims["aud"] != "inventory" || claims["exp"] != float64(now.Add(10*time.Minute).Unix()) {
+		t.Errorf("Unexpected claims %v", claims)
+	}
+	if !first.Expiry.Equal(now.Add(10 * time.Minute)) {
+		t.Errorf("Unexpected expiry %v", first.Expiry)
+	}
+
+	var tests = []struct {
+		name  string
+		after time.Duration
+		fresh bool
+	}{
+		{"reused", 7 * time.Minute, false},
+		{"refreshed before expiry", 8*time.Minute + time.Second, true},
+	}
+	for _, data := range tests {
+		now = time.Unix(1700000000, 0).Add(data.after)
+		token, err := src.Token()
+		if err != nil {
+			t.Fatalf("[%v] Error minting token: %v", data.name, err)
+		}
+		if (token.AccessToken != first.AccessToken) != data.fresh {
+			t.Errorf("[%v] Expected fresh token: %v", data.name, data.fresh)
+		}
+	}
+}
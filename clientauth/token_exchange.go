@@ -0,0 +1,79 @@
+package clientauth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// The grant_type for token exchange (RFC 8693)
+const TokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// Token type identifiers from RFC 8693 section 3
+const (
+	AccessTokenType  = "urn:ietf:params:oauth:token-type:access_token"
+	RefreshTokenType = "urn:ietf:params:oauth:token-type:refresh_token"
+	IDTokenType      = "urn:ietf:params:oauth:token-type:id_token"
+	JWTTokenType     = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// Exchanges a token for another with the RFC 8693 token exchange grant, for
+// example so a service can call another on behalf of its caller.  The
+// authorization server records the actor token's party in the act claim of
+// the issued token.
+type TokenExchange struct {
+	TokenURL string
+
+	// Authenticates the client.  If nil, HTTPClient must authenticate the
+	// request itself, or the server must allow unauthenticated clients.
+	Assertion *ClientAssertion
+
+	SubjectTokenType   string   // Defaults to AccessTokenType
+	ActorTokenType     string   // Defaults to AccessTokenType
+	RequestedTokenType string   // If set, the type of token to issue
+	Audience           []string // The services the issued token is for
+	Resource           []string // URIs of the resources the issued token is for
+	Scopes             []string
+	HTTPClient         *http.Client // Defaults to http.DefaultClient
+}
+
+// Exchange subjectToken, optionally presented by the party authenticated by
+// actorToken, for a new token
+func (e *TokenExchange) Exchange(ctx context.Context, subjectToken, actorToken string) (*Token, error) {
+	form := url.Values{}
+	if e.Assertion != nil {
+		var err error
+		if form, err = e.Assertion.Form(); err != nil {
+			return nil, err
+		}
+	}
+	form.Set("grant_type", TokenExchangeGrantType)
+	form.Set("subject_token", subjectToken)
+	form.Set("subject_token_type", orDefault(e.SubjectTokenType, AccessTokenType))
+	if actorToken != "" {
+		form.Set("actor_token", actorToken)
+		form.Set("actor_token_type", orDefault(e.ActorTokenType, AccessTokenType))
+	}
+	if e.RequestedTokenType != "" {
+		form.Set("requested_token_type", e.RequestedTokenType)
+	}
+	for _, aud := range e.Audience {
+		form.Add("audience", aud)
+	}
+	for _, resource := range e.Resource {
+		form.Add("resource", resource)
+	}
+	if len(e.Scopes) > 0 {
+		form.Set("scope", jwt.FormatScope(e.Scopes))
+	}
+	return requestToken(ctx, e.HTTPClient, e.TokenURL, form)
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
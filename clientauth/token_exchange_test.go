@@ -0,0 +1,65 @@
+package clientauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTokenExchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("subject_token") == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_request", "error_description": "bad subject"})
+			return
+		}
+		expected := map[string][]string{
+			"grant_type":           {TokenExchangeGrantType},
+			"subject_token":        {"user-token"},
+			"subject_token_type":   {AccessTokenType},
+			"actor_token":          {"service-token"},
+			"actor_token_type":     {JWTTokenType},
+			"requested_token_type": {AccessTokenType},
+			"audience":             {"billing", "ledger"},
+			"resource":             {"https://billing.example.com/"},
+			"scope":                {"invoices:read"},
+		}
+		for k, v := range expected {
+			if !reflect.DeepEqual(r.Form[k], v) {
+				t.Errorf("Expected %v=%v, got %v", k, v, r.Form[k])
+			}
+		}
+		if r.Form.Get("client_assertion") == "" {
+			t.Errorf("Expected the client to authenticate")
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "exchanged", "token_type": "Bearer", "issued_token_type": AccessTokenType, "expires_in": 60,
+		})
+	}))
+	defer server.Close()
+
+	exchange := &TokenExchange{
+		TokenURL:           server.URL,
+		Assertion:          testAssertion(server.URL),
+		ActorTokenType:     JWTTokenType,
+		RequestedTokenType: AccessTokenType,
+		Audience:           []string{"billing", "ledger"},
+		Resource:           []string{"https://billing.example.com/"},
+		Scopes:             []string{"invoices:read"},
+	}
+	token, err := exchange.Exchange(context.Background(), "user-token", "service-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "exchanged" || token.IssuedTokenType != AccessTokenType || token.Expiry.IsZero() {
+		t.Errorf("Unexpected token: %+v", token)
+	}
+
+	if _, err := exchange.Exchange(context.Background(), "bad", ""); err == nil || err.Error() != "requesting token: invalid_request: bad subject" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
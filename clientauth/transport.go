@@ -0,0 +1,79 @@
+package clientauth
+
+import (
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// An http.RoundTripper adding a token from Source to each outgoing request's
+// Authorization header.  Tokens are minted for the target's audience and
+// cached until near expiry.
+type Transport struct {
+	Source *SelfSigned
+
+	// Returns the aud claim for a request.  Defaults to the target URL's
+	// scheme and host, such as https://inventory.internal.  Return "" to use
+	// the template's aud.
+	Audience func(r *http.Request) string
+
+	// If set, returns claims to add to the token for a request.  Tokens with
+	// per-request claims are signed for each request and not cached.
+	Claims func(r *http.Request) jwt.MapClaims
+
+	Base http.RoundTripper // Defaults to http.DefaultTransport
+}
+
+// The target URL's scheme and host
+func OriginAudience(r *http.Request) string {
+	return r.URL.Scheme + "://" + r.URL.Host
+}
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	audience := OriginAudience
+	if t.Audience != nil {
+		audience = t.Audience
+	}
+	aud := audience(r)
+
+	var token *Token
+	var err error
+	if extra := t.claims(r); extra != nil {
+		if aud != "" {
+			extra["aud"] = aud
+		}
+		token, err = t.Source.TokenWithClaims(extra)
+	} else {
+		token, err = t.Source.TokenForAudience(aud)
+	}
+	if err != nil {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+		return nil, err
+	}
+
+	// RoundTrippers must not modify the request
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", token.TokenType+" "+token.AccessToken)
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(r)
+}
+
+func (t *Transport) claims(r *http.Request) jwt.MapClaims {
+	if t.Claims == nil {
+		return nil
+	}
+	claims := jwt.MapClaims{}
+	for k, v := range t.Claims(r) {
+		claims[k] = v
+	}
+	if len(claims) == 0 {
+		return nil
+	}
+	return claims
+}
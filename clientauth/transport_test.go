@@ -0,0 +1,64 @@
+package clientauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestTransport(t *testing.T) {
+	var seen []jwt.MapClaims
+	var raw []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		claims := jwt.MapClaims{}
+		if _, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil }); err != nil {
+			t.Errorf("Error parsing token: %v", err)
+		}
+		seen = append(seen, claims)
+		raw = append(raw, tokenString)
+	}))
+	defer server.Close()
+
+	src := &SelfSigned{Method: jwt.SigningMethodHS256, Key: []byte("secret"), Claims: jwt.MapClaims{"iss": "orders"}}
+	transport := &Transport{
+		Source: src,
+		Claims: func(r *http.Request) jwt.MapClaims {
+			if r.Method == "DELETE" {
+				return jwt.MapClaims{"scope": "admin"}
+			}
+			return nil
+		},
+	}
+	client := &http.Client{Transport: transport}
+
+	for _, method := range []string{"GET", "GET", "DELETE"} {
+		req, _ := http.NewRequest(method, server.URL+"/items", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Error sending request: %v", err)
+		}
+		resp.Body.Close()
+		if req.Header.Get("Authorization") != "" {
+			t.Errorf("Transport modified the caller's request")
+		}
+	}
+
+	if len(seen) != 3 {
+		t.Fatalf("Expected 3 requests, got %v", len(seen))
+	}
+	for i, claims := range seen {
+		if claims["aud"] != server.URL || claims["iss"] != "orders" {
+			t.Errorf("[%v] Unexpected claims %v", i, claims)
+		}
+	}
+	if raw[0] != raw[1] {
+		t.Errorf("Expected the token to be reused for the same audience")
+	}
+	if seen[2]["scope"] != "admin" || raw[2] == raw[0] {
+		t.Errorf("Expected a per-request token with scope, got %v", seen[2])
+	}
+}
@@ -3,10 +3,16 @@
 //
 // Example usage:
 // The following will create and sign a token, then verify it and output the original claims.
-//     echo {\"foo\":\"bar\"} | bin/jwt -key test/sample_key -alg RS256 -sign - | bin/jwt -key test/sample_key.pub -verify -
+//
+//	echo {\"foo\":\"bar\"} | bin/jwt -key test/sample_key -alg RS256 -sign - | bin/jwt -key test/sample_key.pub -verify -
+//
+// Tokens from an identity provider can be verified against its published keys:
+//
+//	bin/jwt -jwks https://auth.example.com/.well-known/jwks.json -verify token.txt
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -15,14 +21,17 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/jwks"
 )
 
 var (
 	// Options
 	flagAlg     = flag.String("alg", "", "signing algorithm identifier")
 	flagKey     = flag.String("key", "", "path to key file or '-' to read from stdin")
+	flagJWKS    = flag.String("jwks", "", "URL of a JWK Set to verify against, instead of -key")
 	flagCompact = flag.Bool("compact", false, "output compact JSON")
 	flagDebug   = flag.Bool("debug", false, "print out all kinds of debug data")
 	flagClaims  = make(ArgList)
@@ -35,6 +44,15 @@ var (
 )
 
 func main() {
+	// Subcommands have their own flags
+	if len(os.Args) > 1 && os.Args[1] == "keygen" {
+		if err := keygen(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Plug in Var flags
 	flag.Var(flagClaims, "claim", "add additional claims. may be used more than once")
 	flag.Var(flagHead, "header", "add additional header params. may be used more than once")
@@ -42,7 +60,8 @@ func main() {
 	// Usage message if you ask for -help or if you mess up inputs.
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  One of the following flags is required: sign, verify\n")
+		fmt.Fprintf(os.Stderr, "  One of the following flags is required: sign, verify, show\n")
+		fmt.Fprintf(os.Stderr, "  Run '%s keygen -h' for key generation\n", os.Args[0])
 		flag.PrintDefaults()
 	}
 
@@ -126,19 +145,24 @@ func verifyToken() error {
 		fmt.Fprintf(os.Stderr, "Token len: %v bytes\n", len(tokData))
 	}
 
+	// Only accept the algorithm asked for, if any
+	parser := new(jwt.Parser)
+	if *flagAlg != "" {
+		parser.ValidMethods = []string{*flagAlg}
+	}
+
 	// Parse the token.  Load the key from command line option
-	token, err := jwt.Parse(string(tokData), func(t *jwt.Token) (interface{}, error) {
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
 		data, err := loadData(*flagKey)
 		if err != nil {
 			return nil, err
 		}
-		if isEs() {
-			return jwt.ParseECPublicKeyFromPEM(data)
-		} else if isRs() {
-			return jwt.ParseRSAPublicKeyFromPEM(data)
-		}
-		return data, nil
-	})
+		return parseVerificationKey(t, data)
+	}
+	if *flagJWKS != "" {
+		keyFunc = jwks.NewRemote(*flagJWKS).Keyfunc
+	}
+	token, err := parser.Parse(string(tokData), keyFunc)
 
 	// Print some debug data
 	if *flagDebug && token != nil {
@@ -160,10 +184,38 @@ func verifyToken() error {
 	if err := printJSON(token.Claims); err != nil {
 		return fmt.Errorf("Failed to output claims: %v", err)
 	}
+	if *flagDebug {
+		printTimes(os.Stderr, token.Claims)
+	}
 
 	return nil
 }
 
+// Load a verification key.  PEM keys and JWKs are recognized from their
+// contents; anything else is an HMAC secret.
+func parseVerificationKey(t *jwt.Token, data []byte) (interface{}, error) {
+	data = bytes.TrimSpace(data)
+	switch {
+	case isEs():
+		return jwt.ParseECPublicKeyFromPEM(data)
+	case isRs():
+		return jwt.ParseRSAPublicKeyFromPEM(data)
+	case bytes.HasPrefix(data, []byte("-----BEGIN")):
+		return jwt.ParsePublicKeyFromPEM(data)
+	case bytes.HasPrefix(data, []byte("{")):
+		var set jwt.JSONWebKeySet
+		if err := json.Unmarshal(data, &set); err == nil && len(set.Keys) > 0 {
+			return set.Keyfunc(t)
+		}
+		var key jwt.JSONWebKey
+		if err := json.Unmarshal(data, &key); err != nil {
+			return nil, fmt.Errorf("Couldn't parse JWK: %v", err)
+		}
+		return key.SigningKey()
+	}
+	return data, nil
+}
+
 // Create, sign, and output a token.  This is a great, simple example of
 // how to use this library to create and sign a token.
 func signToken() error {
@@ -211,20 +263,12 @@ func signToken() error {
 		}
 	}
 
-	if isEs() {
-		if k, ok := key.([]byte); !ok {
-			return fmt.Errorf("Couldn't convert key data to key")
-		} else {
-			key, err = jwt.ParseECPrivateKeyFromPEM(k)
-			if err != nil {
-				return err
-			}
-		}
-	} else if isRs() {
+	// PKCS1, PKCS8 and SEC1 keys are all accepted
+	if isEs() || isRs() {
 		if k, ok := key.([]byte); !ok {
 			return fmt.Errorf("Couldn't convert key data to key")
 		} else {
-			key, err = jwt.ParseRSAPrivateKeyFromPEM(k)
+			key, err = jwt.ParsePrivateKeyFromPEM(k)
 			if err != nil {
 				return err
 			}
@@ -254,7 +298,7 @@ func showToken() error {
 		fmt.Fprintf(os.Stderr, "Token len: %v bytes\n", len(tokData))
 	}
 
-	token, err := jwt.Parse(string(tokData), nil)
+	token, err := jwt.ParseUnverified(string(tokData))
 	if token == nil {
 		return fmt.Errorf("malformed token: %v", err)
 	}
@@ -269,10 +313,42 @@ func showToken() error {
 	if err := printJSON(token.Claims); err != nil {
 		return fmt.Errorf("Failed to output claims: %v", err)
 	}
+	printTimes(os.Stdout, token.Claims)
 
 	return nil
 }
 
+// Print the exp, iat and nbf claims as human-readable times
+func printTimes(w io.Writer, claims jwt.Claims) {
+	m, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	printed := false
+	for _, name := range []string{"iat", "nbf", "exp"} {
+		var t time.Time
+		switch v := m[name].(type) {
+		case float64:
+			t = time.Unix(int64(v), 0)
+		case json.Number:
+			n, _ := v.Int64()
+			t = time.Unix(n, 0)
+		default:
+			continue
+		}
+		if !printed {
+			fmt.Fprintln(w, "Times:")
+			printed = true
+		}
+		rel := fmt.Sprintf("in %v", t.Sub(now).Round(time.Second))
+		if t.Before(now) {
+			rel = fmt.Sprintf("%v ago", now.Sub(t).Round(time.Second))
+		}
+		fmt.Fprintf(w, "    %v: %v (%v)\n", name, t.UTC().Format(time.RFC3339), rel)
+	}
+}
+
 func isEs() bool {
 	return strings.HasPrefix(*flagAlg, "ES")
 }
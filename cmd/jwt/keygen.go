@@ -0,0 +1,197 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// A JWK including private key material.  jwt.JSONWebKey deliberately has no
+// private fields, so this is only used for writing key files.
+type privateJWK struct {
+	jwt.JSONWebKey
+	D  string `json:"d,omitempty"`
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	DP string `json:"dp,omitempty"`
+	DQ string `json:"dq,omitempty"`
+	QI string `json:"qi,omitempty"`
+	K  string `json:"k,omitempty"`
+}
+
+// Generate a key and write it in the requested format.  Asymmetric keys are
+// written as a private key and a public key; with -out, to <out> and <out>.pub.
+//
+//	jwt keygen -type ec -curve P-384 -format jwk -kid 2024-01 -out signing
+func keygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	keyType := fs.String("type", "hmac", "key type: hmac, rsa, ec or ed25519")
+	bits := fs.Int("bits", 0, "HMAC secret or RSA modulus size in bits (default 256 for hmac, 2048 for rsa)")
+	curve := fs.String("curve", "P-256", "EC curve: P-256, P-384 or P-521")
+	format := fs.String("format", "pem", "output format: pem or jwk.  HMAC secrets are written raw for pem")
+	kid := fs.String("kid", "", "key id to set on JWKs")
+	out := fs.String("out", "", "file to write the key to, instead of stdout")
+	fs.Parse(args)
+
+	if *format != "pem" && *format != "jwk" {
+		return fmt.Errorf("Unknown format: %v", *format)
+	}
+
+	var key crypto.Signer
+	switch *keyType {
+	case "hmac":
+		if *bits == 0 {
+			*bits = 256
+		}
+		if *bits < 256 || *bits%8 != 0 {
+			return fmt.Errorf("HMAC secrets must be a multiple of 8 bits, and at least 256")
+		}
+		secret := make([]byte, *bits/8)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		if *format == "pem" {
+			return writeKey(*out, secret)
+		}
+		jwk := privateJWK{JSONWebKey: jwt.JSONWebKey{Kty: "oct", Kid: *kid}, K: jwt.EncodeSegment(secret)}
+		data, err := json.MarshalIndent(jwk, "", "    ")
+		if err != nil {
+			return err
+		}
+		return writeKey(*out, append(data, '\n'))
+	case "rsa":
+		if *bits == 0 {
+			*bits = 2048
+		}
+		if *bits < 2048 {
+			return fmt.Errorf("RSA keys must be at least 2048 bits")
+		}
+		k, err := rsa.GenerateKey(rand.Reader, *bits)
+		if err != nil {
+			return err
+		}
+		key = k
+	case "ec":
+		var c elliptic.Curve
+		switch *curve {
+		case "P-256":
+			c = elliptic.P256()
+		case "P-384":
+			c = elliptic.P384()
+		case "P-521":
+			c = elliptic.P521()
+		default:
+			return fmt.Errorf("Unknown curve: %v", *curve)
+		}
+		k, err := ecdsa.GenerateKey(c, rand.Reader)
+		if err != nil {
+			return err
+		}
+		key = k
+	case "ed25519":
+		_, k, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		key = k
+	default:
+		return fmt.Errorf("Unknown key type: %v", *keyType)
+	}
+
+	var private, public []byte
+	var err error
+	if *format == "pem" {
+		private, public, err = encodePEMKeyPair(key)
+	} else {
+		private, public, err = encodeJWKKeyPair(key, *kid)
+	}
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		os.Stdout.Write(private)
+		os.Stdout.Write(public)
+		return nil
+	}
+	if err := writeKey(*out, private); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(*out+".pub", public, 0644)
+}
+
+// Write a secret to path, or stdout if path is empty
+func writeKey(path string, data []byte) error {
+	if path == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// PKCS8 private key and PKIX public key
+func encodePEMKeyPair(key crypto.Signer) ([]byte, []byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), nil
+}
+
+func encodeJWKKeyPair(key crypto.Signer, kid string) ([]byte, []byte, error) {
+	var public *jwt.JSONWebKey
+	var private privateJWK
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		// jwt.NewJSONWebKey has no OKP support, as there is no EdDSA
+		// signing method to verify with
+		public = &jwt.JSONWebKey{Kty: "OKP", Crv: "Ed25519", X: jwt.EncodeSegment(k.Public().(ed25519.PublicKey))}
+		private.D = jwt.EncodeSegment(k.Seed())
+	default:
+		var err error
+		if public, err = jwt.NewJSONWebKey(key); err != nil {
+			return nil, nil, err
+		}
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			private.D = jwt.EncodeSegment(k.D.Bytes())
+			private.P = jwt.EncodeSegment(k.Primes[0].Bytes())
+			private.Q = jwt.EncodeSegment(k.Primes[1].Bytes())
+			private.DP = jwt.EncodeSegment(k.Precomputed.Dp.Bytes())
+			private.DQ = jwt.EncodeSegment(k.Precomputed.Dq.Bytes())
+			private.QI = jwt.EncodeSegment(k.Precomputed.Qinv.Bytes())
+		case *ecdsa.PrivateKey:
+			d := k.D.Bytes()
+			size := (k.Curve.Params().BitSize + 7) / 8
+			private.D = jwt.EncodeSegment(append(make([]byte, size-len(d)), d...))
+		}
+	}
+	public.Kid = kid
+	private.JSONWebKey = *public
+
+	privateData, err := json.MarshalIndent(private, "", "    ")
+	if err != nil {
+		return nil, nil, err
+	}
+	publicData, err := json.MarshalIndent(public, "", "    ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(privateData, '\n'), append(publicData, '\n'), nil
+}
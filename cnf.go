@@ -0,0 +1,63 @@
+package jwt
+
+import (
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+)
+
+// Certificate binding errors
+var (
+	ErrMissingClientCertificate = errors.New("connection has no client certificate")
+	ErrCertificateBinding       = errors.New("token is not bound to the client certificate")
+)
+
+// The cnf (confirmation) claim from RFC 7800, binding a token to a key its
+// presenter must prove possession of
+type Confirmation struct {
+	JWKThumbprint  string `json:"jkt,omitempty"`      // RFC 9449 DPoP key thumbprint
+	X509Thumbprint string `json:"x5t#S256,omitempty"` // RFC 8705 client certificate thumbprint
+}
+
+// The cnf claim binding a token to a client certificate, for authorization
+// servers issuing certificate-bound tokens (RFC 8705)
+func CertificateConfirmation(cert *x509.Certificate) *Confirmation {
+	return &Confirmation{X509Thumbprint: CertificateThumbprint(cert)}
+}
+
+// Returns the token's cnf claim, or nil if it has none
+func (t *Token) Confirmation() (*Confirmation, error) {
+	claims, err := claimsToMap(t.Claims)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := claims["cnf"]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	cnf := &Confirmation{}
+	if err := json.Unmarshal(data, cnf); err != nil {
+		return nil, NewValidationError("cnf claim must be an object", ValidationErrorClaimsInvalid)
+	}
+	return cnf, nil
+}
+
+// Check the token's cnf x5t#S256 thumbprint matches cert
+func (t *Token) VerifyCertificateBinding(cert *x509.Certificate) error {
+	cnf, err := t.Confirmation()
+	if err != nil {
+		return err
+	}
+	if cnf == nil || cnf.X509Thumbprint == "" {
+		return ErrCertificateBinding
+	}
+	if subtle.ConstantTimeCompare([]byte(cnf.X509Thumbprint), []byte(CertificateThumbprint(cert))) != 1 {
+		return ErrCertificateBinding
+	}
+	return nil
+}
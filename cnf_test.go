@@ -0,0 +1,36 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestTokenConfirmation(t *testing.T) {
+	var confirmationTestData = []struct {
+		name   string
+		claims jwt.Claims
+		want   *jwt.Confirmation
+		valid  bool
+	}{
+		{"map", jwt.MapClaims{"cnf": map[string]interface{}{"jkt": "abc"}}, &jwt.Confirmation{JWKThumbprint: "abc"}, true},
+		{"struct", struct {
+			jwt.StandardClaims
+			Cnf jwt.Confirmation `json:"cnf"`
+		}{Cnf: jwt.Confirmation{JWKThumbprint: "abc"}}, &jwt.Confirmation{JWKThumbprint: "abc"}, true},
+		{"missing", jwt.MapClaims{"sub": "alice"}, nil, true},
+		{"not an object", jwt.MapClaims{"cnf": "abc"}, nil, false},
+	}
+
+	for _, data := range confirmationTestData {
+		token := &jwt.Token{Claims: data.claims}
+		cnf, err := token.Confirmation()
+		if (err == nil) != data.valid {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+			continue
+		}
+		if (cnf == nil) != (data.want == nil) || (cnf != nil && *cnf != *data.want) {
+			t.Errorf("[%v] Expected %+v, got %+v", data.name, data.want, cnf)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !tinygo
+
+package jwt
+
+import (
+	"crypto/tls"
+)
+
+// Check the token is bound to the client certificate of a mutual TLS
+// connection, such as http.Request.TLS
+func VerifyTLSBinding(token *Token, state *tls.ConnectionState) error {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return ErrMissingClientCertificate
+	}
+	return token.VerifyCertificateBinding(state.PeerCertificates[0])
+}
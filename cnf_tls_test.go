@@ -0,0 +1,37 @@
+//go:build !tinygo
+
+package jwt_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestVerifyTLSBinding(t *testing.T) {
+	root, rootKey := makeTestCertificate(t, "root", nil, nil)
+	client, _ := makeTestCertificate(t, "client", root, rootKey)
+	other, _ := makeTestCertificate(t, "other", root, rootKey)
+
+	token := &jwt.Token{Claims: jwt.MapClaims{"sub": "alice", "cnf": jwt.CertificateConfirmation(client)}}
+	var bindingTestData = []struct {
+		name  string
+		token *jwt.Token
+		state *tls.ConnectionState
+		err   error
+	}{
+		{"bound", token, &tls.ConnectionState{PeerCertificates: []*x509.Certificate{client, root}}, nil},
+		{"other certificate", token, &tls.ConnectionState{PeerCertificates: []*x509.Certificate{other}}, jwt.ErrCertificateBinding},
+		{"no client certificate", token, &tls.ConnectionState{}, jwt.ErrMissingClientCertificate},
+		{"plain http", token, nil, jwt.ErrMissingClientCertificate},
+		{"unbound token", &jwt.Token{Claims: jwt.MapClaims{"sub": "alice"}}, &tls.ConnectionState{PeerCertificates: []*x509.Certificate{client}}, jwt.ErrCertificateBinding},
+	}
+
+	for _, data := range bindingTestData {
+		if err := jwt.VerifyTLSBinding(data.token, data.state); err != data.err {
+			t.Errorf("[%v] Expected error %v, got %v", data.name, data.err, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package conformance
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func loadECKeys(t *testing.T, bits string) (*ecdsa.PrivateKey, *ecdsa.PublicKey) {
+	privateData, err := ioutil.ReadFile("../test/ec" + bits + "-private.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	private, err := jwt.ParseECPrivateKeyFromPEM(privateData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return private, &private.PublicKey
+}
+
+func TestSuites(t *testing.T) {
+	hmacKey, err := ioutil.ReadFile("../test/hmacTestKey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey := test.LoadRSAPrivateKeyFromDisk("../test/sample_key")
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherECKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	suites := []*Suite{
+		{Method: jwt.SigningMethodHS256, SignKey: hmacKey, VerifyKey: hmacKey, WrongKey: []byte("wrong")},
+		{Method: jwt.SigningMethodHS384, SignKey: hmacKey, VerifyKey: hmacKey, WrongKey: []byte("wrong")},
+		{Method: jwt.SigningMethodHS512, SignKey: hmacKey, VerifyKey: hmacKey, WrongKey: []byte("wrong")},
+	}
+	for _, method := range []jwt.SigningMethod{jwt.SigningMethodRS256, jwt.SigningMethodRS384, jwt.SigningMethodRS512,
+		jwt.SigningMethodPS256, jwt.SigningMethodPS384, jwt.SigningMethodPS512} {
+		suites = append(suites, &Suite{Method: method, SignKey: rsaKey, VerifyKey: &rsaKey.PublicKey, WrongKey: &otherRSAKey.PublicKey})
+	}
+	for bits, method := range map[string]jwt.SigningMethod{"256": jwt.SigningMethodES256, "384": jwt.SigningMethodES384, "512": jwt.SigningMethodES512} {
+		private, public := loadECKeys(t, bits)
+		suites = append(suites, &Suite{Method: method, SignKey: private, VerifyKey: public, WrongKey: &otherECKey.PublicKey})
+	}
+
+	for _, suite := range suites {
+		t.Run(suite.Method.Alg(), suite.Run)
+	}
+}
+
+func TestVectors(t *testing.T) {
+	for _, v := range Vectors {
+		method := jwt.GetSigningMethod(v.Alg)
+		if method == nil {
+			t.Errorf("[%v] No signing method for %s", v.Name, v.Alg)
+			continue
+		}
+		signingString, signature := v.Parts()
+		if err := method.Verify(signingString, signature, v.Key); err != nil {
+			t.Errorf("[%v] Verification failed: %v", v.Name, err)
+		}
+	}
+	if n := len(VectorsFor("HS256")); n != 3 {
+		t.Errorf("Expected 3 HS256 vectors, got %d", n)
+	}
+}
+
+// Accepts any signature, and so fails nearly every check
+type acceptAll struct{ jwt.SigningMethod }
+
+func (m acceptAll) Verify(signingString, signature string, key interface{}) error {
+	return nil
+}
+
+func TestCheckReportsFailures(t *testing.T) {
+	hmacKey := []byte("secret")
+	errs := NewSuite(acceptAll{jwt.SigningMethodHS256}, hmacKey, hmacKey).Check()
+	var failed []string
+	for _, err := range errs {
+		failed = append(failed, err.Error())
+	}
+	for _, want := range []string{"[tampered signature]", "[empty signature]", "[tampered payload]", "[invalid key type]"} {
+		if !strings.Contains(strings.Join(failed, "\n"), want) {
+			t.Errorf("Expected a %s failure in %v", want, failed)
+		}
+	}
+
+	if errs := NewSuite(jwt.SigningMethodHS256, hmacKey, hmacKey).Check(); len(errs) != 0 {
+		t.Errorf("Unexpected failures: %v", errs)
+	}
+	if _, err := NewSuiteForAlg("XX999", nil, nil); err == nil {
+		t.Errorf("Expected an error for an unregistered alg")
+	}
+}
+
+// A method that panics on a bad key is reported rather than crashing the run
+type panicky struct{ jwt.SigningMethod }
+
+func (m panicky) Verify(signingString, signature string, key interface{}) error {
+	return m.SigningMethod.Verify(signingString, signature, key.([]byte))
+}
+
+func TestCheckRecoversPanics(t *testing.T) {
+	hmacKey := []byte("secret")
+	errs := NewSuite(panicky{jwt.SigningMethodHS256}, hmacKey, hmacKey).Check()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "[invalid key type] panic") {
+		t.Errorf("Unexpected failures: %v", errs)
+	}
+}
@@ -0,0 +1,18 @@
+// Conformance checks for signing methods, so a new algorithm can show that it
+// interoperates with published test vectors and resists the standard attacks
+// on JWS verification.
+//
+// Vectors holds the examples from RFC 7515, RFC 7519 and RFC 7520 that can be
+// checked with the algorithms in this module.  A Suite signs and verifies with
+// a method, checks it against the vectors for its alg, and makes sure that
+// tampered signatures, tampered signing input, wrong keys, alg=none and
+// algorithm substitution are all rejected:
+//
+//	func TestMyMethod(t *testing.T) {
+//		suite := conformance.NewSuite(myMethod, privateKey, publicKey)
+//		suite.WrongKey = otherPublicKey
+//		suite.Run(t)
+//	}
+//
+// Check runs the same checks outside of a test and returns the failures.
+package conformance
@@ -0,0 +1,308 @@
+package conformance
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Checks a signing method with a key pair: SignKey signs and VerifyKey
+// verifies.  For HMAC methods both are the same secret.
+type Suite struct {
+	Method    jwt.SigningMethod
+	SignKey   interface{}
+	VerifyKey interface{}
+	WrongKey  interface{} // Another verification key, which must reject the method's signatures.  Optional
+}
+
+// Create a Suite for method.  Set WrongKey to also check that signatures do
+// not verify with another key.
+func NewSuite(method jwt.SigningMethod, signKey, verifyKey interface{}) *Suite {
+	return &Suite{Method: method, SignKey: signKey, VerifyKey: verifyKey}
+}
+
+// Create a Suite for the method registered for alg
+func NewSuiteForAlg(alg string, signKey, verifyKey interface{}) (*Suite, error) {
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return nil, fmt.Errorf("conformance: no signing method registered for %q", alg)
+	}
+	return NewSuite(method, signKey, verifyKey), nil
+}
+
+type check struct {
+	name string
+	run  func(s *Suite) error
+}
+
+var checks = []check{
+	{"sign and verify", checkSignVerify},
+	{"parse", checkParse},
+	{"vectors", checkVectors},
+	{"tampered signature", checkTamperedSignature},
+	{"truncated signature", checkTruncatedSignature},
+	{"empty signature", checkEmptySignature},
+	{"tampered payload", checkTamperedPayload},
+	{"tampered header", checkTamperedHeader},
+	{"wrong key", checkWrongKey},
+	{"invalid key type", checkInvalidKeyType},
+	{"alg none", checkAlgNone},
+	{"alg substitution", checkAlgSubstitution},
+}
+
+// Run every check, returning an error for each that fails
+func (s *Suite) Check() []error {
+	var errs []error
+	for _, c := range checks {
+		if err := s.run(c); err != nil {
+			errs = append(errs, fmt.Errorf("[%s] %v", c.name, err))
+		}
+	}
+	return errs
+}
+
+// Run every check as a subtest of t
+func (s *Suite) Run(t *testing.T) {
+	for _, c := range checks {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if err := s.run(c); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Run c, reporting a panic in the method as a failure
+func (s *Suite) run(c check) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return c.run(s)
+}
+
+const (
+	testPayload     = `{"sub":"conformance","admin":false}`
+	tamperedPayload = `{"sub":"conformance","admin":true}`
+)
+
+func (s *Suite) header(alg string) string {
+	return jwt.EncodeSegment([]byte(`{"alg":"` + alg + `","typ":"JWT"}`))
+}
+
+// Sign a fixed payload, returning the signing string and signature
+func (s *Suite) sign() (string, string, error) {
+	signingString := s.header(s.Method.Alg()) + "." + jwt.EncodeSegment([]byte(testPayload))
+	signature, err := s.Method.Sign(signingString, s.SignKey)
+	if err != nil {
+		return "", "", fmt.Errorf("signing failed: %v", err)
+	}
+	return signingString, signature, nil
+}
+
+// Fails unless verifying signature over signingString with key fails
+func (s *Suite) reject(signingString, signature string, key interface{}) error {
+	if err := s.Method.Verify(signingString, signature, key); err == nil {
+		return errors.New("signature was accepted")
+	}
+	return nil
+}
+
+func checkSignVerify(s *Suite) error {
+	signingString, signature, err := s.sign()
+	if err != nil {
+		return err
+	}
+	if _, err := jwt.DecodeSegment(signature); err != nil {
+		return fmt.Errorf("signature is not base64url encoded: %v", err)
+	}
+	if strings.ContainsAny(signature, "=+/") {
+		return errors.New("signature is padded or uses the standard base64 alphabet")
+	}
+	if err := s.Method.Verify(signingString, signature, s.VerifyKey); err != nil {
+		return fmt.Errorf("verification failed: %v", err)
+	}
+	return nil
+}
+
+func checkParse(s *Suite) error {
+	tokenString, err := jwt.NewWithClaims(s.Method, jwt.MapClaims{"sub": "conformance"}).SignedString(s.SignKey)
+	if err != nil {
+		return fmt.Errorf("signing failed: %v", err)
+	}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{s.Method.Alg()}))
+	token, err := parser.Parse(tokenString, func(*jwt.Token) (interface{}, error) {
+		return s.VerifyKey, nil
+	})
+	if err != nil {
+		return fmt.Errorf("parse failed: %v", err)
+	}
+	if !token.Valid || token.Method.Alg() != s.Method.Alg() {
+		return fmt.Errorf("unexpected token: valid=%t alg=%s", token.Valid, token.Method.Alg())
+	}
+	return nil
+}
+
+func checkVectors(s *Suite) error {
+	for _, v := range VectorsFor(s.Method.Alg()) {
+		signingString, signature := v.Parts()
+		if err := s.Method.Verify(signingString, signature, v.Key); err != nil {
+			return fmt.Errorf("%s: verification failed: %v", v.Name, err)
+		}
+		if err := s.reject(signingString+"x", signature, v.Key); err != nil {
+			return fmt.Errorf("%s: tampered: %v", v.Name, err)
+		}
+	}
+	return nil
+}
+
+func checkTamperedSignature(s *Suite) error {
+	signingString, signature, err := s.sign()
+	if err != nil {
+		return err
+	}
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil || len(sig) == 0 {
+		return fmt.Errorf("unexpected signature %q", signature)
+	}
+	for _, i := range []int{0, len(sig) / 2, len(sig) - 1} {
+		tampered := append([]byte(nil), sig...)
+		tampered[i] ^= 0x01
+		if err := s.reject(signingString, jwt.EncodeSegment(tampered), s.VerifyKey); err != nil {
+			return fmt.Errorf("byte %d flipped: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func checkTruncatedSignature(s *Suite) error {
+	signingString, signature, err := s.sign()
+	if err != nil {
+		return err
+	}
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil || len(sig) == 0 {
+		return fmt.Errorf("unexpected signature %q", signature)
+	}
+	if err := s.reject(signingString, jwt.EncodeSegment(sig[:len(sig)-1]), s.VerifyKey); err != nil {
+		return err
+	}
+	return s.reject(signingString, jwt.EncodeSegment(append(sig, 0)), s.VerifyKey)
+}
+
+func checkEmptySignature(s *Suite) error {
+	signingString, _, err := s.sign()
+	if err != nil {
+		return err
+	}
+	return s.reject(signingString, "", s.VerifyKey)
+}
+
+func checkTamperedPayload(s *Suite) error {
+	_, signature, err := s.sign()
+	if err != nil {
+		return err
+	}
+	signingString := s.header(s.Method.Alg()) + "." + jwt.EncodeSegment([]byte(tamperedPayload))
+	return s.reject(signingString, signature, s.VerifyKey)
+}
+
+func checkTamperedHeader(s *Suite) error {
+	_, signature, err := s.sign()
+	if err != nil {
+		return err
+	}
+	header := jwt.EncodeSegment([]byte(`{"alg":"` + s.Method.Alg() + `","typ":"JWT","kid":"attacker"}`))
+	return s.reject(header+"."+jwt.EncodeSegment([]byte(testPayload)), signature, s.VerifyKey)
+}
+
+func checkWrongKey(s *Suite) error {
+	if s.WrongKey == nil {
+		return nil
+	}
+	signingString, signature, err := s.sign()
+	if err != nil {
+		return err
+	}
+	return s.reject(signingString, signature, s.WrongKey)
+}
+
+func checkInvalidKeyType(s *Suite) error {
+	signingString, signature, err := s.sign()
+	if err != nil {
+		return err
+	}
+	for _, key := range []interface{}{nil, "not a key", struct{}{}} {
+		if err := s.reject(signingString, signature, key); err != nil {
+			return fmt.Errorf("key %#v: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// A token with alg=none must not verify with a key meant for the method
+func checkAlgNone(s *Suite) error {
+	for _, alg := range []string{"none", "None", "NONE"} {
+		tokenString := s.header(alg) + "." + jwt.EncodeSegment([]byte(tamperedPayload)) + "."
+		if err := s.rejectToken(tokenString); err != nil {
+			return fmt.Errorf("alg %s: %v", alg, err)
+		}
+	}
+	return nil
+}
+
+// A token signed with HMAC, using the public verification key as the secret,
+// must not verify with that key
+func checkAlgSubstitution(s *Suite) error {
+	secrets := publicKeySecrets(s.VerifyKey)
+	for _, alg := range []string{"HS256", "HS384", "HS512"} {
+		if alg == s.Method.Alg() {
+			continue
+		}
+		method := jwt.GetSigningMethod(alg)
+		for _, secret := range secrets {
+			signingString := s.header(alg) + "." + jwt.EncodeSegment([]byte(tamperedPayload))
+			signature, err := method.Sign(signingString, secret)
+			if err != nil {
+				return err
+			}
+			if err := s.rejectToken(signingString + "." + signature); err != nil {
+				return fmt.Errorf("alg %s: %v", alg, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Fails unless parsing tokenString fails with a Keyfunc returning VerifyKey
+// whatever the token's alg, as a careless application's would
+func (s *Suite) rejectToken(tokenString string) error {
+	token, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) {
+		return s.VerifyKey, nil
+	})
+	if err == nil || (token != nil && token.Valid) {
+		return errors.New("token was accepted")
+	}
+	return nil
+}
+
+// The encodings of a public key an attacker might use as an HMAC secret.
+// There are none for a secret key, which is never published.
+func publicKeySecrets(key interface{}) [][]byte {
+	if signer, ok := key.(crypto.Signer); ok {
+		key = signer.Public()
+	}
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil
+	}
+	return [][]byte{der, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})}
+}
@@ -0,0 +1,98 @@
+package conformance
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// A published example JWS and the key that verifies it
+type Vector struct {
+	Name  string // The RFC section the example comes from
+	Alg   string
+	Token string
+	Key   interface{} // Verifies Token with the method registered for Alg
+}
+
+// Split the vector's token into its signing string and signature
+func (v *Vector) Parts() (signingString, signature string) {
+	i := strings.LastIndex(v.Token, ".")
+	if i < 0 {
+		return v.Token, ""
+	}
+	return v.Token[:i], v.Token[i+1:]
+}
+
+// The published examples.  Examples with randomized signatures, such as those
+// for ECDSA, can only be verified, never reproduced by signing.
+var Vectors = []Vector{
+	{
+		Name:  "RFC 7515 A.1",
+		Alg:   "HS256",
+		Token: "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9." + rfc7515Payload + ".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk",
+		Key:   octKey("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"),
+	},
+	{
+		Name:  "RFC 7515 A.3",
+		Alg:   "ES256",
+		Token: "eyJhbGciOiJFUzI1NiJ9." + rfc7515Payload + ".DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q",
+		Key:   publicJWK(`{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}`),
+	},
+	{
+		Name:  "RFC 7515 A.5",
+		Alg:   "none",
+		Token: "eyJhbGciOiJub25lIn0." + rfc7515Payload + ".",
+		Key:   jwt.UnsafeAllowNoneSignatureType,
+	},
+	{
+		// The example JWT of RFC 7519 section 3.1 is that of RFC 7515 A.1
+		Name:  "RFC 7519 3.1",
+		Alg:   "HS256",
+		Token: "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9." + rfc7515Payload + ".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk",
+		Key:   octKey("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"),
+	},
+	{
+		Name:  "RFC 7520 4.4",
+		Alg:   "HS256",
+		Token: "eyJhbGciOiJIUzI1NiIsImtpZCI6IjAxOGMwYWU1LTRkOWItNDcxYi1iZmQ2LWVlZjMxNGJjNzAzNyJ9." + rfc7520Payload + ".s0h6KThzkfBBBkLspW1h84VsJZFTsPPqMDA7g1Md7p0",
+		Key:   octKey("hJtXIZ2uSN5kbQfbtTNWbpdmhkV8FJG-Onbc6mxCcYg"),
+	},
+}
+
+// {"iss":"joe",\r\n "exp":1300819380,\r\n "http://example.com/is_root":true}
+const rfc7515Payload = "eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ"
+
+// The Fellowship of the Ring quotation used throughout RFC 7520
+const rfc7520Payload = "SXTigJlzIGEgZGFuZ2Vyb3VzIGJ1c2luZXNzLCBGcm9kbywgZ29pbmcgb3V0IHlvdXIgZG9vci4gWW91IHN0ZXAgb250byB0aGUgcm9hZCwgYW5kIGlmIHlvdSBkb24ndCBrZWVwIHlvdXIgZmVldCwgdGhlcmXigJlzIG5vIGtub3dpbmcgd2hlcmUgeW91IG1pZ2h0IGJlIHN3ZXB0IG9mZiB0by4"
+
+// Returns the vectors for alg
+func VectorsFor(alg string) []Vector {
+	var vectors []Vector
+	for _, v := range Vectors {
+		if v.Alg == alg {
+			vectors = append(vectors, v)
+		}
+	}
+	return vectors
+}
+
+func octKey(k string) []byte {
+	key, err := jwt.DecodeSegment(k)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func publicJWK(data string) interface{} {
+	var jwk jwt.JSONWebKey
+	if err := json.Unmarshal([]byte(data), &jwk); err != nil {
+		panic(err)
+	}
+	key, err := jwk.PublicKey()
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"context"
+	"strings"
+)
+
+// Implement SigningMethodWithContext for signing methods backed by remote
+// signers (KMS, Vault, HSM services) so they can honor deadlines and
+// cancellation.  SignedStringWithContext and ParseWithContext use these
+// methods when available, and Sign / Verify otherwise.
+type SigningMethodWithContext interface {
+	SigningMethod
+	SignContext(ctx context.Context, signingString string, key interface{}) (string, error)
+	VerifyContext(ctx context.Context, signingString, signature string, key interface{}) error
+}
+
+// A Keyfunc that also receives the context passed to ParseWithContext, so key
+// lookups that hit the network or a database (JWKS fetches, tenant stores)
+// can honor deadlines and cancellation.
+type KeyfuncContext func(context.Context, *Token) (interface{}, error)
+
+// Adapt a Keyfunc to a KeyfuncContext that ignores the context
+func keyfuncWithContext(keyFunc Keyfunc) KeyfuncContext {
+	if keyFunc == nil {
+		return nil
+	}
+	return func(_ context.Context, token *Token) (interface{}, error) {
+		return keyFunc(token)
+	}
+}
+
+// Get the complete, signed token, passing ctx to the signing method
+func (t *Token) SignedStringWithContext(ctx context.Context, key interface{}) (string, error) {
+	var sig, sstr string
+	var err error
+	if key, err = t.applySigningKey(key); err != nil {
+		return "", err
+	}
+	if sstr, err = t.SigningString(); err != nil {
+		return "", err
+	}
+	if err = t.checkSize(sstr, key); err != nil {
+		return "", err
+	}
+	if sig, err = signWithContext(ctx, t.Method, sstr, key); err != nil {
+		return "", err
+	}
+	return strings.Join([]string{sstr, sig}, "."), nil
+}
+
+// Parse, validate, and return a token, passing ctx to keyFunc and the signing method.
+// See Parse.
+func ParseWithContext(ctx context.Context, tokenString string, keyFunc KeyfuncContext) (*Token, error) {
+	return new(Parser).ParseWithClaimsContext(ctx, tokenString, MapClaims{}, keyFunc)
+}
+
+// ParseWithClaims, passing ctx to keyFunc and the signing method.  See ParseWithClaims.
+func ParseWithClaimsContext(ctx context.Context, tokenString string, claims Claims, keyFunc KeyfuncContext) (*Token, error) {
+	return new(Parser).ParseWithClaimsContext(ctx, tokenString, claims, keyFunc)
+}
+
+// Parse, validate, and return a token, passing ctx to keyFunc and the signing method
+func (p *Parser) ParseWithContext(ctx context.Context, tokenString string, keyFunc KeyfuncContext) (*Token, error) {
+	return p.ParseWithClaimsContext(ctx, tokenString, MapClaims{}, keyFunc)
+}
+
+// See VerifySignature.  Passes ctx to methods implementing SigningMethodWithContext.
+func VerifySignatureContext(ctx context.Context, method SigningMethod, signingString, signature string, key interface{}) error {
+	return verifySignatureContext(ctx, method, signingString, signature, key, true)
+}
+
+// VerifySignatureContext, rejecting non-canonical signatures only if strict
+func verifySignatureContext(ctx context.Context, method SigningMethod, signingString, signature string, key interface{}, strict bool) error {
+	if err := checkSignature(method, signature, strict); err != nil {
+		return err
+	}
+	key, err := unwrapSigningKey(method, key)
+	if err != nil {
+		return err
+	}
+
+	if m, ok := method.(SigningMethodWithContext); ok {
+		return m.VerifyContext(ctx, signingString, signature, key)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return method.Verify(signingString, signature, key)
+}
+
+func signWithContext(ctx context.Context, method SigningMethod, signingString string, key interface{}) (string, error) {
+	if m, ok := method.(SigningMethodWithContext); ok {
+		return m.SignContext(ctx, signingString, key)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return method.Sign(signingString, key)
+}
@@ -0,0 +1,85 @@
+package jwt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type ctxKey struct{}
+
+// HMAC signing method that requires a value on the context, standing in
+// for a remote signer
+type contextSigningMethod struct {
+	*jwt.SigningMethodHMAC
+}
+
+func (m contextSigningMethod) SignContext(ctx context.Context, signingString string, key interface{}) (string, error) {
+	if ctx.Value(ctxKey{}) == nil {
+		return "", context.Canceled
+	}
+	return m.Sign(signingString, key)
+}
+
+func (m contextSigningMethod) VerifyContext(ctx context.Context, signingString, signature string, key interface{}) error {
+	if ctx.Value(ctxKey{}) == nil {
+		return context.Canceled
+	}
+	return m.Verify(signingString, signature, key)
+}
+
+func TestSigningMethodWithContext(t *testing.T) {
+	method := contextSigningMethod{&jwt.SigningMethodHMAC{Name: "HS256-ctx", Hash: jwt.SigningMethodHS256.Hash}}
+	jwt.RegisterSigningMethod(method.Alg(), func() jwt.SigningMethod { return method })
+	defer jwt.UnregisterSigningMethod(method.Alg())
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, true)
+	keyFunc := func(context.Context, *jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	if _, err := jwt.New(method).SignedStringWithContext(context.Background(), hmacTestKey); err == nil {
+		t.Errorf("Expected SignContext to be used")
+	}
+	tokenString, err := jwt.New(method).SignedStringWithContext(ctx, hmacTestKey)
+	if err != nil {
+		t.Fatalf("Error signing with context: %v", err)
+	}
+
+	if token, err := jwt.ParseWithContext(ctx, tokenString, keyFunc); err != nil || !token.Valid {
+		t.Errorf("Error parsing with context: %v", err)
+	}
+	if _, err := jwt.ParseWithContext(context.Background(), tokenString, keyFunc); err == nil {
+		t.Errorf("Expected VerifyContext to be used")
+	}
+}
+
+func TestParseWithCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := jwt.New(jwt.SigningMethodHS256).SignedStringWithContext(ctx, hmacTestKey); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	tokenString, _ := jwt.New(jwt.SigningMethodHS256).SignedString(hmacTestKey)
+	_, err := jwt.ParseWithContext(ctx, tokenString, func(context.Context, *jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Inner != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestKeyfuncContext(t *testing.T) {
+	tokenString, _ := jwt.New(jwt.SigningMethodHS256).SignedString(hmacTestKey)
+	ctx := context.WithValue(context.Background(), ctxKey{}, hmacTestKey)
+
+	token, err := jwt.ParseWithContext(ctx, tokenString, func(ctx context.Context, token *jwt.Token) (interface{}, error) {
+		return ctx.Value(ctxKey{}), nil
+	})
+	if err != nil || !token.Valid {
+		t.Errorf("Error parsing with key from context: %v", err)
+	}
+
+	if _, err := jwt.ParseWithContext(ctx, tokenString, nil); err == nil {
+		t.Errorf("Token parsed without a keyfunc")
+	}
+}
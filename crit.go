@@ -0,0 +1,103 @@
+package jwt
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Critical header extensions this package knows how to process.  A token listing
+// any other extension in its "crit" header is rejected, as required by RFC 7515.
+var criticalHeaderExtensions = map[string]bool{
+	"b64": true, // RFC 7797 unencoded payload
+}
+var criticalHeaderLock = new(sync.RWMutex)
+
+// Header parameters defined by RFC 7515.  These must not appear in "crit".
+var registeredHeaderParameters = map[string]bool{
+	"alg": true, "jku": true, "jwk": true, "kid": true, "x5u": true, "x5c": true,
+	"x5t": true, "x5t#S256": true, "typ": true, "cty": true, "crit": true,
+}
+
+// Declare that the application understands and processes the named header
+// extension, allowing tokens that list it in their "crit" header to be parsed.
+// Processing the extension is up to the application, typically in the Keyfunc.
+// This is typically done during init()
+func RegisterCriticalHeader(name string) {
+	criticalHeaderLock.Lock()
+	defer criticalHeaderLock.Unlock()
+
+	criticalHeaderExtensions[name] = true
+}
+
+// Reports whether the named header extension has been registered as understood
+func IsCriticalHeaderSupported(name string) bool {
+	criticalHeaderLock.RLock()
+	defer criticalHeaderLock.RUnlock()
+
+	return criticalHeaderExtensions[name]
+}
+
+// Validate the "crit" header of a parsed token per RFC 7515 section 4.1.11.
+// The value must be a non-empty list of extension names, each of which is
+// present in the header and understood by this package or the application.
+func validateCriticalHeaders(header map[string]interface{}) error {
+	v, ok := header["crit"]
+	if !ok {
+		return nil
+	}
+
+	crit, ok := v.([]interface{})
+	if !ok || len(crit) == 0 {
+		return NewValidationError("crit header must be a non-empty array", ValidationErrorMalformed)
+	}
+
+	seen := make(map[string]bool, len(crit))
+	for _, c := range crit {
+		name, ok := c.(string)
+		if !ok || name == "" {
+			return NewValidationError("crit header must only contain header names", ValidationErrorMalformed)
+		}
+		if seen[name] {
+			return NewValidationError(fmt.Sprintf("crit header lists %v more than once", name), ValidationErrorMalformed)
+		}
+		seen[name] = true
+
+		if registeredHeaderParameters[name] {
+			return NewValidationError(fmt.Sprintf("crit header must not list registered header %v", name), ValidationErrorMalformed)
+		}
+		if _, ok := header[name]; !ok {
+			return NewValidationError(fmt.Sprintf("critical header %v is missing", name), ValidationErrorMalformed)
+		}
+		if !IsCriticalHeaderSupported(name) {
+			return NewValidationError(fmt.Sprintf("critical header %v is not supported", name), ValidationErrorUnverifiable)
+		}
+	}
+
+	return nil
+}
+
+// Returns the "crit" header as a list of strings, ignoring values of other types
+func criticalHeaders(header map[string]interface{}) []string {
+	switch crit := header["crit"].(type) {
+	case []string:
+		return crit
+	case []interface{}:
+		names := make([]string, 0, len(crit))
+		for _, c := range crit {
+			if name, ok := c.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+func headerIsCritical(header map[string]interface{}, name string) bool {
+	for _, c := range criticalHeaders(header) {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package jwt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var critTestData = []struct {
+	name   string
+	header string
+	valid  bool
+	errors uint32
+}{
+	{"no crit", `{"alg":"HS256"}`, true, 0},
+	{"supported extension", `{"alg":"HS256","crit":["exp-test"],"exp-test":1}`, true, 0},
+	{"unsupported extension", `{"alg":"HS256","crit":["unknown"],"unknown":1}`, false, jwt.ValidationErrorUnverifiable},
+	{"missing extension", `{"alg":"HS256","crit":["exp-test"]}`, false, jwt.ValidationErrorMalformed},
+	{"registered header", `{"alg":"HS256","crit":["alg"]}`, false, jwt.ValidationErrorMalformed},
+	{"empty crit", `{"alg":"HS256","crit":[]}`, false, jwt.ValidationErrorMalformed},
+	{"non-array crit", `{"alg":"HS256","crit":"exp-test","exp-test":1}`, false, jwt.ValidationErrorMalformed},
+	{"duplicate entries", `{"alg":"HS256","crit":["exp-test","exp-test"],"exp-test":1}`, false, jwt.ValidationErrorMalformed},
+}
+
+func init() {
+	jwt.RegisterCriticalHeader("exp-test")
+}
+
+func TestCriticalHeaders(t *testing.T) {
+	for _, data := range critTestData {
+		signingString := jwt.EncodeSegment([]byte(data.header)) + "." + jwt.EncodeSegment([]byte(`{"foo":"bar"}`))
+		sig, _ := jwt.SigningMethodHS256.Sign(signingString, hmacTestKey)
+		tokenString := strings.Join([]string{signingString, sig}, ".")
+
+		token, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+		if data.valid && (err != nil || !token.Valid) {
+			t.Errorf("[%v] Error while verifying token: %v", data.name, err)
+		}
+		if !data.valid {
+			if err == nil {
+				t.Errorf("[%v] Invalid token passed validation", data.name)
+			} else if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors != data.errors {
+				t.Errorf("[%v] Errors don't match expectation: %v", data.name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package jwt_test
+
+import (
+	"crypto"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+// Hides the concrete key type, as an HSM or KMS client would
+type opaqueSigner struct {
+	signer crypto.Signer
+}
+
+func (s opaqueSigner) Public() crypto.PublicKey {
+	return s.signer.Public()
+}
+
+func (s opaqueSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.signer.Sign(rand, digest, opts)
+}
+
+func TestCryptoSigner(t *testing.T) {
+	rsaKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	ecData, _ := ioutil.ReadFile("test/ec256-private.pem")
+	ecKey, _ := jwt.ParseECPrivateKeyFromPEM(ecData)
+	ec384Data, _ := ioutil.ReadFile("test/ec384-private.pem")
+	ec384Key, _ := jwt.ParseECPrivateKeyFromPEM(ec384Data)
+
+	var tests = []struct {
+		method jwt.SigningMethod
+		signer crypto.Signer
+		valid  bool
+	}{
+		{jwt.SigningMethodRS256, rsaKey, true},
+		{jwt.SigningMethodPS384, rsaKey, true},
+		{jwt.SigningMethodES256, ecKey, true},
+		{jwt.SigningMethodES256, rsaKey, false},
+		{jwt.SigningMethodRS256, ecKey, false},
+		{jwt.SigningMethodES256, ec384Key, false},
+	}
+
+	for _, data := range tests {
+		signer := opaqueSigner{data.signer}
+		sig, err := data.method.Sign("foo.bar", signer)
+		if !data.valid {
+			if err == nil {
+				t.Errorf("[%v] Signed with mismatched key %T", data.method.Alg(), data.signer)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%v] Error signing with crypto.Signer: %v", data.method.Alg(), err)
+			continue
+		}
+
+		// verification works with the exported public key
+		if err := data.method.Verify("foo.bar", sig, signer.Public()); err != nil {
+			t.Errorf("[%v] Error verifying signature: %v", data.method.Alg(), err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// A log-safe summary of a token, from Token.Debug.  It never includes the
+// signature or the raw token string.
+type TokenInfo struct {
+	Algorithm string                 `json:"alg,omitempty"`
+	KeyID     string                 `json:"kid,omitempty"`
+	Type      string                 `json:"typ,omitempty"`
+	Valid     bool                   `json:"valid"`
+	Header    map[string]interface{} `json:"header,omitempty"`
+	Claims    MapClaims              `json:"claims,omitempty"` // With redacted claims masked
+	IssuedAt  *time.Time             `json:"iat,omitempty"`
+	NotBefore *time.Time             `json:"nbf,omitempty"`
+	ExpiresAt *time.Time             `json:"exp,omitempty"`
+	ExpiresIn time.Duration          `json:"-"` // Time left before exp, negative once it has passed.  Zero without exp
+}
+
+// Summarize the token for logging, masking the values of claims matching the
+// redact patterns, as a Redactor does.  The token need not be valid; use
+// ParseUnverified to inspect tokens that failed verification.
+func (t *Token) Debug(redact ...string) *TokenInfo {
+	return NewRedactor(redact...).Debug(t)
+}
+
+// Summarize the token as Token.Debug does, masking the claims r matches
+func (r *Redactor) Debug(t *Token) *TokenInfo {
+	info := &TokenInfo{Valid: t.Valid}
+	if t.Header != nil {
+		info.Algorithm, _ = t.Header["alg"].(string)
+		info.KeyID, _ = t.Header["kid"].(string)
+		info.Type, _ = t.Header["typ"].(string)
+		info.Header = make(map[string]interface{}, len(t.Header))
+		for k, v := range t.Header {
+			info.Header[k] = v
+		}
+	}
+	if t.Claims == nil {
+		return info
+	}
+
+	info.Claims, _ = r.Redact(t.Claims)
+	info.IssuedAt = debugTime(t.Claims, "iat")
+	info.NotBefore = debugTime(t.Claims, "nbf")
+	if info.ExpiresAt = debugTime(t.Claims, "exp"); info.ExpiresAt != nil {
+		info.ExpiresIn = info.ExpiresAt.Sub(TimeFunc()).Truncate(time.Second)
+	}
+	return info
+}
+
+func debugTime(claims Claims, name string) *time.Time {
+	v, ok, err := timeClaim(claims, name)
+	if !ok || err != nil {
+		return nil
+	}
+	t := time.Unix(v, 0).UTC()
+	return &t
+}
+
+// Encodes ExpiresIn as a duration string such as "59m30s"
+func (i *TokenInfo) MarshalJSON() ([]byte, error) {
+	type info TokenInfo
+	v := struct {
+		*info
+		ExpiresIn string `json:"expires_in,omitempty"`
+	}{info: (*info)(i)}
+	if i.ExpiresAt != nil {
+		v.ExpiresIn = i.ExpiresIn.String()
+	}
+	return json.Marshal(v)
+}
+
+// Renders the summary on one line, as key=value pairs
+func (i *TokenInfo) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "alg=%s", i.Algorithm)
+	if i.KeyID != "" {
+		fmt.Fprintf(&buf, " kid=%s", i.KeyID)
+	}
+	if i.Type != "" {
+		fmt.Fprintf(&buf, " typ=%s", i.Type)
+	}
+	fmt.Fprintf(&buf, " valid=%t", i.Valid)
+	if i.IssuedAt != nil {
+		fmt.Fprintf(&buf, " iat=%s", i.IssuedAt.Format(time.RFC3339))
+	}
+	if i.NotBefore != nil {
+		fmt.Fprintf(&buf, " nbf=%s", i.NotBefore.Format(time.RFC3339))
+	}
+	if i.ExpiresAt != nil {
+		fmt.Fprintf(&buf, " exp=%s expires_in=%s", i.ExpiresAt.Format(time.RFC3339), i.ExpiresIn)
+	}
+	if i.Header != nil {
+		header, _ := json.Marshal(i.Header)
+		fmt.Fprintf(&buf, " header=%s", header)
+	}
+	if i.Claims != nil {
+		claims, _ := json.Marshal(i.Claims)
+		fmt.Fprintf(&buf, " claims=%s", claims)
+	}
+	return buf.String()
+}
+
+// Decode tokenString without verifying it and summarize it as Token.Debug
+// does, for inspecting tokens from logs or the command line
+func FormatToken(tokenString string, redact ...string) (string, error) {
+	token, _, err := new(Parser).ParseUnverified(tokenString, MapClaims{})
+	if err != nil {
+		return "", err
+	}
+	return token.Debug(redact...).String(), nil
+}
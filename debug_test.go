@@ -0,0 +1,91 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestTokenDebug(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	claims := jwt.MapClaims{"sub": "user", "email": "user@example.com", "iat": float64(now.Unix()), "exp": float64(now.Unix() + 90)}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = "k1"
+	tokenString, err := token.SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := token.Debug("email", "missing")
+	if info.Algorithm != "HS256" || info.KeyID != "k1" || info.Type != "JWT" {
+		t.Errorf("Unexpected header fields: %+v", info)
+	}
+	if info.Claims["email"] != jwt.RedactedValue || info.Claims["sub"] != "user" {
+		t.Errorf("Unexpected claims: %v", info.Claims)
+	}
+	if _, ok := info.Claims["missing"]; ok {
+		t.Errorf("Redaction added a claim")
+	}
+	if claims["email"] != "user@example.com" {
+		t.Errorf("Debug modified the token's claims")
+	}
+	if info.ExpiresAt == nil || !info.ExpiresAt.Equal(now.Add(90*time.Second)) || info.ExpiresIn != 90*time.Second {
+		t.Errorf("Unexpected expiry: %v %v", info.ExpiresAt, info.ExpiresIn)
+	}
+	if info.NotBefore != nil {
+		t.Errorf("Unexpected nbf: %v", info.NotBefore)
+	}
+
+	s := info.String()
+	for _, want := range []string{"alg=HS256", "kid=k1", "iat=2017-07-14T02:40:00Z", "expires_in=1m30s", `"email":"[REDACTED]"`} {
+		if !strings.Contains(s, want) {
+			t.Errorf("Expected %q in %q", want, s)
+		}
+	}
+	if strings.Contains(s, "user@example.com") || strings.Contains(s, tokenString[strings.LastIndex(tokenString, ".")+1:]) {
+		t.Errorf("String leaked sensitive data: %q", s)
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["expires_in"] != "1m30s" || decoded["kid"] != "k1" {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+
+	formatted, err := jwt.FormatToken(tokenString, "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formatted != s {
+		t.Errorf("FormatToken differs from Debug:\n%s\n%s", formatted, s)
+	}
+	if _, err := jwt.FormatToken("not a token"); err == nil {
+		t.Errorf("Expected an error formatting a malformed token")
+	}
+}
+
+func TestTokenDebugStructClaims(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{Subject: "user", NotBefore: 1500000000})
+	info := token.Debug("sub")
+	if info.Claims["sub"] != jwt.RedactedValue {
+		t.Errorf("Unexpected claims: %v", info.Claims)
+	}
+	if info.NotBefore == nil || info.NotBefore.Unix() != 1500000000 || info.ExpiresAt != nil {
+		t.Errorf("Unexpected times: %v %v", info.NotBefore, info.ExpiresAt)
+	}
+	if strings.Contains(info.String(), "expires_in") {
+		t.Errorf("Unexpected expiry in %q", info.String())
+	}
+}
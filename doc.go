@@ -1,4 +1,11 @@
 // Package jwt is a Go implementation of JSON Web Tokens: http://self-issued.info/docs/draft-jones-json-web-token.html
 //
 // See README.md for more info.
+//
+// The package does not depend on net/http, and builds for GOOS=js and
+// GOOS=wasip1 with GOARCH=wasm, for inspecting tokens in the browser.  With
+// TinyGo, which sets the tinygo build tag, crypto/tls and net/mail are left
+// out: VerifyTLSBinding is unavailable, and the email format of jwt struct
+// tags checks only the shape of an address.  HTTP helpers live in the
+// request and middleware packages.
 package jwt
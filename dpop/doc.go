@@ -0,0 +1,23 @@
+// DPoP proof-of-possession (RFC 9449).
+//
+// A client holding a key pair sends, with each request, a proof JWT signed by
+// that key and naming the request's method and URI.  Access tokens issued to
+// the client carry the key's thumbprint in their cnf claim, so a stolen token
+// is useless without the private key.
+//
+// Clients create proofs with NewProof:
+//
+//	proof, err := dpop.NewProof(jwt.SigningMethodES256, key, "GET", "https://api.example.com/orders", accessToken)
+//	req.Header.Set("Authorization", "DPoP "+accessToken)
+//	req.Header.Set("DPoP", proof)
+//
+// Resource servers check them with a Verifier after validating the access
+// token, for example one extracted with AuthorizationHeaderExtractor:
+//
+//	verifier := &dpop.Verifier{Replay: jwt.NewMemoryReplayGuard()}
+//	if _, err := verifier.VerifyRequest(r, token); err != nil {
+//		// reject the request
+//	}
+//
+// Authorization servers bind issued tokens with Confirmation.
+package dpop
@@ -0,0 +1,169 @@
+package dpop
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var testKey, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+// Matches any non-nil error in test tables
+var errAny = errors.New("any error")
+
+// An access token bound to key
+func boundToken(t *testing.T, key interface{}) *jwt.Token {
+	cnf, err := Confirmation(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice", "cnf": cnf}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestVerify(t *testing.T) {
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	const uri = "https://api.example.com/orders"
+
+	var verifyTestData = []struct {
+		name   string
+		proof  func() (string, error)
+		htm    string
+		htu    string
+		access string
+		err    error
+	}{
+		{"valid", func() (string, error) { return NewProof(jwt.SigningMethodES256, testKey, "GET", uri, "") }, "GET", uri, "", nil},
+		{"normalized uri", func() (string, error) {
+			return NewProof(jwt.SigningMethodES256, testKey, "GET", "HTTPS://API.example.com:443/orders", "")
+		}, "GET", uri + "?page=2", "", nil},
+		{"access token", func() (string, error) { return NewProof(jwt.SigningMethodES256, testKey, "GET", uri, "token") }, "GET", uri, "token", nil},
+		{"wrong access token", func() (string, error) { return NewProof(jwt.SigningMethodES256, testKey, "GET", uri, "token") }, "GET", uri, "other", ErrAccessTokenHash},
+		{"missing ath", func() (string, error) { return NewProof(jwt.SigningMethodES256, testKey, "GET", uri, "") }, "GET", uri, "token", ErrAccessTokenHash},
+		{"wrong method", func() (string, error) { return NewProof(jwt.SigningMethodES256, testKey, "GET", uri, "") }, "POST", uri, "", ErrMethodMismatch},
+		{"wrong uri", func() (string, error) { return NewProof(jwt.SigningMethodES256, testKey, "GET", uri, "") }, "GET", "https://api.example.com/users", "", ErrURIMismatch},
+		{"old", func() (string, error) {
+			return NewProofWithClaims(jwt.SigningMethodES256, testKey, &ProofClaims{
+				StandardClaims: jwt.StandardClaims{IssuedAt: time.Now().Add(-time.Hour).Unix()},
+				HTTPMethod:     "GET", HTTPURI: uri,
+			}, "")
+		}, "GET", uri, "", ErrProofExpired},
+		{"future", func() (string, error) {
+			return NewProofWithClaims(jwt.SigningMethodES256, testKey, &ProofClaims{
+				StandardClaims: jwt.StandardClaims{IssuedAt: time.Now().Add(time.Hour).Unix()},
+				HTTPMethod:     "GET", HTTPURI: uri,
+			}, "")
+		}, "GET", uri, "", ErrProofExpired},
+		{"signed by another key", func() (string, error) {
+			token := jwt.NewWithClaims(jwt.SigningMethodES256, &ProofClaims{
+				StandardClaims: jwt.StandardClaims{Id: "1", IssuedAt: time.Now().Unix()},
+				HTTPMethod:     "GET", HTTPURI: uri,
+			})
+			jwk, _ := jwt.NewJSONWebKey(testKey)
+			token.Header["typ"] = ProofType
+			token.Header["jwk"] = jwk
+			return token.SignedString(otherKey)
+		}, "GET", uri, "", jwt.ErrECDSAVerification},
+		{"private jwk", func() (string, error) {
+			token := jwt.NewWithClaims(jwt.SigningMethodES256, &ProofClaims{
+				StandardClaims: jwt.StandardClaims{Id: "1", IssuedAt: time.Now().Unix()},
+				HTTPMethod:     "GET", HTTPURI: uri,
+			})
+			jwk, _ := jwt.NewJSONWebKey(testKey)
+			token.Header["typ"] = ProofType
+			token.Header["jwk"] = map[string]string{"kty": jwk.Kty, "crv": jwk.Crv, "x": jwk.X, "y": jwk.Y, "d": "AQ"}
+			return token.SignedString(testKey)
+		}, "GET", uri, "", ErrProofKey},
+		{"hmac", func() (string, error) {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, &ProofClaims{
+				StandardClaims: jwt.StandardClaims{Id: "1", IssuedAt: time.Now().Unix()},
+				HTTPMethod:     "GET", HTTPURI: uri,
+			})
+			token.Header["typ"] = ProofType
+			return token.SignedString([]byte("secret"))
+		}, "GET", uri, "", errAny},
+	}
+
+	verifier := &Verifier{}
+	for _, data := range verifyTestData {
+		proof, err := data.proof()
+		if err != nil {
+			t.Fatalf("[%v] Error creating proof: %v", data.name, err)
+		}
+		_, err = verifier.Verify(proof, data.htm, data.htu, data.access)
+		if data.err == errAny {
+			if err == nil {
+				t.Errorf("[%v] Expected proof to be rejected", data.name)
+			}
+			continue
+		}
+		if ve, ok := err.(*jwt.ValidationError); ok {
+			err = ve.Inner
+		}
+		if err != data.err {
+			t.Errorf("[%v] Expected error %v, got %v", data.name, data.err, err)
+		}
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	token := boundToken(t, testKey)
+	verifier := &Verifier{Replay: jwt.NewMemoryReplayGuard()}
+
+	newProof := func(key interface{}) string {
+		proof, err := NewProof(jwt.SigningMethodES256, key, "POST", "https://api.example.com/orders", token.Raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return proof
+	}
+
+	proof := newProof(testKey)
+	r := httptest.NewRequest("POST", "https://api.example.com/orders?id=1", nil)
+	r.Header.Set("Authorization", "DPoP "+token.Raw)
+	r.Header.Set("DPoP", proof)
+
+	if raw, err := AuthorizationHeaderExtractor.ExtractToken(r); err != nil || raw != token.Raw {
+		t.Errorf("Expected DPoP token to be extracted, got %q, %v", raw, err)
+	}
+	verified, err := verifier.VerifyRequest(r, token)
+	if err != nil {
+		t.Fatalf("Error verifying request: %v", err)
+	}
+	if cnf, _ := Confirmation(testKey); verified.Thumbprint != cnf.JWKThumbprint {
+		t.Errorf("Unexpected thumbprint %v", verified.Thumbprint)
+	}
+
+	if _, err := verifier.VerifyRequest(r, token); err == nil {
+		t.Errorf("Expected replayed proof to be rejected")
+	}
+
+	proof = newProof(otherKey)
+	r.Header.Set("DPoP", proof)
+	if _, err := verifier.VerifyRequest(r, token); err != ErrKeyBinding {
+		t.Errorf("Expected ErrKeyBinding, got %v", err)
+	}
+
+	r.Header.Add("DPoP", proof)
+	if _, err := verifier.VerifyRequest(r, token); err != ErrMultipleProofs {
+		t.Errorf("Expected ErrMultipleProofs, got %v", err)
+	}
+	r.Header.Del("DPoP")
+	if _, err := verifier.VerifyRequest(r, token); err != ErrMissingProof {
+		t.Errorf("Expected ErrMissingProof, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package dpop
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// The typ header of a DPoP proof
+const ProofType = "dpop+jwt"
+
+// Claims of a DPoP proof.  Id (jti) and IssuedAt are always set.
+type ProofClaims struct {
+	jwt.StandardClaims
+	HTTPMethod      string `json:"htm"`
+	HTTPURI         string `json:"htu"`
+	AccessTokenHash string `json:"ath,omitempty"` // Set when the proof accompanies an access token
+	Nonce           string `json:"nonce,omitempty"`
+}
+
+// Create a proof for a request with the given method and URI, signed by key
+// and carrying its public half in the jwk header.  If accessToken is not
+// empty, the proof is bound to it with the ath claim.
+func NewProof(method jwt.SigningMethod, key interface{}, htm, htu, accessToken string) (string, error) {
+	return NewProofWithClaims(method, key, &ProofClaims{HTTPMethod: htm, HTTPURI: htu}, accessToken)
+}
+
+// NewProof with caller-provided claims, for example to set a server-provided
+// nonce.  A missing jti or iat is filled in.
+func NewProofWithClaims(method jwt.SigningMethod, key interface{}, claims *ProofClaims, accessToken string) (string, error) {
+	jwk, err := jwt.NewJSONWebKey(key)
+	if err != nil {
+		return "", err
+	}
+	if claims.Id == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return "", err
+		}
+		claims.Id = jwt.EncodeSegment(id)
+	}
+	if claims.IssuedAt == 0 {
+		claims.IssuedAt = jwt.TimeFunc().Unix()
+	}
+	if accessToken != "" {
+		claims.AccessTokenHash = AccessTokenHash(accessToken)
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["typ"] = ProofType
+	token.Header["jwk"] = jwk
+	return token.SignedString(key)
+}
+
+// The ath value for accessToken: its base64url-encoded SHA-256 hash
+func AccessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return jwt.EncodeSegment(sum[:])
+}
+
+// The cnf claim binding an access token to key, for authorization servers
+// issuing DPoP-bound tokens
+func Confirmation(key interface{}) (*jwt.Confirmation, error) {
+	jwk, err := jwt.NewJSONWebKey(key)
+	if err != nil {
+		return nil, err
+	}
+	thumbprint, err := jwk.Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+	return &jwt.Confirmation{JWKThumbprint: jwt.EncodeSegment(thumbprint)}, nil
+}
+
+// Records proof jtis so each proof is accepted only once, such as a
+// jwt.MemoryReplayGuard
+type ReplayGuard = jwt.ReplayGuard
@@ -0,0 +1,212 @@
+package dpop
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/request"
+)
+
+// Errors
+var (
+	ErrMissingProof    = errors.New("request has no DPoP proof")
+	ErrMultipleProofs  = errors.New("request has more than one DPoP proof")
+	ErrProofKey        = errors.New("DPoP proof jwk header is missing, private or invalid")
+	ErrMissingClaims   = errors.New("DPoP proof is missing jti, htm, htu or iat")
+	ErrProofExpired    = errors.New("DPoP proof is too old or issued in the future")
+	ErrMethodMismatch  = errors.New("DPoP proof htm does not match the request method")
+	ErrURIMismatch     = errors.New("DPoP proof htu does not match the request URI")
+	ErrAccessTokenHash = errors.New("DPoP proof ath does not match the access token")
+	ErrKeyBinding      = errors.New("access token is not bound to the DPoP proof key")
+)
+
+// Proofs may only be signed with asymmetric keys
+var defaultValidMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// Checks DPoP proofs.  The zero Verifier accepts proofs up to five minutes
+// old and does not detect replays; set Replay in production.
+type Verifier struct {
+	ValidMethods []string                 // Defaults to the RSA, RSA-PSS and ECDSA methods
+	MaxAge       time.Duration            // How old a proof's iat may be.  Defaults to 5 minutes
+	Leeway       time.Duration            // Allowed clock skew for proofs issued in the future
+	Replay       ReplayGuard              // If set, each proof jti is accepted once
+	CheckNonce   func(nonce string) error // If set, checks the nonce claim, which may be empty
+}
+
+// A verified proof
+type Proof struct {
+	Token      *jwt.Token
+	Claims     *ProofClaims
+	JWK        *jwt.JSONWebKey
+	Thumbprint string // base64url-encoded RFC 7638 thumbprint of JWK
+}
+
+// Verify a proof sent with a request for htm and htu.  If accessToken is not
+// empty, the proof's ath claim must match it.  htu is compared without its
+// query and fragment.
+func (v *Verifier) Verify(proof, htm, htu, accessToken string) (*Proof, error) {
+	methods := v.ValidMethods
+	if methods == nil {
+		methods = defaultValidMethods
+	}
+	parser := jwt.NewParser(jwt.WithValidMethods(methods), jwt.WithType(ProofType), jwt.WithoutClaimsValidation())
+
+	claims := &ProofClaims{}
+	var jwk *jwt.JSONWebKey
+	token, err := parser.ParseWithClaims(proof, claims, func(token *jwt.Token) (interface{}, error) {
+		var err error
+		if jwk, err = headerKey(token.Header); err != nil {
+			return nil, err
+		}
+		return jwk.PublicKey()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Id == "" || claims.HTTPMethod == "" || claims.HTTPURI == "" || claims.IssuedAt == 0 {
+		return nil, ErrMissingClaims
+	}
+	maxAge := v.MaxAge
+	if maxAge == 0 {
+		maxAge = 5 * time.Minute
+	}
+	iat := time.Unix(claims.IssuedAt, 0)
+	now := jwt.TimeFunc()
+	if iat.After(now.Add(v.Leeway)) || iat.Add(maxAge).Before(now) {
+		return nil, ErrProofExpired
+	}
+	if claims.HTTPMethod != htm {
+		return nil, ErrMethodMismatch
+	}
+	if normalizeURI(claims.HTTPURI) != normalizeURI(htu) {
+		return nil, ErrURIMismatch
+	}
+	if accessToken != "" && claims.AccessTokenHash != AccessTokenHash(accessToken) {
+		return nil, ErrAccessTokenHash
+	}
+	if v.CheckNonce != nil {
+		if err := v.CheckNonce(claims.Nonce); err != nil {
+			return nil, err
+		}
+	}
+	if v.Replay != nil {
+		if err := v.Replay.Consume(claims.Id, iat.Add(maxAge+v.Leeway)); err != nil {
+			return nil, err
+		}
+	}
+
+	thumbprint, err := jwk.Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+	return &Proof{Token: token, Claims: claims, JWK: jwk, Thumbprint: jwt.EncodeSegment(thumbprint)}, nil
+}
+
+// Verify the DPoP header of r, and that accessToken, already validated by the
+// caller, is bound to the proof key by its cnf claim.  accessToken may be nil
+// for requests to a token endpoint.
+//
+// The request URI is reconstructed from r, which is wrong behind a proxy that
+// rewrites the host or scheme; call Verify with the public URI instead.
+func (v *Verifier) VerifyRequest(r *http.Request, accessToken *jwt.Token) (*Proof, error) {
+	proofs := r.Header.Values("DPoP")
+	switch {
+	case len(proofs) == 0:
+		return nil, ErrMissingProof
+	case len(proofs) > 1:
+		return nil, ErrMultipleProofs
+	}
+
+	var raw string
+	if accessToken != nil {
+		raw = accessToken.Raw
+	}
+	proof, err := v.Verify(proofs[0], r.Method, RequestURI(r), raw)
+	if err != nil {
+		return nil, err
+	}
+	if accessToken != nil {
+		cnf, err := accessToken.Confirmation()
+		if err != nil {
+			return nil, err
+		}
+		if cnf == nil || cnf.JWKThumbprint != proof.Thumbprint {
+			return nil, ErrKeyBinding
+		}
+	}
+	return proof, nil
+}
+
+// The htu a client would use for r: its scheme, host and path
+func RequestURI(r *http.Request) string {
+	scheme := r.URL.Scheme
+	if scheme == "" {
+		scheme = "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+	}
+	host := r.URL.Host
+	if host == "" {
+		host = r.Host
+	}
+	return scheme + "://" + host + r.URL.EscapedPath()
+}
+
+// Extracts DPoP-bound access tokens from the Authorization header, which uses
+// the DPoP scheme in place of Bearer
+var AuthorizationHeaderExtractor = &request.PostExtractionFilter{
+	Extractor: request.HeaderExtractor{"Authorization"},
+	Filter: func(tok string) (string, error) {
+		if len(tok) > 5 && strings.EqualFold(tok[:5], "DPoP ") {
+			return tok[5:], nil
+		}
+		return "", request.ErrNoTokenInRequest
+	},
+}
+
+// Decode the jwk header, rejecting private keys
+func headerKey(header map[string]interface{}) (*jwt.JSONWebKey, error) {
+	raw, ok := header["jwk"].(map[string]interface{})
+	if !ok {
+		return nil, ErrProofKey
+	}
+	if _, private := raw["d"]; private {
+		return nil, ErrProofKey
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, ErrProofKey
+	}
+	jwk := &jwt.JSONWebKey{}
+	if err := json.Unmarshal(data, jwk); err != nil {
+		return nil, ErrProofKey
+	}
+	return jwk, nil
+}
+
+// Normalize a URI for comparison as RFC 9449 section 4.3 describes: without
+// query or fragment, with the scheme and host lowercased and any default port
+// removed
+func normalizeURI(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || !u.IsAbs() {
+		return s
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	if port := u.Port(); (scheme == "https" && port == "443") || (scheme == "http" && port == "80") {
+		host = strings.TrimSuffix(host, ":"+port)
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return scheme + "://" + host + path
+}
@@ -4,6 +4,7 @@ import (
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/rand"
+	"encoding/asn1"
 	"errors"
 	"math/big"
 )
@@ -96,13 +97,24 @@ func (m *SigningMethodECDSA) Verify(signingString, signature string, key interfa
 }
 
 // Implements the Sign method from SigningMethod
-// For this signing method, key must be an ecdsa.PrivateKey struct
+// For this signing method, key must be an ecdsa.PrivateKey struct or a
+// crypto.Signer backed by an ECDSA key, such as an HSM or KMS client.
 func (m *SigningMethodECDSA) Sign(signingString string, key interface{}) (string, error) {
 	// Get the key
 	var ecdsaKey *ecdsa.PrivateKey
+	var signer crypto.Signer
+	var curveBits int
 	switch k := key.(type) {
 	case *ecdsa.PrivateKey:
 		ecdsaKey = k
+		curveBits = k.Curve.Params().BitSize
+	case crypto.Signer:
+		pub, ok := k.Public().(*ecdsa.PublicKey)
+		if !ok {
+			return "", ErrInvalidKeyType
+		}
+		signer = k
+		curveBits = pub.Curve.Params().BitSize
 	default:
 		return "", ErrInvalidKeyType
 	}
@@ -116,33 +128,57 @@ func (m *SigningMethodECDSA) Sign(signingString string, key interface{}) (string
 	hasher.Write([]byte(signingString))
 
 	// Sign the string and return r, s
-	if r, s, err := ecdsa.Sign(rand.Reader, ecdsaKey, hasher.Sum(nil)); err == nil {
-		curveBits := ecdsaKey.Curve.Params().BitSize
+	var r, s *big.Int
+	var err error
+	if signer != nil {
+		r, s, err = signDigestWithSigner(signer, hasher.Sum(nil), m.Hash)
+	} else {
+		r, s, err = ecdsa.Sign(rand.Reader, ecdsaKey, hasher.Sum(nil))
+	}
+	if err != nil {
+		return "", err
+	}
 
-		if m.CurveBits != curveBits {
-			return "", ErrInvalidKey
-		}
+	if m.CurveBits != curveBits {
+		return "", ErrInvalidKey
+	}
 
-		keyBytes := curveBits / 8
-		if curveBits%8 > 0 {
-			keyBytes += 1
-		}
+	keyBytes := curveBits / 8
+	if curveBits%8 > 0 {
+		keyBytes += 1
+	}
 
-		// We serialize the outpus (r and s) into big-endian byte arrays and pad
-		// them with zeros on the left to make sure the sizes work out. Both arrays
-		// must be keyBytes long, and the output must be 2*keyBytes long.
-		rBytes := r.Bytes()
-		rBytesPadded := make([]byte, keyBytes)
-		copy(rBytesPadded[keyBytes-len(rBytes):], rBytes)
+	// We serialize the outpus (r and s) into big-endian byte arrays and pad
+	// them with zeros on the left to make sure the sizes work out. Both arrays
+	// must be keyBytes long, and the output must be 2*keyBytes long.
+	rBytes := r.Bytes()
+	rBytesPadded := make([]byte, keyBytes)
+	copy(rBytesPadded[keyBytes-len(rBytes):], rBytes)
 
-		sBytes := s.Bytes()
-		sBytesPadded := make([]byte, keyBytes)
-		copy(sBytesPadded[keyBytes-len(sBytes):], sBytes)
+	sBytes := s.Bytes()
+	sBytesPadded := make([]byte, keyBytes)
+	copy(sBytesPadded[keyBytes-len(sBytes):], sBytes)
 
-		out := append(rBytesPadded, sBytesPadded...)
+	out := append(rBytesPadded, sBytesPadded...)
 
-		return EncodeSegment(out), nil
-	} else {
-		return "", err
+	return EncodeSegment(out), nil
+}
+
+// crypto.Signer implementations return ASN.1 DER encoded ECDSA signatures.
+// JWS uses the raw r and s values instead.
+func signDigestWithSigner(signer crypto.Signer, digest []byte, hash crypto.Hash) (r, s *big.Int, err error) {
+	der, err := signer.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sig struct {
+		R, S *big.Int
+	}
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, nil, err
+	} else if len(rest) != 0 {
+		return nil, nil, ErrECDSAVerification
 	}
+	return sig.R, sig.S, nil
 }
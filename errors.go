@@ -9,6 +9,7 @@ var (
 	ErrInvalidKey      = errors.New("key is invalid")
 	ErrInvalidKeyType  = errors.New("key is of invalid type")
 	ErrHashUnavailable = errors.New("the requested hash function is unavailable")
+	ErrClaimNotFound   = errors.New("claim is not present")
 )
 
 // The errors that might occur when parsing and validating a token
@@ -25,6 +26,7 @@ const (
 	ValidationErrorNotValidYet   // NBF validation failed
 	ValidationErrorId            // JTI validation failed
 	ValidationErrorClaimsInvalid // Generic claims validation error
+	ValidationErrorType          // TYP validation failed
 )
 
 // Helper for constructing a ValidationError with a string error message
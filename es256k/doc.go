@@ -0,0 +1,13 @@
+// The ES256K signing method: ECDSA over secp256k1 with SHA-256 (RFC 8812),
+// as used by did:ethr and other blockchain-adjacent identity systems.
+//
+// Importing the package registers the method, so ES256K tokens can be
+// parsed with the usual functions:
+//
+//	import _ "github.com/dgrijalva/jwt-go/es256k"
+//
+// Keys are *secp256k1.PrivateKey and *secp256k1.PublicKey from
+// github.com/decred/dcrd/dcrec/secp256k1/v4.  This package depends on that
+// module and is only built with the secp256k1 build tag, so the core package
+// does not.
+package es256k
@@ -0,0 +1,74 @@
+//go:build secp256k1
+
+package es256k
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/dgrijalva/jwt-go"
+)
+
+var ErrES256KVerification = errors.New("secp256k1: verification error")
+
+// Implements ECDSA over secp256k1.  Expects *secp256k1.PrivateKey for signing
+// and *secp256k1.PublicKey for verification.
+type SigningMethodES256K struct{}
+
+// The ES256K signing method
+var SigningMethod = &SigningMethodES256K{}
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethod.Alg(), func() jwt.SigningMethod {
+		return SigningMethod
+	})
+}
+
+func (m *SigningMethodES256K) Alg() string {
+	return "ES256K"
+}
+
+// Implements the Verify method from SigningMethod.  The signature is R || S,
+// 32 bytes each, as in the other ECDSA methods.
+func (m *SigningMethodES256K) Verify(signingString, signature string, key interface{}) error {
+	pub, ok := key.(*secp256k1.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	if len(sig) != 64 {
+		return ErrES256KVerification
+	}
+
+	var r, s secp256k1.ModNScalar
+	if overflow := r.SetByteSlice(sig[:32]); overflow || r.IsZero() {
+		return ErrES256KVerification
+	}
+	if overflow := s.SetByteSlice(sig[32:]); overflow || s.IsZero() {
+		return ErrES256KVerification
+	}
+	hash := sha256.Sum256([]byte(signingString))
+	if !ecdsa.NewSignature(&r, &s).Verify(hash[:], pub) {
+		return ErrES256KVerification
+	}
+	return nil
+}
+
+// Implements the Sign method from SigningMethod
+func (m *SigningMethodES256K) Sign(signingString string, key interface{}) (string, error) {
+	private, ok := key.(*secp256k1.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	hash := sha256.Sum256([]byte(signingString))
+	sig := ecdsa.Sign(private, hash[:])
+
+	r, s := sig.R(), sig.S()
+	rBytes, sBytes := r.Bytes(), s.Bytes()
+	return jwt.EncodeSegment(append(rBytes[:], sBytes[:]...)), nil
+}
@@ -0,0 +1,46 @@
+//go:build secp256k1
+
+package es256k
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestES256K(t *testing.T) {
+	private, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := secp256k1.GeneratePrivateKey()
+
+	tokenString, err := jwt.NewWithClaims(SigningMethod, jwt.MapClaims{"sub": "did:ethr:0x1234"}).SignedString(private)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+	if sig, _ := jwt.DecodeSegment(tokenString[strings.LastIndex(tokenString, ".")+1:]); len(sig) != 64 {
+		t.Errorf("Expected a 64 byte signature, got %v", len(sig))
+	}
+
+	var tests = []struct {
+		name  string
+		key   interface{}
+		valid bool
+	}{
+		{"valid", private.PubKey(), true},
+		{"wrong key", other.PubKey(), false},
+		{"wrong key type", []byte("secret"), false},
+	}
+	for _, data := range tests {
+		token, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return data.key, nil })
+		if (err == nil) != data.valid {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		}
+		if data.valid && token.Method.Alg() != "ES256K" {
+			t.Errorf("[%v] Unexpected method %v", data.name, token.Method.Alg())
+		}
+	}
+}
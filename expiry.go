@@ -0,0 +1,36 @@
+package jwt
+
+import (
+	"math"
+	"time"
+)
+
+// The time from the exp claim, and whether the token has one
+func (t *Token) ExpiresAt() (time.Time, bool) {
+	if t.Claims == nil {
+		return time.Time{}, false
+	}
+	exp, ok, err := timeClaim(t.Claims, "exp")
+	if !ok || err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(exp, 0), true
+}
+
+// Reports whether the token expires within leeway of TimeFunc().  A positive
+// leeway treats tokens about to expire as expired, which suits pre-emptive
+// refresh; a negative one tolerates clock skew.  Tokens without exp never expire.
+func (t *Token) IsExpired(leeway time.Duration) bool {
+	exp, ok := t.ExpiresAt()
+	return ok && !TimeFunc().Add(leeway).Before(exp)
+}
+
+// The time left before the token expires, negative once it has.  Tokens
+// without exp report the maximum duration.
+func (t *Token) TimeUntilExpiry() time.Duration {
+	exp, ok := t.ExpiresAt()
+	if !ok {
+		return time.Duration(math.MaxInt64)
+	}
+	return exp.Sub(TimeFunc())
+}
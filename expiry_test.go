@@ -0,0 +1,42 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestTokenExpiry(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	var tests = []struct {
+		name    string
+		claims  jwt.Claims
+		hasExp  bool
+		expired bool
+		leeway  time.Duration
+		until   time.Duration
+	}{
+		{"map", jwt.MapClaims{"exp": float64(now.Unix() + 60)}, true, false, 0, time.Minute},
+		{"map within leeway", jwt.MapClaims{"exp": float64(now.Unix() + 60)}, true, true, 2 * time.Minute, time.Minute},
+		{"struct expired", &jwt.StandardClaims{ExpiresAt: now.Unix() - 60}, true, true, 0, -time.Minute},
+		{"struct skew tolerated", jwt.StandardClaims{ExpiresAt: now.Unix() - 60}, true, false, -2 * time.Minute, -time.Minute},
+		{"no exp", jwt.MapClaims{"sub": "user"}, false, false, time.Hour, 1<<63 - 1},
+	}
+
+	for _, data := range tests {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims)
+		if exp, ok := token.ExpiresAt(); ok != data.hasExp || (ok && exp.Sub(now) != data.until) {
+			t.Errorf("[%v] Unexpected ExpiresAt: %v %v", data.name, exp, ok)
+		}
+		if expired := token.IsExpired(data.leeway); expired != data.expired {
+			t.Errorf("[%v] Expected IsExpired %v, got %v", data.name, data.expired, expired)
+		}
+		if until := token.TimeUntilExpiry(); until != data.until {
+			t.Errorf("[%v] Expected TimeUntilExpiry %v, got %v", data.name, data.until, until)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+// Fernet tokens with the same claims types and API shape as this package's
+// JWTs, for teams that want claims encrypted as well as authenticated.
+//
+// Fernet encrypts with AES-128-CBC and authenticates with HMAC-SHA256, using
+// a single 32-byte key.  The claims are JSON, as in a JWT:
+//
+//	key, err := fernet.DecodeKey(os.Getenv("TOKEN_KEY"))
+//	tokenString, err := fernet.NewWithClaims(&jwt.StandardClaims{Subject: "alice"}).SignedString(key)
+//
+//	parser := &fernet.Parser{TTL: time.Hour}
+//	token, err := parser.ParseWithClaims(tokenString, &jwt.StandardClaims{}, func(*fernet.Token) (interface{}, error) {
+//		return key, nil
+//	})
+//
+// Encrypt and Decrypt work with raw messages, and interoperate with other
+// Fernet implementations.
+package fernet
@@ -0,0 +1,128 @@
+package fernet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Errors
+var (
+	ErrInvalidKey   = errors.New("fernet key must be 32 bytes")
+	ErrTokenInvalid = errors.New("fernet token is malformed or fails authentication")
+)
+
+const (
+	version      = 0x80
+	overheadSize = 1 + 8 + aes.BlockSize + sha256.Size // version, timestamp, IV and HMAC
+)
+
+// Generate a random key
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Decode a key in the base64url form other Fernet implementations use
+func DecodeKey(s string) ([]byte, error) {
+	key, err := base64.URLEncoding.DecodeString(s)
+	if err != nil || len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// Encode key in the base64url form other Fernet implementations use
+func EncodeKey(key []byte) string {
+	return base64.URLEncoding.EncodeToString(key)
+}
+
+// Encrypt message into a Fernet token, timestamped now
+func Encrypt(key, message []byte) (string, error) {
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return "", err
+	}
+	return encrypt(key, message, iv, jwt.TimeFunc())
+}
+
+func encrypt(key, message, iv []byte, now time.Time) (string, error) {
+	if len(key) != 32 {
+		return "", ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key[16:])
+	if err != nil {
+		return "", err
+	}
+
+	// PKCS #7 padding
+	pad := aes.BlockSize - len(message)%aes.BlockSize
+	padded := make([]byte, len(message)+pad)
+	copy(padded, message)
+	for i := len(message); i < len(padded); i++ {
+		padded[i] = byte(pad)
+	}
+
+	out := make([]byte, 9+aes.BlockSize+len(padded), overheadSize+len(padded))
+	out[0] = version
+	binary.BigEndian.PutUint64(out[1:9], uint64(now.Unix()))
+	copy(out[9:], iv)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out[9+aes.BlockSize:], padded)
+
+	mac := hmac.New(sha256.New, key[:16])
+	mac.Write(out)
+	return base64.URLEncoding.EncodeToString(mac.Sum(out)), nil
+}
+
+// Authenticate and decrypt token, returning the message and the time the
+// token was created.  The caller is responsible for checking its age.
+func Decrypt(key []byte, token string) ([]byte, time.Time, error) {
+	if len(key) != 32 {
+		return nil, time.Time{}, ErrInvalidKey
+	}
+	data, err := base64.URLEncoding.DecodeString(token)
+	if err != nil || len(data) < overheadSize+aes.BlockSize || data[0] != version {
+		return nil, time.Time{}, ErrTokenInvalid
+	}
+	body, sum := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	mac := hmac.New(sha256.New, key[:16])
+	mac.Write(body)
+	if !hmac.Equal(sum, mac.Sum(nil)) {
+		return nil, time.Time{}, ErrTokenInvalid
+	}
+
+	timestamp := time.Unix(int64(binary.BigEndian.Uint64(body[1:9])), 0)
+	iv, ciphertext := body[9:9+aes.BlockSize], body[9+aes.BlockSize:]
+	if len(ciphertext)%aes.BlockSize != 0 {
+		return nil, time.Time{}, ErrTokenInvalid
+	}
+	block, err := aes.NewCipher(key[16:])
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	message := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(message, ciphertext)
+
+	pad := int(message[len(message)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return nil, time.Time{}, ErrTokenInvalid
+	}
+	for _, b := range message[len(message)-pad:] {
+		if int(b) != pad {
+			return nil, time.Time{}, ErrTokenInvalid
+		}
+	}
+	return message[:len(message)-pad], timestamp, nil
+}
@@ -0,0 +1,74 @@
+package fernet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// The generate and verify vectors from the Fernet specification
+func TestVector(t *testing.T) {
+	key, err := DecodeKey("cw_0x689RpI-jtRR7oE8h_eQsKImvJapLeSbXpwF4e4=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const token = "gAAAAAAdwJ6wAAECAwQFBgcICQoLDA0ODy021cpGVWKZ_eEwCGM4BLLF_5CV9dOPmrhuVUPgJobwOz7JcbmrR64jVmpU4IwqDA=="
+	now, _ := time.Parse(time.RFC3339, "1985-10-26T01:20:00-07:00")
+	iv := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+	if got, err := encrypt(key, []byte("hello"), iv, now); err != nil || got != token {
+		t.Errorf("Expected %v, got %v, %v", token, got, err)
+	}
+	message, timestamp, err := Decrypt(key, token)
+	if err != nil || string(message) != "hello" || !timestamp.Equal(now) {
+		t.Errorf("Unexpected decryption %q at %v, %v", message, timestamp, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	key, _ := GenerateKey()
+	otherKey, _ := GenerateKey()
+	keyFunc := func(*Token) (interface{}, error) { return key, nil }
+
+	now := time.Now()
+	defer func() { jwt.TimeFunc = time.Now }()
+	at := func(d time.Duration) func() time.Time { return func() time.Time { return now.Add(d) } }
+
+	var tests = []struct {
+		name    string
+		claims  jwt.Claims
+		created func() time.Time
+		key     []byte
+		parser  *Parser
+		errors  uint32
+	}{
+		{"valid", &jwt.StandardClaims{Subject: "alice"}, at(0), key, &Parser{TTL: time.Hour}, 0},
+		{"older than ttl", &jwt.StandardClaims{Subject: "alice"}, at(-2 * time.Hour), key, &Parser{TTL: time.Hour}, jwt.ValidationErrorExpired},
+		{"no ttl", &jwt.StandardClaims{Subject: "alice"}, at(-2 * time.Hour), key, &Parser{}, 0},
+		{"created in the future", &jwt.StandardClaims{Subject: "alice"}, at(time.Hour), key, &Parser{}, jwt.ValidationErrorIssuedAt},
+		{"expired claims", &jwt.StandardClaims{Subject: "alice", ExpiresAt: now.Add(-time.Minute).Unix()}, at(0), key, &Parser{}, jwt.ValidationErrorExpired},
+		{"wrong key", &jwt.StandardClaims{Subject: "alice"}, at(0), otherKey, &Parser{}, jwt.ValidationErrorSignatureInvalid},
+	}
+
+	for _, data := range tests {
+		jwt.TimeFunc = data.created
+		tokenString, err := NewWithClaims(data.claims).SignedString(data.key)
+		jwt.TimeFunc = time.Now
+		if err != nil {
+			t.Fatalf("[%v] Error encrypting token: %v", data.name, err)
+		}
+
+		claims := &jwt.StandardClaims{}
+		token, err := data.parser.ParseWithClaims(tokenString, claims, keyFunc)
+		if data.errors == 0 {
+			if err != nil || !token.Valid || claims.Subject != "alice" {
+				t.Errorf("[%v] Error parsing token: %v %+v", data.name, err, claims)
+			}
+			continue
+		}
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&data.errors == 0 {
+			t.Errorf("[%v] Expected error flags %v, got %v", data.name, data.errors, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package fernet
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Tokens created up to this far in the future are accepted, for clock skew
+const maxClockSkew = 60 * time.Second
+
+// A Fernet token.  Different fields will be used depending on whether
+// you're creating or parsing/verifying a token.
+type Token struct {
+	Raw      string // The raw token.  Populated when you Parse a token
+	Claims   jwt.Claims
+	IssuedAt time.Time // The Fernet timestamp.  Populated when you Parse a token
+	Valid    bool      // Is the token valid?  Populated when you Parse/Verify a token
+}
+
+// Create a new token with claims
+func NewWithClaims(claims jwt.Claims) *Token {
+	return &Token{Claims: claims}
+}
+
+// Encrypt the token with a 32-byte key
+func (t *Token) SignedString(key interface{}) (string, error) {
+	k, ok := key.([]byte)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	message, err := json.Marshal(t.Claims)
+	if err != nil {
+		return "", err
+	}
+	return Encrypt(k, message)
+}
+
+// Returns the key for decrypting a token.  Nothing in the token can be read
+// before it is decrypted, so this can only choose by context.
+type Keyfunc func(*Token) (interface{}, error)
+
+// Parses Fernet tokens
+type Parser struct {
+	TTL                  time.Duration // If set, tokens older than this are rejected as expired
+	UseJSONNumber        bool          // Use JSON Number format in JSON decoder
+	SkipClaimsValidation bool          // Skip claims validation during token parsing
+}
+
+// Parse, validate, and return a token, with map claims
+func Parse(tokenString string, keyFunc Keyfunc) (*Token, error) {
+	return new(Parser).Parse(tokenString, keyFunc)
+}
+
+// Parse, validate, and return a token, decoding the claims into claims
+func ParseWithClaims(tokenString string, claims jwt.Claims, keyFunc Keyfunc) (*Token, error) {
+	return new(Parser).ParseWithClaims(tokenString, claims, keyFunc)
+}
+
+func (p *Parser) Parse(tokenString string, keyFunc Keyfunc) (*Token, error) {
+	return p.ParseWithClaims(tokenString, jwt.MapClaims{}, keyFunc)
+}
+
+func (p *Parser) ParseWithClaims(tokenString string, claims jwt.Claims, keyFunc Keyfunc) (*Token, error) {
+	token := &Token{Raw: tokenString, Claims: claims}
+	if keyFunc == nil {
+		return token, jwt.NewValidationError("no Keyfunc was provided.", jwt.ValidationErrorUnverifiable)
+	}
+	key, err := keyFunc(token)
+	if err != nil {
+		if ve, ok := err.(*jwt.ValidationError); ok {
+			return token, ve
+		}
+		return token, &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorUnverifiable}
+	}
+	k, ok := key.([]byte)
+	if !ok {
+		return token, &jwt.ValidationError{Inner: jwt.ErrInvalidKeyType, Errors: jwt.ValidationErrorUnverifiable}
+	}
+
+	message, issuedAt, err := Decrypt(k, tokenString)
+	if err != nil {
+		return token, &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorSignatureInvalid}
+	}
+	token.IssuedAt = issuedAt
+
+	dec := json.NewDecoder(bytes.NewReader(message))
+	if p.UseJSONNumber {
+		dec.UseNumber()
+	}
+	// Special case for map type to avoid weird pointer behavior
+	if c, ok := claims.(jwt.MapClaims); ok {
+		err = dec.Decode(&c)
+	} else {
+		err = dec.Decode(claims)
+	}
+	if err != nil {
+		return token, &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorMalformed}
+	}
+
+	now := jwt.TimeFunc()
+	if issuedAt.After(now.Add(maxClockSkew)) {
+		return token, jwt.NewValidationError("token was created in the future", jwt.ValidationErrorIssuedAt)
+	}
+	if p.TTL > 0 && issuedAt.Add(p.TTL).Before(now) {
+		return token, jwt.NewValidationError("token is older than the TTL", jwt.ValidationErrorExpired)
+	}
+	if !p.SkipClaimsValidation {
+		if err := claims.Valid(); err != nil {
+			if e, ok := err.(*jwt.ValidationError); ok {
+				return token, e
+			}
+			return token, &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorClaimsInvalid}
+		}
+	}
+	token.Valid = true
+	return token, nil
+}
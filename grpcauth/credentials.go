@@ -0,0 +1,44 @@
+//go:build grpc
+
+package grpcauth
+
+import (
+	"context"
+
+	"github.com/dgrijalva/jwt-go"
+	"google.golang.org/grpc/credentials"
+)
+
+// Attaches a bearer token to each outgoing call's authorization metadata.
+// It implements credentials.PerRPCCredentials.
+type Credentials struct {
+	Token    func(ctx context.Context) (string, error) // Returns the token to send
+	Insecure bool                                      // Allow sending the token without transport security
+}
+
+var _ credentials.PerRPCCredentials = (*Credentials)(nil)
+
+// Send the same token on every call
+func StaticCredentials(token string) *Credentials {
+	return &Credentials{Token: func(context.Context) (string, error) { return token, nil }}
+}
+
+// Sign a fresh token for every call with keyring's current key, using the
+// claims returned by newClaims
+func KeyringCredentials(keyring *jwt.Keyring, newClaims func() jwt.Claims) *Credentials {
+	return &Credentials{Token: func(context.Context) (string, error) {
+		return keyring.SignedString(newClaims())
+	}}
+}
+
+func (c *Credentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := c.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+func (c *Credentials) RequireTransportSecurity() bool {
+	return !c.Insecure
+}
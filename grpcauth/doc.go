@@ -0,0 +1,21 @@
+// Utility package for authenticating gRPC calls with JWT bearer tokens.
+//
+// Server interceptors verify the token in the authorization metadata and
+// store it in the context, where handlers retrieve it with
+// middleware.FromContext:
+//
+//	auth := grpcauth.New(keyFunc, grpcauth.RequireScopes("orders:write"))
+//	server := grpc.NewServer(
+//		grpc.UnaryInterceptor(auth.UnaryServerInterceptor()),
+//		grpc.StreamInterceptor(auth.StreamServerInterceptor()),
+//	)
+//
+// Clients attach tokens to outgoing calls with Credentials:
+//
+//	conn, err := grpc.Dial(addr,
+//		grpc.WithTransportCredentials(creds),
+//		grpc.WithPerRPCCredentials(grpcauth.StaticCredentials(token)))
+//
+// This package depends on google.golang.org/grpc and is only built with the
+// grpc build tag, so the rest of jwt-go does not pull it in.
+package grpcauth
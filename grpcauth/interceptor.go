@@ -0,0 +1,156 @@
+//go:build grpc
+
+package grpcauth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Checks the bearer token in each call's authorization metadata
+type Interceptor struct {
+	KeyFunc        jwt.Keyfunc
+	KeyFuncContext jwt.KeyfuncContext           // Used instead of KeyFunc if set, with the call's context
+	Parser         *jwt.Parser                  // Defaults to jwt.NewParser()
+	NewClaims      func() jwt.Claims            // Returns a claims value to parse into.  Defaults to jwt.MapClaims
+	RequiredScopes []string                     // Scopes the token must grant
+	Skip           func(fullMethod string) bool // Methods, such as health checks, that need no token
+}
+
+// Option is used to configure an Interceptor
+type Option func(*Interceptor)
+
+// Create an Interceptor verifying tokens with keyFunc
+func New(keyFunc jwt.Keyfunc, options ...Option) *Interceptor {
+	i := &Interceptor{
+		KeyFunc:   keyFunc,
+		Parser:    jwt.NewParser(),
+		NewClaims: func() jwt.Claims { return jwt.MapClaims{} },
+	}
+	for _, option := range options {
+		option(i)
+	}
+	return i
+}
+
+// Look up keys with keyFunc, which is passed the call's context, so key
+// fetches honor its deadline and cancellation.  It is used instead of the
+// Keyfunc passed to New, which may be nil.
+func WithKeyfuncContext(keyFunc jwt.KeyfuncContext) Option {
+	return func(i *Interceptor) {
+		i.KeyFuncContext = keyFunc
+	}
+}
+
+// Require the token to grant all of scopes, in either its scope or scp claim
+func RequireScopes(scopes ...string) Option {
+	return func(i *Interceptor) {
+		i.RequiredScopes = append(i.RequiredScopes, scopes...)
+	}
+}
+
+// Parse tokens with parser
+func WithParser(parser *jwt.Parser) Option {
+	return func(i *Interceptor) {
+		i.Parser = parser
+	}
+}
+
+// Parse claims into values returned by newClaims
+func WithClaims(newClaims func() jwt.Claims) Option {
+	return func(i *Interceptor) {
+		i.NewClaims = newClaims
+	}
+}
+
+// Let calls to methods for which skip returns true through without a token
+func WithSkip(skip func(fullMethod string) bool) Option {
+	return func(i *Interceptor) {
+		i.Skip = skip
+	}
+}
+
+// Verify unary calls before they reach the handler
+func (i *Interceptor) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if i.Skip != nil && i.Skip(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		token, err := i.Verify(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(middleware.NewContext(ctx, token), req)
+	}
+}
+
+// Verify streaming calls before they reach the handler
+func (i *Interceptor) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if i.Skip != nil && i.Skip(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		token, err := i.Verify(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ss, middleware.NewContext(ss.Context(), token)})
+	}
+}
+
+// Extract and verify the token in ctx's incoming metadata, and check its
+// scopes.  Errors are gRPC statuses: Unauthenticated for a missing or
+// invalid token, and PermissionDenied for missing scopes.
+func (i *Interceptor) Verify(ctx context.Context) (*jwt.Token, error) {
+	tokenString, ok := tokenFromMetadata(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no bearer token in authorization metadata")
+	}
+	token, err := i.Parser.ParseWithClaimsContext(ctx, tokenString, i.NewClaims(), i.keyFunc())
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if !jwt.HasScopes(token, i.RequiredScopes...) {
+		return nil, status.Error(codes.PermissionDenied, middleware.ErrInsufficientScope.Error())
+	}
+	return token, nil
+}
+
+func (i *Interceptor) keyFunc() jwt.KeyfuncContext {
+	if i.KeyFuncContext != nil {
+		return i.KeyFuncContext
+	}
+	return func(_ context.Context, token *jwt.Token) (interface{}, error) {
+		return i.KeyFunc(token)
+	}
+}
+
+func tokenFromMetadata(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	for _, v := range md.Get("authorization") {
+		if len(v) > 7 && strings.EqualFold(v[:7], "bearer ") {
+			return strings.TrimSpace(v[7:]), true
+		}
+	}
+	return "", false
+}
+
+// A ServerStream whose context carries the verified token
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,95 @@
+//go:build grpc
+
+package grpcauth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testKey = []byte("grpcauth test key")
+
+func keyFunc(*jwt.Token) (interface{}, error) { return testKey, nil }
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	valid, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"scope": "orders:read orders:write"}).SignedString(testKey)
+	readOnly, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"scope": "orders:read"}).SignedString(testKey)
+
+	var tests = []struct {
+		name   string
+		auth   string
+		method string
+		code   codes.Code
+	}{
+		{"valid", "Bearer " + valid, "/orders.Orders/Create", codes.OK},
+		{"lowercase scheme", "bearer " + valid, "/orders.Orders/Create", codes.OK},
+		{"missing", "", "/orders.Orders/Create", codes.Unauthenticated},
+		{"invalid", "Bearer " + valid + "x", "/orders.Orders/Create", codes.Unauthenticated},
+		{"insufficient scope", "Bearer " + readOnly, "/orders.Orders/Create", codes.PermissionDenied},
+		{"skipped", "", "/grpc.health.v1.Health/Check", codes.OK},
+	}
+
+	interceptor := New(keyFunc, RequireScopes("orders:write"), WithSkip(func(method string) bool {
+		return method == "/grpc.health.v1.Health/Check"
+	})).UnaryServerInterceptor()
+
+	for _, data := range tests {
+		ctx := context.Background()
+		if data.auth != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", data.auth))
+		}
+		var found bool
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			_, found = middleware.FromContext(ctx)
+			return req, nil
+		}
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: data.method}, handler)
+		if code := status.Code(err); code != data.code {
+			t.Errorf("[%v] Expected code %v, got %v", data.name, data.code, code)
+		}
+		if data.code == codes.OK && data.auth != "" && !found {
+			t.Errorf("[%v] Token missing from handler context", data.name)
+		}
+	}
+}
+
+func TestVerifyContext(t *testing.T) {
+	valid, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(testKey)
+	type ctxKey struct{}
+
+	// The keyfunc sees the call's context
+	var seen interface{}
+	interceptor := New(nil, WithKeyfuncContext(func(ctx context.Context, token *jwt.Token) (interface{}, error) {
+		seen = ctx.Value(ctxKey{})
+		return testKey, nil
+	}))
+	ctx := context.WithValue(context.Background(), ctxKey{}, "call")
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+valid))
+	if _, err := interceptor.Verify(ctx); err != nil || seen != "call" {
+		t.Errorf("Unexpected result %v, keyfunc saw %v", err, seen)
+	}
+
+	// The default parser rejects padded segments, as the HTTP middleware does
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+valid+"="))
+	if _, err := New(keyFunc).Verify(ctx); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected padded token to be rejected, got %v", err)
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	creds := StaticCredentials("abc")
+	md, err := creds.GetRequestMetadata(context.Background())
+	if err != nil || md["authorization"] != "Bearer abc" {
+		t.Errorf("Unexpected metadata: %v %v", md, err)
+	}
+	if !creds.RequireTransportSecurity() {
+		t.Errorf("Expected transport security to be required by default")
+	}
+}
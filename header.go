@@ -0,0 +1,98 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrReservedHeader = errors.New("header parameter is managed by the token")
+)
+
+// Set a header parameter such as kid, x5t, jku or an application specific value.
+// alg is derived from the token's signing method and can't be changed here;
+// setting it to any other value returns ErrReservedHeader.  typ must be
+// changed with SetType, so it isn't overwritten by accident.
+func (t *Token) SetHeader(name string, value interface{}) error {
+	switch name {
+	case "alg":
+		if t.Method == nil || value != t.Method.Alg() {
+			return ErrReservedHeader
+		}
+	case "typ":
+		return ErrReservedHeader
+	}
+
+	if t.Header == nil {
+		t.Header = map[string]interface{}{}
+	}
+	t.Header[name] = value
+	return nil
+}
+
+// Set the typ header, e.g. "at+jwt" for RFC 9068 access tokens
+func (t *Token) SetType(typ string) {
+	if t.Header == nil {
+		t.Header = map[string]interface{}{}
+	}
+	t.Header["typ"] = typ
+}
+
+// Get the complete, signed token with additional header parameters.  The
+// parameters are applied as with SetHeader, but the token itself is not modified.
+func (t *Token) SignedStringWithHeader(key interface{}, header map[string]interface{}) (string, error) {
+	signed := *t
+	signed.Header = make(map[string]interface{}, len(t.Header)+len(header))
+	for k, v := range t.Header {
+		signed.Header[k] = v
+	}
+	for k, v := range header {
+		if err := signed.SetHeader(k, v); err != nil {
+			return "", err
+		}
+	}
+	return signed.SignedString(key)
+}
+
+// Media type values are case-insensitive and the "application/" prefix
+// may be omitted (RFC 7515 sections 4.1.9 and 4.1.10)
+func normalizeMediaType(typ string) string {
+	typ = strings.ToLower(typ)
+	return strings.TrimPrefix(typ, "application/")
+}
+
+// Reports whether the header's cty indicates a nested JWT
+func isNestedToken(header map[string]interface{}) bool {
+	cty, _ := header["cty"].(string)
+	return normalizeMediaType(cty) == "jwt"
+}
+
+// Check header parameters the parser has been configured to require
+func (p *Parser) validateTypeHeader(header map[string]interface{}) error {
+	if p.RequiredType == "" {
+		return nil
+	}
+
+	typ, _ := header["typ"].(string)
+	if normalizeMediaType(typ) != normalizeMediaType(p.RequiredType) {
+		return NewValidationError(fmt.Sprintf("token type %q is invalid, expected %q", typ, p.RequiredType), ValidationErrorType)
+	}
+	return nil
+}
+
+// Check the header of a parsed token before its key is looked up
+func (p *Parser) validateHeader(header map[string]interface{}) error {
+	// Reject tokens with critical extensions we don't understand
+	if err := validateCriticalHeaders(header); err != nil {
+		return err
+	}
+	if p.StrictHeader {
+		if _, err := ParseHeader(header, true); err != nil {
+			return err
+		}
+	}
+
+	// Verify typ header
+	return p.validateTypeHeader(header)
+}
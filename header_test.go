@@ -0,0 +1,61 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestParseNestedTokenRejected(t *testing.T) {
+	token := jwt.New(jwt.SigningMethodHS256)
+	token.Header["cty"] = "JWT"
+	tokenString, _ := token.SignedString(hmacTestKey)
+
+	_, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors != jwt.ValidationErrorMalformed {
+		t.Errorf("Expected malformed error for nested token, got %v", err)
+	}
+}
+
+func TestSetHeader(t *testing.T) {
+	token := jwt.New(jwt.SigningMethodHS256)
+
+	if err := token.SetHeader("kid", "2024-key"); err != nil {
+		t.Errorf("Error setting kid: %v", err)
+	}
+	if err := token.SetHeader("alg", "HS256"); err != nil {
+		t.Errorf("Error setting alg to its current value: %v", err)
+	}
+	if err := token.SetHeader("alg", "none"); err != jwt.ErrReservedHeader {
+		t.Errorf("Expected ErrReservedHeader for alg, got %v", err)
+	}
+	if err := token.SetHeader("typ", "at+jwt"); err != jwt.ErrReservedHeader {
+		t.Errorf("Expected ErrReservedHeader for typ, got %v", err)
+	}
+	token.SetType("at+jwt")
+
+	if token.Header["kid"] != "2024-key" || token.Header["alg"] != "HS256" || token.Header["typ"] != "at+jwt" {
+		t.Errorf("Unexpected header: %v", token.Header)
+	}
+}
+
+func TestSignedStringWithHeader(t *testing.T) {
+	token := jwt.New(jwt.SigningMethodHS256)
+
+	tokenString, err := token.SignedStringWithHeader(hmacTestKey, map[string]interface{}{"kid": "2024-key"})
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+	if _, ok := token.Header["kid"]; ok {
+		t.Errorf("SignedStringWithHeader modified the token")
+	}
+
+	parsed, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+	if err != nil || parsed.Header["kid"] != "2024-key" {
+		t.Errorf("Header not present in signed token: %v %v", parsed.Header, err)
+	}
+
+	if _, err := token.SignedStringWithHeader(hmacTestKey, map[string]interface{}{"alg": "none"}); err != jwt.ErrReservedHeader {
+		t.Errorf("Expected ErrReservedHeader, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package jwt
+
+import (
+	"fmt"
+)
+
+// A typed view of a token's header.  The registered parameters the package
+// uses have fields of their own; any other parameter, such as x5t, jku or an
+// application specific value, is kept in Extra.
+type Header struct {
+	Alg   string   // Signing algorithm
+	Typ   string   // Media type of the token, e.g. "JWT" or "at+jwt"
+	Kid   string   // ID of the key used to sign the token
+	Cty   string   // Media type of the payload, "JWT" for nested tokens
+	X5c   []string // Certificate chain, leaf first, as standard base64 DER
+	Crit  []string // Extensions the recipient must understand
+	Extra map[string]interface{}
+}
+
+// Convert a header map into a Header.  In strict mode, a registered parameter
+// of the wrong type, a missing alg, and a crit header listing extensions that
+// are not supported are errors.  Otherwise malformed registered parameters
+// are kept in Extra, and unsupported extensions can be found with
+// UnsupportedCritical.
+func ParseHeader(header map[string]interface{}, strict bool) (*Header, error) {
+	h := &Header{}
+	for name, value := range header {
+		var ok bool
+		switch name {
+		case "alg":
+			h.Alg, ok = value.(string)
+		case "typ":
+			h.Typ, ok = value.(string)
+		case "kid":
+			h.Kid, ok = value.(string)
+		case "cty":
+			h.Cty, ok = value.(string)
+		case "x5c":
+			h.X5c, ok = headerStrings(value)
+		case "crit":
+			h.Crit, ok = headerStrings(value)
+		default:
+			if h.Extra == nil {
+				h.Extra = map[string]interface{}{}
+			}
+			h.Extra[name] = value
+			continue
+		}
+		if ok {
+			continue
+		}
+		if strict {
+			return nil, NewValidationError(fmt.Sprintf("%v header has an invalid type", name), ValidationErrorMalformed)
+		}
+		if h.Extra == nil {
+			h.Extra = map[string]interface{}{}
+		}
+		h.Extra[name] = value
+	}
+
+	if strict {
+		if h.Alg == "" {
+			return nil, NewValidationError("alg header is missing", ValidationErrorMalformed)
+		}
+		if err := validateCriticalHeaders(header); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// The token's header as a Header, leniently converted.  See ParseHeader.
+func (t *Token) TypedHeader() (*Header, error) {
+	return ParseHeader(t.Header, false)
+}
+
+// Create a new Token with the given header.  alg is always taken from the
+// signing method, and typ defaults to "JWT".
+func NewWithHeader(method SigningMethod, header *Header, claims Claims) *Token {
+	token := NewWithClaims(method, claims)
+	if header != nil {
+		token.Header = header.Map()
+		token.Header["alg"] = method.Alg()
+		if header.Typ == "" {
+			token.Header["typ"] = "JWT"
+		}
+	}
+	return token
+}
+
+// The header as a map, as stored in Token.Header.  Empty fields are omitted;
+// Extra parameters do not override the typed fields.
+func (h *Header) Map() map[string]interface{} {
+	m := make(map[string]interface{}, len(h.Extra)+6)
+	for k, v := range h.Extra {
+		m[k] = v
+	}
+	for name, value := range map[string]string{"alg": h.Alg, "typ": h.Typ, "kid": h.Kid, "cty": h.Cty} {
+		if value != "" {
+			m[name] = value
+		}
+	}
+	if h.X5c != nil {
+		m["x5c"] = h.X5c
+	}
+	if h.Crit != nil {
+		m["crit"] = h.Crit
+	}
+	return m
+}
+
+// The extensions listed in crit that are neither supported by the package
+// nor registered with RegisterCriticalHeader
+func (h *Header) UnsupportedCritical() []string {
+	var names []string
+	for _, name := range h.Crit {
+		if !IsCriticalHeaderSupported(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Converts a header value holding a list of strings, as decoded from JSON or
+// set when creating a token
+func headerStrings(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		s := make([]string, len(v))
+		for i, e := range v {
+			str, ok := e.(string)
+			if !ok {
+				return nil, false
+			}
+			s[i] = str
+		}
+		return s, true
+	}
+	return nil, false
+}
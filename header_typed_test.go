@@ -0,0 +1,89 @@
+package jwt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestParseHeader(t *testing.T) {
+	header := map[string]interface{}{
+		"alg":  "HS256",
+		"typ":  "JWT",
+		"kid":  "2024-key",
+		"x5c":  []interface{}{"MIIB"},
+		"crit": []interface{}{"exp"},
+		"exp":  float64(1500000000),
+	}
+	h, err := jwt.ParseHeader(header, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Alg != "HS256" || h.Typ != "JWT" || h.Kid != "2024-key" || !reflect.DeepEqual(h.X5c, []string{"MIIB"}) {
+		t.Errorf("Unexpected header: %+v", h)
+	}
+	if !reflect.DeepEqual(h.UnsupportedCritical(), []string{"exp"}) || h.Extra["exp"] != float64(1500000000) {
+		t.Errorf("Unexpected extensions: %v %v", h.UnsupportedCritical(), h.Extra)
+	}
+	if _, err := jwt.ParseHeader(header, true); err == nil {
+		t.Errorf("Expected an unsupported critical header to be rejected")
+	}
+
+	// Malformed registered parameters are kept aside unless strict
+	header = map[string]interface{}{"alg": "HS256", "kid": float64(7)}
+	if h, err := jwt.ParseHeader(header, false); err != nil || h.Kid != "" || h.Extra["kid"] != float64(7) {
+		t.Errorf("Unexpected lenient result: %+v %v", h, err)
+	}
+	if _, err := jwt.ParseHeader(header, true); err == nil {
+		t.Errorf("Expected a numeric kid to be rejected")
+	}
+	if _, err := jwt.ParseHeader(map[string]interface{}{"typ": "JWT"}, true); err == nil {
+		t.Errorf("Expected a missing alg to be rejected")
+	}
+}
+
+func TestNewWithHeader(t *testing.T) {
+	token := jwt.NewWithHeader(jwt.SigningMethodHS256, &jwt.Header{Alg: "none", Kid: "2024-key", Extra: map[string]interface{}{"kid": "other", "app": "x"}}, jwt.MapClaims{})
+	tokenString, err := token.SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	parsed, err := jwt.NewParser(jwt.WithStrictHeader()).Parse(tokenString, keyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, _ := parsed.TypedHeader()
+	if h.Alg != "HS256" || h.Typ != "JWT" || h.Kid != "2024-key" || h.Extra["app"] != "x" {
+		t.Errorf("Unexpected header: %+v", h)
+	}
+}
+
+func TestParseStrictHeader(t *testing.T) {
+	token := jwt.New(jwt.SigningMethodHS256)
+	token.Header["kid"] = 7
+	tokenString, _ := token.SignedString(hmacTestKey)
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	if _, err := jwt.Parse(tokenString, keyFunc); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	_, err := jwt.NewParser(jwt.WithStrictHeader()).Parse(tokenString, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors != jwt.ValidationErrorMalformed {
+		t.Errorf("Expected a malformed error, got %v", err)
+	}
+}
+
+func TestParseIntoStrictHeader(t *testing.T) {
+	token := jwt.New(jwt.SigningMethodHS256)
+	token.Header["x5c"] = "not a list"
+	tokenString, _ := token.SignedString(hmacTestKey)
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	err := jwt.NewParser(jwt.WithStrictHeader()).ParseInto(&jwt.Token{}, tokenString, jwt.MapClaims{}, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors != jwt.ValidationErrorMalformed {
+		t.Errorf("Expected a malformed error, got %v", err)
+	}
+}
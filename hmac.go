@@ -19,8 +19,15 @@ var (
 	SigningMethodHS384  *SigningMethodHMAC
 	SigningMethodHS512  *SigningMethodHMAC
 	ErrSignatureInvalid = errors.New("signature is invalid")
+	ErrHMACKeyTooShort  = errors.New("key is shorter than the hash output size")
 )
 
+// StrictHMACKeyLength enables the key size requirement of RFC 7518 section 3.2:
+// when set, signing with a key shorter than the hash output (32 bytes for HS256,
+// 48 for HS384, 64 for HS512) fails with ErrHMACKeyTooShort.
+// It is off by default for compatibility with existing short secrets.
+var StrictHMACKeyLength = false
+
 func init() {
 	// HS256
 	SigningMethodHS256 = &SigningMethodHMAC{"HS256", crypto.SHA256}
@@ -85,6 +92,10 @@ func (m *SigningMethodHMAC) Sign(signingString string, key interface{}) (string,
 			return "", ErrHashUnavailable
 		}
 
+		if StrictHMACKeyLength && len(keyBytes) < m.Hash.Size() {
+			return "", ErrHMACKeyTooShort
+		}
+
 		hasher := hmac.New(m.Hash.New, keyBytes)
 		hasher.Write([]byte(signingString))
 
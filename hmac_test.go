@@ -78,6 +78,28 @@ func TestHMACSign(t *testing.T) {
 	}
 }
 
+func TestHMACStrictKeyLength(t *testing.T) {
+	jwt.StrictHMACKeyLength = true
+	defer func() { jwt.StrictHMACKeyLength = false }()
+
+	var tests = []struct {
+		method *jwt.SigningMethodHMAC
+		key    []byte
+		err    error
+	}{
+		{jwt.SigningMethodHS256, make([]byte, 31), jwt.ErrHMACKeyTooShort},
+		{jwt.SigningMethodHS256, make([]byte, 32), nil},
+		{jwt.SigningMethodHS384, make([]byte, 47), jwt.ErrHMACKeyTooShort},
+		{jwt.SigningMethodHS512, hmacTestKey, nil},
+	}
+
+	for _, data := range tests {
+		if _, err := data.method.Sign("foo.bar", data.key); err != data.err {
+			t.Errorf("[%v] Key of %v bytes: expected %v, got %v", data.method.Alg(), len(data.key), data.err, err)
+		}
+	}
+}
+
 func BenchmarkHS256Signing(b *testing.B) {
 	benchmarkSigning(b, jwt.SigningMethodHS256, hmacTestKey)
 }
@@ -0,0 +1,87 @@
+package introspection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Largest introspection response Client will read
+const maxResponseSize = 1 << 20
+
+// Calls an introspection endpoint, authenticating with HTTP Basic
+// authentication if ClientID is set
+type Client struct {
+	Endpoint     string
+	ClientID     string
+	ClientSecret string
+	HTTPClient   *http.Client // Defaults to http.DefaultClient
+}
+
+// Ask the endpoint about tokenString.  An inactive token is not an error;
+// check Response.Active, or use Verify.
+func (c *Client) Introspect(ctx context.Context, tokenString string) (*Response, error) {
+	form := url.Values{"token": {tokenString}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequest("POST", c.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil, fmt.Errorf("introspecting token: unexpected status %v", resp.Status)
+	}
+	r := &Response{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(r); err != nil {
+		return nil, fmt.Errorf("decoding introspection response: %v", err)
+	}
+	return r, nil
+}
+
+// Introspect tokenString and return it as a token whose claims are the
+// *Response.  Inactive tokens fail with a *jwt.ValidationError wrapping
+// ErrInactiveToken.  The token's Method is nil, as no signature was checked
+// locally.
+func (c *Client) Verify(ctx context.Context, tokenString string) (*jwt.Token, error) {
+	r, err := c.Introspect(ctx, tokenString)
+	if err != nil {
+		return nil, &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorUnverifiable}
+	}
+	token := &jwt.Token{Raw: tokenString, Header: map[string]interface{}{}, Claims: r}
+	if err := r.Valid(); err != nil {
+		return token, err
+	}
+	token.Valid = true
+	return token, nil
+}
+
+// Validate JWTs locally with parser and keyFunc, and introspect anything
+// else, such as opaque tokens
+func (c *Client) ParseOrIntrospect(ctx context.Context, parser *jwt.Parser, tokenString string, keyFunc jwt.Keyfunc) (*jwt.Token, error) {
+	if _, err := jwt.SplitToken(tokenString); err == nil {
+		return parser.Parse(tokenString, keyFunc)
+	}
+	return c.Verify(ctx, tokenString)
+}
@@ -0,0 +1,11 @@
+// OAuth 2.0 Token Introspection (RFC 7662).
+//
+// Client asks an authorization server whether a token is active, for opaque
+// tokens or as a fallback where local validation is not possible:
+//
+//	client := &introspection.Client{Endpoint: "https://auth.example.com/introspect", ClientID: "api", ClientSecret: secret}
+//	token, err := client.Verify(ctx, tokenString)
+//
+// Handler is the server side, answering introspection requests for JWTs
+// this service issued.
+package introspection
@@ -0,0 +1,50 @@
+package introspection
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Answers introspection requests for JWTs verified by KeyFunc.  RFC 7662
+// requires the endpoint to be protected, so requests are rejected unless
+// Authorize accepts them.
+type Handler struct {
+	Parser    *jwt.Parser // Defaults to jwt.NewParser()
+	KeyFunc   jwt.Keyfunc
+	Authorize func(r *http.Request) bool // Authenticates the caller, e.g. a resource server's credentials
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Authorize == nil || !h.Authorize(r) {
+		w.Header().Set("WWW-Authenticate", "Basic")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	tokenString := r.PostFormValue("token")
+	if tokenString == "" {
+		http.Error(w, "missing token parameter", http.StatusBadRequest)
+		return
+	}
+
+	parser := h.Parser
+	if parser == nil {
+		parser = jwt.NewParser()
+	}
+	resp := &Response{}
+	if token, err := parser.Parse(tokenString, h.KeyFunc); err == nil {
+		if resp, err = NewResponse(token); err != nil {
+			resp = &Response{}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,90 @@
+package introspection
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var testKey = []byte("secret")
+
+func TestIntrospection(t *testing.T) {
+	handler := &Handler{
+		KeyFunc: func(*jwt.Token) (interface{}, error) { return testKey, nil },
+		Authorize: func(r *http.Request) bool {
+			id, secret, ok := r.BasicAuth()
+			return ok && id == "api" && secret == "s3cret"
+		},
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	exp := time.Now().Add(time.Hour).Unix()
+	valid, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user", "aud": "api", "exp": exp, "scope": "orders:read", "email": "user@example.com",
+	}).SignedString(testKey)
+	expired, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}).SignedString(testKey)
+
+	client := &Client{Endpoint: server.URL, ClientID: "api", ClientSecret: "s3cret"}
+	var tests = []struct {
+		name   string
+		token  string
+		active bool
+	}{
+		{"valid", valid, true},
+		{"expired", expired, false},
+		{"opaque", "2YotnFZFEjr1zCsicMWpAA", false},
+	}
+	for _, data := range tests {
+		resp, err := client.Introspect(context.Background(), data.token)
+		if err != nil {
+			t.Errorf("[%v] Error introspecting token: %v", data.name, err)
+			continue
+		}
+		if resp.Active != data.active {
+			t.Errorf("[%v] Expected active %v, got %v", data.name, data.active, resp.Active)
+		}
+		if data.active && (resp.Subject != "user" || resp.ExpiresAt != exp || resp.Scope != "orders:read" || !resp.Audience.Contains("api")) {
+			t.Errorf("[%v] Unexpected response: %+v", data.name, resp)
+		}
+	}
+
+	token, err := client.Verify(context.Background(), valid)
+	if err != nil || !token.Valid || !jwt.HasScope(token, "orders:read") {
+		t.Errorf("Unexpected Verify result: %v %v", token, err)
+	}
+	if _, err := client.Verify(context.Background(), expired); err == nil {
+		t.Errorf("Verify accepted an inactive token")
+	}
+
+	// Callers must authenticate
+	if _, err := (&Client{Endpoint: server.URL}).Introspect(context.Background(), valid); err == nil {
+		t.Errorf("Unauthenticated introspection succeeded")
+	}
+}
+
+func TestParseOrIntrospect(t *testing.T) {
+	var introspected int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		introspected++
+		w.Write([]byte(`{"active":true,"sub":"user"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{Endpoint: server.URL}
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	local, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(testKey)
+
+	for _, tokenString := range []string{local, "2YotnFZFEjr1zCsicMWpAA"} {
+		if token, err := client.ParseOrIntrospect(context.Background(), jwt.NewParser(), tokenString, keyFunc); err != nil || !token.Valid {
+			t.Errorf("Unexpected result for %v: %v", tokenString, err)
+		}
+	}
+	if introspected != 1 {
+		t.Errorf("Expected only the opaque token to be introspected, got %v calls", introspected)
+	}
+}
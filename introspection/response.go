@@ -0,0 +1,80 @@
+package introspection
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var ErrInactiveToken = errors.New("token is not active")
+
+// An introspection response, as described in RFC 7662 section 2.2.  It
+// implements jwt.Claims, so it can stand in for a token's claims.
+type Response struct {
+	Active    bool             `json:"active"`
+	Scope     string           `json:"scope,omitempty"`
+	ClientID  string           `json:"client_id,omitempty"`
+	Username  string           `json:"username,omitempty"`
+	TokenType string           `json:"token_type,omitempty"`
+	ExpiresAt int64            `json:"exp,omitempty"`
+	IssuedAt  int64            `json:"iat,omitempty"`
+	NotBefore int64            `json:"nbf,omitempty"`
+	Subject   string           `json:"sub,omitempty"`
+	Audience  jwt.ClaimStrings `json:"aud,omitempty"`
+	Issuer    string           `json:"iss,omitempty"`
+	Id        string           `json:"jti,omitempty"`
+}
+
+// Fails for inactive tokens, and checks exp and nbf in case the response
+// was cached
+func (r *Response) Valid() error {
+	if !r.Active {
+		return &jwt.ValidationError{Inner: ErrInactiveToken, Errors: jwt.ValidationErrorClaimsInvalid}
+	}
+	return jwt.StandardClaims{ExpiresAt: r.ExpiresAt, NotBefore: r.NotBefore}.Valid()
+}
+
+// Build a response for the verified token, from its registered claims and
+// scope.  Other claims are not disclosed.  Tokens that are not valid get an
+// inactive response.
+func NewResponse(token *jwt.Token) (*Response, error) {
+	if !token.Valid {
+		return &Response{}, nil
+	}
+	data := token.RawClaims
+	if data == nil {
+		var err error
+		if data, err = json.Marshal(token.Claims); err != nil {
+			return nil, err
+		}
+	}
+	var claims struct {
+		ClientID  string           `json:"client_id"`
+		Username  string           `json:"username"`
+		ExpiresAt float64          `json:"exp"`
+		IssuedAt  float64          `json:"iat"`
+		NotBefore float64          `json:"nbf"`
+		Subject   string           `json:"sub"`
+		Audience  jwt.ClaimStrings `json:"aud"`
+		Issuer    string           `json:"iss"`
+		Id        string           `json:"jti"`
+	}
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, err
+	}
+	return &Response{
+		Active:    true,
+		Scope:     jwt.FormatScope(jwt.Scopes(token.Claims)),
+		ClientID:  claims.ClientID,
+		Username:  claims.Username,
+		TokenType: "Bearer",
+		ExpiresAt: int64(claims.ExpiresAt),
+		IssuedAt:  int64(claims.IssuedAt),
+		NotBefore: int64(claims.NotBefore),
+		Subject:   claims.Subject,
+		Audience:  claims.Audience,
+		Issuer:    claims.Issuer,
+		Id:        claims.Id,
+	}, nil
+}
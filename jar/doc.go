@@ -0,0 +1,20 @@
+// JWT-Secured Authorization Requests (JAR, RFC 9101) and JWT-secured
+// authorization responses (JARM), as FAPI profiles require.
+//
+// Clients sign the authorization request parameters into a request object
+// and send it by value:
+//
+//	object, err := jar.NewRequestObject(jwt.SigningMethodPS256, key, &jar.RequestClaims{
+//		ClientID:     "client",
+//		Audience:     jwt.ClaimStrings{"https://auth.example.com"},
+//		ResponseType: "code",
+//		RedirectURI:  "https://client.example.com/callback",
+//		Scope:        "openid accounts",
+//		State:        state,
+//	})
+//	redirect := jar.AuthorizationURL("https://auth.example.com/authorize", "client", object)
+//
+// and check the signed response the authorization server returns with
+// VerifyResponse.  Authorization servers validate request objects with a
+// Verifier.
+package jar
@@ -0,0 +1,113 @@
+package jar
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+var (
+	privateKey = test.LoadRSAPrivateKeyFromDisk("../test/sample_key")
+	publicKey  = test.LoadRSAPublicKeyFromDisk("../test/sample_key.pub")
+)
+
+func keyFunc(*jwt.Token) (interface{}, error) { return publicKey, nil }
+
+func TestRequestObject(t *testing.T) {
+	verifier := &Verifier{Issuer: "https://auth.example.com", KeyFunc: keyFunc}
+	newClaims := func() *RequestClaims {
+		return &RequestClaims{
+			ClientID:     "client",
+			Audience:     jwt.ClaimStrings{"https://auth.example.com"},
+			ResponseType: "code",
+			RedirectURI:  "https://client.example.com/callback",
+			State:        "xyz",
+		}
+	}
+	now := time.Now()
+
+	var tests = []struct {
+		name     string
+		claims   func() *RequestClaims
+		method   jwt.SigningMethod
+		key      interface{}
+		clientID string
+		valid    bool
+	}{
+		{"valid", newClaims, jwt.SigningMethodPS256, privateKey, "client", true},
+		{"client_id mismatch", newClaims, jwt.SigningMethodPS256, privateKey, "other", false},
+		{"iss mismatch", func() *RequestClaims { c := newClaims(); c.Issuer = "other"; return c }, jwt.SigningMethodPS256, privateKey, "client", false},
+		{"wrong audience", func() *RequestClaims {
+			c := newClaims()
+			c.Audience = jwt.ClaimStrings{"https://other.example.com"}
+			return c
+		}, jwt.SigningMethodPS256, privateKey, "client", false},
+		{"too long lived", func() *RequestClaims {
+			c := newClaims()
+			c.NotBefore = now.Unix()
+			c.ExpiresAt = now.Add(2 * time.Hour).Unix()
+			return c
+		}, jwt.SigningMethodPS256, privateKey, "client", false},
+		{"expired", func() *RequestClaims {
+			c := newClaims()
+			c.NotBefore = now.Add(-time.Hour).Unix()
+			c.ExpiresAt = now.Add(-time.Minute).Unix()
+			return c
+		}, jwt.SigningMethodPS256, privateKey, "client", false},
+		{"hmac", newClaims, jwt.SigningMethodHS256, []byte("secret"), "client", false},
+	}
+
+	for _, data := range tests {
+		object, err := NewRequestObject(data.method, data.key, data.claims())
+		if err != nil {
+			t.Fatalf("[%v] Error signing request object: %v", data.name, err)
+		}
+		claims, err := verifier.Verify(object, data.clientID)
+		if (err == nil) != data.valid {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		}
+		if data.valid && (claims.RedirectURI != "https://client.example.com/callback" || claims.Id == "") {
+			t.Errorf("[%v] Unexpected claims %+v", data.name, claims)
+		}
+	}
+
+	object, _ := NewRequestObject(jwt.SigningMethodPS256, privateKey, newClaims())
+	u, _ := url.Parse(AuthorizationURL("https://auth.example.com/authorize?prompt=login", "client", object))
+	if q := u.Query(); q.Get("client_id") != "client" || q.Get("request") != object || q.Get("prompt") != "login" {
+		t.Errorf("Unexpected authorization URL %v", u)
+	}
+	token, _, _ := new(jwt.Parser).ParseUnverified(object, &RequestClaims{})
+	if token.Header["typ"] != RequestObjectType {
+		t.Errorf("Unexpected typ %v", token.Header["typ"])
+	}
+}
+
+func TestVerifyResponse(t *testing.T) {
+	exp := time.Now().Add(time.Minute).Unix()
+	var tests = []struct {
+		name   string
+		claims *ResponseClaims
+		valid  bool
+	}{
+		{"valid", &ResponseClaims{Issuer: "https://auth.example.com", Audience: jwt.ClaimStrings{"client"}, ExpiresAt: exp, Code: "abc", State: "xyz"}, true},
+		{"wrong issuer", &ResponseClaims{Issuer: "https://other.example.com", Audience: jwt.ClaimStrings{"client"}, ExpiresAt: exp, Code: "abc", State: "xyz"}, false},
+		{"wrong audience", &ResponseClaims{Issuer: "https://auth.example.com", Audience: jwt.ClaimStrings{"other"}, ExpiresAt: exp, Code: "abc", State: "xyz"}, false},
+		{"wrong state", &ResponseClaims{Issuer: "https://auth.example.com", Audience: jwt.ClaimStrings{"client"}, ExpiresAt: exp, Code: "abc", State: "abc"}, false},
+		{"no exp", &ResponseClaims{Issuer: "https://auth.example.com", Audience: jwt.ClaimStrings{"client"}, Code: "abc", State: "xyz"}, false},
+		{"error response", &ResponseClaims{Issuer: "https://auth.example.com", Audience: jwt.ClaimStrings{"client"}, ExpiresAt: exp, Error: "access_denied", State: "xyz"}, false},
+	}
+
+	for _, data := range tests {
+		response, _ := jwt.NewWithClaims(jwt.SigningMethodRS256, data.claims).SignedString(privateKey)
+		claims, err := VerifyResponse(response, keyFunc, "https://auth.example.com", "client", "xyz")
+		if (err == nil) != data.valid {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		}
+		if data.valid && claims.Code != "abc" {
+			t.Errorf("[%v] Unexpected code %q", data.name, claims.Code)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package jar
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// The typ header of a request object
+const RequestObjectType = "oauth-authz-req+jwt"
+
+// FAPI limits request objects to 60 minutes between nbf and exp
+const MaxLifetime = 60 * time.Minute
+
+// Errors
+var (
+	ErrClientIDMismatch = errors.New("request object client_id does not match the request")
+	ErrRequestLifetime  = errors.New("request object must have nbf and exp no more than 60 minutes apart")
+	ErrStateMismatch    = errors.New("authorization response state does not match the request")
+)
+
+// Asymmetric methods only; FAPI forbids shared secrets and none
+var defaultValidMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// Claims of a request object: the authorization request parameters, plus the
+// JWT claims RFC 9101 and FAPI require
+type RequestClaims struct {
+	Issuer    string           `json:"iss"` // The client ID
+	Audience  jwt.ClaimStrings `json:"aud"` // The authorization server's issuer identifier
+	ExpiresAt int64            `json:"exp"`
+	NotBefore int64            `json:"nbf"`
+	IssuedAt  int64            `json:"iat,omitempty"`
+	Id        string           `json:"jti,omitempty"`
+
+	ClientID            string `json:"client_id"`
+	ResponseType        string `json:"response_type"`
+	RedirectURI         string `json:"redirect_uri,omitempty"`
+	Scope               string `json:"scope,omitempty"`
+	State               string `json:"state,omitempty"`
+	Nonce               string `json:"nonce,omitempty"`
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
+	ResponseMode        string `json:"response_mode,omitempty"`
+}
+
+// Validates the time based claims.  Unlike jwt.StandardClaims, exp and nbf
+// are required, and may be at most MaxLifetime apart.
+func (c *RequestClaims) Valid() error {
+	if c.ExpiresAt == 0 || c.NotBefore == 0 || c.ExpiresAt-c.NotBefore > int64(MaxLifetime/time.Second) {
+		return &jwt.ValidationError{Inner: ErrRequestLifetime, Errors: jwt.ValidationErrorClaimsInvalid}
+	}
+	return jwt.StandardClaims{ExpiresAt: c.ExpiresAt, NotBefore: c.NotBefore, IssuedAt: c.IssuedAt}.Valid()
+}
+
+// Sign a request object.  iss defaults to ClientID; if unset, iat and nbf
+// default to now, exp to five minutes from now, and jti to a random value.
+func NewRequestObject(method jwt.SigningMethod, key interface{}, claims *RequestClaims) (string, error) {
+	now := jwt.TimeFunc()
+	if claims.Issuer == "" {
+		claims.Issuer = claims.ClientID
+	}
+	if claims.IssuedAt == 0 {
+		claims.IssuedAt = now.Unix()
+	}
+	if claims.NotBefore == 0 {
+		claims.NotBefore = now.Unix()
+	}
+	if claims.ExpiresAt == 0 {
+		claims.ExpiresAt = now.Add(5 * time.Minute).Unix()
+	}
+	if claims.Id == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return "", err
+		}
+		claims.Id = jwt.EncodeSegment(id)
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["typ"] = RequestObjectType
+	return token.SignedString(key)
+}
+
+// The authorization endpoint URL sending requestObject by value.  RFC 9101
+// requires client_id alongside the request object.
+func AuthorizationURL(endpoint, clientID, requestObject string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	q := u.Query()
+	q.Set("client_id", clientID)
+	q.Set("request", requestObject)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Validates request objects at the authorization server
+type Verifier struct {
+	Issuer       string      // The authorization server's issuer identifier, which must be in aud
+	KeyFunc      jwt.Keyfunc // Returns the key of the client named in iss
+	ValidMethods []string    // Defaults to the RSA, RSA-PSS and ECDSA methods
+}
+
+// Verify requestObject, sent with the client_id parameter clientID
+func (v *Verifier) Verify(requestObject, clientID string) (*RequestClaims, error) {
+	methods := v.ValidMethods
+	if methods == nil {
+		methods = defaultValidMethods
+	}
+	claims := &RequestClaims{}
+	if _, err := jwt.NewParser(jwt.WithValidMethods(methods)).ParseWithClaims(requestObject, claims, v.KeyFunc); err != nil {
+		return nil, err
+	}
+	if claims.ClientID != clientID || claims.Issuer != clientID {
+		return nil, &jwt.ValidationError{Inner: ErrClientIDMismatch, Errors: jwt.ValidationErrorIssuer}
+	}
+	if !claims.Audience.Verify(v.Issuer, true) {
+		return nil, jwt.NewValidationError("request object was not issued for "+v.Issuer, jwt.ValidationErrorAudience)
+	}
+	return claims, nil
+}
@@ -0,0 +1,51 @@
+package jar
+
+import (
+	"errors"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Claims of a JARM authorization response
+type ResponseClaims struct {
+	Issuer    string           `json:"iss"`
+	Audience  jwt.ClaimStrings `json:"aud"`
+	ExpiresAt int64            `json:"exp"`
+
+	Code             string `json:"code,omitempty"`
+	State            string `json:"state,omitempty"`
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// Validates exp, which is required
+func (c *ResponseClaims) Valid() error {
+	if c.ExpiresAt == 0 {
+		return jwt.NewValidationError("authorization response must have an exp claim", jwt.ValidationErrorExpired)
+	}
+	return jwt.StandardClaims{ExpiresAt: c.ExpiresAt}.Valid()
+}
+
+// Verify the response parameter of a JARM authorization response: it must be
+// signed by the authorization server issuer, for clientID, and carry the
+// state sent in the request.  An error response from the server is returned
+// as an error once the response is verified.
+func VerifyResponse(response string, keyFunc jwt.Keyfunc, issuer, clientID, state string) (*ResponseClaims, error) {
+	claims := &ResponseClaims{}
+	if _, err := jwt.NewParser(jwt.WithValidMethods(defaultValidMethods)).ParseWithClaims(response, claims, keyFunc); err != nil {
+		return nil, err
+	}
+	if claims.Issuer != issuer {
+		return nil, jwt.NewValidationError("authorization response was not issued by "+issuer, jwt.ValidationErrorIssuer)
+	}
+	if !claims.Audience.Verify(clientID, true) {
+		return nil, jwt.NewValidationError("authorization response was not issued for "+clientID, jwt.ValidationErrorAudience)
+	}
+	if claims.State != state {
+		return nil, ErrStateMismatch
+	}
+	if claims.Error != "" {
+		return claims, errors.New("authorization failed: " + claims.Error + ": " + claims.ErrorDescription)
+	}
+	return claims, nil
+}
@@ -0,0 +1,54 @@
+package jwt
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// A JSON implementation used to encode and decode token headers and claims.
+// Implement it to plug in an alternative library such as jsoniter or go-json;
+// their functions and decoders already have these signatures.
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	NewDecoder(r io.Reader) JSONDecoder
+}
+
+// A streaming JSON decoder, as returned by JSONCodec.NewDecoder
+type JSONDecoder interface {
+	UseNumber()
+	Decode(v interface{}) error
+}
+
+// The codec used by Tokens and Parsers that don't set their own.  Defaults to
+// encoding/json.
+var DefaultJSONCodec JSONCodec = StdJSONCodec{}
+
+// JSONCodec backed by encoding/json
+type StdJSONCodec struct{}
+
+func (StdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (StdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (StdJSONCodec) NewDecoder(r io.Reader) JSONDecoder {
+	return json.NewDecoder(r)
+}
+
+func (t *Token) jsonCodec() JSONCodec {
+	if t.JSONCodec != nil {
+		return t.JSONCodec
+	}
+	return DefaultJSONCodec
+}
+
+func (p *Parser) jsonCodec() JSONCodec {
+	if p.JSONCodec != nil {
+		return p.JSONCodec
+	}
+	return DefaultJSONCodec
+}
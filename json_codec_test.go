@@ -0,0 +1,58 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Wraps encoding/json, counting calls
+type countingCodec struct {
+	marshals, decodes int
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.decodes++
+	return json.Unmarshal(data, v)
+}
+
+func (c *countingCodec) NewDecoder(r io.Reader) jwt.JSONDecoder {
+	c.decodes++
+	return json.NewDecoder(r)
+}
+
+func TestJSONCodec(t *testing.T) {
+	codec := &countingCodec{}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"})
+	token.JSONCodec = codec
+	tokenString, err := token.SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+	if codec.marshals != 2 {
+		t.Errorf("Expected header and claims to be marshaled with codec, got %v calls", codec.marshals)
+	}
+
+	parser := jwt.NewParser(jwt.WithJSONCodec(codec))
+	if _, err := parser.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }); err != nil {
+		t.Fatalf("Error parsing token: %v", err)
+	}
+	if codec.decodes != 2 {
+		t.Errorf("Expected header and claims to be decoded with codec, got %v calls", codec.decodes)
+	}
+
+	// Other tokens and parsers are unaffected
+	codec.marshals = 0
+	jwt.New(jwt.SigningMethodHS256).SignedString(hmacTestKey)
+	if codec.marshals != 0 {
+		t.Errorf("Codec used by another token")
+	}
+}
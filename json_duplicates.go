@@ -0,0 +1,62 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Reports an error if any JSON object in data, at any depth, repeats a key.
+// encoding/json silently keeps the last value, so a token could otherwise
+// carry a claim that other implementations read differently.  Nesting is
+// limited to the parser's MaxJSONDepth, or defaultMaxJSONDepth if unset, so
+// hostile input can't exhaust the stack.
+func (p *Parser) checkDuplicateKeys(data []byte) error {
+	maxDepth := p.MaxJSONDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxJSONDepth
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return checkDuplicateKeysValue(dec, 0, maxDepth)
+}
+
+func checkDuplicateKeysValue(dec *json.Decoder, depth, maxDepth int) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == json.Delim('{') || tok == json.Delim('[') {
+		if depth++; depth > maxDepth {
+			return fmt.Errorf("JSON is nested deeper than %v levels", maxDepth)
+		}
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		keys := make(map[string]bool)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := tok.(string)
+			if keys[key] {
+				return fmt.Errorf("duplicate key %q", key)
+			}
+			keys[key] = true
+			if err := checkDuplicateKeysValue(dec, depth, maxDepth); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	case json.Delim('['):
+		for dec.More() {
+			if err := checkDuplicateKeysValue(dec, depth, maxDepth); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	}
+	return err
+}
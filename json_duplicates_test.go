@@ -0,0 +1,59 @@
+package jwt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestRejectDuplicateKeys(t *testing.T) {
+	var tests = []struct {
+		name   string
+		header string
+		claims string
+		valid  bool
+	}{
+		{"no duplicates", `{"alg":"HS256"}`, `{"sub":"user","nested":{"sub":"other"},"list":[{"a":1},{"a":2}]}`, true},
+		{"duplicate claim", `{"alg":"HS256"}`, `{"sub":"user","sub":"admin"}`, false},
+		{"escaped duplicate claim", `{"alg":"HS256"}`, `{"sub":"user","s\u0075b":"admin"}`, false},
+		{"nested duplicate", `{"alg":"HS256"}`, `{"roles":{"admin":false,"admin":true}}`, false},
+		{"duplicate header", `{"alg":"HS256","alg":"none"}`, `{"sub":"user"}`, false},
+	}
+
+	parser := jwt.NewParser(jwt.WithRejectDuplicateKeys())
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	for _, data := range tests {
+		signingString := jwt.EncodeSegment([]byte(data.header)) + "." + jwt.EncodeSegment([]byte(data.claims))
+		sig, _ := jwt.SigningMethodHS256.Sign(signingString, hmacTestKey)
+		tokenString := signingString + "." + sig
+
+		_, err := parser.Parse(tokenString, keyFunc)
+		if data.valid && err != nil {
+			t.Errorf("[%v] Error while verifying token: %v", data.name, err)
+		}
+		if !data.valid {
+			if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorMalformed == 0 {
+				t.Errorf("[%v] Expected malformed error, got %v", data.name, err)
+			}
+			if err := parser.ParseInto(&jwt.Token{}, tokenString, jwt.MapClaims{}, keyFunc); err == nil {
+				t.Errorf("[%v] ParseInto accepted duplicate keys", data.name)
+			}
+		}
+	}
+}
+
+func TestRejectDuplicateKeysDepth(t *testing.T) {
+	deep := `{"sub":"user","nested":` + strings.Repeat("[", 10000) + strings.Repeat("]", 10000) + `}`
+	signingString := jwt.EncodeSegment([]byte(`{"alg":"HS256"}`)) + "." + jwt.EncodeSegment([]byte(deep))
+	sig, _ := jwt.SigningMethodHS256.Sign(signingString, hmacTestKey)
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	// Without MaxJSONDepth the duplicate key check applies its own limit
+	parser := jwt.NewParser(jwt.WithRejectDuplicateKeys())
+	_, err := parser.Parse(signingString+"."+sig, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorMalformed == 0 {
+		t.Errorf("Expected malformed error, got %v", err)
+	}
+}
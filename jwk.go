@@ -0,0 +1,198 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrJWKUnsupportedKey = errors.New("key type cannot be represented as a public JWK")
+	ErrJWKInvalid        = errors.New("JWK is invalid or has an unsupported kty/crv")
+)
+
+// A public key in JSON Web Key format, as described in RFC 7517.  Only RSA and
+// EC public keys are supported; symmetric keys are never published.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// A JWK Set document, as served from /.well-known/jwks.json
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// Convert a public key to a JWK.  Private keys are accepted and converted to
+// their public half.
+func NewJSONWebKey(key interface{}) (*JSONWebKey, error) {
+	if signer, ok := key.(crypto.Signer); ok {
+		key = signer.Public()
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return &JSONWebKey{
+			Kty: "RSA",
+			N:   EncodeSegment(k.N.Bytes()),
+			E:   EncodeSegment(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		crv := k.Curve.Params().Name
+		switch crv {
+		case "P-256", "P-384", "P-521":
+		default:
+			return nil, ErrJWKUnsupportedKey
+		}
+		return &JSONWebKey{
+			Kty: "EC",
+			Crv: crv,
+			X:   EncodeSegment(padBytes(k.X.Bytes(), size)),
+			Y:   EncodeSegment(padBytes(k.Y.Bytes(), size)),
+		}, nil
+	}
+	return nil, ErrJWKUnsupportedKey
+}
+
+// Returns the *rsa.PublicKey or *ecdsa.PublicKey described by the JWK
+func (k *JSONWebKey) PublicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, ErrJWKInvalid
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, ErrJWKInvalid
+		}
+		x, err := decodeJWKInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, ErrJWKInvalid
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, ErrJWKInvalid
+}
+
+// The RFC 7638 thumbprint: a SHA-256 hash of the key's required members.
+// Encode it with EncodeSegment for use as a kid or a cnf jkt value.
+func (k *JSONWebKey) Thumbprint() ([]byte, error) {
+	var members map[string]string
+	switch k.Kty {
+	case "RSA":
+		members = map[string]string{"e": k.E, "kty": k.Kty, "n": k.N}
+	case "EC":
+		members = map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X, "y": k.Y}
+	default:
+		return nil, ErrJWKInvalid
+	}
+	for _, v := range members {
+		if v == "" {
+			return nil, ErrJWKInvalid
+		}
+	}
+	// encoding/json sorts map keys and adds no whitespace, as RFC 7638 requires
+	data, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// Returns the public key wrapped in a SigningKey.  If the JWK has an alg, the
+// key may only be used with that signing method.
+func (k *JSONWebKey) SigningKey() (*SigningKey, error) {
+	key, err := k.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	sk := &SigningKey{Key: key, ID: k.Kid}
+	if k.Alg != "" {
+		if sk.Method = GetSigningMethod(k.Alg); sk.Method == nil {
+			return nil, ErrJWKInvalid
+		}
+	}
+	return sk, nil
+}
+
+// Returns the key with the given kid, or nil
+func (s *JSONWebKeySet) Key(kid string) *JSONWebKey {
+	for i := range s.Keys {
+		if s.Keys[i].Kid == kid {
+			return &s.Keys[i]
+		}
+	}
+	return nil
+}
+
+// A Keyfunc that returns the key named by the token's kid header
+func (s *JSONWebKeySet) Keyfunc(token *Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	jwk := s.Key(kid)
+	if jwk == nil {
+		return nil, ErrUnknownKeyID
+	}
+	return jwk.SigningKey()
+}
+
+func decodeJWKInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, ErrJWKInvalid
+	}
+	b, err := DecodeSegment(s)
+	if err != nil {
+		return nil, ErrJWKInvalid
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+	return padded
+}
@@ -0,0 +1,69 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestJSONWebKeyRoundTrip(t *testing.T) {
+	ecBytes, _ := ioutil.ReadFile("test/ec512-public.pem")
+	ecKey, err := jwt.ParseECPublicKeyFromPEM(ecBytes)
+	if err != nil {
+		t.Fatalf("Error loading EC key: %v", err)
+	}
+
+	for _, key := range []interface{}{jwtTestDefaultKey, ecKey} {
+		jwk, err := jwt.NewJSONWebKey(key)
+		if err != nil {
+			t.Fatalf("Error converting %T: %v", key, err)
+		}
+		data, _ := json.Marshal(jwk)
+		var decoded jwt.JSONWebKey
+		json.Unmarshal(data, &decoded)
+
+		public, err := decoded.PublicKey()
+		if err != nil {
+			t.Fatalf("Error converting JWK %s: %v", data, err)
+		}
+		if !reflect.DeepEqual(public, key) {
+			t.Errorf("Key mismatch after round trip for %T", key)
+		}
+	}
+
+	if jwk, _ := jwt.NewJSONWebKey(ecKey); len(jwk.X) != 88 {
+		t.Errorf("P-521 coordinate not padded: %v", jwk.X)
+	}
+	if _, err := jwt.NewJSONWebKey(hmacTestKey); err != jwt.ErrJWKUnsupportedKey {
+		t.Errorf("Expected ErrJWKUnsupportedKey for HMAC key, got %v", err)
+	}
+
+	bad := jwt.JSONWebKey{Kty: "EC", Crv: "P-256", X: "AQ", Y: "AQ"}
+	if _, err := bad.PublicKey(); err != jwt.ErrJWKInvalid {
+		t.Errorf("Expected ErrJWKInvalid for point off curve, got %v", err)
+	}
+}
+
+func TestJSONWebKeyThumbprint(t *testing.T) {
+	// RFC 7638 section 3.1
+	key := jwt.JSONWebKey{
+		Kty: "RSA",
+		E:   "AQAB",
+		N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		Kid: "2011-04-29",
+	}
+	thumbprint, err := key.Thumbprint()
+	if err != nil {
+		t.Fatalf("Error computing thumbprint: %v", err)
+	}
+	if got := jwt.EncodeSegment(thumbprint); got != "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs" {
+		t.Errorf("Unexpected thumbprint: %v", got)
+	}
+
+	if _, err := (&jwt.JSONWebKey{Kty: "EC", Crv: "P-256"}).Thumbprint(); err != jwt.ErrJWKInvalid {
+		t.Errorf("Expected ErrJWKInvalid for incomplete key, got %v", err)
+	}
+}
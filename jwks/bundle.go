@@ -0,0 +1,112 @@
+package jwks
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Media type of a signed bundle, for its typ header
+const BundleType = "jwk-bundle+jwt"
+
+var ErrBundleAudience = errors.New("token was not issued for any of the bundle's audiences")
+
+// An issuer's public keys together with the policy for verifying its tokens,
+// for verifying tokens offline or in air-gapped environments that cannot
+// fetch a JWK Set.  A bundle is distributed as a JWT whose claims are the
+// Bundle, signed by a key the verifying environment already trusts.
+type Bundle struct {
+	Issuer     string            `json:"iss"`
+	Audiences  []string          `json:"audiences,omitempty"` // If set, tokens must be issued for one of them
+	Algorithms []string          `json:"algs"`                // The algs accepted for the issuer's tokens
+	Keys       jwt.JSONWebKeySet `json:"jwks"`
+	IssuedAt   int64             `json:"iat"`
+	ExpiresAt  int64             `json:"exp,omitempty"` // After this the bundle is refused.  Zero means never
+}
+
+// Create a bundle of the keys keyring accepts for verification.  Algorithms
+// defaults to the algs of the keys.
+func NewBundle(issuer string, keyring *jwt.Keyring, lifetime time.Duration) *Bundle {
+	b := &Bundle{Issuer: issuer, Keys: *keyring.KeySet(), IssuedAt: jwt.TimeFunc().Unix()}
+	if lifetime > 0 {
+		b.ExpiresAt = jwt.TimeFunc().Add(lifetime).Unix()
+	}
+	seen := map[string]bool{}
+	for _, k := range b.Keys.Keys {
+		if k.Alg != "" && !seen[k.Alg] {
+			seen[k.Alg] = true
+			b.Algorithms = append(b.Algorithms, k.Alg)
+		}
+	}
+	return b
+}
+
+// Checks the bundle has not expired and names an issuer and algorithms
+func (b *Bundle) Valid() error {
+	if err := (jwt.StandardClaims{IssuedAt: b.IssuedAt, ExpiresAt: b.ExpiresAt}).Valid(); err != nil {
+		return err
+	}
+	if b.Issuer == "" || len(b.Algorithms) == 0 {
+		return jwt.NewValidationError("bundle must have an issuer and algorithms", jwt.ValidationErrorClaimsInvalid)
+	}
+	return nil
+}
+
+// Sign the bundle for distribution
+func (b *Bundle) Sign(method jwt.SigningMethod, key interface{}) (string, error) {
+	token := jwt.NewWithClaims(method, b)
+	token.Header["typ"] = BundleType
+	return token.SignedString(key)
+}
+
+// Verify a signed bundle with trustedKey, the key of whoever signs bundles,
+// and refuse it once it has expired
+func LoadBundle(signed string, trustedKey interface{}) (*Bundle, error) {
+	b := &Bundle{}
+	parser := jwt.NewParser(jwt.WithType(BundleType))
+	if _, err := parser.ParseWithClaims(signed, b, func(*jwt.Token) (interface{}, error) { return trustedKey, nil }); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// LoadBundle from the file at path
+func LoadBundleFile(path string, trustedKey interface{}) (*Bundle, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadBundle(string(data), trustedKey)
+}
+
+// A parser enforcing the bundle's policy: its algorithms, issuer and
+// audiences
+func (b *Bundle) Parser() *jwt.Parser {
+	return jwt.NewParser(
+		jwt.WithValidMethods(b.Algorithms),
+		jwt.WithValidator(jwt.IssuerValidator(b.Issuer)),
+		jwt.WithValidator(b.audienceValidator()),
+	)
+}
+
+// Verify a token from the bundle's issuer, entirely offline
+func (b *Bundle) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return b.Parser().ParseWithClaims(tokenString, claims, b.Keys.Keyfunc)
+}
+
+func (b *Bundle) audienceValidator() jwt.Validator {
+	return jwt.Validator{Name: "aud", Validate: func(ctx context.Context, token *jwt.Token) error {
+		if len(b.Audiences) == 0 {
+			return nil
+		}
+		for _, aud := range b.Audiences {
+			if err := jwt.AudienceValidator(aud).Validate(ctx, token); err == nil {
+				return nil
+			}
+		}
+		return &jwt.ValidationError{Inner: ErrBundleAudience, Errors: jwt.ValidationErrorAudience}
+	}}
+}
@@ -0,0 +1,81 @@
+package jwks
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func TestBundle(t *testing.T) {
+	issuerKey := &jwt.SigningKey{Key: test.LoadRSAPrivateKeyFromDisk("../test/sample_key"), ID: "rsa", Method: jwt.SigningMethodRS256}
+	ring := jwt.NewKeyring()
+	ring.Add(jwt.KeyringEntry{SigningKey: issuerKey})
+
+	bundle := NewBundle("https://idp.example.com", ring, time.Hour)
+	bundle.Audiences = []string{"api", "admin"}
+	if len(bundle.Algorithms) != 1 || bundle.Algorithms[0] != "RS256" || len(bundle.Keys.Keys) != 1 {
+		t.Fatalf("Unexpected bundle: %+v", bundle)
+	}
+
+	trustKey := []byte("bundle-signing-secret")
+	signed, err := bundle.Sign(jwt.SigningMethodHS256, trustKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "idp.bundle")
+	if err := ioutil.WriteFile(path, []byte(signed), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBundleFile(path, []byte("wrong")); err == nil {
+		t.Errorf("Expected a bundle signed with another key to be refused")
+	}
+	if _, err := LoadBundleFile(filepath.Join(t.TempDir(), "missing"), trustKey); !os.IsNotExist(err) {
+		t.Errorf("Expected a missing file error, got %v", err)
+	}
+	loaded, err := LoadBundleFile(path, trustKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+		s, _ := jwt.NewWithClaims(method, claims).SignedString(key)
+		return s
+	}
+	var tests = []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"valid", sign(jwt.SigningMethodRS256, issuerKey, jwt.MapClaims{"iss": "https://idp.example.com", "aud": "admin"}), true},
+		{"other issuer", sign(jwt.SigningMethodRS256, issuerKey, jwt.MapClaims{"iss": "https://evil.example.com", "aud": "api"}), false},
+		{"other audience", sign(jwt.SigningMethodRS256, issuerKey, jwt.MapClaims{"iss": "https://idp.example.com", "aud": "billing"}), false},
+		{"alg not in policy", sign(jwt.SigningMethodHS256, trustKey, jwt.MapClaims{"iss": "https://idp.example.com", "aud": "api"}), false},
+	}
+	for _, data := range tests {
+		if _, err := loaded.Parse(data.token, jwt.MapClaims{}); (err == nil) != data.valid {
+			t.Errorf("[%v] Unexpected result: %v", data.name, err)
+		}
+	}
+}
+
+func TestBundleExpired(t *testing.T) {
+	ring := jwt.NewKeyring()
+	ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: test.LoadRSAPrivateKeyFromDisk("../test/sample_key"), ID: "rsa", Method: jwt.SigningMethodRS256}})
+	bundle := NewBundle("https://idp.example.com", ring, time.Hour)
+	bundle.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	signed, _ := bundle.Sign(jwt.SigningMethodHS256, []byte("k"))
+	if _, err := LoadBundle(signed, []byte("k")); err == nil {
+		t.Errorf("Expected an expired bundle to be refused")
+	}
+
+	bundle = &Bundle{IssuedAt: time.Now().Unix()}
+	signed, _ = bundle.Sign(jwt.SigningMethodHS256, []byte("k"))
+	if _, err := LoadBundle(signed, []byte("k")); err == nil {
+		t.Errorf("Expected a bundle without issuer or algorithms to be refused")
+	}
+}
@@ -0,0 +1,14 @@
+// Utility package for publishing and consuming JSON Web Key Sets.
+//
+// Handler serves the public keys of a jwt.Keyring as a JWK Set document,
+// typically mounted at /.well-known/jwks.json.  Remote fetches and caches
+// such a document and provides a Keyfunc for verifying tokens against it.
+//
+// For environments that cannot reach the issuer, a Bundle packages its keys
+// with the policy for its tokens in a single signed file:
+//
+//	signed, _ := jwks.NewBundle(issuer, keyring, 30*24*time.Hour).Sign(jwt.SigningMethodES256, bundleKey)
+//
+//	bundle, err := jwks.LoadBundleFile("idp.bundle", bundlePublicKey)
+//	token, err := bundle.Parse(tokenString, jwt.MapClaims{})
+package jwks
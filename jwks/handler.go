@@ -0,0 +1,60 @@
+package jwks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Default value for Handler.MaxAge
+const DefaultMaxAge = 15 * time.Minute
+
+// Media type of a JWK Set document, from RFC 7517 section 8.5
+const ContentType = "application/jwk-set+json"
+
+// An http.Handler serving a JWK Set document.  Responses carry Cache-Control
+// and ETag headers so clients can cache the keys and revalidate cheaply.
+type Handler struct {
+	Keys   func() *jwt.JSONWebKeySet // Returns the keys to publish.  Called on every request
+	MaxAge time.Duration             // How long clients may cache the document
+}
+
+// Create a Handler publishing the public keys of keyring
+func NewHandler(keyring *jwt.Keyring) *Handler {
+	return &Handler{Keys: keyring.KeySet, MaxAge: DefaultMaxAge}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := json.Marshal(h.Keys())
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	header := w.Header()
+	header.Set("Content-Type", ContentType)
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.MaxAge/time.Second)))
+	header.Set("ETag", etag)
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(body)
+}
@@ -0,0 +1,58 @@
+package jwks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func TestHandler(t *testing.T) {
+	ring := jwt.NewKeyring()
+	ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: test.LoadRSAPrivateKeyFromDisk("../test/sample_key"), ID: "rsa", Method: jwt.SigningMethodRS256}})
+	ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: []byte("secret"), ID: "hmac", Method: jwt.SigningMethodHS256}})
+	handler := NewHandler(ring)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Unexpected status: %v", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Unexpected Content-Type: %v", ct)
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "public, max-age=900" {
+		t.Errorf("Unexpected Cache-Control: %v", cc)
+	}
+
+	var set jwt.JSONWebKeySet
+	if err := json.Unmarshal(w.Body.Bytes(), &set); err != nil {
+		t.Fatalf("Error decoding JWK Set: %v", err)
+	}
+	if len(set.Keys) != 1 || set.Keys[0].Kid != "rsa" || set.Keys[0].Alg != "RS256" {
+		t.Fatalf("Unexpected keys published: %+v", set.Keys)
+	}
+
+	// The published set verifies tokens from the keyring
+	tokenString, _ := ring.SignedString(jwt.MapClaims{"foo": "bar"})
+	if _, err := jwt.Parse(tokenString, set.Keyfunc); err != nil {
+		t.Errorf("Error verifying with published keys: %v", err)
+	}
+
+	r := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+	r.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for matching ETag, got %v", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/.well-known/jwks.json", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for POST, got %v", w.Code)
+	}
+}
@@ -0,0 +1,195 @@
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Defaults for Remote
+const (
+	DefaultRefreshInterval    = time.Hour
+	DefaultMinRefreshInterval = time.Minute
+)
+
+// Largest JWK Set document Remote will read
+const maxDocumentSize = 1 << 20
+
+var ErrNoKeys = errors.New("JWK Set has no keys")
+
+// A JWK Set fetched from a URL and cached.  The set is refetched once it is
+// older than RefreshInterval, or when a token names a kid the cached set does not
+// have, at most once per MinRefreshInterval.  Failed fetches count too, so a
+// failing server is not retried more often, and the last set fetched is used
+// until a fetch succeeds.
+type Remote struct {
+	URL                string
+	Client             *http.Client  // Defaults to http.DefaultClient
+	RefreshInterval    time.Duration // How long a fetched set is used before refetching
+	MinRefreshInterval time.Duration // Minimum time between fetches, to limit unknown kid lookups
+
+	// If set, called after each fetch with its error, or nil on success.
+	// Fetches never overlap, so neither do calls.  It is called before the
+	// fetch completes, so it must not call the Remote.
+	OnRefresh func(err error)
+
+	mu        sync.Mutex
+	set       *jwt.JSONWebKeySet
+	fetched   time.Time    // When set was fetched
+	attempted time.Time    // When the last fetch was started, even if it failed
+	err       error        // From the last fetch
+	inflight  *refreshCall // The fetch in progress, if any
+}
+
+// A fetch in progress, shared by every caller that needs it
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// Create a Remote for the JWK Set at url
+func NewRemote(url string) *Remote {
+	return &Remote{
+		URL:                url,
+		RefreshInterval:    DefaultRefreshInterval,
+		MinRefreshInterval: DefaultMinRefreshInterval,
+	}
+}
+
+// A Keyfunc that returns the key named by the token's kid header
+//
+//	token, err := jwt.Parse(tokenString, remote.Keyfunc)
+func (r *Remote) Keyfunc(token *jwt.Token) (interface{}, error) {
+	return r.KeyfuncContext(context.Background(), token)
+}
+
+// A KeyfuncContext that returns the key named by the token's kid header.
+// ctx is used for any fetch the lookup needs.
+func (r *Remote) KeyfuncContext(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	now := time.Now()
+	set, fetched := r.cached()
+	if set == nil || now.Sub(fetched) >= r.RefreshInterval {
+		err := r.refresh(ctx, now, r.MinRefreshInterval)
+		if set, _ = r.cached(); set == nil {
+			return nil, err
+		}
+	}
+
+	jwk := set.Key(kid)
+	if jwk == nil {
+		// The issuer may have rotated keys since the last fetch
+		if err := r.refresh(ctx, now, r.MinRefreshInterval); err != nil {
+			return nil, err
+		}
+		set, _ = r.cached()
+		jwk = set.Key(kid)
+	}
+	if jwk == nil {
+		return nil, jwt.ErrUnknownKeyID
+	}
+	return jwk.SigningKey()
+}
+
+// Fetch the JWK Set now, or wait for the fetch already in progress
+func (r *Remote) Refresh(ctx context.Context) error {
+	return r.refresh(ctx, time.Now(), 0)
+}
+
+// Returns the cached set, if any, and when it was fetched
+func (r *Remote) cached() (*jwt.JSONWebKeySet, time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.set, r.fetched
+}
+
+// Fetch the set without holding r.mu, so lookups in the cached set aren't
+// blocked by a slow server.  Concurrent callers wait for the same fetch
+// rather than starting their own.  If the last fetch was attempted less than
+// min before now, its error is returned instead.
+func (r *Remote) refresh(ctx context.Context, now time.Time, min time.Duration) error {
+	r.mu.Lock()
+	if call := r.inflight; call != nil {
+		r.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if now.Sub(r.attempted) < min {
+		err := r.err
+		r.mu.Unlock()
+		return err
+	}
+	call := &refreshCall{done: make(chan struct{})}
+	r.inflight = call
+	r.attempted = time.Now()
+	r.mu.Unlock()
+
+	set, err := r.fetch(ctx)
+	if r.OnRefresh != nil {
+		r.OnRefresh(err)
+	}
+
+	r.mu.Lock()
+	if err == nil {
+		r.set = set
+		r.fetched = time.Now()
+	}
+	r.err = err
+	r.inflight = nil
+	r.mu.Unlock()
+
+	call.err = err
+	close(call.done)
+	return err
+}
+
+func (r *Remote) fetch(ctx context.Context) (*jwt.JSONWebKeySet, error) {
+	set := &jwt.JSONWebKeySet{}
+	if err := getJSON(ctx, r.Client, r.URL, set); err != nil {
+		return nil, err
+	}
+	if len(set.Keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	return set, nil
+}
+
+// Fetch url and decode the JSON response into v
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return fmt.Errorf("fetching %v: unexpected status %v", url, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(v); err != nil {
+		return fmt.Errorf("decoding %v: %v", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package jwks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func TestRemote(t *testing.T) {
+	ring := jwt.NewKeyring()
+	ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: test.LoadRSAPrivateKeyFromDisk("../test/sample_key"), ID: "first", Method: jwt.SigningMethodRS256}})
+
+	var fetches int
+	handler := NewHandler(ring)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	remote := NewRemote(server.URL)
+	var refreshes int
+	remote.OnRefresh = func(err error) {
+		if err != nil {
+			t.Errorf("Error refreshing key set: %v", err)
+		}
+		refreshes++
+	}
+	tokenString, _ := ring.SignedString(jwt.MapClaims{"foo": "bar"})
+	for i := 0; i < 2; i++ {
+		if _, err := jwt.Parse(tokenString, remote.Keyfunc); err != nil {
+			t.Fatalf("Error verifying token: %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected the key set to be cached, got %v fetches", fetches)
+	}
+
+	// An unknown kid is only refetched once MinRefreshInterval has passed
+	ring.Rotate(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: test.LoadRSAPrivateKeyFromDisk("../test/sample_key"), ID: "second", Method: jwt.SigningMethodRS256}}, time.Hour)
+	tokenString, _ = ring.SignedString(jwt.MapClaims{"foo": "bar"})
+	if _, err := jwt.Parse(tokenString, remote.Keyfunc); err == nil {
+		t.Errorf("Unknown kid refetched before MinRefreshInterval")
+	}
+
+	remote.MinRefreshInterval = 0
+	if _, err := jwt.Parse(tokenString, remote.Keyfunc); err != nil {
+		t.Errorf("Error verifying token after rotation: %v", err)
+	}
+	if fetches != 2 || refreshes != 2 {
+		t.Errorf("Expected a refetch for the unknown kid, got %v fetches and %v refreshes", fetches, refreshes)
+	}
+}
+
+func TestRemoteConcurrentRefresh(t *testing.T) {
+	ring := jwt.NewKeyring()
+	ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: test.LoadRSAPrivateKeyFromDisk("../test/sample_key"), ID: "first", Method: jwt.SigningMethodRS256}})
+
+	var mu sync.Mutex
+	var fetches int
+	release := make(chan struct{})
+	handler := NewHandler(ring)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetches++
+		mu.Unlock()
+		<-release
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	remote := NewRemote(server.URL)
+	tokenString, _ := ring.SignedString(jwt.MapClaims{"foo": "bar"})
+
+	// Lookups arriving during a fetch share it
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := jwt.Parse(tokenString, remote.Keyfunc)
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Error verifying token: %v", err)
+		}
+	}
+	mu.Lock()
+	if fetches != 1 {
+		t.Errorf("Expected one shared fetch, got %v", fetches)
+	}
+	mu.Unlock()
+
+	// A slow refetch doesn't block lookups of cached keys
+	ring.Rotate(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: test.LoadRSAPrivateKeyFromDisk("../test/sample_key"), ID: "second", Method: jwt.SigningMethodRS256}}, time.Hour)
+	release = make(chan struct{})
+	remote.MinRefreshInterval = 0
+	rotated, _ := ring.SignedString(jwt.MapClaims{"foo": "bar"})
+	done := make(chan error)
+	go func() {
+		_, err := jwt.Parse(rotated, remote.Keyfunc)
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if _, err := jwt.Parse(tokenString, remote.Keyfunc); err != nil {
+		t.Errorf("Error verifying cached key during a fetch: %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("Error verifying token after rotation: %v", err)
+	}
+}
+
+func TestRemoteFailingServer(t *testing.T) {
+	ring := jwt.NewKeyring()
+	ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: test.LoadRSAPrivateKeyFromDisk("../test/sample_key"), ID: "first", Method: jwt.SigningMethodRS256}})
+
+	var fetches int
+	failing := true
+	handler := NewHandler(ring)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		if failing {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	remote := NewRemote(server.URL)
+	remote.MinRefreshInterval = time.Hour
+	tokenString, _ := ring.SignedString(jwt.MapClaims{"foo": "bar"})
+
+	// Failed fetches are retried at most once per MinRefreshInterval
+	for i := 0; i < 3; i++ {
+		if _, err := jwt.Parse(tokenString, remote.Keyfunc); err == nil {
+			t.Errorf("Token verified without a key set")
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected one fetch per MinRefreshInterval, got %v", fetches)
+	}
+
+	failing = false
+	remote.MinRefreshInterval = 0
+	if _, err := jwt.Parse(tokenString, remote.Keyfunc); err != nil {
+		t.Fatalf("Error verifying token: %v", err)
+	}
+
+	// Neither a stale set nor unknown kids refetch from a failing server
+	failing = true
+	fetches = 0
+	if err := remote.Refresh(context.Background()); err == nil {
+		t.Errorf("Expected the refresh to fail")
+	}
+	remote.RefreshInterval = 0
+	remote.MinRefreshInterval = time.Hour
+	for i := 0; i < 3; i++ {
+		if _, err := jwt.Parse(tokenString, remote.Keyfunc); err != nil {
+			t.Errorf("Error verifying token with the stale set: %v", err)
+		}
+		unknown := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"foo": "bar"})
+		unknown.Header["kid"] = fmt.Sprintf("random-%v", i)
+		unknownString, _ := unknown.SignedString(test.LoadRSAPrivateKeyFromDisk("../test/sample_key"))
+		if _, err := jwt.Parse(unknownString, remote.Keyfunc); err == nil {
+			t.Errorf("Token with unknown kid verified")
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected one fetch per MinRefreshInterval, got %v", fetches)
+	}
+}
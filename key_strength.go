@@ -0,0 +1,54 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+)
+
+// Smallest RSA modulus accepted by strict key checks, as NIST SP 800-131A requires
+const MinRSAKeyBits = 2048
+
+// A key rejected by strict key checks
+type WeakKeyError struct {
+	Reason string
+}
+
+func (e *WeakKeyError) Error() string {
+	return "weak key: " + e.Reason
+}
+
+// Check key is safe to use: RSA keys must have a modulus of at least
+// MinRSAKeyBits and an odd public exponent of at least 65537.  Private keys
+// are checked by their public half; other key types always pass.
+func CheckKeyStrength(key interface{}) error {
+	if sk, ok := signingKey(key); ok {
+		key = sk.Key
+	}
+	if signer, ok := key.(crypto.Signer); ok {
+		key = signer.Public()
+	}
+	if k, ok := key.(*rsa.PublicKey); ok {
+		return checkRSAKeyStrength(k)
+	}
+	return nil
+}
+
+func checkRSAKeyStrength(k *rsa.PublicKey) error {
+	if k.N == nil {
+		return &WeakKeyError{"RSA modulus is missing"}
+	}
+	if bits := k.N.BitLen(); bits < MinRSAKeyBits {
+		return &WeakKeyError{fmt.Sprintf("RSA modulus is %d bits, at least %d are required", bits, MinRSAKeyBits)}
+	}
+	if k.N.Bit(0) == 0 {
+		return &WeakKeyError{"RSA modulus is even"}
+	}
+	if k.E%2 == 0 {
+		return &WeakKeyError{fmt.Sprintf("RSA public exponent %d is even", k.E)}
+	}
+	if k.E < 65537 {
+		return &WeakKeyError{fmt.Sprintf("RSA public exponent %d is below 65537", k.E)}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package jwt_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func TestCheckKeyStrength(t *testing.T) {
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	strong := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+
+	var keyStrengthTestData = []struct {
+		name   string
+		key    interface{}
+		reason string
+	}{
+		{"2048 bit public key", &strong.PublicKey, ""},
+		{"2048 bit private key", strong, ""},
+		{"signing key", &jwt.SigningKey{Key: strong}, ""},
+		{"hmac key", hmacTestKey, ""},
+		{"1024 bit key", &small.PublicKey, "RSA modulus is 1024 bits, at least 2048 are required"},
+		{"1024 bit private key", small, "RSA modulus is 1024 bits"},
+		{"exponent 3", &rsa.PublicKey{N: strong.N, E: 3}, "RSA public exponent 3 is below 65537"},
+		{"even exponent", &rsa.PublicKey{N: strong.N, E: 65538}, "RSA public exponent 65538 is even"},
+		{"even modulus", &rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), 2048), E: 65537}, "RSA modulus is even"},
+	}
+
+	for _, data := range keyStrengthTestData {
+		err := jwt.CheckKeyStrength(data.key)
+		if data.reason == "" {
+			if err != nil {
+				t.Errorf("[%v] Unexpected error: %v", data.name, err)
+			}
+			continue
+		}
+		if e, ok := err.(*jwt.WeakKeyError); !ok || !strings.HasPrefix(e.Reason, data.reason) {
+			t.Errorf("[%v] Expected %q, got %v", data.name, data.reason, err)
+		}
+	}
+}
+
+func TestParserStrictKeys(t *testing.T) {
+	small, _ := rsa.GenerateKey(rand.Reader, 1024)
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"foo": "bar"}).SignedString(small)
+	keyFunc := func(*jwt.Token) (interface{}, error) { return &small.PublicKey, nil }
+
+	if _, err := jwt.NewParser().Parse(tokenString, keyFunc); err != nil {
+		t.Errorf("Expected weak key to be accepted by default, got %v", err)
+	}
+	_, err := jwt.NewParser(jwt.WithStrictKeys()).Parse(tokenString, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorUnverifiable == 0 {
+		t.Fatalf("Expected an unverifiable error, got %v", err)
+	} else if _, ok := ve.Inner.(*jwt.WeakKeyError); !ok {
+		t.Errorf("Expected a WeakKeyError, got %v", ve.Inner)
+	}
+
+	ring := jwt.NewKeyring()
+	ring.StrictKeys = true
+	entry := jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: small, ID: "small", Method: jwt.SigningMethodRS256}}
+	if _, ok := ring.Add(entry).(*jwt.WeakKeyError); !ok {
+		t.Errorf("Expected keyring to reject weak key")
+	}
+}
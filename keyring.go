@@ -0,0 +1,300 @@
+package jwt
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoSigningKey  = errors.New("keyring has no key valid for signing")
+	ErrUnknownKeyID  = errors.New("key id (kid) is unknown or retired")
+	ErrMissingKeyID  = errors.New("key must have an id")
+	ErrDuplicateKey  = errors.New("a key with this id is already in the keyring")
+	ErrMissingMethod = errors.New("key must have a signing method")
+)
+
+// A key held by a Keyring, along with the windows in which it may be used
+type KeyringEntry struct {
+	*SigningKey                 // The signing key.  ID and Method are required
+	VerificationKey interface{} // Key used to verify tokens.  Defaults to the public half of a crypto.Signer, or Key itself
+	NotBefore       time.Time   // The key is used for signing from this time on.  Zero means immediately
+	NotAfter        time.Time   // The key is no longer used for signing after this time.  Zero means no limit
+	VerifyUntil     time.Time   // Tokens signed with the key are accepted until this time.  Zero means until retired
+}
+
+func (e *KeyringEntry) canSign(now time.Time) bool {
+	return !now.Before(e.NotBefore) && (e.NotAfter.IsZero() || now.Before(e.NotAfter))
+}
+
+func (e *KeyringEntry) canVerify(now time.Time) bool {
+	return e.VerifyUntil.IsZero() || now.Before(e.VerifyUntil)
+}
+
+func (e *KeyringEntry) verificationKey() interface{} {
+	if e.VerificationKey != nil {
+		return e.VerificationKey
+	}
+	if signer, ok := e.Key.(crypto.Signer); ok {
+		return signer.Public()
+	}
+	return e.Key
+}
+
+// A set of signing keys with validity windows, for issuing and verifying tokens
+// across key rotations.  New tokens are signed with the current key and carry
+// its kid; tokens are verified with whichever key their kid names, for as long
+// as that key is accepted for verification.
+//
+// Time is taken from TimeFunc.  A Keyring is safe for concurrent use.
+type Keyring struct {
+	// Called after Rotate replaces the current signing key.  previous is nil
+	// if the keyring had no current key.
+	OnRotate func(previous, current *SigningKey)
+
+	// Reject weak keys in Add and Rotate.  See CheckKeyStrength.
+	StrictKeys bool
+
+	mu      sync.RWMutex
+	entries []*KeyringEntry
+}
+
+// Create an empty Keyring
+func NewKeyring() *Keyring {
+	return &Keyring{}
+}
+
+// Add a key to the keyring
+func (k *Keyring) Add(entry KeyringEntry) error {
+	if entry.SigningKey == nil || entry.ID == "" {
+		return ErrMissingKeyID
+	}
+	if entry.Method == nil {
+		return ErrMissingMethod
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.add(&entry)
+}
+
+// Remove a key immediately.  Tokens signed with it no longer verify.
+// Returns false if no key has this id.
+func (k *Keyring) Retire(kid string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for i, e := range k.entries {
+		if e.ID == kid {
+			k.entries = append(k.entries[:i], k.entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Remove keys that are no longer accepted for verification.
+// Returns the number of keys removed.
+func (k *Keyring) Prune() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := TimeFunc()
+	kept := k.entries[:0]
+	for _, e := range k.entries {
+		if e.canVerify(now) {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(k.entries) - len(kept)
+	k.entries = kept
+	return removed
+}
+
+// Returns the key currently used for signing: of the keys inside their signing
+// window, the one that became valid most recently.
+func (k *Keyring) Current() (*SigningKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if e := k.current(TimeFunc()); e != nil {
+		return e.SigningKey, nil
+	}
+	return nil, ErrNoSigningKey
+}
+
+// Create a token for claims and sign it with the current key, setting kid
+func (k *Keyring) SignedString(claims Claims) (string, error) {
+	key, err := k.Current()
+	if err != nil {
+		return "", err
+	}
+	return NewWithClaims(key.Method, claims).SignedString(key)
+}
+
+// A Keyfunc that returns the key named by the token's kid header, if it is
+// still accepted for verification.  The key is tied to its signing method, so
+// tokens using any other alg are rejected.
+//
+//	token, err := jwt.Parse(tokenString, keyring.Keyfunc)
+func (k *Keyring) Keyfunc(token *Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	e := k.find(kid)
+	if e == nil || !e.canVerify(TimeFunc()) {
+		return nil, ErrUnknownKeyID
+	}
+	return &SigningKey{Key: e.verificationKey(), ID: e.ID, Method: e.Method}, nil
+}
+
+// Returns the public halves of the keys still accepted for verification, for
+// publishing as a JWK Set.  Symmetric keys are left out.
+func (k *Keyring) KeySet() *JSONWebKeySet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := TimeFunc()
+	set := &JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, e := range k.entries {
+		if !e.canVerify(now) {
+			continue
+		}
+		jwk, err := NewJSONWebKey(e.verificationKey())
+		if err != nil {
+			continue
+		}
+		jwk.Kid = e.ID
+		jwk.Alg = e.Method.Alg()
+		jwk.Use = "sig"
+		set.Keys = append(set.Keys, *jwk)
+	}
+	return set
+}
+
+// Make next the current signing key, from its NotBefore or now.  The
+// previous current key keeps signing until then, and is accepted for
+// verification for grace more, which should be at least the lifetime of the
+// tokens it signed.
+func (k *Keyring) Rotate(next KeyringEntry, grace time.Duration) error {
+	now := TimeFunc()
+	if next.NotBefore.IsZero() {
+		next.NotBefore = now
+	}
+	handover := next.NotBefore
+	if handover.Before(now) {
+		handover = now
+	}
+
+	if next.SigningKey == nil || next.ID == "" {
+		return ErrMissingKeyID
+	}
+	if next.Method == nil {
+		return ErrMissingMethod
+	}
+
+	k.mu.Lock()
+	previous := k.current(now)
+	if err := k.add(&next); err != nil {
+		k.mu.Unlock()
+		return err
+	}
+	var previousKey *SigningKey
+	if previous != nil {
+		if previous.NotAfter.IsZero() || previous.NotAfter.After(handover) {
+			previous.NotAfter = handover
+		}
+		if until := handover.Add(grace); previous.VerifyUntil.IsZero() || previous.VerifyUntil.After(until) {
+			previous.VerifyUntil = until
+		}
+		previousKey = previous.SigningKey
+	}
+	k.mu.Unlock()
+
+	if k.OnRotate != nil {
+		k.OnRotate(previousKey, next.SigningKey)
+	}
+	return nil
+}
+
+// Rotate keys every interval using keys from generate, pruning keys
+// that are no longer accepted.  Errors from generate or Rotate are passed to
+// onError, which may be nil.  Call the returned function to stop rotating.
+func (k *Keyring) StartRotation(interval, grace time.Duration, generate func() (KeyringEntry, error), onError func(error)) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				err := k.rotateWith(grace, generate)
+				if err != nil && onError != nil {
+					onError(err)
+				}
+				k.Prune()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+func (k *Keyring) rotateWith(grace time.Duration, generate func() (KeyringEntry, error)) error {
+	next, err := generate()
+	if err != nil {
+		return fmt.Errorf("generating key: %v", err)
+	}
+	return k.Rotate(next, grace)
+}
+
+// callers must hold k.mu
+func (k *Keyring) add(entry *KeyringEntry) error {
+	if k.find(entry.ID) != nil {
+		return ErrDuplicateKey
+	}
+	if k.StrictKeys {
+		if err := CheckKeyStrength(entry.Key); err != nil {
+			return err
+		}
+		if err := CheckKeyStrength(entry.verificationKey()); err != nil {
+			return err
+		}
+	}
+	k.entries = append(k.entries, entry)
+	return nil
+}
+
+// callers must hold k.mu
+func (k *Keyring) current(now time.Time) *KeyringEntry {
+	var current *KeyringEntry
+	for _, e := range k.entries {
+		if e.canSign(now) && (current == nil || e.NotBefore.After(current.NotBefore)) {
+			current = e
+		}
+	}
+	return current
+}
+
+// callers must hold k.mu
+func (k *Keyring) find(kid string) *KeyringEntry {
+	for _, e := range k.entries {
+		if e.ID == kid {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func TestKeyringRotation(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	ring := jwt.NewKeyring()
+	var rotated []string
+	ring.OnRotate = func(previous, current *jwt.SigningKey) {
+		rotated = append(rotated, current.ID)
+	}
+
+	if _, err := ring.SignedString(jwt.MapClaims{}); err != jwt.ErrNoSigningKey {
+		t.Fatalf("Expected ErrNoSigningKey, got %v", err)
+	}
+
+	privateKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	if err := ring.Rotate(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: privateKey, ID: "rsa", Method: jwt.SigningMethodRS256}}, time.Hour); err != nil {
+		t.Fatalf("Error adding key: %v", err)
+	}
+	oldToken, err := ring.SignedString(jwt.MapClaims{"foo": "bar"})
+	if err != nil {
+		t.Fatalf("Error signing: %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if err := ring.Rotate(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: hmacTestKey, ID: "hmac", Method: jwt.SigningMethodHS256}}, time.Hour); err != nil {
+		t.Fatalf("Error rotating: %v", err)
+	}
+	if current, _ := ring.Current(); current.ID != "hmac" {
+		t.Errorf("Expected hmac to be current, got %v", current.ID)
+	}
+	newToken, _ := ring.SignedString(jwt.MapClaims{"foo": "bar"})
+
+	for _, tokenString := range []string{oldToken, newToken} {
+		if _, err := jwt.Parse(tokenString, ring.Keyfunc); err != nil {
+			t.Errorf("Error verifying during grace period: %v", err)
+		}
+	}
+
+	// After the grace period the old key is no longer accepted
+	now = now.Add(2 * time.Hour)
+	if _, err := jwt.Parse(oldToken, ring.Keyfunc); err == nil {
+		t.Errorf("Old key accepted after grace period")
+	}
+	if removed := ring.Prune(); removed != 1 {
+		t.Errorf("Expected 1 key pruned, got %v", removed)
+	}
+
+	if !ring.Retire("hmac") {
+		t.Errorf("Retire did not find key")
+	}
+	if _, err := jwt.Parse(newToken, ring.Keyfunc); err == nil {
+		t.Errorf("Retired key accepted")
+	}
+
+	if len(rotated) != 2 || rotated[0] != "rsa" || rotated[1] != "hmac" {
+		t.Errorf("Unexpected OnRotate calls: %v", rotated)
+	}
+}
+
+func TestKeyringRotateFutureKey(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	ring := jwt.NewKeyring()
+	privateKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	if err := ring.Rotate(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: privateKey, ID: "rsa", Method: jwt.SigningMethodRS256}}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	// The previous key keeps signing until the next one takes over
+	next := jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: hmacTestKey, ID: "hmac", Method: jwt.SigningMethodHS256}, NotBefore: now.Add(time.Hour)}
+	if err := ring.Rotate(next, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if current, err := ring.Current(); err != nil || current.ID != "rsa" {
+		t.Errorf("Expected rsa to be current until the handover, got %v %v", current, err)
+	}
+	oldToken, _ := ring.SignedString(jwt.MapClaims{"foo": "bar"})
+
+	now = now.Add(time.Hour)
+	if current, err := ring.Current(); err != nil || current.ID != "hmac" {
+		t.Errorf("Expected hmac to be current after the handover, got %v %v", current, err)
+	}
+
+	// The grace period starts at the handover
+	now = now.Add(59 * time.Minute)
+	if _, err := jwt.Parse(oldToken, ring.Keyfunc); err != nil {
+		t.Errorf("Error verifying during grace period: %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := jwt.Parse(oldToken, ring.Keyfunc); err == nil {
+		t.Errorf("Old key accepted after grace period")
+	}
+}
+
+func TestKeyringRejections(t *testing.T) {
+	ring := jwt.NewKeyring()
+	key := &jwt.SigningKey{Key: hmacTestKey, ID: "hmac", Method: jwt.SigningMethodHS256}
+
+	if err := ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: hmacTestKey, Method: jwt.SigningMethodHS256}}); err != jwt.ErrMissingKeyID {
+		t.Errorf("Expected ErrMissingKeyID, got %v", err)
+	}
+	if err := ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: hmacTestKey, ID: "x"}}); err != jwt.ErrMissingMethod {
+		t.Errorf("Expected ErrMissingMethod, got %v", err)
+	}
+	ring.Add(jwt.KeyringEntry{SigningKey: key})
+	if err := ring.Add(jwt.KeyringEntry{SigningKey: key}); err != jwt.ErrDuplicateKey {
+		t.Errorf("Expected ErrDuplicateKey, got %v", err)
+	}
+
+	// A token naming the key but using another alg must not verify
+	token := jwt.New(jwt.SigningMethodHS512)
+	token.Header["kid"] = "hmac"
+	tokenString, _ := token.SignedString(hmacTestKey)
+	if _, err := jwt.Parse(tokenString, ring.Keyfunc); err == nil {
+		t.Errorf("Token with mismatched alg passed validation")
+	}
+
+	tokenString, _ = jwt.New(jwt.SigningMethodHS256).SignedString(hmacTestKey)
+	if _, err := jwt.Parse(tokenString, ring.Keyfunc); err == nil {
+		t.Errorf("Token without kid passed validation")
+	}
+}
+
+func TestKeyringStartRotation(t *testing.T) {
+	ring := jwt.NewKeyring()
+	rotated := make(chan string, 1)
+	ring.OnRotate = func(previous, current *jwt.SigningKey) {
+		select {
+		case rotated <- current.ID:
+		default:
+		}
+	}
+
+	generate := func() (jwt.KeyringEntry, error) {
+		id := time.Now().Format(time.RFC3339Nano)
+		return jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: hmacTestKey, ID: id, Method: jwt.SigningMethodHS256}}, nil
+	}
+	stop := ring.StartRotation(time.Millisecond, time.Minute, generate, nil)
+	defer stop()
+
+	select {
+	case <-rotated:
+	case <-time.After(time.Second):
+		t.Fatalf("Key was not rotated")
+	}
+	if _, err := ring.SignedString(jwt.MapClaims{}); err != nil {
+		t.Errorf("Error signing after rotation: %v", err)
+	}
+}
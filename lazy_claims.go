@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// Claims that keep the raw JSON of the payload and decode it only as needed,
+// for tokens with very large claim sets, such as thousands of group
+// memberships, when the caller only needs a few claims.  Pass a *LazyClaims
+// to ParseWithClaims, or use WithLazyClaims.
+//
+// Valid decodes only exp, iat and nbf.  Decode reads a single claim, and Map
+// decodes the whole claim set once, on first use.  Numbers are decoded as
+// json.Number.  A LazyClaims is safe for concurrent reads once parsed.
+type LazyClaims struct {
+	raw []byte
+
+	once   sync.Once
+	claims MapClaims
+	err    error
+}
+
+// Keeps a copy of data, checking only that it is a JSON object
+func (c *LazyClaims) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return errors.New("claims must be a JSON object")
+	}
+	*c = LazyClaims{raw: append([]byte(nil), data...)}
+	return nil
+}
+
+// Returns the raw claims, unchanged
+func (c *LazyClaims) MarshalJSON() ([]byte, error) {
+	if c.raw == nil {
+		return []byte("{}"), nil
+	}
+	return c.raw, nil
+}
+
+// The raw JSON of the claims
+func (c *LazyClaims) Raw() []byte {
+	return c.raw
+}
+
+// Validates the time based claims exp, iat and nbf, as MapClaims.Valid does,
+// without decoding the other claims
+func (c *LazyClaims) Valid() error {
+	var times struct {
+		ExpiresAt interface{} `json:"exp"`
+		IssuedAt  interface{} `json:"iat"`
+		NotBefore interface{} `json:"nbf"`
+	}
+	if err := c.decode(&times); err != nil {
+		return &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	return MapClaims{"exp": times.ExpiresAt, "iat": times.IssuedAt, "nbf": times.NotBefore}.Valid()
+}
+
+// Decode the named top-level claim into v.  Returns ErrClaimNotFound if the
+// claim is absent or null.
+func (c *LazyClaims) Decode(name string, v interface{}) error {
+	if c.raw == nil {
+		return ErrClaimNotFound
+	}
+	raw, err := RawClaim(c.raw, name)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// All of the claims, decoded on first use
+func (c *LazyClaims) Map() (MapClaims, error) {
+	c.once.Do(func() {
+		c.claims = MapClaims{}
+		if c.err = c.decode(&c.claims); c.err != nil {
+			c.claims = nil
+		}
+	})
+	return c.claims, c.err
+}
+
+func (c *LazyClaims) decode(v interface{}) error {
+	if c.raw == nil {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(c.raw))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
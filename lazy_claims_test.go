@@ -0,0 +1,51 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestLazyClaims(t *testing.T) {
+	groups := make([]string, 5000)
+	for i := range groups {
+		groups[i] = fmt.Sprintf("group-%d", i)
+	}
+	now := time.Now().Unix()
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	tokenString := signHS256(jwt.MapClaims{"sub": "user", "exp": now + 60, "groups": groups})
+
+	token, err := jwt.NewParser(jwt.WithLazyClaims()).Parse(tokenString, keyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, ok := token.Claims.(*jwt.LazyClaims)
+	if !ok {
+		t.Fatalf("Expected LazyClaims, got %T", token.Claims)
+	}
+
+	var sub string
+	if err := claims.Decode("sub", &sub); err != nil || sub != "user" {
+		t.Errorf("Unexpected sub: %v %v", sub, err)
+	}
+	var exp int64
+	if err := claims.Decode("exp", &exp); err != nil || exp != now+60 {
+		t.Errorf("Unexpected exp: %v %v", exp, err)
+	}
+	if err := claims.Decode("email", &sub); err != jwt.ErrClaimNotFound {
+		t.Errorf("Expected ErrClaimNotFound, got %v", err)
+	}
+	m, err := claims.Map()
+	if err != nil || len(m["groups"].([]interface{})) != 5000 || m["exp"] != json.Number(fmt.Sprint(now+60)) {
+		t.Errorf("Unexpected claims: %v", err)
+	}
+
+	expired := signHS256(jwt.MapClaims{"sub": "user", "exp": now - 60})
+	_, err = jwt.ParseWithClaims(expired, &jwt.LazyClaims{}, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorExpired == 0 {
+		t.Errorf("Expected an expired error, got %v", err)
+	}
+}
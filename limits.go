@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"fmt"
+)
+
+// Nesting limit used by StrictPolicy, and by the duplicate key check when
+// the parser has no MaxJSONDepth
+const defaultMaxJSONDepth = 32
+
+// Check the token against the parser's size limit, before it is split
+func (p *Parser) checkTokenSize(tokenString string) error {
+	if p.MaxTokenSize > 0 && len(tokenString) > p.MaxTokenSize {
+		return NewValidationError(fmt.Sprintf("token is larger than %v bytes", p.MaxTokenSize), ValidationErrorMalformed)
+	}
+	return nil
+}
+
+// Check the encoded segments against the parser's size limit, before they are decoded
+func (p *Parser) checkSegmentSizes(segments ...string) error {
+	if p.MaxSegmentSize > 0 {
+		for _, seg := range segments {
+			if len(seg) > p.MaxSegmentSize {
+				return NewValidationError(fmt.Sprintf("token segment is larger than %v bytes", p.MaxSegmentSize), ValidationErrorMalformed)
+			}
+		}
+	}
+	return nil
+}
+
+// Check decoded JSON against the parser's nesting limit
+func (p *Parser) checkDepthLimit(data []byte) error {
+	if p.MaxJSONDepth > 0 && jsonDepth(data) > p.MaxJSONDepth {
+		return fmt.Errorf("JSON is nested deeper than %v levels", p.MaxJSONDepth)
+	}
+	return nil
+}
+
+// Returns the maximum nesting depth of objects and arrays in data, without
+// decoding it.  Invalid JSON is left for the decoder to reject.
+func jsonDepth(data []byte) int {
+	depth, max := 0, 0
+	inString, escaped := false, false
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			if depth++; depth > max {
+				max = depth
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return max
+}
@@ -0,0 +1,46 @@
+package jwt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestParserLimits(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	deep := strings.Repeat(`{"a":`, 20) + "1" + strings.Repeat("}", 20)
+
+	var tests = []struct {
+		name   string
+		claims string
+		parser *jwt.Parser
+		valid  bool
+	}{
+		{"within limits", `{"sub":"user","tags":[["a"]]}`, jwt.NewParser(jwt.WithMaxTokenSize(1024), jwt.WithMaxSegmentSize(512), jwt.WithMaxJSONDepth(3)), true},
+		{"token too large", `{"data":"` + strings.Repeat("x", 2048) + `"}`, jwt.NewParser(jwt.WithMaxTokenSize(1024)), false},
+		{"segment too large", `{"data":"` + strings.Repeat("x", 1024) + `"}`, jwt.NewParser(jwt.WithMaxSegmentSize(512)), false},
+		{"too deep", deep, jwt.NewParser(jwt.WithMaxJSONDepth(10)), false},
+		{"brackets in strings", `{"s":"` + strings.Repeat("[", 20) + `"}`, jwt.NewParser(jwt.WithMaxJSONDepth(2)), true},
+		{"no limits", deep, jwt.NewParser(), true},
+	}
+
+	for _, data := range tests {
+		signingString := jwt.EncodeSegment([]byte(`{"alg":"HS256"}`)) + "." + jwt.EncodeSegment([]byte(data.claims))
+		sig, _ := jwt.SigningMethodHS256.Sign(signingString, hmacTestKey)
+		tokenString := signingString + "." + sig
+
+		_, err := data.parser.Parse(tokenString, keyFunc)
+		if data.valid && err != nil {
+			t.Errorf("[%v] Error while verifying token: %v", data.name, err)
+		}
+		if !data.valid {
+			if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorMalformed == 0 {
+				t.Errorf("[%v] Expected malformed error, got %v", data.name, err)
+			}
+		}
+		if err := data.parser.ParseInto(&jwt.Token{}, tokenString, jwt.MapClaims{}, keyFunc); (err == nil) != data.valid {
+			t.Errorf("[%v] ParseInto result mismatch: %v", data.name, err)
+		}
+	}
+}
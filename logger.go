@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"context"
+	"time"
+)
+
+// Receives events from a Parser, for logging and tracing.  Log is called
+// synchronously from Parse, so it should be quick.  Events never include the
+// raw token string.
+type Logger interface {
+	Log(ctx context.Context, event LogEvent)
+}
+
+// Adapts a function to a Logger
+type LoggerFunc func(ctx context.Context, event LogEvent)
+
+func (f LoggerFunc) Log(ctx context.Context, event LogEvent) {
+	f(ctx, event)
+}
+
+// What happened in a LogEvent
+type LogEventKind int
+
+const (
+	EventKeySelected      LogEventKind = iota + 1 // The Keyfunc returned a key
+	EventTokenParsed                              // The token was valid
+	EventValidationFailed                         // Parsing or validation failed
+)
+
+func (k LogEventKind) String() string {
+	switch k {
+	case EventKeySelected:
+		return "key selected"
+	case EventTokenParsed:
+		return "token parsed"
+	case EventValidationFailed:
+		return "validation failed"
+	}
+	return "unknown"
+}
+
+type LogEvent struct {
+	Kind      LogEventKind
+	Token     *Token        // May be nil or partly decoded, and unverified unless Kind is EventTokenParsed
+	KeyID     string        // The kid header, if any
+	Algorithm string        // The alg header, if any
+	Err       error         // Why validation failed
+	Reason    string        // ErrorReason(Err)
+	Duration  time.Duration // Time spent in Parse, for EventTokenParsed and EventValidationFailed
+}
+
+func newLogEvent(kind LogEventKind, token *Token) LogEvent {
+	event := LogEvent{Kind: kind, Token: token}
+	if token != nil {
+		event.KeyID, _ = token.Header["kid"].(string)
+		event.Algorithm, _ = token.Header["alg"].(string)
+	}
+	return event
+}
+
+func (p *Parser) logResult(ctx context.Context, token *Token, err error, start time.Time) {
+	event := newLogEvent(EventTokenParsed, token)
+	if err != nil {
+		event.Kind = EventValidationFailed
+		event.Err = err
+		event.Reason = ErrorReason(err)
+	}
+	event.Duration = time.Since(start)
+	p.Logger.Log(ctx, event)
+}
+
+// A short, stable name for why a token was rejected, such as "expired" or
+// "signature_invalid", for logs and metrics.  Errors that are not a
+// *ValidationError are reported as "error", and nil as "".
+func ErrorReason(err error) string {
+	if err == nil {
+		return ""
+	}
+	ve, ok := err.(*ValidationError)
+	if !ok {
+		return "error"
+	}
+	switch {
+	case ve.Errors&ValidationErrorMalformed != 0:
+		return "malformed"
+	case ve.Errors&ValidationErrorUnverifiable != 0:
+		return "unverifiable"
+	case ve.Errors&ValidationErrorSignatureInvalid != 0:
+		return "signature_invalid"
+	case ve.Errors&ValidationErrorType != 0:
+		return "type"
+	case ve.Errors&ValidationErrorExpired != 0:
+		return "expired"
+	case ve.Errors&ValidationErrorNotValidYet != 0:
+		return "not_valid_yet"
+	case ve.Errors&ValidationErrorIssuedAt != 0:
+		return "issued_at"
+	case ve.Errors&ValidationErrorAudience != 0:
+		return "audience"
+	case ve.Errors&ValidationErrorIssuer != 0:
+		return "issuer"
+	case ve.Errors&ValidationErrorId != 0:
+		return "id"
+	case ve.Errors&ValidationErrorClaimsInvalid != 0:
+		return "claims_invalid"
+	}
+	return "error"
+}
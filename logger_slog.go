@@ -0,0 +1,25 @@
+//go:build go1.21
+
+package jwt
+
+import (
+	"context"
+	"log/slog"
+)
+
+// A Logger writing to l.  Failures are logged at Info and other events at
+// Debug.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return LoggerFunc(func(ctx context.Context, event LogEvent) {
+		level := slog.LevelDebug
+		attrs := []slog.Attr{slog.String("kid", event.KeyID), slog.String("alg", event.Algorithm)}
+		if event.Kind != EventKeySelected {
+			attrs = append(attrs, slog.Duration("duration", event.Duration))
+		}
+		if event.Err != nil {
+			level = slog.LevelInfo
+			attrs = append(attrs, slog.String("reason", event.Reason), slog.String("error", event.Err.Error()))
+		}
+		l.LogAttrs(ctx, level, "jwt: "+event.Kind.String(), attrs...)
+	})
+}
@@ -0,0 +1,28 @@
+//go:build go1.21
+
+package jwt_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := jwt.NewSlogLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	parser := jwt.NewParser(jwt.WithLogger(logger))
+
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}).SignedString(hmacTestKey)
+	parser.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+
+	// The default handler drops the Debug key selection event
+	out := buf.String()
+	if !strings.Contains(out, `msg="jwt: validation failed"`) || !strings.Contains(out, "reason=expired") || strings.Contains(out, tokenString) {
+		t.Errorf("Unexpected log output: %v", out)
+	}
+}
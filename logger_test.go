@@ -0,0 +1,73 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestLogger(t *testing.T) {
+	var events []jwt.LogEvent
+	parser := jwt.NewParser(jwt.WithLogger(jwt.LoggerFunc(func(ctx context.Context, event jwt.LogEvent) {
+		events = append(events, event)
+	})))
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	var tests = []struct {
+		name   string
+		claims jwt.MapClaims
+		kinds  []jwt.LogEventKind
+		reason string
+	}{
+		{"valid", jwt.MapClaims{"sub": "user"}, []jwt.LogEventKind{jwt.EventKeySelected, jwt.EventTokenParsed}, ""},
+		{"expired", jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}, []jwt.LogEventKind{jwt.EventKeySelected, jwt.EventValidationFailed}, "expired"},
+	}
+
+	for _, data := range tests {
+		events = nil
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims)
+		token.Header["kid"] = "k1"
+		tokenString, _ := token.SignedString(hmacTestKey)
+		parser.Parse(tokenString, keyFunc)
+
+		if len(events) != len(data.kinds) {
+			t.Errorf("[%v] Expected %v events, got %v", data.name, len(data.kinds), len(events))
+			continue
+		}
+		for i, event := range events {
+			if event.Kind != data.kinds[i] || event.KeyID != "k1" || event.Algorithm != "HS256" {
+				t.Errorf("[%v] Unexpected event %v: %+v", data.name, i, event)
+			}
+		}
+		if last := events[len(events)-1]; last.Reason != data.reason || last.Duration <= 0 {
+			t.Errorf("[%v] Expected reason %q, got %q after %v", data.name, data.reason, last.Reason, last.Duration)
+		}
+	}
+
+	// Malformed tokens fail before any key is selected
+	events = nil
+	parser.ParseInto(new(jwt.Token), "not a token", jwt.MapClaims{}, keyFunc)
+	if len(events) != 1 || events[0].Reason != "malformed" {
+		t.Errorf("Unexpected events for a malformed token: %+v", events)
+	}
+}
+
+func TestErrorReason(t *testing.T) {
+	var tests = []struct {
+		err    error
+		reason string
+	}{
+		{nil, ""},
+		{errors.New("boom"), "error"},
+		{jwt.NewValidationError("", jwt.ValidationErrorSignatureInvalid|jwt.ValidationErrorExpired), "signature_invalid"},
+		{jwt.NewValidationError("", jwt.ValidationErrorAudience), "audience"},
+	}
+	for _, data := range tests {
+		if reason := jwt.ErrorReason(data.err); reason != data.reason {
+			t.Errorf("[%v] Expected %q, got %q", data.err, data.reason, reason)
+		}
+	}
+}
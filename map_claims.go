@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	// "fmt"
@@ -10,9 +11,21 @@ import (
 // This is the default claims type if you don't supply one
 type MapClaims map[string]interface{}
 
-// Compares the aud claim against cmp.
+// Compares the aud claim against cmp.  aud may be a string or an array of strings.
 // If required is false, this method will return true if the value matches or is unset
 func (m MapClaims) VerifyAudience(cmp string, req bool) bool {
+	switch aud := m["aud"].(type) {
+	case []interface{}:
+		var values ClaimStrings
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values.Verify(cmp, req)
+	case []string:
+		return ClaimStrings(aud).Verify(cmp, req)
+	}
 	aud, _ := m["aud"].(string)
 	return verifyAud(aud, cmp, req)
 }
@@ -24,7 +37,7 @@ func (m MapClaims) VerifyExpiresAt(cmp int64, req bool) bool {
 	case float64:
 		return verifyExp(int64(exp), cmp, req)
 	case json.Number:
-		v, _ := exp.Int64()
+		v := numberToInt64(exp)
 		return verifyExp(v, cmp, req)
 	}
 	return req == false
@@ -37,7 +50,7 @@ func (m MapClaims) VerifyIssuedAt(cmp int64, req bool) bool {
 	case float64:
 		return verifyIat(int64(iat), cmp, req)
 	case json.Number:
-		v, _ := iat.Int64()
+		v := numberToInt64(iat)
 		return verifyIat(v, cmp, req)
 	}
 	return req == false
@@ -57,7 +70,7 @@ func (m MapClaims) VerifyNotBefore(cmp int64, req bool) bool {
 	case float64:
 		return verifyNbf(int64(nbf), cmp, req)
 	case json.Number:
-		v, _ := nbf.Int64()
+		v := numberToInt64(nbf)
 		return verifyNbf(v, cmp, req)
 	}
 	return req == false
@@ -92,3 +105,35 @@ func (m MapClaims) Valid() error {
 
 	return vErr
 }
+
+// Returns claims as a MapClaims, re-decoding other claims types through JSON.
+// Numbers are decoded as json.Number.
+func claimsToMap(claims Claims) (MapClaims, error) {
+	switch c := claims.(type) {
+	case MapClaims:
+		return c, nil
+	case *LazyClaims:
+		return c.Map()
+	}
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	m := MapClaims{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Converts a json.Number to int64, accepting fractional and exponent forms
+// such as 1.5e9 that Int64 alone rejects
+func numberToInt64(n json.Number) int64 {
+	if v, err := n.Int64(); err == nil {
+		return v
+	}
+	f, _ := n.Float64()
+	return int64(f)
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/request"
+)
+
+// Error codes from RFC 6750 section 3.1
+const (
+	ErrorCodeInvalidRequest    = "invalid_request"
+	ErrorCodeInvalidToken      = "invalid_token"
+	ErrorCodeInsufficientScope = "insufficient_scope"
+)
+
+// A rejected request, as described to the client by RFC 6750
+type BearerError struct {
+	Status      int    // 401 Unauthorized or 403 Forbidden
+	Code        string // One of the ErrorCode constants, or empty if no token was sent
+	Description string // Safe to show the client; never includes key or token details
+	Scope       string // Scopes the request needs, for insufficient_scope
+	Err         error  // The underlying error
+}
+
+// Describe err, returned by Verify, for the client
+func (m *Middleware) BearerError(err error) *BearerError {
+	e := &BearerError{Status: http.StatusUnauthorized, Code: ErrorCodeInvalidToken, Err: err}
+	switch err {
+	case request.ErrNoTokenInRequest:
+		// RFC 6750 3.1: no error code when the request had no credentials
+		e.Code = ""
+	case ErrInsufficientScope:
+		e.Status = http.StatusForbidden
+		e.Code = ErrorCodeInsufficientScope
+		e.Scope = jwt.FormatScope(m.RequiredScopes)
+		e.Description = "The access token does not grant the required scope"
+	case ErrInsufficientRole:
+		e.Status = http.StatusForbidden
+		e.Code = ErrorCodeInsufficientScope
+		e.Description = "The access token does not have the required role"
+	case jwt.ErrCertificateBinding, jwt.ErrMissingClientCertificate:
+		e.Description = "The access token is not bound to the client certificate"
+	default:
+		e.Description = tokenErrorDescriptions[jwt.ErrorReason(err)]
+		if e.Description == "" {
+			e.Description = "The access token is invalid"
+		}
+	}
+	return e
+}
+
+var tokenErrorDescriptions = map[string]string{
+	"malformed":         "The access token is malformed",
+	"signature_invalid": "The access token signature is invalid",
+	"expired":           "The access token expired",
+	"not_valid_yet":     "The access token is not valid yet",
+	"audience":          "The access token was not issued for this audience",
+	"issuer":            "The access token was not issued by a trusted issuer",
+}
+
+// The WWW-Authenticate header value challenging the client
+func (e *BearerError) Challenge(realm string) string {
+	var params []string
+	if realm != "" {
+		params = append(params, "realm="+quote(realm))
+	}
+	if e.Code != "" {
+		params = append(params, "error="+quote(e.Code))
+	}
+	if e.Description != "" {
+		params = append(params, "error_description="+quote(e.Description))
+	}
+	if e.Scope != "" {
+		params = append(params, "scope="+quote(e.Scope))
+	}
+	if len(params) == 0 {
+		return "Bearer"
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+// RFC 6750 restricts these values to printable ASCII without '"' or '\'
+func quote(s string) string {
+	b := make([]byte, 0, len(s)+2)
+	b = append(b, '"')
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 0x20 && c <= 0x7e && c != '"' && c != '\\' {
+			b = append(b, c)
+		}
+	}
+	return string(append(b, '"'))
+}
+
+// Respond to a request Verify rejected, with the RFC 6750 WWW-Authenticate
+// challenge and status, then the body from ErrorBody
+func (m *Middleware) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	e := m.BearerError(err)
+	w.Header().Set("WWW-Authenticate", e.Challenge(m.Realm))
+	if m.ErrorBody != nil {
+		m.ErrorBody(w, r, e)
+		return
+	}
+	http.Error(w, http.StatusText(e.Status), e.Status)
+}
+
+// An ErrorBody writing an RFC 7807 application/problem+json document
+func ProblemJSONBody(w http.ResponseWriter, r *http.Request, e *BearerError) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(struct {
+		Type   string `json:"type"`
+		Title  string `json:"title"`
+		Status int    `json:"status"`
+		Detail string `json:"detail,omitempty"`
+	}{"about:blank", http.StatusText(e.Status), e.Status, e.Description})
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestWriteError(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	handler := New(keyFunc, WithRealm("api"), RequireScopes("orders:read", "orders:write")).Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	var tests = []struct {
+		name      string
+		claims    jwt.Claims
+		status    int
+		challenge string
+	}{
+		{"no token", nil, http.StatusUnauthorized, `Bearer realm="api"`},
+		{"expired", jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}, http.StatusUnauthorized,
+			`Bearer realm="api", error="invalid_token", error_description="The access token expired"`},
+		{"insufficient scope", jwt.MapClaims{"scope": "orders:read"}, http.StatusForbidden,
+			`Bearer realm="api", error="insufficient_scope", error_description="The access token does not grant the required scope", scope="orders:read orders:write"`},
+	}
+
+	for _, data := range tests {
+		r := httptest.NewRequest("GET", "/orders", nil)
+		if data.claims != nil {
+			tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).SignedString(testKey)
+			r.Header.Set("Authorization", "Bearer "+tokenString)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v, got %v", data.name, data.status, w.Code)
+		}
+		if challenge := w.Header().Get("WWW-Authenticate"); challenge != data.challenge {
+			t.Errorf("[%v] Expected challenge %v, got %v", data.name, data.challenge, challenge)
+		}
+	}
+}
+
+func TestProblemJSONBody(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	handler := New(keyFunc, WithErrorBody(ProblemJSONBody)).Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	r := httptest.NewRequest("GET", "/orders", nil)
+	r.Header.Set("Authorization", "Bearer not.a.token")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	var problem map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Error decoding body: %v", err)
+	}
+	if w.Header().Get("Content-Type") != "application/problem+json" || problem["status"] != float64(http.StatusUnauthorized) || problem["detail"] != "The access token is malformed" {
+		t.Errorf("Unexpected response: %v %v", w.Header(), problem)
+	}
+}
+
+func TestBearerChallengeQuoting(t *testing.T) {
+	e := &BearerError{Code: ErrorCodeInvalidToken, Description: "bad \"token\"\n"}
+	if challenge := e.Challenge(`a\b`); challenge != `Bearer realm="ab", error="invalid_token", error_description="bad token"` {
+		t.Errorf("Unexpected challenge: %v", challenge)
+	}
+}
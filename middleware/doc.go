@@ -0,0 +1,16 @@
+// Utility package for protecting HTTP handlers with JWT bearer tokens.
+//
+// The verified token is stored in the request context and can be retrieved
+// with FromContext:
+//
+//	auth := middleware.New(keyFunc, middleware.RequireScopes("orders:write"))
+//	http.Handle("/orders", auth.Handler(ordersHandler))
+//
+// Handler has the standard func(http.Handler) http.Handler shape, so it can
+// be used directly as chi middleware:
+//
+//	r := chi.NewRouter()
+//	r.Use(auth.Handler)
+//
+// Adapters for gin and echo are in the ginjwt and echojwt subpackages.
+package middleware
@@ -0,0 +1,11 @@
+// Adapter exposing a middleware.Middleware as echo middleware.
+//
+//	auth := middleware.New(keyFunc, middleware.RequireScopes("orders:write"))
+//	e.Use(echojwt.Handler(auth))
+//
+// The verified token is available from echojwt.FromContext, and from
+// middleware.FromContext on the request context.
+//
+// This package depends on github.com/labstack/echo/v4 and is only built with
+// the echo build tag.
+package echojwt
@@ -0,0 +1,36 @@
+//go:build echo
+
+package echojwt
+
+import (
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// Key under which Handler stores the verified token in the echo context
+const ContextKey = "jwt-go/token"
+
+// Returns echo middleware verifying requests with m.  Rejected requests are
+// answered by m.ErrorHandler and not passed on.
+func Handler(m *middleware.Middleware) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			r := c.Request()
+			token, err := m.Verify(r)
+			if err != nil {
+				m.ErrorHandler(c.Response(), r, err)
+				return nil
+			}
+			c.SetRequest(r.WithContext(middleware.NewContext(r.Context(), token)))
+			c.Set(ContextKey, token)
+			return next(c)
+		}
+	}
+}
+
+// Returns the token stored by Handler, if any
+func FromContext(c echo.Context) (*jwt.Token, bool) {
+	token, ok := c.Get(ContextKey).(*jwt.Token)
+	return token, ok
+}
@@ -0,0 +1,50 @@
+//go:build echo
+
+package echojwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+var testKey = []byte("secret")
+
+func TestHandler(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	e := echo.New()
+	e.Use(Handler(middleware.New(keyFunc, middleware.RequireScopes("orders:write"))))
+	e.GET("/orders", func(c echo.Context) error {
+		if _, ok := FromContext(c); !ok {
+			t.Errorf("Token missing from echo context")
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	var tests = []struct {
+		name   string
+		claims jwt.Claims
+		status int
+	}{
+		{"valid", jwt.MapClaims{"scope": "orders:write"}, http.StatusOK},
+		{"missing scope", jwt.MapClaims{"scope": "orders:read"}, http.StatusForbidden},
+		{"no token", nil, http.StatusUnauthorized},
+	}
+
+	for _, data := range tests {
+		r := httptest.NewRequest("GET", "/orders", nil)
+		if data.claims != nil {
+			tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).SignedString(testKey)
+			r.Header.Set("Authorization", "Bearer "+tokenString)
+		}
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, r)
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v, got %v", data.name, data.status, w.Code)
+		}
+	}
+}
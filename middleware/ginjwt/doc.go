@@ -0,0 +1,11 @@
+// Adapter exposing a middleware.Middleware as gin middleware.
+//
+//	auth := middleware.New(keyFunc, middleware.RequireScopes("orders:write"))
+//	router.Use(ginjwt.Handler(auth))
+//
+// The verified token is available from ginjwt.FromContext, and from
+// middleware.FromContext on the request context.
+//
+// This package depends on github.com/gin-gonic/gin and is only built with
+// the gin build tag.
+package ginjwt
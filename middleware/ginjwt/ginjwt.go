@@ -0,0 +1,38 @@
+//go:build gin
+
+package ginjwt
+
+import (
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// Key under which Handler stores the verified token in the gin context
+const ContextKey = "jwt-go/token"
+
+// Returns gin middleware verifying requests with m.  Rejected requests are
+// answered by m.ErrorHandler and aborted.
+func Handler(m *middleware.Middleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, err := m.Verify(c.Request)
+		if err != nil {
+			m.ErrorHandler(c.Writer, c.Request, err)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(middleware.NewContext(c.Request.Context(), token))
+		c.Set(ContextKey, token)
+		c.Next()
+	}
+}
+
+// Returns the token stored by Handler, if any
+func FromContext(c *gin.Context) (*jwt.Token, bool) {
+	v, ok := c.Get(ContextKey)
+	if !ok {
+		return nil, false
+	}
+	token, ok := v.(*jwt.Token)
+	return token, ok
+}
@@ -0,0 +1,51 @@
+//go:build gin
+
+package ginjwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+var testKey = []byte("secret")
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	router := gin.New()
+	router.Use(Handler(middleware.New(keyFunc, middleware.RequireScopes("orders:write"))))
+	router.GET("/orders", func(c *gin.Context) {
+		if _, ok := FromContext(c); !ok {
+			t.Errorf("Token missing from gin context")
+		}
+		c.Status(http.StatusOK)
+	})
+
+	var tests = []struct {
+		name   string
+		claims jwt.Claims
+		status int
+	}{
+		{"valid", jwt.MapClaims{"scope": "orders:write"}, http.StatusOK},
+		{"missing scope", jwt.MapClaims{"scope": "orders:read"}, http.StatusForbidden},
+		{"no token", nil, http.StatusUnauthorized},
+	}
+
+	for _, data := range tests {
+		r := httptest.NewRequest("GET", "/orders", nil)
+		if data.claims != nil {
+			tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).SignedString(testKey)
+			r.Header.Set("Authorization", "Bearer "+tokenString)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v, got %v", data.name, data.status, w.Code)
+		}
+	}
+}
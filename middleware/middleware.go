@@ -0,0 +1,247 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/request"
+)
+
+var (
+	ErrInsufficientScope = errors.New("token does not grant the required scopes")
+	ErrInsufficientRole  = errors.New("token does not have the required roles")
+)
+
+// Checks the request's bearer token before passing it on to the next handler
+type Middleware struct {
+	KeyFunc          jwt.Keyfunc
+	Extractor        request.Extractor                                            // Defaults to request.OAuth2Extractor
+	Parser           *jwt.Parser                                                  // Defaults to jwt.NewParser()
+	NewClaims        func() jwt.Claims                                            // Returns a claims value to parse into.  Defaults to jwt.MapClaims
+	RequiredScopes   []string                                                     // Scopes the token must grant
+	RoleMapper       jwt.RoleMapper                                               // Finds the token's roles, for RequiredRoles
+	RequiredRoles    []string                                                     // Roles the token must have
+	CertificateBound bool                                                         // Require tokens bound to the client's TLS certificate by a cnf x5t#S256 claim
+	ErrorHandler     func(w http.ResponseWriter, r *http.Request, err error)      // Writes the response when a request is rejected.  Defaults to WriteError
+	Realm            string                                                       // Realm for the WWW-Authenticate challenge
+	ErrorBody        func(w http.ResponseWriter, r *http.Request, e *BearerError) // Writes the body for WriteError.  Defaults to the status text
+
+	// If set, called after each Verify with its result and how long it took
+	OnVerify func(r *http.Request, token *jwt.Token, err error, elapsed time.Duration)
+
+	// If set, each rejected request is logged here with a summary of its
+	// token, from which claims matched by Redactor are masked.  Redactor
+	// defaults to jwt.DefaultRedactor.
+	ErrorLog *log.Logger
+	Redactor *jwt.Redactor
+}
+
+// Reads the roles claim, used when no RoleMapper is set
+var defaultRoleMapper = jwt.Auth0Roles("roles")
+
+// Option is used to configure a Middleware
+type Option func(*Middleware)
+
+// Create a Middleware verifying tokens with keyFunc
+func New(keyFunc jwt.Keyfunc, options ...Option) *Middleware {
+	m := &Middleware{
+		KeyFunc:   keyFunc,
+		Extractor: request.OAuth2Extractor,
+		Parser:    jwt.NewParser(),
+		NewClaims: func() jwt.Claims { return jwt.MapClaims{} },
+	}
+	m.ErrorHandler = m.WriteError
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Require the token to grant all of scopes, in either its scope or scp claim
+func RequireScopes(scopes ...string) Option {
+	return func(m *Middleware) {
+		m.RequiredScopes = append(m.RequiredScopes, scopes...)
+	}
+}
+
+// Require the token to have all of roles, as found by mapper.  A nil mapper
+// reads the roles claim.
+func RequireRoles(mapper jwt.RoleMapper, roles ...string) Option {
+	return func(m *Middleware) {
+		m.RoleMapper = mapper
+		m.RequiredRoles = append(m.RequiredRoles, roles...)
+	}
+}
+
+// Require tokens to be bound to the client certificate of the mutual TLS
+// connection they arrive on, as described by RFC 8705
+func RequireCertificateBinding() Option {
+	return func(m *Middleware) {
+		m.CertificateBound = true
+	}
+}
+
+// Extract the token with extractor
+func WithExtractor(extractor request.Extractor) Option {
+	return func(m *Middleware) {
+		m.Extractor = extractor
+	}
+}
+
+// Parse tokens with parser
+func WithParser(parser *jwt.Parser) Option {
+	return func(m *Middleware) {
+		m.Parser = parser
+	}
+}
+
+// Parse claims into values returned by newClaims
+func WithClaims(newClaims func() jwt.Claims) Option {
+	return func(m *Middleware) {
+		m.NewClaims = newClaims
+	}
+}
+
+// Write rejections with handler
+func WithErrorHandler(handler func(w http.ResponseWriter, r *http.Request, err error)) Option {
+	return func(m *Middleware) {
+		m.ErrorHandler = handler
+	}
+}
+
+// Name realm in WWW-Authenticate challenges
+func WithRealm(realm string) Option {
+	return func(m *Middleware) {
+		m.Realm = realm
+	}
+}
+
+// Write error response bodies with body, such as ProblemJSONBody
+func WithErrorBody(body func(w http.ResponseWriter, r *http.Request, e *BearerError)) Option {
+	return func(m *Middleware) {
+		m.ErrorBody = body
+	}
+}
+
+// Call onVerify after each Verify
+func WithOnVerify(onVerify func(r *http.Request, token *jwt.Token, err error, elapsed time.Duration)) Option {
+	return func(m *Middleware) {
+		m.OnVerify = onVerify
+	}
+}
+
+// Log rejected requests to logger, masking the claims matched by redactor.
+// A nil redactor uses jwt.DefaultRedactor.
+func WithErrorLog(logger *log.Logger, redactor *jwt.Redactor) Option {
+	return func(m *Middleware) {
+		m.ErrorLog = logger
+		m.Redactor = redactor
+	}
+}
+
+// Wrap next so it only receives requests with a valid token
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := m.Verify(r)
+		if err != nil {
+			m.ErrorHandler(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), token)))
+	})
+}
+
+// Extract and verify the request's token, and check its scopes
+func (m *Middleware) Verify(r *http.Request) (*jwt.Token, error) {
+	start := time.Now()
+	token, err := m.verify(r)
+	if err != nil && m.ErrorLog != nil {
+		m.logError(r, token, err)
+	}
+	// Tokens that failed to parse are passed on only to the log
+	if token != nil && !token.Valid {
+		token = nil
+	}
+	if m.OnVerify != nil {
+		m.OnVerify(r, token, err, time.Since(start))
+	}
+	return token, err
+}
+
+func (m *Middleware) verify(r *http.Request) (*jwt.Token, error) {
+	token, err := request.ParseFromRequest(r, m.Extractor, m.KeyFunc,
+		request.WithClaims(m.NewClaims()), request.WithParser(m.Parser))
+	if err != nil {
+		return token, err
+	}
+	if !jwt.HasScopes(token, m.RequiredScopes...) {
+		return token, ErrInsufficientScope
+	}
+	if len(m.RequiredRoles) > 0 && !jwt.HasRoles(token.Claims, m.roleMapper(), m.RequiredRoles...) {
+		return token, ErrInsufficientRole
+	}
+	if m.CertificateBound {
+		if err := jwt.VerifyTLSBinding(token, r.TLS); err != nil {
+			return token, err
+		}
+	}
+	return token, nil
+}
+
+func (m *Middleware) roleMapper() jwt.RoleMapper {
+	if m.RoleMapper == nil {
+		return defaultRoleMapper
+	}
+	return m.RoleMapper
+}
+
+func (m *Middleware) logError(r *http.Request, token *jwt.Token, err error) {
+	if token == nil {
+		m.ErrorLog.Printf("jwt: rejected %s %s: %s: %v", r.Method, r.URL.Path, ErrorReason(err), err)
+		return
+	}
+	redactor := m.Redactor
+	if redactor == nil {
+		redactor = jwt.DefaultRedactor
+	}
+	m.ErrorLog.Printf("jwt: rejected %s %s: %s: %v: %s", r.Method, r.URL.Path, ErrorReason(err), err, redactor.Debug(token))
+}
+
+// A short name for why Verify rejected a request, for logs and metrics:
+// "no_token", "insufficient_scope", "insufficient_role", "certificate_binding",
+// or one of the reasons from jwt.ErrorReason
+func ErrorReason(err error) string {
+	switch err {
+	case ErrInsufficientScope:
+		return "insufficient_scope"
+	case ErrInsufficientRole:
+		return "insufficient_role"
+	case request.ErrNoTokenInRequest:
+		return "no_token"
+	case jwt.ErrCertificateBinding, jwt.ErrMissingClientCertificate:
+		return "certificate_binding"
+	}
+	return jwt.ErrorReason(err)
+}
+
+// Responds as Middleware.WriteError does, without a realm or scope
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	new(Middleware).WriteError(w, r, err)
+}
+
+type contextKey struct{}
+
+// Returns a copy of ctx carrying token
+func NewContext(ctx context.Context, token *jwt.Token) context.Context {
+	return context.WithValue(ctx, contextKey{}, token)
+}
+
+// Returns the token stored by Handler, if any
+func FromContext(ctx context.Context) (*jwt.Token, bool) {
+	token, ok := ctx.Value(contextKey{}).(*jwt.Token)
+	return token, ok
+}
@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var testKey = []byte("secret")
+
+func TestMiddleware(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	var seen *jwt.Token
+	handler := New(keyFunc, RequireScopes("orders:write")).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = FromContext(r.Context())
+	}))
+
+	var tests = []struct {
+		name   string
+		claims jwt.Claims
+		status int
+	}{
+		{"scope string", jwt.MapClaims{"scope": "orders:read orders:write"}, http.StatusOK},
+		{"scp array", jwt.MapClaims{"scp": []string{"orders:write"}}, http.StatusOK},
+		{"missing scope", jwt.MapClaims{"scope": "orders:read"}, http.StatusForbidden},
+		{"no token", nil, http.StatusUnauthorized},
+	}
+
+	for _, data := range tests {
+		seen = nil
+		r := httptest.NewRequest("GET", "/orders", nil)
+		if data.claims != nil {
+			tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).SignedString(testKey)
+			r.Header.Set("Authorization", "Bearer "+tokenString)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v, got %v", data.name, data.status, w.Code)
+		}
+		if (data.status == http.StatusOK) != (seen != nil) {
+			t.Errorf("[%v] Token in context mismatch: %v", data.name, seen)
+		}
+	}
+}
+
+func TestMiddlewareStrictParser(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	handler := New(keyFunc).Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	// The default parser is NewParser's, which rejects padded segments
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(testKey)
+	r := httptest.NewRequest("GET", "/orders", nil)
+	r.Header.Set("Authorization", "Bearer "+tokenString+"=")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %v, got %v", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestMiddlewareRequireRoles(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	handler := New(keyFunc, RequireRoles(jwt.KeycloakRoles("orders"), "orders:write")).Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	var tests = []struct {
+		name   string
+		claims jwt.MapClaims
+		status int
+	}{
+		{"has role", jwt.MapClaims{"resource_access": map[string]interface{}{"orders": map[string]interface{}{"roles": []string{"write"}}}}, http.StatusOK},
+		{"realm role only", jwt.MapClaims{"realm_access": map[string]interface{}{"roles": []string{"write"}}}, http.StatusForbidden},
+	}
+
+	for _, data := range tests {
+		tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).SignedString(testKey)
+		r := httptest.NewRequest("GET", "/orders", nil)
+		r.Header.Set("Authorization", "Bearer "+tokenString)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v, got %v", data.name, data.status, w.Code)
+		}
+	}
+}
+
+func TestMiddlewareRequireRolesNilMapper(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	handler := New(keyFunc, RequireRoles(nil, "admin")).Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	var tests = []struct {
+		name   string
+		claims jwt.MapClaims
+		status int
+	}{
+		{"roles claim", jwt.MapClaims{"roles": []string{"admin"}}, http.StatusOK},
+		{"missing role", jwt.MapClaims{"roles": []string{"user"}}, http.StatusForbidden},
+		{"no roles claim", jwt.MapClaims{"sub": "user"}, http.StatusForbidden},
+	}
+
+	for _, data := range tests {
+		tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).SignedString(testKey)
+		r := httptest.NewRequest("GET", "/orders", nil)
+		r.Header.Set("Authorization", "Bearer "+tokenString)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v, got %v", data.name, data.status, w.Code)
+		}
+	}
+}
+
+func TestMiddlewareOnVerify(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	var reasons []string
+	m := New(keyFunc, RequireScopes("orders:write"), WithOnVerify(func(r *http.Request, token *jwt.Token, err error, elapsed time.Duration) {
+		reasons = append(reasons, ErrorReason(err))
+	}))
+	handler := m.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	for _, claims := range []jwt.Claims{jwt.MapClaims{"scope": "orders:write"}, jwt.MapClaims{"scope": "orders:read"}, nil} {
+		r := httptest.NewRequest("GET", "/orders", nil)
+		if claims != nil {
+			tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testKey)
+			r.Header.Set("Authorization", "Bearer "+tokenString)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	if !reflect.DeepEqual(reasons, []string{"", "insufficient_scope", "no_token"}) {
+		t.Errorf("Unexpected reasons: %q", reasons)
+	}
+}
+
+func TestMiddlewareErrorLog(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	var buf bytes.Buffer
+	var verified []*jwt.Token
+	m := New(keyFunc, RequireScopes("orders:write"), WithErrorLog(log.New(&buf, "", 0), nil),
+		WithOnVerify(func(r *http.Request, token *jwt.Token, err error, elapsed time.Duration) {
+			verified = append(verified, token)
+		}))
+	handler := m.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	var tests = []struct {
+		name   string
+		claims jwt.MapClaims
+		key    []byte
+		logged string
+	}{
+		{"valid", jwt.MapClaims{"scope": "orders:write", "email": "a@example.com"}, testKey, ""},
+		{"missing scope", jwt.MapClaims{"scope": "orders:read", "email": "a@example.com"}, testKey, `insufficient_scope: token does not grant the required scopes: alg=HS256`},
+		{"bad signature", jwt.MapClaims{"sub": "alice", "email": "a@example.com"}, []byte("wrong"), `signature_invalid: signature is invalid: alg=HS256 typ=JWT valid=false header={"alg":"HS256","typ":"JWT"} claims={"email":"[REDACTED]","sub":"alice"}`},
+		{"no token", nil, nil, "no_token"},
+	}
+
+	for _, data := range tests {
+		buf.Reset()
+		verified = nil
+		r := httptest.NewRequest("GET", "/orders", nil)
+		if data.claims != nil {
+			tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).SignedString(data.key)
+			r.Header.Set("Authorization", "Bearer "+tokenString)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		logged := buf.String()
+		if (data.logged == "") != (logged == "") || !strings.Contains(logged, data.logged) {
+			t.Errorf("[%v] Expected log containing %q, got %q", data.name, data.logged, logged)
+		}
+		if strings.Contains(logged, "a@example.com") {
+			t.Errorf("[%v] Log leaked a redacted claim: %q", data.name, logged)
+		}
+		if data.name == "bad signature" && verified[0] != nil {
+			t.Errorf("[%v] Unverified token passed to OnVerify", data.name)
+		}
+	}
+}
+
+func TestMiddlewareRequireCertificateBinding(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "client"}}
+	der, _ := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	cert, _ := x509.ParseCertificate(der)
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+	handler := New(keyFunc, RequireCertificateBinding()).Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	var tests = []struct {
+		name   string
+		claims jwt.MapClaims
+		tls    *tls.ConnectionState
+		status int
+	}{
+		{"bound", jwt.MapClaims{"cnf": jwt.CertificateConfirmation(cert)}, &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}, http.StatusOK},
+		{"unbound", jwt.MapClaims{"sub": "alice"}, &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}, http.StatusUnauthorized},
+		{"no client certificate", jwt.MapClaims{"cnf": jwt.CertificateConfirmation(cert)}, nil, http.StatusUnauthorized},
+	}
+
+	for _, data := range tests {
+		r := httptest.NewRequest("GET", "/orders", nil)
+		r.TLS = data.tls
+		tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).SignedString(testKey)
+		r.Header.Set("Authorization", "Bearer "+tokenString)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != data.status {
+			t.Errorf("[%v] Expected status %v, got %v", data.name, data.status, w.Code)
+		}
+	}
+}
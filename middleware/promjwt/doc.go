@@ -0,0 +1,17 @@
+// Prometheus metrics for the middleware and remote JWK Sets.  A Collector
+// exports:
+//
+//	<namespace>_jwt_requests_total            counter, by outcome and reason
+//	<namespace>_jwt_verify_duration_seconds   histogram, by outcome
+//	<namespace>_jwt_jwks_refreshes_total      counter, by url and result
+//
+// Register it, then instrument what should be measured:
+//
+//	metrics := promjwt.NewCollector("api")
+//	prometheus.MustRegister(metrics)
+//	metrics.Instrument(auth)
+//	metrics.InstrumentRemote(provider.Keys)
+//
+// This package depends on github.com/prometheus/client_golang and is only
+// built with the prometheus build tag.
+package promjwt
@@ -0,0 +1,101 @@
+//go:build prometheus
+
+package promjwt
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/jwks"
+	"github.com/dgrijalva/jwt-go/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collects middleware and JWK Set metrics.  It implements prometheus.Collector.
+type Collector struct {
+	requests  *prometheus.CounterVec
+	latency   *prometheus.HistogramVec
+	refreshes *prometheus.CounterVec
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// Create a Collector whose metric names start with namespace, if non-empty
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jwt",
+			Name:      "requests_total",
+			Help:      "Requests checked by the JWT middleware, by outcome and rejection reason.",
+		}, []string{"outcome", "reason"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "jwt",
+			Name:      "verify_duration_seconds",
+			Help:      "Time spent extracting and verifying tokens.",
+			Buckets:   []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"outcome"}),
+		refreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jwt",
+			Name:      "jwks_refreshes_total",
+			Help:      "JWK Set fetches, by URL and result.",
+		}, []string{"url", "result"}),
+	}
+}
+
+// Record the results of m's Verify, keeping any OnVerify already set
+func (c *Collector) Instrument(m *middleware.Middleware) {
+	previous := m.OnVerify
+	m.OnVerify = func(r *http.Request, token *jwt.Token, err error, elapsed time.Duration) {
+		c.ObserveVerify(err, elapsed)
+		if previous != nil {
+			previous(r, token, err, elapsed)
+		}
+	}
+}
+
+// Record the fetches of remote, keeping any OnRefresh already set
+func (c *Collector) InstrumentRemote(remote *jwks.Remote) {
+	previous := remote.OnRefresh
+	url := remote.URL
+	remote.OnRefresh = func(err error) {
+		c.ObserveRefresh(url, err)
+		if previous != nil {
+			previous(err)
+		}
+	}
+}
+
+// Record a verification result directly, for code not using the middleware
+func (c *Collector) ObserveVerify(err error, elapsed time.Duration) {
+	outcome := "accepted"
+	if err != nil {
+		outcome = "rejected"
+	}
+	c.requests.WithLabelValues(outcome, middleware.ErrorReason(err)).Inc()
+	c.latency.WithLabelValues(outcome).Observe(elapsed.Seconds())
+}
+
+// Record a JWK Set fetch directly
+func (c *Collector) ObserveRefresh(url string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	c.refreshes.WithLabelValues(url, result).Inc()
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.latency.Describe(ch)
+	c.refreshes.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.latency.Collect(ch)
+	c.refreshes.Collect(ch)
+}
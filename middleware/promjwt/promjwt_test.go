@@ -0,0 +1,57 @@
+//go:build prometheus
+
+package promjwt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/jwks"
+	"github.com/dgrijalva/jwt-go/middleware"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var testKey = []byte("secret")
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("test")
+	auth := middleware.New(func(*jwt.Token) (interface{}, error) { return testKey, nil }, middleware.RequireScopes("orders:write"))
+	c.Instrument(auth)
+	handler := auth.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	send := func(claims jwt.Claims) {
+		r := httptest.NewRequest("GET", "/orders", nil)
+		if claims != nil {
+			tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testKey)
+			r.Header.Set("Authorization", "Bearer "+tokenString)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	send(jwt.MapClaims{"scope": "orders:write"})
+	send(jwt.MapClaims{"scope": "orders:read"})
+	send(nil)
+
+	var tests = []struct {
+		outcome, reason string
+		count           float64
+	}{
+		{"accepted", "", 1},
+		{"rejected", "insufficient_scope", 1},
+		{"rejected", "no_token", 1},
+	}
+	for _, data := range tests {
+		if count := testutil.ToFloat64(c.requests.WithLabelValues(data.outcome, data.reason)); count != data.count {
+			t.Errorf("[%v %v] Expected %v requests, got %v", data.outcome, data.reason, data.count, count)
+		}
+	}
+
+	remote := jwks.NewRemote("https://auth.example.com/jwks.json")
+	c.InstrumentRemote(remote)
+	remote.OnRefresh(errors.New("fetch failed"))
+	if count := testutil.ToFloat64(c.refreshes.WithLabelValues(remote.URL, "failure")); count != 1 {
+		t.Errorf("Expected 1 failed refresh, got %v", count)
+	}
+}
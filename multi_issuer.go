@@ -0,0 +1,92 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownIssuer = errors.New("token issuer is not trusted")
+
+// How tokens from one issuer are verified by a MultiIssuerVerifier
+type IssuerConfig struct {
+	KeyFunc      KeyfuncContext // Returns the issuer's keys, such as jwks.Remote.KeyfuncContext.  Required
+	Audience     string         // If set, aud must contain it
+	ValidMethods []string       // If set, only these algs are accepted from the issuer
+}
+
+// Verifies tokens from several issuers, such as a gateway accepting tokens
+// from more than one identity provider.  The configuration is chosen by the
+// token's iss claim, so keys, audiences and algorithms trusted for one issuer
+// are never used for another's tokens.  Configure it before use; it is then
+// safe for concurrent use.
+type MultiIssuerVerifier struct {
+	Issuers map[string]*IssuerConfig
+
+	// Options shared by all issuers, such as Validators.  Defaults to
+	// NewParser().  Its ValidMethods are checked in addition to the issuer's.
+	Parser *Parser
+}
+
+// Create a MultiIssuerVerifier sharing the options of parser, which may be nil
+func NewMultiIssuerVerifier(parser *Parser) *MultiIssuerVerifier {
+	return &MultiIssuerVerifier{Issuers: make(map[string]*IssuerConfig), Parser: parser}
+}
+
+// Trust tokens from issuer, verified as config describes
+func (v *MultiIssuerVerifier) Add(issuer string, config *IssuerConfig) {
+	if v.Issuers == nil {
+		v.Issuers = make(map[string]*IssuerConfig)
+	}
+	v.Issuers[issuer] = config
+}
+
+// Parse and verify a token from one of the issuers
+func (v *MultiIssuerVerifier) Parse(tokenString string, claims Claims) (*Token, error) {
+	return v.ParseContext(context.Background(), tokenString, claims)
+}
+
+// Like Parse, passing ctx to the issuer's KeyFunc
+func (v *MultiIssuerVerifier) ParseContext(ctx context.Context, tokenString string, claims Claims) (*Token, error) {
+	parser := v.Parser
+	if parser == nil {
+		parser = NewParser()
+	}
+	return parser.ParseWithClaimsContext(ctx, tokenString, claims, v.KeyfuncContext)
+}
+
+// A Keyfunc routing to the issuer's KeyFunc after checking the token's alg
+// and aud against the issuer's configuration, for use with a Middleware or
+// another Parser
+func (v *MultiIssuerVerifier) Keyfunc(token *Token) (interface{}, error) {
+	return v.KeyfuncContext(context.Background(), token)
+}
+
+// Like Keyfunc, passing ctx to the issuer's KeyFunc
+func (v *MultiIssuerVerifier) KeyfuncContext(ctx context.Context, token *Token) (interface{}, error) {
+	claims, err := claimsToMap(token.Claims)
+	if err != nil {
+		return nil, err
+	}
+	issuer, _ := claims["iss"].(string)
+	config, ok := v.Issuers[issuer]
+	if !ok || config.KeyFunc == nil {
+		return nil, &ValidationError{Inner: ErrUnknownIssuer, Errors: ValidationErrorIssuer}
+	}
+	if config.ValidMethods != nil && !containsString(config.ValidMethods, token.Method.Alg()) {
+		return nil, NewValidationError(fmt.Sprintf("signing method %v is invalid for issuer %v", token.Method.Alg(), issuer), ValidationErrorSignatureInvalid)
+	}
+	if config.Audience != "" && !claims.VerifyAudience(config.Audience, true) {
+		return nil, NewValidationError("token was not issued for "+config.Audience, ValidationErrorAudience)
+	}
+	return config.KeyFunc(ctx, token)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
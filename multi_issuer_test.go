@@ -0,0 +1,62 @@
+package jwt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func TestMultiIssuerVerifier(t *testing.T) {
+	rsaKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	verifier := jwt.NewMultiIssuerVerifier(nil)
+	verifier.Add("https://idp-a.example.com", &jwt.IssuerConfig{
+		KeyFunc:      func(context.Context, *jwt.Token) (interface{}, error) { return jwtTestDefaultKey, nil },
+		Audience:     "api",
+		ValidMethods: []string{"RS256"},
+	})
+	verifier.Add("https://idp-b.example.com", &jwt.IssuerConfig{
+		KeyFunc: func(context.Context, *jwt.Token) (interface{}, error) { return hmacTestKey, nil },
+	})
+
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+		s, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	var tests = []struct {
+		name   string
+		token  string
+		errors uint32
+	}{
+		{"issuer a", sign(jwt.SigningMethodRS256, rsaKey, jwt.MapClaims{"iss": "https://idp-a.example.com", "aud": "api"}), 0},
+		{"issuer b", sign(jwt.SigningMethodHS256, hmacTestKey, jwt.MapClaims{"iss": "https://idp-b.example.com"}), 0},
+		{"wrong audience", sign(jwt.SigningMethodRS256, rsaKey, jwt.MapClaims{"iss": "https://idp-a.example.com", "aud": "other"}), jwt.ValidationErrorAudience},
+		{"alg not allowed for issuer", sign(jwt.SigningMethodHS256, hmacTestKey, jwt.MapClaims{"iss": "https://idp-a.example.com", "aud": "api"}), jwt.ValidationErrorSignatureInvalid},
+		{"key of another issuer", sign(jwt.SigningMethodHS256, []byte("other"), jwt.MapClaims{"iss": "https://idp-b.example.com"}), jwt.ValidationErrorSignatureInvalid},
+		{"unknown issuer", sign(jwt.SigningMethodHS256, hmacTestKey, jwt.MapClaims{"iss": "https://evil.example.com"}), jwt.ValidationErrorIssuer},
+		{"no issuer", sign(jwt.SigningMethodHS256, hmacTestKey, jwt.MapClaims{}), jwt.ValidationErrorIssuer},
+	}
+
+	for _, data := range tests {
+		token, err := verifier.Parse(data.token, jwt.MapClaims{})
+		if data.errors == 0 {
+			if err != nil || !token.Valid {
+				t.Errorf("[%v] Unexpected error: %v", data.name, err)
+			}
+			continue
+		}
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&data.errors == 0 {
+			t.Errorf("[%v] Expected error flags %v, got %v", data.name, data.errors, err)
+		}
+	}
+
+	// The Keyfunc routes the same way in other parsers
+	if _, err := jwt.Parse(tests[1].token, verifier.Keyfunc); err != nil {
+		t.Errorf("Unexpected error using Keyfunc: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"context"
+	"strings"
+)
+
+// Parse a nested token (cty: JWT) and return both the outer and inner tokens.
+// outerKeyFunc supplies the key for the enclosing signature, innerKeyFunc the
+// key for the enclosed token.  The outer token has no Claims; its payload is
+// the inner token.
+func ParseNested(tokenString string, outerKeyFunc, innerKeyFunc Keyfunc) (outer, inner *Token, err error) {
+	return new(Parser).ParseNested(tokenString, outerKeyFunc, MapClaims{}, innerKeyFunc)
+}
+
+// Parse a nested token (cty: JWT), verifying the outer signature with outerKeyFunc
+// and then parsing the inner token into claims using innerKeyFunc.  Parser options
+// such as RequiredType apply to the inner token.  The inner token is only parsed
+// if the outer token is valid.
+func (p *Parser) ParseNested(tokenString string, outerKeyFunc Keyfunc, claims Claims, innerKeyFunc Keyfunc) (outer, inner *Token, err error) {
+	var parts []string
+	if outer, parts, err = p.parseHeader(tokenString); err != nil {
+		return outer, nil, err
+	}
+
+	if !isNestedToken(outer.Header) {
+		return outer, nil, NewValidationError("token is not a nested JWT (cty: JWT)", ValidationErrorMalformed)
+	}
+	if err = validateCriticalHeaders(outer.Header); err != nil {
+		return outer, nil, err
+	}
+
+	var payload []byte
+	if payload, err = p.decodePayload(outer.Header, parts[1]); err != nil {
+		return outer, nil, err
+	}
+	outer.RawClaims = payload
+	if err = lookupSigningMethod(outer); err != nil {
+		return outer, nil, err
+	}
+
+	// Verify the outer signature before looking at the inner token
+	key, err := p.lookupKey(context.Background(), outer, keyfuncWithContext(outerKeyFunc))
+	if err != nil {
+		return outer, nil, err
+	}
+	outer.Signature = parts[2]
+	if err = verifySignatureContext(context.Background(), outer.Method, strings.Join(parts[0:2], "."), outer.Signature, key, p.StrictDecoding); err != nil {
+		return outer, nil, &ValidationError{Inner: err, Errors: ValidationErrorSignatureInvalid}
+	}
+	outer.Valid = true
+
+	inner, err = p.ParseWithClaims(string(payload), claims, innerKeyFunc)
+	return outer, inner, err
+}
@@ -0,0 +1,53 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func makeNestedToken(t *testing.T, innerKey, outerKey interface{}) string {
+	inner, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"foo": "bar"}).SignedString(innerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := jwt.EncodeSegment([]byte(`{"alg":"HS256","cty":"JWT"}`))
+	signingString := header + "." + jwt.EncodeSegment([]byte(inner))
+	sig, err := jwt.SigningMethodHS256.Sign(signingString, outerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingString + "." + sig
+}
+
+func TestParseNested(t *testing.T) {
+	privateKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	tokenString := makeNestedToken(t, privateKey, hmacTestKey)
+
+	outerKeyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	outer, inner, err := jwt.ParseNested(tokenString, outerKeyFunc, defaultKeyFunc)
+	if err != nil {
+		t.Fatalf("Error parsing nested token: %v", err)
+	}
+	if !outer.Valid || !inner.Valid {
+		t.Errorf("Expected both tokens to be valid")
+	}
+	if claims := inner.Claims.(jwt.MapClaims); claims["foo"] != "bar" {
+		t.Errorf("Inner claims mismatch: %v", claims)
+	}
+
+	// outer signature must be checked before the inner token is parsed
+	wrongKeyFunc := func(*jwt.Token) (interface{}, error) { return []byte("wrong"), nil }
+	outer, inner, err = jwt.ParseNested(tokenString, wrongKeyFunc, defaultKeyFunc)
+	if err == nil || outer.Valid || inner != nil {
+		t.Errorf("Nested token with invalid outer signature passed validation")
+	}
+
+	// plain tokens are not nested
+	plain, _ := jwt.New(jwt.SigningMethodHS256).SignedString(hmacTestKey)
+	if _, _, err = jwt.ParseNested(plain, outerKeyFunc, defaultKeyFunc); err == nil {
+		t.Errorf("Plain token accepted as nested token")
+	}
+}
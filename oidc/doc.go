@@ -0,0 +1,7 @@
+// Utility package for verifying tokens from OpenID Connect providers.
+//
+// NewProvider fetches the issuer's discovery document and JWK Set:
+//
+//	provider, err := oidc.NewProvider(ctx, "https://accounts.google.com")
+//	token, err := provider.Parse(idToken, &jwt.StandardClaims{})
+package oidc
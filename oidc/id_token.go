@@ -0,0 +1,140 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var (
+	ErrNonceMismatch      = errors.New("nonce claim does not match")
+	ErrAuthorizedParty    = errors.New("azp claim is missing or does not match the client id")
+	ErrAccessTokenHash    = errors.New("at_hash claim does not match the access token")
+	ErrCodeHash           = errors.New("c_hash claim does not match the authorization code")
+	ErrUnsupportedHashAlg = errors.New("no hash is defined for the signing method")
+)
+
+// Claims of an ID token, from OpenID Connect Core section 2
+type IDTokenClaims struct {
+	Issuer          string           `json:"iss"`
+	Subject         string           `json:"sub"`
+	Audience        jwt.ClaimStrings `json:"aud"`
+	ExpiresAt       int64            `json:"exp"`
+	IssuedAt        int64            `json:"iat"`
+	AuthTime        int64            `json:"auth_time,omitempty"`
+	Nonce           string           `json:"nonce,omitempty"`
+	AuthorizedParty string           `json:"azp,omitempty"`
+	AccessTokenHash string           `json:"at_hash,omitempty"`
+	CodeHash        string           `json:"c_hash,omitempty"`
+}
+
+// Validates the time based claims.  Unlike jwt.StandardClaims, exp and iat are required.
+func (c *IDTokenClaims) Valid() error {
+	if c.ExpiresAt == 0 || c.IssuedAt == 0 {
+		return jwt.NewValidationError("ID token must have exp and iat claims", jwt.ValidationErrorClaimsInvalid)
+	}
+	return jwt.StandardClaims{ExpiresAt: c.ExpiresAt, IssuedAt: c.IssuedAt}.Valid()
+}
+
+// Compares the iss claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c *IDTokenClaims) VerifyIssuer(cmp string, req bool) bool {
+	return jwt.MapClaims{"iss": c.Issuer}.VerifyIssuer(cmp, req)
+}
+
+// Checks the ID token specific claims of a parsed and verified token
+type IDTokenVerifier struct {
+	ClientID    string // Required.  Must be in aud, and equal azp when present
+	Nonce       string // If set, the nonce claim must equal this value
+	AccessToken string // If set, at_hash is required and must match this access token
+	Code        string // If set, c_hash is required and must match this authorization code
+}
+
+// Check the claims of token, which must have been parsed with *IDTokenClaims
+func (v *IDTokenVerifier) Verify(token *jwt.Token) error {
+	claims, ok := token.Claims.(*IDTokenClaims)
+	if !ok {
+		return jwt.NewValidationError("token claims are not *IDTokenClaims", jwt.ValidationErrorClaimsInvalid)
+	}
+
+	if !claims.Audience.Verify(v.ClientID, true) {
+		return jwt.NewValidationError("ID token was not issued for "+v.ClientID, jwt.ValidationErrorAudience)
+	}
+	// azp is required when there are several audiences, and must name us when present
+	if len(claims.Audience) > 1 || claims.AuthorizedParty != "" {
+		if !equal(claims.AuthorizedParty, v.ClientID) {
+			return &jwt.ValidationError{Inner: ErrAuthorizedParty, Errors: jwt.ValidationErrorClaimsInvalid}
+		}
+	}
+
+	if v.Nonce != "" && !equal(claims.Nonce, v.Nonce) {
+		return &jwt.ValidationError{Inner: ErrNonceMismatch, Errors: jwt.ValidationErrorClaimsInvalid}
+	}
+	if v.AccessToken != "" {
+		if err := verifyHash(token, claims.AccessTokenHash, v.AccessToken, ErrAccessTokenHash); err != nil {
+			return err
+		}
+	}
+	if v.Code != "" {
+		if err := verifyHash(token, claims.CodeHash, v.Code, ErrCodeHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Parse and verify an ID token from the provider, then check its ID token
+// claims with verifier
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken string, verifier *IDTokenVerifier) (*IDTokenClaims, error) {
+	claims := &IDTokenClaims{}
+	token, err := p.ParseContext(ctx, rawIDToken, claims)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifier.Verify(token); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Compute the at_hash or c_hash for value: the left half of its hash, using
+// the hash of the signing algorithm, base64url encoded
+func TokenHash(alg, value string) (string, error) {
+	var hash crypto.Hash
+	switch {
+	case alg == "EdDSA":
+		hash = crypto.SHA512
+	case strings.HasSuffix(alg, "256"):
+		hash = crypto.SHA256
+	case strings.HasSuffix(alg, "384"):
+		hash = crypto.SHA384
+	case strings.HasSuffix(alg, "512"):
+		hash = crypto.SHA512
+	default:
+		return "", ErrUnsupportedHashAlg
+	}
+
+	h := hash.New()
+	h.Write([]byte(value))
+	sum := h.Sum(nil)
+	return jwt.EncodeSegment(sum[:len(sum)/2]), nil
+}
+
+func verifyHash(token *jwt.Token, claim, value string, mismatch error) error {
+	expected, err := TokenHash(token.Method.Alg(), value)
+	if err != nil {
+		return &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorClaimsInvalid}
+	}
+	if !equal(claim, expected) {
+		return &jwt.ValidationError{Inner: mismatch, Errors: jwt.ValidationErrorClaimsInvalid}
+	}
+	return nil
+}
+
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
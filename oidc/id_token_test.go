@@ -0,0 +1,70 @@
+package oidc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestTokenHash(t *testing.T) {
+	hash, err := TokenHash("RS256", "jHkWEdUXMU1BwAsC4vtUsZwnNp2Ss1fmOWHPNGx3cxg")
+	if err != nil || hash != "572hWpoxBvAbliWIbbnxIw" {
+		t.Errorf("Unexpected hash %v: %v", hash, err)
+	}
+	if _, err := TokenHash("none", "x"); err != ErrUnsupportedHashAlg {
+		t.Errorf("Expected ErrUnsupportedHashAlg, got %v", err)
+	}
+}
+
+func TestIDTokenVerifier(t *testing.T) {
+	atHash, _ := TokenHash("HS256", "access-token")
+	cHash, _ := TokenHash("HS256", "code")
+	now := time.Now()
+
+	var tests = []struct {
+		name     string
+		claims   IDTokenClaims
+		verifier IDTokenVerifier
+		valid    bool
+		err      error
+	}{
+		{"valid", IDTokenClaims{Audience: jwt.ClaimStrings{"client"}, Nonce: "n-0S6"}, IDTokenVerifier{ClientID: "client", Nonce: "n-0S6"}, true, nil},
+		{"wrong audience", IDTokenClaims{Audience: jwt.ClaimStrings{"other"}}, IDTokenVerifier{ClientID: "client"}, false, nil},
+		{"nonce mismatch", IDTokenClaims{Audience: jwt.ClaimStrings{"client"}, Nonce: "replayed"}, IDTokenVerifier{ClientID: "client", Nonce: "n-0S6"}, false, ErrNonceMismatch},
+		{"multiple audiences with azp", IDTokenClaims{Audience: jwt.ClaimStrings{"client", "api"}, AuthorizedParty: "client"}, IDTokenVerifier{ClientID: "client"}, true, nil},
+		{"multiple audiences without azp", IDTokenClaims{Audience: jwt.ClaimStrings{"client", "api"}}, IDTokenVerifier{ClientID: "client"}, false, ErrAuthorizedParty},
+		{"azp for another client", IDTokenClaims{Audience: jwt.ClaimStrings{"client"}, AuthorizedParty: "api"}, IDTokenVerifier{ClientID: "client"}, false, ErrAuthorizedParty},
+		{"hashes", IDTokenClaims{Audience: jwt.ClaimStrings{"client"}, AccessTokenHash: atHash, CodeHash: cHash}, IDTokenVerifier{ClientID: "client", AccessToken: "access-token", Code: "code"}, true, nil},
+		{"at_hash missing", IDTokenClaims{Audience: jwt.ClaimStrings{"client"}}, IDTokenVerifier{ClientID: "client", AccessToken: "access-token"}, false, ErrAccessTokenHash},
+		{"c_hash mismatch", IDTokenClaims{Audience: jwt.ClaimStrings{"client"}, CodeHash: atHash}, IDTokenVerifier{ClientID: "client", Code: "code"}, false, ErrCodeHash},
+	}
+
+	for _, data := range tests {
+		data.claims.ExpiresAt = now.Add(time.Hour).Unix()
+		data.claims.IssuedAt = now.Unix()
+		tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &data.claims).SignedString([]byte("secret"))
+		token, err := jwt.ParseWithClaims(tokenString, &IDTokenClaims{}, func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil })
+		if err != nil {
+			t.Fatalf("[%v] Error parsing token: %v", data.name, err)
+		}
+
+		err = data.verifier.Verify(token)
+		if data.valid && err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		}
+		if !data.valid && err == nil {
+			t.Errorf("[%v] Invalid token passed verification", data.name)
+		}
+		if data.err != nil {
+			if ve, ok := err.(*jwt.ValidationError); !ok || ve.Inner != data.err {
+				t.Errorf("[%v] Expected %v, got %v", data.name, data.err, err)
+			}
+		}
+	}
+
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &IDTokenClaims{Audience: jwt.ClaimStrings{"client"}}).SignedString([]byte("secret"))
+	if _, err := jwt.ParseWithClaims(tokenString, &IDTokenClaims{}, func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil }); err == nil {
+		t.Errorf("ID token without exp and iat passed validation")
+	}
+}
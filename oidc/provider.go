@@ -0,0 +1,142 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/jwks"
+)
+
+// Path of the discovery document, relative to the issuer
+const DiscoveryPath = "/.well-known/openid-configuration"
+
+// Largest discovery document Discover will read
+const maxDocumentSize = 1 << 20
+
+// Provider metadata from the discovery document.  Only the fields needed to
+// verify tokens are decoded.
+type Configuration struct {
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	AuthorizationEndpoint            string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint                    string   `json:"token_endpoint,omitempty"`
+	UserinfoEndpoint                 string   `json:"userinfo_endpoint,omitempty"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported,omitempty"`
+}
+
+// Fetch the discovery document for issuer.  client may be nil to use
+// http.DefaultClient.  As required by OpenID Connect Discovery, the issuer in
+// the document must match the one requested.
+func Discover(ctx context.Context, client *http.Client, issuer string) (*Configuration, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	url := strings.TrimSuffix(issuer, "/") + DiscoveryPath
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil, fmt.Errorf("fetching %v: unexpected status %v", url, resp.Status)
+	}
+	config := &Configuration{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(config); err != nil {
+		return nil, fmt.Errorf("decoding %v: %v", url, err)
+	}
+
+	if config.Issuer != issuer {
+		return nil, fmt.Errorf("discovery document issuer %q does not match %q", config.Issuer, issuer)
+	}
+	if config.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document for %v has no jwks_uri", issuer)
+	}
+	return config, nil
+}
+
+// An OpenID Connect provider, with its keys fetched from jwks_uri
+type Provider struct {
+	Config *Configuration
+	Keys   *jwks.Remote
+}
+
+// Discover the provider for issuer using http.DefaultClient
+func NewProvider(ctx context.Context, issuer string) (*Provider, error) {
+	return NewProviderWithClient(ctx, nil, issuer)
+}
+
+// Discover the provider for issuer using client for all requests
+func NewProviderWithClient(ctx context.Context, client *http.Client, issuer string) (*Provider, error) {
+	config, err := Discover(ctx, client, issuer)
+	if err != nil {
+		return nil, err
+	}
+	keys := jwks.NewRemote(config.JWKSURI)
+	keys.Client = client
+	return &Provider{Config: config, Keys: keys}, nil
+}
+
+// A Keyfunc that checks the token was issued by the provider and returns the
+// key named by its kid header
+func (p *Provider) Keyfunc(token *jwt.Token) (interface{}, error) {
+	return p.KeyfuncContext(context.Background(), token)
+}
+
+// Like Keyfunc, using ctx for any key fetch
+func (p *Provider) KeyfuncContext(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	if err := p.verifyIssuer(token.Claims); err != nil {
+		return nil, err
+	}
+	return p.Keys.KeyfuncContext(ctx, token)
+}
+
+// Parse and verify a token from the provider.  Only the signing algorithms
+// the provider advertises for ID tokens are accepted, or RS256 if it
+// advertises none.
+func (p *Provider) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return p.ParseContext(context.Background(), tokenString, claims)
+}
+
+// Like Parse, using ctx for any key fetch
+func (p *Provider) ParseContext(ctx context.Context, tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods(p.signingAlgs()))
+	return parser.ParseWithClaimsContext(ctx, tokenString, claims, p.KeyfuncContext)
+}
+
+// The algorithms ID tokens may be signed with.  Providers that don't
+// advertise any use RS256, as OpenID Connect Core section 3.1.3.7 requires.
+func (p *Provider) signingAlgs() []string {
+	if len(p.Config.IDTokenSigningAlgValuesSupported) == 0 {
+		return []string{"RS256"}
+	}
+	return p.Config.IDTokenSigningAlgValuesSupported
+}
+
+type issuerVerifier interface {
+	VerifyIssuer(cmp string, req bool) bool
+}
+
+func (p *Provider) verifyIssuer(claims jwt.Claims) error {
+	v, ok := claims.(issuerVerifier)
+	if !ok {
+		return jwt.NewValidationError("claims type cannot report its issuer", jwt.ValidationErrorIssuer)
+	}
+	if !v.VerifyIssuer(p.Config.Issuer, true) {
+		return jwt.NewValidationError("token was not issued by "+p.Config.Issuer, jwt.ValidationErrorIssuer)
+	}
+	return nil
+}
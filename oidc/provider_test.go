@@ -0,0 +1,120 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/jwks"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func newTestProvider(ring *jwt.Keyring, issuer *string, algs ...string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/keys", jwks.NewHandler(ring))
+	mux.HandleFunc(DiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&Configuration{
+			Issuer:                           *issuer,
+			JWKSURI:                          *issuer + "/keys",
+			IDTokenSigningAlgValuesSupported: algs,
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestProvider(t *testing.T) {
+	ring := jwt.NewKeyring()
+	ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: test.LoadRSAPrivateKeyFromDisk("../test/sample_key"), ID: "rsa", Method: jwt.SigningMethodRS256}})
+
+	var issuer string
+	server := newTestProvider(ring, &issuer, "RS256")
+	defer server.Close()
+	issuer = server.URL
+
+	provider, err := NewProvider(context.Background(), issuer)
+	if err != nil {
+		t.Fatalf("Error discovering provider: %v", err)
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+	var tests = []struct {
+		name   string
+		claims jwt.StandardClaims
+		valid  bool
+	}{
+		{"valid", jwt.StandardClaims{Issuer: issuer, ExpiresAt: exp}, true},
+		{"wrong issuer", jwt.StandardClaims{Issuer: "https://evil.example.com", ExpiresAt: exp}, false},
+		{"missing issuer", jwt.StandardClaims{ExpiresAt: exp}, false},
+	}
+
+	for _, data := range tests {
+		tokenString, _ := ring.SignedString(data.claims)
+		token, err := provider.Parse(tokenString, &jwt.StandardClaims{})
+		if data.valid && err != nil {
+			t.Errorf("[%v] Error while verifying token: %v", data.name, err)
+		}
+		if !data.valid && err == nil {
+			t.Errorf("[%v] Invalid token passed validation", data.name)
+		}
+		if !data.valid && err != nil {
+			if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorIssuer == 0 {
+				t.Errorf("[%v] Expected issuer error, got %v", data.name, err)
+			}
+		}
+		if data.valid && !token.Valid {
+			t.Errorf("[%v] Token not marked valid", data.name)
+		}
+	}
+
+	// Only the advertised algorithms are accepted
+	ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: []byte("secret"), ID: "hmac", Method: jwt.SigningMethodHS256}})
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{Issuer: issuer}).SignedString(&jwt.SigningKey{Key: []byte("secret"), ID: "hmac"})
+	if _, err := provider.Parse(tokenString, &jwt.StandardClaims{}); err == nil {
+		t.Errorf("Token with unadvertised alg passed validation")
+	}
+}
+
+func TestProviderDefaultAlgs(t *testing.T) {
+	ring := jwt.NewKeyring()
+	ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: test.LoadRSAPrivateKeyFromDisk("../test/sample_key"), ID: "rsa", Method: jwt.SigningMethodRS256}})
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ring.Add(jwt.KeyringEntry{SigningKey: &jwt.SigningKey{Key: ecKey, ID: "ec", Method: jwt.SigningMethodES256}})
+
+	// The discovery document omits id_token_signing_alg_values_supported
+	var issuer string
+	server := newTestProvider(ring, &issuer)
+	defer server.Close()
+	issuer = server.URL
+
+	provider, err := NewProvider(context.Background(), issuer)
+	if err != nil {
+		t.Fatalf("Error discovering provider: %v", err)
+	}
+	tokenString, _ := ring.SignedString(jwt.StandardClaims{Issuer: issuer})
+	if _, err := provider.Parse(tokenString, &jwt.StandardClaims{}); err != nil {
+		t.Errorf("Error verifying RS256 token: %v", err)
+	}
+
+	// Only RS256 is accepted, even from keys the provider publishes
+	tokenString, _ = jwt.NewWithClaims(jwt.SigningMethodES256, jwt.StandardClaims{Issuer: issuer}).SignedString(&jwt.SigningKey{Key: ecKey, ID: "ec"})
+	if _, err := provider.Parse(tokenString, &jwt.StandardClaims{}); err == nil {
+		t.Errorf("ES256 token passed validation without advertised algorithms")
+	}
+}
+
+func TestDiscoverIssuerMismatch(t *testing.T) {
+	issuer := "https://accounts.example.com"
+	server := newTestProvider(jwt.NewKeyring(), &issuer, "RS256")
+	defer server.Close()
+
+	if _, err := Discover(context.Background(), nil, server.URL); err == nil {
+		t.Errorf("Discovery document with mismatched issuer was accepted")
+	}
+}
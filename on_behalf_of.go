@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"errors"
+	"time"
+)
+
+var (
+	ErrDelegationDepth = errors.New("token delegation chain is too long")
+	ErrMissingAudience = errors.New("on-behalf-of token needs an audience")
+	ErrMissingActor    = errors.New("on-behalf-of token needs an actor")
+	ErrSigningKeyNoAlg = errors.New("signing key has no Method")
+	ErrOriginalInvalid = errors.New("only a verified token can be used to mint on its behalf")
+)
+
+// Claims MintOnBehalfOf copies from the original token by default
+var DefaultOnBehalfOfClaims = []string{"sub", "scope", "scp", "client_id", "roles", "groups", "tenant"}
+
+// Claims that describe a token itself rather than its subject, and are never
+// copied to an on-behalf-of token
+var onBehalfOfReserved = map[string]bool{
+	"iss": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true,
+	"act": true, "may_act": true, "cnf": true,
+}
+
+// The guardrails for MintOnBehalfOf
+type OnBehalfOf struct {
+	Actor    *Actor        // The calling service, recorded in act.  Required
+	Issuer   string        // The new token's iss.  Defaults to Actor.Subject
+	Claims   []string      // Claims to copy from the original.  Defaults to DefaultOnBehalfOfClaims
+	Lifetime time.Duration // Defaults to 5 minutes, and never extends past the original's exp
+	MaxDepth int           // The most actors the chain may have, including Actor.  Defaults to 3
+}
+
+// Mint a token for newAud carrying the original token's subject, for a
+// service calling another within the same trust domain on behalf of its
+// caller.  Only the allowed claims are copied, act is set to the calling
+// service with any previous actors nested inside it, and exp is shortened.
+//
+// The original must have been verified.  If it has a may_act claim, the
+// actor must match it.  key must have a Method.
+func MintOnBehalfOf(original *Token, newAud string, key *SigningKey, policy OnBehalfOf) (string, error) {
+	if !original.Valid {
+		return "", ErrOriginalInvalid
+	}
+	if newAud == "" {
+		return "", ErrMissingAudience
+	}
+	if policy.Actor == nil || policy.Actor.Subject == "" {
+		return "", ErrMissingActor
+	}
+	if key == nil || key.Method == nil {
+		return "", ErrSigningKeyNoAlg
+	}
+
+	mayAct, err := original.MayAct()
+	if err != nil {
+		return "", err
+	}
+	if mayAct != nil && !policy.Actor.Matches(mayAct) {
+		return "", ErrActorNotAllowed
+	}
+	previous, err := original.Actor()
+	if err != nil {
+		return "", err
+	}
+	maxDepth := policy.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = 3
+	}
+	if len(previous.Chain())+1 > maxDepth {
+		return "", ErrDelegationDepth
+	}
+	actor := *policy.Actor
+	actor.Actor = previous
+
+	originalClaims, err := claimsToMap(original.Claims)
+	if err != nil {
+		return "", err
+	}
+	names := policy.Claims
+	if names == nil {
+		names = DefaultOnBehalfOfClaims
+	}
+	claims := MapClaims{}
+	for _, name := range names {
+		if v, ok := originalClaims[name]; ok && !onBehalfOfReserved[name] {
+			claims[name] = v
+		}
+	}
+
+	now := TimeFunc()
+	lifetime := policy.Lifetime
+	if lifetime == 0 {
+		lifetime = 5 * time.Minute
+	}
+	exp := now.Add(lifetime)
+	if originalExp, ok := original.ExpiresAt(); ok && originalExp.Before(exp) {
+		exp = originalExp
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	issuer := policy.Issuer
+	if issuer == "" {
+		issuer = actor.Subject
+	}
+
+	claims["iss"] = issuer
+	claims["aud"] = newAud
+	claims["iat"] = now.Unix()
+	claims["exp"] = exp.Unix()
+	claims["jti"] = EncodeSegment(id)
+	claims["act"] = &actor
+	return NewWithClaims(key.Method, claims).SignedString(key)
+}
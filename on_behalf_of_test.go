@@ -0,0 +1,104 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestMintOnBehalfOf(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	parse := func(claims jwt.MapClaims) *jwt.Token {
+		s, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(hmacTestKey)
+		token, err := jwt.Parse(s, keyFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	key := &jwt.SigningKey{Key: hmacTestKey, ID: "orders-1", Method: jwt.SigningMethodHS256}
+	orders := &jwt.Actor{Subject: "orders"}
+
+	original := parse(jwt.MapClaims{"sub": "user", "scope": "orders:write", "email": "a@example.com", "aud": "orders", "jti": "orig", "exp": now.Add(time.Hour).Unix()})
+	minted, err := jwt.MintOnBehalfOf(original, "billing", key, jwt.OnBehalfOf{Actor: orders})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Parse(minted, keyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	if claims["sub"] != "user" || claims["scope"] != "orders:write" || claims["aud"] != "billing" || claims["iss"] != "orders" || token.Header["kid"] != "orders-1" {
+		t.Errorf("Unexpected claims: %v", claims)
+	}
+	if _, ok := claims["email"]; ok || claims["jti"] == "orig" {
+		t.Errorf("Unexpected claims copied: %v", claims)
+	}
+	if exp, _ := token.ExpiresAt(); !exp.Equal(now.Add(5 * time.Minute)) {
+		t.Errorf("Unexpected exp: %v", exp)
+	}
+
+	// A second hop nests the first actor
+	billing := &jwt.Actor{Subject: "billing"}
+	secondString, err := jwt.MintOnBehalfOf(token, "ledger", key, jwt.OnBehalfOf{Actor: billing, Lifetime: time.Hour, MaxDepth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := jwt.Parse(secondString, keyFunc)
+	actor, _ := second.Actor()
+	if chain := actor.Chain(); len(chain) != 2 || chain[0].Subject != "billing" || chain[1].Subject != "orders" {
+		t.Errorf("Unexpected chain: %+v", chain)
+	}
+	if exp, _ := second.ExpiresAt(); !exp.Equal(now.Add(5 * time.Minute)) {
+		t.Errorf("Expected exp capped at the original's, got %v", exp)
+	}
+	if _, err := jwt.MintOnBehalfOf(second, "audit", key, jwt.OnBehalfOf{Actor: &jwt.Actor{Subject: "ledger"}, MaxDepth: 2}); err != jwt.ErrDelegationDepth {
+		t.Errorf("Expected ErrDelegationDepth, got %v", err)
+	}
+}
+
+func TestMintOnBehalfOfGuardrails(t *testing.T) {
+	key := &jwt.SigningKey{Key: hmacTestKey, Method: jwt.SigningMethodHS256}
+	orders := &jwt.Actor{Subject: "orders"}
+	valid := func(claims jwt.MapClaims) *jwt.Token {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		token.Valid = true
+		return token
+	}
+
+	var tests = []struct {
+		name     string
+		original *jwt.Token
+		aud      string
+		key      *jwt.SigningKey
+		policy   jwt.OnBehalfOf
+		err      error
+	}{
+		{"unverified", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}), "billing", key, jwt.OnBehalfOf{Actor: orders}, jwt.ErrOriginalInvalid},
+		{"no audience", valid(jwt.MapClaims{"sub": "user"}), "", key, jwt.OnBehalfOf{Actor: orders}, jwt.ErrMissingAudience},
+		{"no actor", valid(jwt.MapClaims{"sub": "user"}), "billing", key, jwt.OnBehalfOf{}, jwt.ErrMissingActor},
+		{"no method", valid(jwt.MapClaims{"sub": "user"}), "billing", &jwt.SigningKey{Key: hmacTestKey}, jwt.OnBehalfOf{Actor: orders}, jwt.ErrSigningKeyNoAlg},
+		{"may_act", valid(jwt.MapClaims{"sub": "user", "may_act": map[string]interface{}{"sub": "billing"}}), "billing", key, jwt.OnBehalfOf{Actor: orders}, jwt.ErrActorNotAllowed},
+		{"may_act allowed", valid(jwt.MapClaims{"sub": "user", "may_act": map[string]interface{}{"sub": "orders"}}), "billing", key, jwt.OnBehalfOf{Actor: orders}, nil},
+		{"reserved claims", valid(jwt.MapClaims{"sub": "user", "cnf": map[string]interface{}{}}), "billing", key, jwt.OnBehalfOf{Actor: orders, Claims: []string{"sub", "cnf"}}, nil},
+	}
+	for _, data := range tests {
+		minted, err := jwt.MintOnBehalfOf(data.original, data.aud, data.key, data.policy)
+		if err != data.err {
+			t.Errorf("[%v] Expected error %v, got %v", data.name, data.err, err)
+			continue
+		}
+		if err == nil {
+			token, _ := jwt.ParseUnverified(minted)
+			if _, ok := token.Claims.(jwt.MapClaims)["cnf"]; ok {
+				t.Errorf("[%v] Reserved claim copied: %v", data.name, token.Claims)
+			}
+		}
+	}
+}
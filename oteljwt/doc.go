@@ -0,0 +1,15 @@
+// OpenTelemetry instrumentation for jwt-go parsers.  An Instrumentation is
+// a jwt.Logger that records a span for each parse and these metrics:
+//
+//	jwt.parse.duration         histogram of parse time in seconds, by alg and outcome
+//	jwt.parse.tokens           counter of parsed tokens, by alg and outcome
+//	jwt.validation.failures    counter of rejected tokens, by reason
+//
+//	inst, err := oteljwt.New()
+//	parser := jwt.NewParser(jwt.WithLogger(inst))
+//
+// Spans are children of the context passed to ParseWithContext.
+//
+// This package depends on go.opentelemetry.io/otel and is only built with the
+// otel build tag.
+package oteljwt
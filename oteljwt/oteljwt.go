@@ -0,0 +1,114 @@
+//go:build otel
+
+package oteljwt
+
+import (
+	"context"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Instrumentation scope name for the tracer and meter
+const ScopeName = "github.com/dgrijalva/jwt-go/oteljwt"
+
+// Records parse events as spans and metrics.  It implements jwt.Logger.
+type Instrumentation struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	tokens   metric.Int64Counter
+	failures metric.Int64Counter
+}
+
+var _ jwt.Logger = (*Instrumentation)(nil)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option is used to configure an Instrumentation
+type Option func(*config)
+
+// Create spans with provider instead of the global TracerProvider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// Record metrics with provider instead of the global MeterProvider
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// Create an Instrumentation using the global providers, unless overridden
+func New(options ...Option) (*Instrumentation, error) {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	meter := c.meterProvider.Meter(ScopeName)
+	i := &Instrumentation{tracer: c.tracerProvider.Tracer(ScopeName)}
+	var err error
+	if i.duration, err = meter.Float64Histogram("jwt.parse.duration",
+		metric.WithUnit("s"), metric.WithDescription("Time spent parsing and validating tokens")); err != nil {
+		return nil, err
+	}
+	if i.tokens, err = meter.Int64Counter("jwt.parse.tokens",
+		metric.WithDescription("Tokens parsed, by algorithm and outcome")); err != nil {
+		return nil, err
+	}
+	if i.failures, err = meter.Int64Counter("jwt.validation.failures",
+		metric.WithDescription("Tokens rejected, by reason")); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (i *Instrumentation) Log(ctx context.Context, event jwt.LogEvent) {
+	if event.Kind == jwt.EventKeySelected {
+		return
+	}
+
+	outcome := "valid"
+	if event.Err != nil {
+		outcome = "invalid"
+	}
+	alg := attribute.String("jwt.alg", event.Algorithm)
+	attrs := []attribute.KeyValue{alg, attribute.String("jwt.outcome", outcome)}
+
+	// The span is recorded after the fact, with the parse's real start time
+	end := time.Now()
+	spanAttrs := append([]attribute.KeyValue{attribute.String("jwt.kid", event.KeyID)}, attrs...)
+	if event.Reason != "" {
+		spanAttrs = append(spanAttrs, attribute.String("jwt.reason", event.Reason))
+	}
+	_, span := i.tracer.Start(ctx, "jwt.Parse",
+		trace.WithTimestamp(end.Add(-event.Duration)),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(spanAttrs...))
+	if event.Err != nil {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Reason)
+	}
+	span.End(trace.WithTimestamp(end))
+
+	set := metric.WithAttributes(attrs...)
+	i.duration.Record(ctx, event.Duration.Seconds(), set)
+	i.tokens.Add(ctx, 1, set)
+	if event.Err != nil {
+		i.failures.Add(ctx, 1, metric.WithAttributes(alg, attribute.String("jwt.reason", event.Reason)))
+	}
+}
@@ -0,0 +1,98 @@
+package jwt
+
+import (
+	"context"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Parse and validate a token into a caller-provided token and claims, for hot
+// paths where allocations matter.  The token's Header map and RawHeader and
+// RawClaims buffers are reused, so parsing repeatedly into the same token
+// allocates little beyond what JSON decoding of the claims needs.  On success
+// token.Valid is true.
+//
+// claims is cleared before the token's claims are decoded into it, so it can
+// be reused too without claims carrying over from the previous token.
+//
+// Validation is the same as ParseWithClaims.  The token must not be shared
+// while it is being parsed into, and references into its previous Header or
+// raw buffers are invalidated.
+func (p *Parser) ParseInto(token *Token, tokenString string, claims Claims, keyFunc Keyfunc) error {
+	return p.ParseIntoContext(context.Background(), token, tokenString, claims, keyfuncWithContext(keyFunc))
+}
+
+// ParseInto, passing ctx to keyFunc and the signing method
+func (p *Parser) ParseIntoContext(ctx context.Context, token *Token, tokenString string, claims Claims, keyFunc KeyfuncContext) error {
+	if p.Logger == nil {
+		return p.parseInto(ctx, token, tokenString, claims, keyFunc)
+	}
+	start := time.Now()
+	err := p.parseInto(ctx, token, tokenString, claims, keyFunc)
+	p.logResult(ctx, token, err, start)
+	return err
+}
+
+func (p *Parser) parseInto(ctx context.Context, token *Token, tokenString string, claims Claims, keyFunc KeyfuncContext) error {
+	resetClaims(claims)
+	return p.parse(ctx, token, tokenString, claims, keyFunc, p.reuseSegment)
+}
+
+// Clear claims decoded from a previous token.  MapClaims are emptied and
+// pointers to structs are zeroed.
+func resetClaims(claims Claims) {
+	if m, ok := claims.(MapClaims); ok {
+		for k := range m {
+			delete(m, k)
+		}
+		return
+	}
+	if v := reflect.ValueOf(claims); v.Kind() == reflect.Ptr && !v.IsNil() {
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	}
+}
+
+// Split a compact token on its two periods, without allocating
+func splitToken(tokenString string) (header, payload, signature string, ok bool) {
+	i := strings.IndexByte(tokenString, '.')
+	if i < 0 {
+		return "", "", "", false
+	}
+	j := strings.IndexByte(tokenString[i+1:], '.')
+	if j < 0 {
+		return "", "", "", false
+	}
+	j += i + 1
+	if strings.IndexByte(tokenString[j+1:], '.') >= 0 {
+		return "", "", "", false
+	}
+	return tokenString[:i], tokenString[i+1 : j], tokenString[j+1:], true
+}
+
+// Decode seg over dst, reusing its capacity
+func (p *Parser) reuseSegment(dst []byte, seg string) ([]byte, error) {
+	return p.appendDecodeSegment(dst[:0], seg)
+}
+
+// Like decodeSegment, appending the decoded bytes to dst
+func (p *Parser) appendDecodeSegment(dst []byte, seg string) ([]byte, error) {
+	if p.StrictDecoding && !isCanonicalSegment(seg) {
+		return dst, ErrNonCanonicalSegment
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	src := append((*buf)[:0], strings.TrimRight(seg, "=")...)
+	*buf = src
+
+	n := base64.RawURLEncoding.DecodedLen(len(src))
+	dst = grow(dst, n)
+	m, err := base64.RawURLEncoding.Decode(dst[len(dst):len(dst)+n], src)
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+m], nil
+}
@@ -0,0 +1,115 @@
+package jwt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func TestParser_ParseInto(t *testing.T) {
+	privateKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+
+	// The same token is reused for every parse
+	token := &jwt.Token{}
+	for _, data := range jwtTestData {
+		if data.tokenString == "" {
+			data.tokenString = test.MakeSampleToken(data.claims, privateKey)
+		}
+
+		var parser = data.parser
+		if parser == nil {
+			parser = new(jwt.Parser)
+		}
+		var claims jwt.Claims
+		switch data.claims.(type) {
+		case jwt.MapClaims:
+			claims = jwt.MapClaims{}
+		case *jwt.StandardClaims:
+			claims = &jwt.StandardClaims{}
+		}
+		err := parser.ParseInto(token, data.tokenString, claims, data.keyfunc)
+
+		if !reflect.DeepEqual(data.claims, token.Claims) {
+			t.Errorf("[%v] Claims mismatch. Expecting: %v  Got: %v", data.name, data.claims, token.Claims)
+		}
+		if data.valid && err != nil {
+			t.Errorf("[%v] Error while verifying token: %T:%v", data.name, err, err)
+		}
+		if !data.valid && err == nil {
+			t.Errorf("[%v] Invalid token passed validation", data.name)
+		}
+		if (err == nil && !token.Valid) || (err != nil && token.Valid) {
+			t.Errorf("[%v] Inconsistent behavior between returned error and token.Valid", data.name)
+		}
+		if data.errors != 0 && err != nil {
+			if e := err.(*jwt.ValidationError).Errors; e != data.errors {
+				t.Errorf("[%v] Errors don't match expectation.  %v != %v", data.name, e, data.errors)
+			}
+		}
+		if data.valid && token.Signature != data.tokenString[len(data.tokenString)-len(token.Signature):] {
+			t.Errorf("[%v] Signature not set", data.name)
+		}
+	}
+}
+
+type reusedClaims struct {
+	jwt.StandardClaims
+	Admin bool `json:"admin"`
+}
+
+func TestParser_ParseIntoReusedClaims(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	alice := signHS256(jwt.MapClaims{"sub": "alice", "admin": true})
+	bob := signHS256(jwt.MapClaims{"sub": "bob"})
+	parser := jwt.NewParser()
+	token := &jwt.Token{}
+
+	m := jwt.MapClaims{}
+	for _, tokenString := range []string{alice, bob} {
+		if err := parser.ParseInto(token, tokenString, m, keyFunc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(m, jwt.MapClaims{"sub": "bob"}) {
+		t.Errorf("Claims carried over between tokens: %v", m)
+	}
+
+	s := &reusedClaims{}
+	for _, tokenString := range []string{alice, bob} {
+		if err := parser.ParseInto(token, tokenString, s, keyFunc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.Subject != "bob" || s.Admin {
+		t.Errorf("Claims carried over between tokens: %+v", s)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{Subject: "user", Issuer: "issuer"}).SignedString(hmacTestKey)
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jwt.ParseWithClaims(tokenString, &jwt.StandardClaims{}, keyFunc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseInto(b *testing.B) {
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{Subject: "user", Issuer: "issuer"}).SignedString(hmacTestKey)
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	parser := &jwt.Parser{}
+	token := &jwt.Token{}
+	claims := &jwt.StandardClaims{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := parser.ParseInto(token, tokenString, claims, keyFunc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,65 +2,143 @@ package jwt
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 type Parser struct {
-	ValidMethods         []string // If populated, only these methods will be considered valid
-	UseJSONNumber        bool     // Use JSON Number format in JSON decoder
-	SkipClaimsValidation bool     // Skip claims validation during token parsing
+	ValidMethods         []string  // If populated, only these methods will be considered valid
+	UseJSONNumber        bool      // Use JSON Number format in JSON decoder
+	SkipClaimsValidation bool      // Skip claims validation during token parsing
+	RequiredType         string    // If populated, the typ header must match this media type
+	JSONCodec            JSONCodec // JSON implementation used for decoding.  Defaults to DefaultJSONCodec
+
+	// If set, tokens whose signatures were already verified skip verification
+	VerificationCache VerificationCache
+
+	// If non-nil, claims are checked by these steps, in order, instead of
+	// their Valid method.  See DefaultValidators.
+	Validators []Validator
+
+	// Reject tokens whose header or claims repeat a JSON key
+	RejectDuplicateKeys bool
+
+	// Limits on untrusted input, checked before decoding.  Zero means no limit.
+	MaxTokenSize   int // Maximum length of the whole token string
+	MaxSegmentSize int // Maximum length of each encoded segment
+	MaxJSONDepth   int // Maximum nesting of objects and arrays in the header and claims
+
+	// Decode the header, payload and signature as strict, unpadded base64url,
+	// as RFC 7515 requires.  Set by NewParser; the zero Parser is lenient for
+	// compatibility.
+	StrictDecoding bool
+
+	// Reject tokens with claims that have no matching field in a struct claims
+	// type.  Has no effect on MapClaims.
+	DisallowUnknownFields bool
+
+	// If set, receives an event for each key lookup and each parse result
+	Logger Logger
+
+	// Reject weak keys returned by the Keyfunc, such as RSA keys shorter than
+	// 2048 bits.  See CheckKeyStrength.
+	StrictKeys bool
+
+	// Reject tokens whose registered header parameters have the wrong type,
+	// or that have no alg.  See ParseHeader.
+	StrictHeader bool
+
+	// Parse decodes claims into a *LazyClaims rather than MapClaims
+	LazyClaims bool
+
+	// Limit on the size of a compressed payload once decompressed.  Zero
+	// means DefaultMaxDecompressedSize.  See SetCompression.
+	MaxDecompressedSize int
+
+	// If set, the EncryptedClaims are decrypted before the claims are
+	// decoded.  The token's RawClaims keep the encrypted values, as signed.
+	// See FieldCipher.
+	FieldCipher     FieldCipher
+	EncryptedClaims []string
+
+	// Applied in order to the claims of valid tokens.  See ClaimsMapper.
+	ClaimsMappers []ClaimsMapper
+
+	validators []func(*Token) error // Additional claims checks, added by options
 }
 
 // Parse, validate, and return a token.
 // keyFunc will receive the parsed token and should return the key for validating.
 // If everything is kosher, err will be nil
 func (p *Parser) Parse(tokenString string, keyFunc Keyfunc) (*Token, error) {
+	if p.LazyClaims {
+		return p.ParseWithClaims(tokenString, &LazyClaims{}, keyFunc)
+	}
 	return p.ParseWithClaims(tokenString, MapClaims{}, keyFunc)
 }
 
 func (p *Parser) ParseWithClaims(tokenString string, claims Claims, keyFunc Keyfunc) (*Token, error) {
-	token, parts, err := p.ParseUnverified(tokenString, claims)
-	if err != nil {
-		return token, err
+	return p.ParseWithClaimsContext(context.Background(), tokenString, claims, keyfuncWithContext(keyFunc))
+}
+
+// ParseWithClaims, passing ctx to keyFunc and the signing method
+func (p *Parser) ParseWithClaimsContext(ctx context.Context, tokenString string, claims Claims, keyFunc KeyfuncContext) (*Token, error) {
+	if p.Logger == nil {
+		return p.parseWithClaims(ctx, tokenString, claims, keyFunc)
 	}
+	start := time.Now()
+	token, err := p.parseWithClaims(ctx, tokenString, claims, keyFunc)
+	p.logResult(ctx, token, err, start)
+	return token, err
+}
 
-	// Verify signing method is in the required set
-	if p.ValidMethods != nil {
-		var signingMethodValid = false
-		var alg = token.Method.Alg()
-		for _, m := range p.ValidMethods {
-			if m == alg {
-				signingMethodValid = true
-				break
-			}
-		}
-		if !signingMethodValid {
-			// signing method is not in the listed set
-			return token, NewValidationError(fmt.Sprintf("signing method %v is invalid", alg), ValidationErrorSignatureInvalid)
+func (p *Parser) parseWithClaims(ctx context.Context, tokenString string, claims Claims, keyFunc KeyfuncContext) (*Token, error) {
+	token := &Token{}
+	if err := p.parse(ctx, token, tokenString, claims, keyFunc, p.decodeNewSegment); err != nil {
+		// Tokens that could not be split are not returned
+		if token.Raw == "" {
+			return nil, err
 		}
+		return token, err
 	}
+	return token, nil
+}
 
-	// Lookup key
-	var key interface{}
-	if keyFunc == nil {
-		// keyFunc was not provided.  short circuiting validation
-		return token, NewValidationError("no Keyfunc was provided.", ValidationErrorUnverifiable)
+// Decodes a base64url segment, reusing dst if it can.  ParseWithClaims
+// decodes into new buffers, and ParseInto into the token's previous ones.
+type segmentDecoder func(dst []byte, seg string) ([]byte, error)
+
+func (p *Parser) decodeNewSegment(_ []byte, seg string) ([]byte, error) {
+	return p.decodeSegment(seg)
+}
+
+// The pipeline shared by ParseWithClaims and ParseInto: decode tokenString
+// into token, check its header, look up the key, validate the claims, verify
+// the signature and map the claims of the valid token
+func (p *Parser) parse(ctx context.Context, token *Token, tokenString string, claims Claims, keyFunc KeyfuncContext, decode segmentDecoder) error {
+	signingString, signature, err := p.parseUnverified(token, tokenString, claims, decode)
+	if err != nil {
+		return err
 	}
-	if key, err = keyFunc(token); err != nil {
-		// keyFunc returned an error
-		if ve, ok := err.(*ValidationError); ok {
-			return token, ve
-		}
-		return token, &ValidationError{Inner: err, Errors: ValidationErrorUnverifiable}
+
+	if err = p.validateHeader(token.Header); err != nil {
+		return err
+	}
+
+	// Lookup key
+	key, err := p.lookupKey(ctx, token, keyFunc)
+	if err != nil {
+		return err
 	}
 
 	vErr := &ValidationError{}
 
 	// Validate Claims
 	if !p.SkipClaimsValidation {
-		if err := token.Claims.Valid(); err != nil {
+		if err := p.validateClaims(ctx, token); err != nil {
 
 			// If the Claims Valid returned an error, check if it is a validation error,
 			// If it was another error type, create a ValidationError with a generic ClaimsInvalid flag set
@@ -73,18 +151,79 @@ func (p *Parser) ParseWithClaims(tokenString string, claims Claims, keyFunc Keyf
 	}
 
 	// Perform validation
-	token.Signature = parts[2]
-	if err = token.Method.Verify(strings.Join(parts[0:2], "."), token.Signature, key); err != nil {
+	token.Signature = signature
+	if err = p.verifySignature(ctx, token, signingString, key); err != nil {
 		vErr.Inner = err
 		vErr.Errors |= ValidationErrorSignatureInvalid
 	}
 
-	if vErr.valid() {
-		token.Valid = true
-		return token, nil
+	if !vErr.valid() {
+		return vErr
+	}
+
+	// Normalize the claims of the validated token
+	if err = p.mapClaims(token); err != nil {
+		return &ValidationError{Inner: err, Errors: ValidationErrorClaimsInvalid}
+	}
+	token.Valid = true
+	return nil
+}
+
+// Split tokenString and decode it into token, without verifying it.  token
+// is only reset once the token has been split.  Returns the signing string
+// and the signature segment.
+func (p *Parser) parseUnverified(token *Token, tokenString string, claims Claims, decode segmentDecoder) (signingString, signature string, err error) {
+	if err = p.checkTokenSize(tokenString); err != nil {
+		return "", "", err
+	}
+	header, payload, signature, ok := splitToken(tokenString)
+	if !ok {
+		return "", "", NewValidationError("token contains an invalid number of segments", ValidationErrorMalformed)
+	}
+	if err = p.checkSegmentSizes(header, payload, signature); err != nil {
+		return "", "", err
+	}
+
+	if err = p.decodeTokenHeader(token, tokenString, header, decode); err != nil {
+		return "", "", err
+	}
+	token.Claims = claims
+	if err = p.decodeTokenClaims(token, payload, decode); err != nil {
+		return "", "", err
 	}
+	if err = lookupSigningMethod(token); err != nil {
+		return "", "", err
+	}
+	return tokenString[:len(header)+1+len(payload)], signature, nil
+}
 
-	return token, vErr
+// Run the parser's Validators, or the claims' own Valid method if it has none
+// (after the registered claims checks, for claims embedding RegisteredClaims),
+// then any checks added by options, then the constraints in the claims' jwt
+// struct tags, then the claims' Validate method if they implement
+// ClaimsValidator
+func (p *Parser) validateClaims(ctx context.Context, token *Token) error {
+	if p.Validators != nil {
+		if err := runValidators(ctx, token, p.Validators); err != nil {
+			return err
+		}
+	} else if err := validateRegisteredClaims(ctx, token); err != nil {
+		return err
+	} else if err := token.Claims.Valid(); err != nil {
+		return err
+	}
+	for _, validate := range p.validators {
+		if err := validate(token); err != nil {
+			return err
+		}
+	}
+	if err := ValidateClaimTags(token.Claims); err != nil {
+		return err
+	}
+	if v, ok := token.Claims.(ClaimsValidator); ok {
+		return v.Validate(ctx)
+	}
+	return nil
 }
 
 // WARNING: Don't use this method unless you know what you're doing
@@ -94,55 +233,220 @@ func (p *Parser) ParseWithClaims(tokenString string, claims Claims, keyFunc Keyf
 // been checked previously in the stack) and you want to extract values from
 // it.
 func (p *Parser) ParseUnverified(tokenString string, claims Claims) (token *Token, parts []string, err error) {
-	parts = strings.Split(tokenString, ".")
-	if len(parts) != 3 {
-		return nil, parts, NewValidationError("token contains an invalid number of segments", ValidationErrorMalformed)
+	if token, parts, err = p.parseHeader(tokenString); err != nil {
+		return token, parts, err
 	}
 
-	token = &Token{Raw: tokenString}
+	// parse Claims
+	token.Claims = claims
+	if err = p.decodeTokenClaims(token, parts[1], p.decodeNewSegment); err != nil {
+		return token, parts, err
+	}
 
-	// parse Header
-	var headerBytes []byte
-	if headerBytes, err = DecodeSegment(parts[0]); err != nil {
+	// Lookup signature method
+	if err = lookupSigningMethod(token); err != nil {
+		return token, parts, err
+	}
+
+	return token, parts, nil
+}
+
+// Reset token and decode the header segment into it
+func (p *Parser) decodeTokenHeader(token *Token, tokenString, segment string, decode segmentDecoder) error {
+	token.Raw = tokenString
+	token.Method = nil
+	token.Claims = nil
+	token.Signature = ""
+	token.Valid = false
+	for k := range token.Header {
+		delete(token.Header, k)
+	}
+
+	var err error
+	if token.RawHeader, err = decode(token.RawHeader, segment); err != nil {
 		if strings.HasPrefix(strings.ToLower(tokenString), "bearer ") {
-			return token, parts, NewValidationError("tokenstring should not contain 'bearer '", ValidationErrorMalformed)
+			return NewValidationError("tokenstring should not contain 'bearer '", ValidationErrorMalformed)
 		}
-		return token, parts, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+		return &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
 	}
-	if err = json.Unmarshal(headerBytes, &token.Header); err != nil {
-		return token, parts, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	if err = p.decodeHeader(token.RawHeader, &token.Header); err != nil {
+		return &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
 	}
+	return nil
+}
 
-	// parse Claims
-	var claimBytes []byte
-	token.Claims = claims
+// Decode the payload segment into token.Claims
+func (p *Parser) decodeTokenClaims(token *Token, segment string, decode segmentDecoder) error {
+	// The payload of a nested token is another token, not a claims set
+	if isNestedToken(token.Header) {
+		return NewValidationError("token is a nested JWT (cty: JWT)", ValidationErrorMalformed)
+	}
+	claimBytes, err := p.decodePayloadInto(token.RawClaims, token.Header, segment, decode)
+	if err != nil {
+		return err
+	}
+	token.RawClaims = claimBytes
+	// RawClaims stay as signed; only the decoded claims see the plaintext
+	if claimBytes, err = p.decryptClaims(claimBytes); err != nil {
+		return &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	if err = p.decodeClaims(claimBytes, token.Claims); err != nil {
+		return &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	return nil
+}
 
-	if claimBytes, err = DecodeSegment(parts[1]); err != nil {
-		return token, parts, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+// Decode the header JSON into header
+func (p *Parser) decodeHeader(data []byte, header *map[string]interface{}) error {
+	if err := p.checkDepthLimit(data); err != nil {
+		return err
+	}
+	if p.RejectDuplicateKeys {
+		if err := p.checkDuplicateKeys(data); err != nil {
+			return err
+		}
+	}
+	return p.jsonCodec().Unmarshal(data, header)
+}
+
+// Decode the claims JSON into claims, honoring the parser's decoding options
+func (p *Parser) decodeClaims(data []byte, claims Claims) error {
+	if err := p.checkDepthLimit(data); err != nil {
+		return err
+	}
+	if p.RejectDuplicateKeys {
+		if err := p.checkDuplicateKeys(data); err != nil {
+			return err
+		}
 	}
-	dec := json.NewDecoder(bytes.NewBuffer(claimBytes))
+	dec := p.jsonCodec().NewDecoder(bytes.NewReader(data))
 	if p.UseJSONNumber {
 		dec.UseNumber()
 	}
+	if p.DisallowUnknownFields {
+		strict, ok := dec.(interface{ DisallowUnknownFields() })
+		if !ok {
+			return errors.New("JSON codec does not support DisallowUnknownFields")
+		}
+		strict.DisallowUnknownFields()
+	}
 	// JSON Decode.  Special case for map type to avoid weird pointer behavior
-	if c, ok := token.Claims.(MapClaims); ok {
-		err = dec.Decode(&c)
-	} else {
-		err = dec.Decode(&claims)
+	if c, ok := claims.(MapClaims); ok {
+		return dec.Decode(&c)
+	}
+	return dec.Decode(&claims)
+}
+
+// Split the token and decode its header
+func (p *Parser) parseHeader(tokenString string) (token *Token, parts []string, err error) {
+	if err = p.checkTokenSize(tokenString); err != nil {
+		return nil, nil, err
+	}
+	header, payload, signature, ok := splitToken(tokenString)
+	if !ok {
+		return nil, strings.Split(tokenString, "."), NewValidationError("token contains an invalid number of segments", ValidationErrorMalformed)
+	}
+	parts = []string{header, payload, signature}
+	if err = p.checkSegmentSizes(header, payload, signature); err != nil {
+		return nil, parts, err
+	}
+
+	token = &Token{}
+	if err = p.decodeTokenHeader(token, tokenString, header, p.decodeNewSegment); err != nil {
+		return token, parts, err
 	}
-	// Handle decode error
+	return token, parts, nil
+}
+
+// Decode the payload segment, honoring the b64 header
+func (p *Parser) decodePayload(header map[string]interface{}, segment string) ([]byte, error) {
+	return p.decodePayloadInto(nil, header, segment, p.decodeNewSegment)
+}
+
+// decodePayload, decoding into dst if it can
+func (p *Parser) decodePayloadInto(dst []byte, header map[string]interface{}, segment string, decode segmentDecoder) ([]byte, error) {
+	unencoded, err := parseUnencodedPayloadHeader(header)
 	if err != nil {
-		return token, parts, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+		return nil, err
+	}
+	if unencoded {
+		return p.decompressPayload(header, append(dst[:0], segment...))
 	}
 
-	// Lookup signature method
+	payload, err := decode(dst, segment)
+	if err != nil {
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	return p.decompressPayload(header, payload)
+}
+
+// Decode a segment, strictly if the parser is configured to
+func (p *Parser) decodeSegment(seg string) ([]byte, error) {
+	if p.StrictDecoding {
+		return decodeStrictSegment(seg)
+	}
+	return DecodeSegment(seg)
+}
+
+// Set token.Method from the alg header
+func lookupSigningMethod(token *Token) error {
 	if method, ok := token.Header["alg"].(string); ok {
 		if token.Method = GetSigningMethod(method); token.Method == nil {
-			return token, parts, NewValidationError("signing method (alg) is unavailable.", ValidationErrorUnverifiable)
+			return NewValidationError("signing method (alg) is unavailable.", ValidationErrorUnverifiable)
 		}
 	} else {
-		return token, parts, NewValidationError("signing method (alg) is unspecified.", ValidationErrorUnverifiable)
+		return NewValidationError("signing method (alg) is unspecified.", ValidationErrorUnverifiable)
 	}
+	return nil
+}
 
-	return token, parts, nil
+// Check the signing method against the allow-list and fetch the key from keyFunc
+func (p *Parser) lookupKey(ctx context.Context, token *Token, keyFunc KeyfuncContext) (interface{}, error) {
+	// Verify signing method is in the required set
+	if p.ValidMethods != nil {
+		var signingMethodValid = false
+		var alg = token.Method.Alg()
+		for _, m := range p.ValidMethods {
+			if m == alg {
+				signingMethodValid = true
+				break
+			}
+		}
+		if !signingMethodValid {
+			// signing method is not in the listed set
+			return nil, NewValidationError(fmt.Sprintf("signing method %v is invalid", alg), ValidationErrorSignatureInvalid)
+		}
+	}
+
+	// Lookup key
+	if keyFunc == nil {
+		// keyFunc was not provided.  short circuiting validation
+		return nil, NewValidationError("no Keyfunc was provided.", ValidationErrorUnverifiable)
+	}
+	key, err := keyFunc(ctx, token)
+	if err != nil {
+		// keyFunc returned an error
+		if ve, ok := err.(*ValidationError); ok {
+			return nil, ve
+		}
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorUnverifiable}
+	}
+	if err := p.checkKey(key); err != nil {
+		return nil, err
+	}
+	if p.Logger != nil {
+		p.Logger.Log(ctx, newLogEvent(EventKeySelected, token))
+	}
+	return key, nil
+}
+
+// Reject weak keys if the parser has StrictKeys
+func (p *Parser) checkKey(key interface{}) error {
+	if !p.StrictKeys {
+		return nil
+	}
+	if err := CheckKeyStrength(key); err != nil {
+		return &ValidationError{Inner: err, Errors: ValidationErrorUnverifiable}
+	}
+	return nil
 }
@@ -0,0 +1,194 @@
+package jwt
+
+// ParserOption is used to implement functional-style options that modify
+// the behavior of the parser.  To add new options, just create a function
+// (ideally beginning with With or Without) that returns an anonymous function
+// that takes a *Parser type as input and manipulates its configuration accordingly.
+type ParserOption func(*Parser)
+
+// Create a new Parser with the specified options.  Unlike the zero Parser, it
+// decodes segments strictly; use WithLenientDecoding for legacy tokens.
+func NewParser(options ...ParserOption) *Parser {
+	p := &Parser{StrictDecoding: true}
+
+	// loop through our parsing options and apply them
+	for _, option := range options {
+		option(p)
+	}
+
+	return p
+}
+
+// Supply the algorithm methods that the parser will check.
+// Only those methods will be considered valid.
+func WithValidMethods(methods []string) ParserOption {
+	return func(p *Parser) {
+		p.ValidMethods = methods
+	}
+}
+
+// Disable claims validation during token parsing
+func WithoutClaimsValidation() ParserOption {
+	return func(p *Parser) {
+		p.SkipClaimsValidation = true
+	}
+}
+
+// Require the typ header to match the given media type, e.g. "at+jwt" for
+// RFC 9068 access tokens.  Comparison is case-insensitive and an "application/"
+// prefix is ignored, per RFC 7515 section 4.1.9.
+func WithType(typ string) ParserOption {
+	return func(p *Parser) {
+		p.RequiredType = typ
+	}
+}
+
+// Decode headers and claims with codec instead of DefaultJSONCodec
+func WithJSONCodec(codec JSONCodec) ParserOption {
+	return func(p *Parser) {
+		p.JSONCodec = codec
+	}
+}
+
+// Decode numeric claims as json.Number instead of float64, so integers
+// beyond 2^53 keep their exact value
+func WithJSONNumber() ParserOption {
+	return func(p *Parser) {
+		p.UseJSONNumber = true
+	}
+}
+
+// Reject tokens with claims that have no matching field in the claims struct
+// they are parsed into
+func WithDisallowUnknownFields() ParserOption {
+	return func(p *Parser) {
+		p.DisallowUnknownFields = true
+	}
+}
+
+// Skip signature verification for tokens recorded in cache, and record
+// tokens whose signatures verify
+func WithVerificationCache(cache VerificationCache) ParserOption {
+	return func(p *Parser) {
+		p.VerificationCache = cache
+	}
+}
+
+// Accept padded or otherwise non-canonical base64url header, payload and
+// signature segments, for interoperating with legacy token producers
+func WithLenientDecoding() ParserOption {
+	return func(p *Parser) {
+		p.StrictDecoding = false
+	}
+}
+
+// Reject tokens whose header or claims contain duplicate JSON keys
+func WithRejectDuplicateKeys() ParserOption {
+	return func(p *Parser) {
+		p.RejectDuplicateKeys = true
+	}
+}
+
+// Reject tokens longer than size bytes before decoding them
+func WithMaxTokenSize(size int) ParserOption {
+	return func(p *Parser) {
+		p.MaxTokenSize = size
+	}
+}
+
+// Reject tokens with any encoded segment longer than size bytes
+func WithMaxSegmentSize(size int) ParserOption {
+	return func(p *Parser) {
+		p.MaxSegmentSize = size
+	}
+}
+
+// Reject tokens whose header or claims nest objects and arrays more than depth levels
+func WithMaxJSONDepth(depth int) ParserOption {
+	return func(p *Parser) {
+		p.MaxJSONDepth = depth
+	}
+}
+
+// Check claims with exactly validators, in order, instead of their Valid method
+func WithValidators(validators ...Validator) ParserOption {
+	return func(p *Parser) {
+		p.Validators = validators
+	}
+}
+
+// Add a step to the end of the validation pipeline, which starts out as
+// DefaultValidators
+func WithValidator(validator Validator) ParserOption {
+	return func(p *Parser) {
+		if p.Validators == nil {
+			p.Validators = DefaultValidators()
+		}
+		p.Validators = append(p.Validators, validator)
+	}
+}
+
+// Remove the steps with the given name, such as "exp", from the validation
+// pipeline, which starts out as DefaultValidators
+func WithoutValidator(name string) ParserOption {
+	return func(p *Parser) {
+		if p.Validators == nil {
+			p.Validators = DefaultValidators()
+		}
+		p.Validators = withoutValidator(p.Validators, name)
+	}
+}
+
+// Send parse events to logger
+func WithLogger(logger Logger) ParserOption {
+	return func(p *Parser) {
+		p.Logger = logger
+	}
+}
+
+// Reject weak verification keys, such as RSA keys shorter than 2048 bits
+func WithStrictKeys() ParserOption {
+	return func(p *Parser) {
+		p.StrictKeys = true
+	}
+}
+
+// Add mappers that normalize the claims of valid tokens, in order.  See
+// ClaimsMapper.
+func WithClaimsMapper(mappers ...ClaimsMapper) ParserOption {
+	return func(p *Parser) {
+		p.ClaimsMappers = append(p.ClaimsMappers, mappers...)
+	}
+}
+
+// Reject tokens whose registered header parameters, such as kid or x5c, have
+// the wrong type.  See ParseHeader.
+func WithStrictHeader() ParserOption {
+	return func(p *Parser) {
+		p.StrictHeader = true
+	}
+}
+
+// Make Parse decode claims into a *LazyClaims, so only the claims that are
+// used are decoded.  See LazyClaims.
+func WithLazyClaims() ParserOption {
+	return func(p *Parser) {
+		p.LazyClaims = true
+	}
+}
+
+// Decrypt the named claims with fc before decoding the claims.  See
+// FieldCipher and EncryptClaims.
+func WithFieldCipher(fc FieldCipher, names ...string) ParserOption {
+	return func(p *Parser) {
+		p.FieldCipher = fc
+		p.EncryptedClaims = append(p.EncryptedClaims, names...)
+	}
+}
+
+// Limit how large a compressed payload may grow when decompressed
+func WithMaxDecompressedSize(size int) ParserOption {
+	return func(p *Parser) {
+		p.MaxDecompressedSize = size
+	}
+}
@@ -2,9 +2,11 @@ package jwt_test
 
 import (
 	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -172,6 +174,15 @@ var jwtTestData = []struct {
 		jwt.ValidationErrorNotValidYet | jwt.ValidationErrorExpired,
 		&jwt.Parser{UseJSONNumber: true},
 	},
+	{
+		"JSON Number - exponent expired",
+		"", // autogen
+		defaultKeyFunc,
+		jwt.MapClaims{"foo": "bar", "exp": json.Number("1.5e9")},
+		false,
+		jwt.ValidationErrorExpired,
+		jwt.NewParser(jwt.WithJSONNumber()),
+	},
 	{
 		"SkipClaimsValidation during token parsing",
 		"", // autogen
@@ -181,6 +192,24 @@ var jwtTestData = []struct {
 		0,
 		&jwt.Parser{UseJSONNumber: true, SkipClaimsValidation: true},
 	},
+	{
+		"required type",
+		"", // autogen
+		defaultKeyFunc,
+		jwt.MapClaims{"foo": "bar"},
+		true,
+		0,
+		jwt.NewParser(jwt.WithType("application/jwt")),
+	},
+	{
+		"invalid type",
+		"", // autogen
+		defaultKeyFunc,
+		jwt.MapClaims{"foo": "bar"},
+		false,
+		jwt.ValidationErrorType,
+		jwt.NewParser(jwt.WithType("at+jwt")),
+	},
 }
 
 func TestParser_Parse(t *testing.T) {
@@ -247,6 +276,134 @@ func TestParser_Parse(t *testing.T) {
 	}
 }
 
+func TestParser_JSONNumberPrecision(t *testing.T) {
+	tokenString := test.MakeSampleToken(jwt.MapClaims{"id": json.Number("9007199254740993")}, test.LoadRSAPrivateKeyFromDisk("test/sample_key"))
+
+	token, err := jwt.NewParser(jwt.WithJSONNumber()).Parse(tokenString, defaultKeyFunc)
+	if err != nil {
+		t.Fatalf("Error parsing token: %v", err)
+	}
+	if id := token.Claims.(jwt.MapClaims)["id"]; id != json.Number("9007199254740993") {
+		t.Errorf("Large integer claim lost precision: %v", id)
+	}
+}
+
+func TestParser_DisallowUnknownFields(t *testing.T) {
+	privateKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	parser := jwt.NewParser(jwt.WithDisallowUnknownFields())
+
+	tokenString := test.MakeSampleToken(jwt.MapClaims{"sub": "user", "exp": time.Now().Add(time.Hour).Unix()}, privateKey)
+	if _, err := parser.ParseWithClaims(tokenString, &jwt.StandardClaims{}, defaultKeyFunc); err != nil {
+		t.Errorf("Error parsing token with known claims: %v", err)
+	}
+
+	tokenString = test.MakeSampleToken(jwt.MapClaims{"sub": "user", "admin": true}, privateKey)
+	_, err := parser.ParseWithClaims(tokenString, &jwt.StandardClaims{}, defaultKeyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorMalformed == 0 {
+		t.Errorf("Expected malformed error for unknown claim, got %v", err)
+	}
+
+	// MapClaims accept any claim
+	if _, err := parser.Parse(tokenString, defaultKeyFunc); err != nil {
+		t.Errorf("Error parsing into MapClaims: %v", err)
+	}
+}
+
+func TestParser_RawSegments(t *testing.T) {
+	header := `{"typ":"JWT", "alg":"HS256"}`
+	claims := `{"sub":"user",  "n":1.0}`
+	signingString := jwt.EncodeSegment([]byte(header)) + "." + jwt.EncodeSegment([]byte(claims))
+	sig, _ := jwt.SigningMethodHS256.Sign(signingString, hmacTestKey)
+
+	token, err := jwt.Parse(signingString+"."+sig, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+	if err != nil {
+		t.Fatalf("Error parsing token: %v", err)
+	}
+	if string(token.RawHeader) != header {
+		t.Errorf("RawHeader mismatch: %s", token.RawHeader)
+	}
+	if string(token.RawClaims) != claims {
+		t.Errorf("RawClaims mismatch: %s", token.RawClaims)
+	}
+}
+
+func TestParser_StrictDecoding(t *testing.T) {
+	// A header whose encoding needs padding, sent padded
+	header := base64.URLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"} `))
+	if !strings.HasSuffix(header, "=") {
+		t.Fatalf("Test header is not padded: %v", header)
+	}
+	signingString := header + "." + jwt.EncodeSegment([]byte(`{"foo":"bar"}`))
+	sig, _ := jwt.SigningMethodHS256.Sign(signingString, hmacTestKey)
+	tokenString := signingString + "." + sig
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	if _, err := new(jwt.Parser).Parse(tokenString, keyFunc); err != nil {
+		t.Errorf("Zero Parser rejected padded segment: %v", err)
+	}
+	if _, err := jwt.NewParser(jwt.WithLenientDecoding()).Parse(tokenString, keyFunc); err != nil {
+		t.Errorf("Lenient parser rejected padded segment: %v", err)
+	}
+	_, err := jwt.NewParser().Parse(tokenString, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorMalformed == 0 {
+		t.Errorf("Expected strict parser to reject padded segment, got %v", err)
+	}
+	if err := jwt.NewParser().ParseInto(&jwt.Token{}, tokenString, jwt.MapClaims{}, keyFunc); err == nil {
+		t.Errorf("Expected strict ParseInto to reject padded segment")
+	}
+
+	// The signature segment follows the same switch
+	padded := signHS256(jwt.MapClaims{"foo": "bar"}) + "="
+	if _, err := jwt.NewParser(jwt.WithLenientDecoding()).Parse(padded, keyFunc); err != nil {
+		t.Errorf("Lenient parser rejected padded signature: %v", err)
+	}
+	if err := jwt.NewParser(jwt.WithLenientDecoding()).ParseInto(&jwt.Token{}, padded, jwt.MapClaims{}, keyFunc); err != nil {
+		t.Errorf("Lenient ParseInto rejected padded signature: %v", err)
+	}
+
+	if _, err := jwt.DecodeSegment("abcde"); err == nil {
+		t.Errorf("Segment with a single leftover character decoded")
+	}
+}
+
+func TestParser_PaddedSignature(t *testing.T) {
+	// HS256 signatures encode to 43 characters, so one "=" pads them
+	padded := signHS256(jwt.MapClaims{"foo": "bar"}) + "="
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	if _, err := new(jwt.Parser).Parse(padded, keyFunc); err != nil {
+		t.Errorf("Zero Parser rejected padded signature: %v", err)
+	}
+	_, err := jwt.NewParser().Parse(padded, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorSignatureInvalid == 0 {
+		t.Errorf("Expected strict parser to reject padded signature, got %v", err)
+	}
+}
+
+func TestParseUnverified(t *testing.T) {
+	tokenString := test.MakeSampleToken(jwt.MapClaims{"iss": "tenant-a"}, test.LoadRSAPrivateKeyFromDisk("test/sample_key"))
+
+	token, err := jwt.ParseUnverified(tokenString)
+	if err != nil {
+		t.Fatalf("Error parsing token: %v", err)
+	}
+	if token.Valid {
+		t.Errorf("Unverified token marked valid")
+	}
+	if iss := token.Claims.(jwt.MapClaims)["iss"]; iss != "tenant-a" {
+		t.Errorf("Unexpected iss: %v", iss)
+	}
+
+	claims := &jwt.StandardClaims{}
+	if _, err := jwt.ParseUnverifiedWithClaims(tokenString, claims); err != nil || claims.Issuer != "tenant-a" {
+		t.Errorf("Unexpected claims %v: %v", claims, err)
+	}
+
+	if _, err := jwt.ParseUnverified("not.a-token"); err == nil {
+		t.Errorf("Malformed token parsed")
+	}
+}
+
 func TestParser_ParseUnverified(t *testing.T) {
 	privateKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
 
@@ -0,0 +1,18 @@
+// PASETO tokens with the same claims types and API shape as this package's
+// JWTs, for teams evaluating or migrating between the formats.
+//
+// Claims types that work with jwt.NewWithClaims and jwt.ParseWithClaims work
+// here unchanged.  exp, iat and nbf are converted between NumericDate and the
+// RFC 3339 strings PASETO uses:
+//
+//	token := paseto.NewWithClaims(paseto.V4Public, &jwt.StandardClaims{Subject: "alice", ExpiresAt: exp})
+//	tokenString, err := token.SignedString(privateKey)
+//
+//	token, err := paseto.ParseWithClaims(tokenString, &jwt.StandardClaims{}, func(*paseto.Token) (interface{}, error) {
+//		return publicKey, nil
+//	})
+//
+// V2Public and V4Public use Ed25519 keys.  V2Local and V4Local, which use
+// 32-byte symmetric keys, depend on golang.org/x/crypto and are only built
+// with the xcrypto build tag.
+package paseto
@@ -0,0 +1,138 @@
+//go:build xcrypto
+
+package paseto
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+
+	"github.com/dgrijalva/jwt-go"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Symmetrically encrypted tokens, with 32-byte keys
+var (
+	V2Local = register(&Protocol{name: "v2.local", seal: sealV2Local, open: openV2Local})
+	V4Local = register(&Protocol{name: "v4.local", implicit: true, seal: sealV4Local, open: openV4Local})
+)
+
+func localKey(key interface{}) ([]byte, error) {
+	k, ok := key.([]byte)
+	if !ok || len(k) != 32 {
+		return nil, jwt.ErrInvalidKeyType
+	}
+	return k, nil
+}
+
+// Keyed BLAKE2b of the concatenated pieces
+func blake2bSum(size int, key []byte, pieces ...[]byte) []byte {
+	h, err := blake2b.New(size, key)
+	if err != nil {
+		panic(err)
+	}
+	for _, p := range pieces {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
+
+// XChaCha20-Poly1305, with the nonce derived from the message and a random key
+func sealV2Local(header string, message, footer, implicit []byte, key interface{}) ([]byte, error) {
+	k, err := localKey(key)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	nonce := blake2bSum(chacha20poly1305.NonceSizeX, b, message)
+	aead, err := chacha20poly1305.NewX(k)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, message, pae([]byte(header), nonce, footer)), nil
+}
+
+func openV2Local(header string, payload, footer, implicit []byte, key interface{}) ([]byte, error) {
+	k, err := localKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) < chacha20poly1305.NonceSizeX+chacha20poly1305.Overhead {
+		return nil, ErrTokenInvalid
+	}
+	aead, err := chacha20poly1305.NewX(k)
+	if err != nil {
+		return nil, err
+	}
+	nonce, ciphertext := payload[:chacha20poly1305.NonceSizeX], payload[chacha20poly1305.NonceSizeX:]
+	message, err := aead.Open(nil, nonce, ciphertext, pae([]byte(header), nonce, footer))
+	if err != nil {
+		return nil, ErrTokenInvalid
+	}
+	return message, nil
+}
+
+const (
+	v4NonceSize = 32
+	v4TagSize   = 32
+)
+
+// Derive the v4 encryption key, XChaCha20 nonce and authentication key
+func v4Keys(k, nonce []byte) (encKey, encNonce, authKey []byte) {
+	tmp := blake2bSum(56, k, []byte("paseto-encryption-key"), nonce)
+	return tmp[:32], tmp[32:], blake2bSum(32, k, []byte("paseto-auth-key-for-aead"), nonce)
+}
+
+// XChaCha20, then a keyed BLAKE2b tag over the header, nonce, ciphertext,
+// footer and implicit assertion
+func sealV4Local(header string, message, footer, implicit []byte, key interface{}) ([]byte, error) {
+	k, err := localKey(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, v4NonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	encKey, encNonce, authKey := v4Keys(k, nonce)
+	cipher, err := chacha20.NewUnauthenticatedCipher(encKey, encNonce)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, len(message))
+	cipher.XORKeyStream(ciphertext, message)
+	tag := blake2bSum(v4TagSize, authKey, pae([]byte(header), nonce, ciphertext, footer, implicit))
+
+	payload := append(nonce, ciphertext...)
+	return append(payload, tag...), nil
+}
+
+func openV4Local(header string, payload, footer, implicit []byte, key interface{}) ([]byte, error) {
+	k, err := localKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) < v4NonceSize+v4TagSize {
+		return nil, ErrTokenInvalid
+	}
+	nonce := payload[:v4NonceSize]
+	ciphertext := payload[v4NonceSize : len(payload)-v4TagSize]
+	tag := payload[len(payload)-v4TagSize:]
+
+	encKey, encNonce, authKey := v4Keys(k, nonce)
+	expected := blake2bSum(v4TagSize, authKey, pae([]byte(header), nonce, ciphertext, footer, implicit))
+	if subtle.ConstantTimeCompare(tag, expected) != 1 {
+		return nil, ErrTokenInvalid
+	}
+	cipher, err := chacha20.NewUnauthenticatedCipher(encKey, encNonce)
+	if err != nil {
+		return nil, err
+	}
+	message := make([]byte, len(ciphertext))
+	cipher.XORKeyStream(message, ciphertext)
+	return message, nil
+}
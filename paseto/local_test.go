@@ -0,0 +1,37 @@
+//go:build xcrypto
+
+package paseto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestLocal(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	otherKey := []byte("fedcba9876543210fedcba9876543210")
+
+	for _, protocol := range []*Protocol{V2Local, V4Local} {
+		token := NewWithClaims(protocol, &jwt.StandardClaims{Subject: "alice", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+		token.Footer = []byte(`{"kid":"k1"}`)
+		tokenString, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("[%v] Error encrypting token: %v", protocol.Name(), err)
+		}
+
+		claims := &jwt.StandardClaims{}
+		if _, err := ParseWithClaims(tokenString, claims, func(*Token) (interface{}, error) { return key, nil }); err != nil || claims.Subject != "alice" {
+			t.Errorf("[%v] Error decrypting token: %v %+v", protocol.Name(), err, claims)
+		}
+		if _, err := ParseWithClaims(tokenString, &jwt.StandardClaims{}, func(*Token) (interface{}, error) { return otherKey, nil }); err == nil {
+			t.Errorf("[%v] Expected wrong key to fail", protocol.Name())
+		}
+
+		tampered := tokenString[:len(tokenString)-2] + "AA"
+		if _, err := ParseWithClaims(tampered, &jwt.StandardClaims{}, func(*Token) (interface{}, error) { return key, nil }); err == nil {
+			t.Errorf("[%v] Expected tampered footer to fail", protocol.Name())
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package paseto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Errors
+var (
+	ErrImplicitUnsupported = errors.New("implicit assertions require a v4 protocol")
+	ErrTokenInvalid        = errors.New("token authentication failed")
+)
+
+// A PASETO version and purpose, such as v4.public
+type Protocol struct {
+	name     string
+	implicit bool // Supports implicit assertions
+	seal     func(header string, message, footer, implicit []byte, key interface{}) ([]byte, error)
+	open     func(header string, payload, footer, implicit []byte, key interface{}) ([]byte, error)
+}
+
+var protocols = map[string]*Protocol{}
+
+func register(p *Protocol) *Protocol {
+	protocols[p.name] = p
+	return p
+}
+
+// The version and purpose, such as "v4.public"
+func (p *Protocol) Name() string {
+	return p.name
+}
+
+// A PASETO token.  Different fields will be used depending on whether
+// you're creating or parsing/verifying a token.
+type Token struct {
+	Raw      string    // The raw token.  Populated when you Parse a token
+	Protocol *Protocol // The version and purpose
+	Claims   jwt.Claims
+	Footer   []byte // Authenticated but never encrypted, often a key ID
+	Implicit []byte // Authenticated but not sent, for v4 only.  Set before signing; parse with Parser.Implicit
+	Valid    bool   // Is the token valid?  Populated when you Parse/Verify a token
+}
+
+// Create a new token for protocol with claims
+func NewWithClaims(protocol *Protocol, claims jwt.Claims) *Token {
+	return &Token{Protocol: protocol, Claims: claims}
+}
+
+// Sign or encrypt the token with key: an ed25519.PrivateKey for public
+// protocols, or a 32-byte []byte for local ones
+func (t *Token) SignedString(key interface{}) (string, error) {
+	if len(t.Implicit) > 0 && !t.Protocol.implicit {
+		return "", ErrImplicitUnsupported
+	}
+	message, err := encodeClaims(t.Claims)
+	if err != nil {
+		return "", err
+	}
+	header := t.Protocol.name + "."
+	payload, err := t.Protocol.seal(header, message, t.Footer, t.Implicit, key)
+	if err != nil {
+		return "", err
+	}
+	s := header + base64.RawURLEncoding.EncodeToString(payload)
+	if len(t.Footer) > 0 {
+		s += "." + base64.RawURLEncoding.EncodeToString(t.Footer)
+	}
+	return s, nil
+}
+
+// Returns the key for verifying or decrypting a token.  The footer is
+// available for finding the key, but its contents are not yet authenticated.
+type Keyfunc func(*Token) (interface{}, error)
+
+// Parses PASETO tokens
+type Parser struct {
+	ValidProtocols       []string // If populated, only these protocols, such as "v4.public", will be accepted
+	SkipClaimsValidation bool     // Skip claims validation during token parsing
+	Implicit             []byte   // The implicit assertion the token was created with, for v4 protocols
+}
+
+// Parse, validate, and return a token, with map claims
+func Parse(tokenString string, keyFunc Keyfunc) (*Token, error) {
+	return new(Parser).Parse(tokenString, keyFunc)
+}
+
+// Parse, validate, and return a token, decoding the claims into claims
+func ParseWithClaims(tokenString string, claims jwt.Claims, keyFunc Keyfunc) (*Token, error) {
+	return new(Parser).ParseWithClaims(tokenString, claims, keyFunc)
+}
+
+func (p *Parser) Parse(tokenString string, keyFunc Keyfunc) (*Token, error) {
+	return p.ParseWithClaims(tokenString, jwt.MapClaims{}, keyFunc)
+}
+
+func (p *Parser) ParseWithClaims(tokenString string, claims jwt.Claims, keyFunc Keyfunc) (*Token, error) {
+	parts := strings.Split(tokenString, ".")
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, jwt.NewValidationError("token contains an invalid number of segments", jwt.ValidationErrorMalformed)
+	}
+	name := parts[0] + "." + parts[1]
+	protocol, ok := protocols[name]
+	if !ok {
+		return nil, jwt.NewValidationError(fmt.Sprintf("protocol %v is unavailable", name), jwt.ValidationErrorUnverifiable)
+	}
+	if p.ValidProtocols != nil && !contains(p.ValidProtocols, name) {
+		return nil, jwt.NewValidationError(fmt.Sprintf("protocol %v is invalid", name), jwt.ValidationErrorSignatureInvalid)
+	}
+	if len(p.Implicit) > 0 && !protocol.implicit {
+		return nil, &jwt.ValidationError{Inner: ErrImplicitUnsupported, Errors: jwt.ValidationErrorUnverifiable}
+	}
+
+	token := &Token{Raw: tokenString, Protocol: protocol, Claims: claims, Implicit: p.Implicit}
+	payload, err := base64.RawURLEncoding.Strict().DecodeString(parts[2])
+	if err != nil {
+		return token, &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorMalformed}
+	}
+	if len(parts) == 4 {
+		if token.Footer, err = base64.RawURLEncoding.Strict().DecodeString(parts[3]); err != nil {
+			return token, &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorMalformed}
+		}
+	}
+
+	if keyFunc == nil {
+		return token, jwt.NewValidationError("no Keyfunc was provided.", jwt.ValidationErrorUnverifiable)
+	}
+	key, err := keyFunc(token)
+	if err != nil {
+		if ve, ok := err.(*jwt.ValidationError); ok {
+			return token, ve
+		}
+		return token, &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorUnverifiable}
+	}
+
+	message, err := protocol.open(name+".", payload, token.Footer, p.Implicit, key)
+	if err != nil {
+		return token, &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorSignatureInvalid}
+	}
+	if err := decodeClaims(message, claims); err != nil {
+		return token, &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorMalformed}
+	}
+
+	if !p.SkipClaimsValidation {
+		if err := claims.Valid(); err != nil {
+			if e, ok := err.(*jwt.ValidationError); ok {
+				return token, e
+			}
+			return token, &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorClaimsInvalid}
+		}
+	}
+	token.Valid = true
+	return token, nil
+}
+
+// The registered claims PASETO encodes as RFC 3339 strings rather than
+// NumericDate
+var timeClaims = []string{"exp", "iat", "nbf"}
+
+// Encode claims as JSON, converting NumericDate claims to RFC 3339
+func encodeClaims(claims jwt.Claims) ([]byte, error) {
+	m, err := toMap(claims)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range timeClaims {
+		if n, ok := m[name].(json.Number); ok {
+			seconds, err := n.Int64()
+			if err != nil {
+				f, _ := n.Float64()
+				seconds = int64(f)
+			}
+			m[name] = time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+		}
+	}
+	return json.Marshal(m)
+}
+
+// Decode claims from JSON, converting RFC 3339 time claims to NumericDate
+func decodeClaims(data []byte, claims jwt.Claims) error {
+	m := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return err
+	}
+	for _, name := range timeClaims {
+		if s, ok := m[name].(string); ok {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return fmt.Errorf("%v claim is not an RFC 3339 time: %v", name, err)
+			}
+			m[name] = json.Number(fmt.Sprint(t.Unix()))
+		}
+	}
+	converted, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if c, ok := claims.(jwt.MapClaims); ok {
+		// Keep numbers as float64, as jwt.Parse does
+		return json.Unmarshal(converted, &c)
+	}
+	return json.Unmarshal(converted, claims)
+}
+
+func toMap(claims jwt.Claims) (map[string]interface{}, error) {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Pre-authentication encoding, which unambiguously joins pieces for signing
+func pae(pieces ...[]byte) []byte {
+	n := 8
+	for _, p := range pieces {
+		n += 8 + len(p)
+	}
+	out := make([]byte, 0, n)
+	out = appendLE64(out, len(pieces))
+	for _, p := range pieces {
+		out = appendLE64(out, len(p))
+		out = append(out, p...)
+	}
+	return out
+}
+
+func appendLE64(b []byte, n int) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(n)&^(1<<63))
+	return append(b, buf[:]...)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
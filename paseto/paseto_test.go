@@ -0,0 +1,108 @@
+package paseto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Test vector 4-S-1 from the PASETO specification
+func TestV4PublicVector(t *testing.T) {
+	secret, _ := hex.DecodeString("b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a37741eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2")
+	public := ed25519.PrivateKey(secret).Public()
+	tokenString := "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA"
+
+	claims := jwt.MapClaims{}
+	parser := &Parser{SkipClaimsValidation: true}
+	if _, err := parser.ParseWithClaims(tokenString, claims, func(*Token) (interface{}, error) { return public, nil }); err != nil {
+		t.Fatalf("Error verifying test vector: %v", err)
+	}
+	if claims["data"] != "this is a signed message" || claims["exp"] != float64(1640995200) {
+		t.Errorf("Unexpected claims %v", claims)
+	}
+}
+
+func TestPublic(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	otherPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	exp := time.Now().Add(time.Hour).Unix()
+
+	var tests = []struct {
+		name     string
+		protocol *Protocol
+		claims   jwt.Claims
+		footer   string
+		implicit string
+		parser   *Parser
+		key      interface{}
+		errors   uint32
+	}{
+		{"v4", V4Public, &jwt.StandardClaims{Subject: "alice", ExpiresAt: exp}, "", "", &Parser{}, public, 0},
+		{"v2", V2Public, &jwt.StandardClaims{Subject: "alice", ExpiresAt: exp}, "", "", &Parser{}, public, 0},
+		{"footer", V4Public, &jwt.StandardClaims{Subject: "alice"}, `{"kid":"k1"}`, "", &Parser{}, public, 0},
+		{"implicit", V4Public, &jwt.StandardClaims{Subject: "alice"}, "", "tenant-1", &Parser{Implicit: []byte("tenant-1")}, public, 0},
+		{"wrong implicit", V4Public, &jwt.StandardClaims{Subject: "alice"}, "", "tenant-1", &Parser{Implicit: []byte("tenant-2")}, public, jwt.ValidationErrorSignatureInvalid},
+		{"wrong key", V4Public, &jwt.StandardClaims{Subject: "alice"}, "", "", &Parser{}, otherPublic, jwt.ValidationErrorSignatureInvalid},
+		{"expired", V4Public, &jwt.StandardClaims{Subject: "alice", ExpiresAt: time.Now().Add(-time.Hour).Unix()}, "", "", &Parser{}, public, jwt.ValidationErrorExpired},
+		{"invalid protocol", V2Public, &jwt.StandardClaims{Subject: "alice"}, "", "", &Parser{ValidProtocols: []string{"v4.public"}}, public, jwt.ValidationErrorSignatureInvalid},
+	}
+
+	for _, data := range tests {
+		token := NewWithClaims(data.protocol, data.claims)
+		token.Footer = []byte(data.footer)
+		token.Implicit = []byte(data.implicit)
+		tokenString, err := token.SignedString(private)
+		if err != nil {
+			t.Fatalf("[%v] Error signing token: %v", data.name, err)
+		}
+		if !strings.HasPrefix(tokenString, data.protocol.Name()+".") {
+			t.Errorf("[%v] Unexpected token %v", data.name, tokenString)
+		}
+
+		claims := &jwt.StandardClaims{}
+		var footer string
+		parsed, err := data.parser.ParseWithClaims(tokenString, claims, func(token *Token) (interface{}, error) {
+			footer = string(token.Footer)
+			return data.key, nil
+		})
+		if data.errors == 0 {
+			if err != nil || !parsed.Valid {
+				t.Errorf("[%v] Error parsing token: %v", data.name, err)
+			} else if claims.Subject != "alice" || footer != data.footer {
+				t.Errorf("[%v] Unexpected claims %+v or footer %q", data.name, claims, footer)
+			}
+			continue
+		}
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&data.errors == 0 {
+			t.Errorf("[%v] Expected error flags %v, got %v", data.name, data.errors, err)
+		}
+	}
+}
+
+func TestTimeClaimsEncoding(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	tokenString, _ := NewWithClaims(V4Public, &jwt.StandardClaims{ExpiresAt: 1640995200}).SignedString(private)
+
+	payload, _ := base64.RawURLEncoding.DecodeString(strings.Split(tokenString, ".")[2])
+	if message := string(payload[:len(payload)-ed25519.SignatureSize]); message != `{"exp":"2022-01-01T00:00:00Z"}` {
+		t.Errorf("Expected exp as an RFC 3339 string, got %v", message)
+	}
+
+	if _, err := NewWithClaims(V2Public, jwt.MapClaims{}).SignedString(private); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	token := NewWithClaims(V2Public, jwt.MapClaims{})
+	token.Implicit = []byte("x")
+	if _, err := token.SignedString(private); err != ErrImplicitUnsupported {
+		t.Errorf("Expected ErrImplicitUnsupported, got %v", err)
+	}
+	if _, err := NewWithClaims(V4Public, jwt.MapClaims{}).SignedString([]byte("secret")); err != jwt.ErrInvalidKeyType {
+		t.Errorf("Expected ErrInvalidKeyType, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package paseto
+
+import (
+	"crypto/ed25519"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Ed25519 signed tokens
+var (
+	V2Public = register(&Protocol{name: "v2.public", seal: signPublic(false), open: verifyPublic(false)})
+	V4Public = register(&Protocol{name: "v4.public", implicit: true, seal: signPublic(true), open: verifyPublic(true)})
+)
+
+// v2 signs PAE(h, m, f); v4 adds the implicit assertion
+func publicPreAuth(v4 bool, header string, message, footer, implicit []byte) []byte {
+	if v4 {
+		return pae([]byte(header), message, footer, implicit)
+	}
+	return pae([]byte(header), message, footer)
+}
+
+func signPublic(v4 bool) func(string, []byte, []byte, []byte, interface{}) ([]byte, error) {
+	return func(header string, message, footer, implicit []byte, key interface{}) ([]byte, error) {
+		private, ok := key.(ed25519.PrivateKey)
+		if !ok || len(private) != ed25519.PrivateKeySize {
+			return nil, jwt.ErrInvalidKeyType
+		}
+		sig := ed25519.Sign(private, publicPreAuth(v4, header, message, footer, implicit))
+		return append(message, sig...), nil
+	}
+}
+
+func verifyPublic(v4 bool) func(string, []byte, []byte, []byte, interface{}) ([]byte, error) {
+	return func(header string, payload, footer, implicit []byte, key interface{}) ([]byte, error) {
+		public, ok := key.(ed25519.PublicKey)
+		if !ok || len(public) != ed25519.PublicKeySize {
+			return nil, jwt.ErrInvalidKeyType
+		}
+		if len(payload) < ed25519.SignatureSize {
+			return nil, ErrTokenInvalid
+		}
+		message, sig := payload[:len(payload)-ed25519.SignatureSize], payload[len(payload)-ed25519.SignatureSize:]
+		if !ed25519.Verify(public, publicPreAuth(v4, header, message, footer, implicit), sig) {
+			return nil, ErrTokenInvalid
+		}
+		return message, nil
+	}
+}
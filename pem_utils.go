@@ -0,0 +1,81 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+)
+
+var (
+	ErrUnsupportedKeyType = errors.New("Key is not a supported PKCS1, PKCS8, SEC1 or PKIX key")
+)
+
+// Parse a PEM encoded private key, detecting the encoding from its contents.
+// Supports PKCS1 (RSA), SEC1 (EC) and PKCS8 (any) keys.  The result is an
+// *rsa.PrivateKey or *ecdsa.PrivateKey, or another type allowed in PKCS8.
+func ParsePrivateKeyFromPEM(key []byte) (crypto.PrivateKey, error) {
+	// Parse PEM block
+	var block *pem.Block
+	if block, _ = pem.Decode(key); block == nil {
+		return nil, ErrKeyMustBePEMEncoded
+	}
+
+	return parsePrivateKeyDER(block.Bytes)
+}
+
+// Parse a PEM encoded private key protected with password.  See ParsePrivateKeyFromPEM.
+// Only legacy RFC 1423 PEM encryption ("Proc-Type: 4,ENCRYPTED") is supported.
+func ParsePrivateKeyFromPEMWithPassword(key []byte, password string) (crypto.PrivateKey, error) {
+	var err error
+
+	// Parse PEM block
+	var block *pem.Block
+	if block, _ = pem.Decode(key); block == nil {
+		return nil, ErrKeyMustBePEMEncoded
+	}
+
+	var blockDecrypted []byte
+	if blockDecrypted, err = x509.DecryptPEMBlock(block, []byte(password)); err != nil {
+		return nil, err
+	}
+
+	return parsePrivateKeyDER(blockDecrypted)
+}
+
+// Parse a PEM encoded public key, detecting the encoding from its contents.
+// Supports PKIX and PKCS1 (RSA) public keys as well as X.509 certificates,
+// in which case the certificate's public key is returned.
+func ParsePublicKeyFromPEM(key []byte) (crypto.PublicKey, error) {
+	// Parse PEM block
+	var block *pem.Block
+	if block, _ = pem.Decode(key); block == nil {
+		return nil, ErrKeyMustBePEMEncoded
+	}
+
+	if parsedKey, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return parsedKey, nil
+	}
+	if parsedKey, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return parsedKey, nil
+	}
+	if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		return cert.PublicKey, nil
+	}
+
+	return nil, ErrUnsupportedKeyType
+}
+
+func parsePrivateKeyDER(der []byte) (crypto.PrivateKey, error) {
+	if parsedKey, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return parsedKey, nil
+	}
+	if parsedKey, err := x509.ParseECPrivateKey(der); err == nil {
+		return parsedKey, nil
+	}
+	if parsedKey, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return parsedKey, nil
+	}
+
+	return nil, ErrUnsupportedKeyType
+}
@@ -0,0 +1,81 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestParsePrivateKeyFromPEM(t *testing.T) {
+	var tests = []struct {
+		file string
+		rsa  bool
+	}{
+		{"test/sample_key", true},
+		{"test/ec256-private.pem", false},
+		{"test/ec512-private.pem", false},
+	}
+
+	for _, data := range tests {
+		keyData, _ := ioutil.ReadFile(data.file)
+		key, err := jwt.ParsePrivateKeyFromPEM(keyData)
+		if err != nil {
+			t.Errorf("[%v] Error parsing key: %v", data.file, err)
+			continue
+		}
+		if _, ok := key.(*rsa.PrivateKey); ok != data.rsa {
+			t.Errorf("[%v] Unexpected key type %T", data.file, key)
+		}
+	}
+
+	if _, err := jwt.ParsePrivateKeyFromPEM([]byte("not a key")); err != jwt.ErrKeyMustBePEMEncoded {
+		t.Errorf("Expected ErrKeyMustBePEMEncoded, got %v", err)
+	}
+}
+
+func TestParsePrivateKeyFromPEMWithPassword(t *testing.T) {
+	keyData, _ := ioutil.ReadFile("test/privateSecure.pem")
+	if _, err := jwt.ParsePrivateKeyFromPEMWithPassword(keyData, "password"); err != nil {
+		t.Errorf("Error parsing encrypted RSA key: %v", err)
+	}
+	if _, err := jwt.ParsePrivateKeyFromPEMWithPassword(keyData, "wrong"); err == nil {
+		t.Errorf("Encrypted key parsed with wrong password")
+	}
+
+	ecData, _ := ioutil.ReadFile("test/ec256-private.pem")
+	ecKey, _ := jwt.ParseECPrivateKeyFromPEM(ecData)
+	der, _ := x509.MarshalECPrivateKey(ecKey)
+	block, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", der, []byte("password"), x509.PEMCipherAES256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted := pem.EncodeToMemory(block)
+
+	parsed, err := jwt.ParseECPrivateKeyFromPEMWithPassword(encrypted, "password")
+	if err != nil {
+		t.Fatalf("Error parsing encrypted EC key: %v", err)
+	}
+	if parsed.D.Cmp(ecKey.D) != 0 {
+		t.Errorf("Decrypted EC key doesn't match")
+	}
+	if key, err := jwt.ParsePrivateKeyFromPEMWithPassword(encrypted, "password"); err != nil {
+		t.Errorf("Error parsing encrypted EC key: %v", err)
+	} else if _, ok := key.(*ecdsa.PrivateKey); !ok {
+		t.Errorf("Unexpected key type %T", key)
+	}
+}
+
+func TestParsePublicKeyFromPEM(t *testing.T) {
+	for _, file := range []string{"test/sample_key.pub", "test/ec256-public.pem", "test/ec384-public.pem"} {
+		keyData, _ := ioutil.ReadFile(file)
+		if _, err := jwt.ParsePublicKeyFromPEM(keyData); err != nil {
+			t.Errorf("[%v] Error parsing public key: %v", file, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package jwt
+
+// Security postures for parsers, each a single ParserOption.  Options passed
+// after a policy override it:
+//
+//	parser := jwt.NewParser(jwt.StrictPolicy("RS256"), jwt.WithJSONNumber())
+
+// Strict posture for new services: only methods are accepted, with none
+// always excluded, so an empty list rejects every token.  Segments must be
+// canonical base64url, exp is required, duplicate JSON keys and weak keys are
+// rejected, and tokens are limited to 16KB.
+func StrictPolicy(methods ...string) ParserOption {
+	allowed := make([]string, 0, len(methods))
+	for _, m := range methods {
+		if m != "none" {
+			allowed = append(allowed, m)
+		}
+	}
+	return func(p *Parser) {
+		p.ValidMethods = allowed
+		p.StrictDecoding = true
+		p.RejectDuplicateKeys = true
+		p.StrictKeys = true
+		p.MaxTokenSize = 16 << 10
+		p.MaxJSONDepth = defaultMaxJSONDepth
+		p.Validators = []Validator{
+			ExpirationValidator(true),
+			IssuedAtValidator(false),
+			NotBeforeValidator(false),
+		}
+	}
+}
+
+// The NewParser defaults: canonical base64url, any registered method, and
+// time claims checked only when present
+func CompatiblePolicy() ParserOption {
+	return func(p *Parser) {
+		*p = Parser{StrictDecoding: true}
+	}
+}
+
+// The zero Parser's behavior, as in earlier releases: padded and
+// non-canonical base64url is accepted too
+func LegacyPolicy() ParserOption {
+	return func(p *Parser) {
+		*p = Parser{}
+	}
+}
@@ -0,0 +1,69 @@
+package jwt_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestPolicies(t *testing.T) {
+	hmacKeyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	exp := time.Now().Add(time.Hour).Unix()
+	sign := func(method jwt.SigningMethod, claims jwt.MapClaims, key interface{}) string {
+		s, _ := jwt.NewWithClaims(method, claims).SignedString(key)
+		return s
+	}
+	withExp := sign(jwt.SigningMethodHS256, jwt.MapClaims{"exp": exp}, hmacTestKey)
+	withoutExp := sign(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"}, hmacTestKey)
+	padded := withoutExp[:strings.LastIndex(withoutExp, ".")] + "=="
+	padded += "." + mustSign(t, jwt.SigningMethodHS256, padded)
+	duplicate := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9." + jwt.EncodeSegment([]byte(`{"sub":"a","sub":"b"}`))
+	duplicate += "." + mustSign(t, jwt.SigningMethodHS256, duplicate)
+
+	var policyTestData = []struct {
+		name   string
+		policy jwt.ParserOption
+		token  string
+		valid  bool
+	}{
+		{"strict", jwt.StrictPolicy("HS256"), withExp, true},
+		{"strict without exp", jwt.StrictPolicy("HS256"), withoutExp, false},
+		{"strict method not listed", jwt.StrictPolicy("RS256"), withExp, false},
+		{"strict without methods", jwt.StrictPolicy(), withExp, false},
+		{"strict duplicate keys", jwt.StrictPolicy("HS256"), duplicate, false},
+		{"compatible without exp", jwt.CompatiblePolicy(), withoutExp, true},
+		{"compatible padded", jwt.CompatiblePolicy(), padded, false},
+		{"compatible duplicate keys", jwt.CompatiblePolicy(), duplicate, true},
+		{"legacy padded", jwt.LegacyPolicy(), padded, true},
+	}
+
+	for _, data := range policyTestData {
+		_, err := jwt.NewParser(data.policy).Parse(data.token, hmacKeyFunc)
+		if (err == nil) != data.valid {
+			t.Errorf("[%v] Expected valid %v, got %v", data.name, data.valid, err)
+		}
+	}
+
+	// none is never allowed by the strict policy, even if listed
+	none := sign(jwt.SigningMethodNone, jwt.MapClaims{"exp": exp}, jwt.UnsafeAllowNoneSignatureType)
+	if _, err := jwt.NewParser(jwt.StrictPolicy("none")).Parse(none, func(*jwt.Token) (interface{}, error) {
+		return jwt.UnsafeAllowNoneSignatureType, nil
+	}); err == nil {
+		t.Errorf("Expected none to be rejected by the strict policy")
+	}
+
+	// Later options override the policy
+	if p := jwt.NewParser(jwt.StrictPolicy("HS256"), jwt.WithMaxTokenSize(1<<20)); p.MaxTokenSize != 1<<20 {
+		t.Errorf("Expected later option to override policy")
+	}
+}
+
+func mustSign(t *testing.T, method jwt.SigningMethod, signingString string) string {
+	sig, err := method.Sign(signingString, hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
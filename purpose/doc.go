@@ -0,0 +1,18 @@
+// Tokens that may only be used for one thing, such as the links in email
+// verification, password reset and invitation emails.
+//
+// Each token carries a purpose claim, so a token issued for one flow cannot
+// be replayed in another, a short exp, and a random jti.  Issue fills these
+// in; Validate checks them, and with a jwt.ReplayGuard accepts each token
+// only once:
+//
+//	link, _ := purpose.Issue(jwt.SigningMethodHS256, key, purpose.NewPasswordReset(user.ID, user.PasswordVersion))
+//
+//	claims := &purpose.PasswordResetClaims{}
+//	if err := purpose.Validate(link, purpose.ResetPassword, claims, keyFunc, guard); err != nil {
+//		// reject the link
+//	}
+//	if claims.PasswordVersion != user.PasswordVersion {
+//		// the password has changed since the link was sent
+//	}
+package purpose
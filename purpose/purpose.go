@@ -0,0 +1,151 @@
+package purpose
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Well-known purposes
+const (
+	VerifyEmail   = "verify_email"
+	ResetPassword = "reset_password"
+	AcceptInvite  = "accept_invite"
+)
+
+// How long tokens for each purpose last when Issue is given no exp.  Other
+// purposes last DefaultLifetime.
+var Lifetimes = map[string]time.Duration{
+	VerifyEmail:   24 * time.Hour,
+	ResetPassword: 30 * time.Minute,
+	AcceptInvite:  7 * 24 * time.Hour,
+}
+
+const DefaultLifetime = time.Hour
+
+var (
+	ErrMissingPurpose  = errors.New("token has no purpose")
+	ErrPurposeMismatch = errors.New("token was issued for a different purpose")
+)
+
+// The claims of every purpose token.  Embed it to add purpose-specific
+// claims.
+type Claims struct {
+	jwt.StandardClaims
+	Purpose string `json:"purpose"`
+}
+
+func (c *Claims) purposeClaims() *Claims {
+	return c
+}
+
+// Claims types accepted by Issue and Validate: Claims, or a struct embedding
+// it
+type PurposeClaims interface {
+	jwt.Claims
+	purposeClaims() *Claims
+}
+
+// Claims of an email verification link
+type EmailVerificationClaims struct {
+	Claims
+	Email string `json:"email"`
+}
+
+// Claims for verifying that subject owns email
+func NewEmailVerification(subject, email string) *EmailVerificationClaims {
+	c := &EmailVerificationClaims{Email: email}
+	c.Subject, c.Purpose = subject, VerifyEmail
+	return c
+}
+
+// Claims of a password reset link.  PasswordVersion should identify the
+// subject's current password, such as a counter or a hash of the stored
+// password hash, so the link stops working once the password changes.
+type PasswordResetClaims struct {
+	Claims
+	PasswordVersion string `json:"pwv,omitempty"`
+}
+
+// Claims for resetting subject's password
+func NewPasswordReset(subject, passwordVersion string) *PasswordResetClaims {
+	c := &PasswordResetClaims{PasswordVersion: passwordVersion}
+	c.Subject, c.Purpose = subject, ResetPassword
+	return c
+}
+
+// Claims of an invitation
+type InviteClaims struct {
+	Claims
+	Email     string `json:"email"`
+	Role      string `json:"role,omitempty"`
+	InvitedBy string `json:"invited_by,omitempty"`
+}
+
+// Claims inviting email, on behalf of invitedBy
+func NewInvite(email, role, invitedBy string) *InviteClaims {
+	c := &InviteClaims{Email: email, Role: role, InvitedBy: invitedBy}
+	c.Purpose = AcceptInvite
+	return c
+}
+
+// Sign a purpose token.  The purpose claim must be set; a missing jti, iat or
+// exp is filled in, with exp from Lifetimes.
+func Issue(method jwt.SigningMethod, key interface{}, claims PurposeClaims) (string, error) {
+	c := claims.purposeClaims()
+	if c.Purpose == "" {
+		return "", ErrMissingPurpose
+	}
+	now := jwt.TimeFunc()
+	if c.Id == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return "", err
+		}
+		c.Id = jwt.EncodeSegment(id)
+	}
+	if c.IssuedAt == 0 {
+		c.IssuedAt = now.Unix()
+	}
+	if c.ExpiresAt == 0 {
+		lifetime, ok := Lifetimes[c.Purpose]
+		if !ok {
+			lifetime = DefaultLifetime
+		}
+		c.ExpiresAt = now.Add(lifetime).Unix()
+	}
+	return jwt.NewWithClaims(method, claims).SignedString(key)
+}
+
+// Parse and validate tokenString into claims, checking it was issued for
+// purpose and has not expired.  If guard is set, the token's jti is consumed
+// so the token is accepted only once; tokens for a different purpose are
+// rejected before anything is consumed.
+func Validate(tokenString, purpose string, claims PurposeClaims, keyFunc jwt.Keyfunc, guard jwt.ReplayGuard) error {
+	parser := jwt.NewParser(jwt.WithValidators(
+		jwt.ExpirationValidator(true),
+		jwt.IssuedAtValidator(false),
+		jwt.NotBeforeValidator(false),
+		purposeValidator(purpose),
+	))
+	var err error
+	if guard != nil {
+		_, err = parser.ParseOnce(tokenString, claims, keyFunc, guard)
+	} else {
+		_, err = parser.ParseWithClaims(tokenString, claims, keyFunc)
+	}
+	return err
+}
+
+func purposeValidator(purpose string) jwt.Validator {
+	return jwt.Validator{Name: "purpose", Validate: func(ctx context.Context, token *jwt.Token) error {
+		c, ok := token.Claims.(PurposeClaims)
+		if !ok || c.purposeClaims().Purpose != purpose {
+			return &jwt.ValidationError{Inner: ErrPurposeMismatch, Errors: jwt.ValidationErrorClaimsInvalid}
+		}
+		return nil
+	}}
+}
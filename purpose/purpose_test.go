@@ -0,0 +1,75 @@
+package purpose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var testKey = []byte("secret")
+
+func keyFunc(*jwt.Token) (interface{}, error) { return testKey, nil }
+
+func TestIssueAndValidate(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	link, err := Issue(jwt.SigningMethodHS256, testKey, NewPasswordReset("alice", "v3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	guard := jwt.NewMemoryReplayGuard()
+
+	// The wrong purpose is rejected without consuming the token
+	if err := Validate(link, VerifyEmail, &EmailVerificationClaims{}, keyFunc, guard); err == nil {
+		t.Errorf("Expected a purpose mismatch")
+	} else if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorClaimsInvalid == 0 {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	claims := &PasswordResetClaims{}
+	if err := Validate(link, ResetPassword, claims, keyFunc, guard); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if claims.Subject != "alice" || claims.PasswordVersion != "v3" || claims.Id == "" || claims.IssuedAt != now.Unix() || claims.ExpiresAt != now.Add(30*time.Minute).Unix() {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+	if err := Validate(link, ResetPassword, &PasswordResetClaims{}, keyFunc, guard); err == nil {
+		t.Errorf("Expected a replayed token to be rejected")
+	}
+
+	// Without a guard the token may be reused until it expires
+	invite, _ := Issue(jwt.SigningMethodHS256, testKey, NewInvite("bob@example.com", "editor", "alice"))
+	for i := 0; i < 2; i++ {
+		if err := Validate(invite, AcceptInvite, &InviteClaims{}, keyFunc, nil); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	now = now.Add(8 * 24 * time.Hour)
+	if err := Validate(invite, AcceptInvite, &InviteClaims{}, keyFunc, nil); err == nil {
+		t.Errorf("Expected an expired invite to be rejected")
+	}
+}
+
+func TestIssueErrors(t *testing.T) {
+	if _, err := Issue(jwt.SigningMethodHS256, testKey, &Claims{}); err != ErrMissingPurpose {
+		t.Errorf("Expected ErrMissingPurpose, got %v", err)
+	}
+
+	// Purpose tokens must expire
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{Purpose: "custom"}).SignedString(testKey)
+	if err := Validate(tokenString, "custom", &Claims{}, keyFunc, nil); err == nil {
+		t.Errorf("Expected a token without exp to be rejected")
+	}
+
+	c := &Claims{Purpose: "custom"}
+	tokenString, _ = Issue(jwt.SigningMethodHS256, testKey, c)
+	if c.ExpiresAt-c.IssuedAt != int64(DefaultLifetime/time.Second) {
+		t.Errorf("Unexpected lifetime: %v", c.ExpiresAt-c.IssuedAt)
+	}
+	if err := Validate(tokenString, "custom", &Claims{}, keyFunc, nil); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
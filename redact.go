@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"path"
+)
+
+// Replaces masked claim values by default
+const RedactedValue = "[REDACTED]"
+
+// Masks the values of claims whose names match glob patterns, producing a copy
+// of a claims set that is safe to log.  Patterns use path.Match syntax and
+// match either a top-level claim name, such as "email", or the dotted path of a
+// claim nested in an object, such as "address.*".
+type Redactor struct {
+	Patterns []string
+	Mask     interface{} // Replaces masked values.  Defaults to RedactedValue
+}
+
+// Masks the personal data claims registered by OpenID Connect
+var DefaultRedactor = NewRedactor("name", "*_name", "nickname", "preferred_username", "email", "phone_number",
+	"address", "birthdate", "picture", "profile", "website")
+
+// Create a Redactor masking claims that match any of patterns
+func NewRedactor(patterns ...string) *Redactor {
+	return &Redactor{Patterns: patterns}
+}
+
+// Returns a copy of claims with the matching values masked.  The claims
+// themselves are never modified.  A nil Redactor masks nothing.
+func (r *Redactor) Redact(claims Claims) (MapClaims, error) {
+	m, err := claimsToMap(claims)
+	if err != nil {
+		return nil, err
+	}
+	return MapClaims(r.redactObject(m, "")), nil
+}
+
+func (r *Redactor) redactObject(obj map[string]interface{}, prefix string) map[string]interface{} {
+	out := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		name := prefix + k
+		if r.Matches(name) {
+			out[k] = r.mask()
+			continue
+		}
+		switch v := v.(type) {
+		case map[string]interface{}:
+			out[k] = r.redactObject(v, name+".")
+		case MapClaims:
+			out[k] = MapClaims(r.redactObject(v, name+"."))
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Reports whether the claim with the given name or dotted path is masked
+func (r *Redactor) Matches(name string) bool {
+	if r == nil {
+		return false
+	}
+	for _, pattern := range r.Patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) mask() interface{} {
+	if r.Mask != nil {
+		return r.Mask
+	}
+	return RedactedValue
+}
@@ -0,0 +1,82 @@
+package jwt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestRedactor(t *testing.T) {
+	var tests = []struct {
+		name     string
+		redactor *jwt.Redactor
+		claims   jwt.Claims
+		expected jwt.MapClaims
+	}{
+		{
+			"exact and glob",
+			jwt.NewRedactor("email", "*_name"),
+			jwt.MapClaims{"sub": "user", "email": "a@example.com", "given_name": "Ann", "family_name": "Lee"},
+			jwt.MapClaims{"sub": "user", "email": jwt.RedactedValue, "given_name": jwt.RedactedValue, "family_name": jwt.RedactedValue},
+		},
+		{
+			"nested path",
+			jwt.NewRedactor("address.street_*"),
+			jwt.MapClaims{"address": map[string]interface{}{"street_address": "1 Main St", "country": "NZ"}},
+			jwt.MapClaims{"address": map[string]interface{}{"street_address": jwt.RedactedValue, "country": "NZ"}},
+		},
+		{
+			"whole object",
+			jwt.NewRedactor("address"),
+			jwt.MapClaims{"address": map[string]interface{}{"country": "NZ"}},
+			jwt.MapClaims{"address": jwt.RedactedValue},
+		},
+		{
+			"custom mask",
+			&jwt.Redactor{Patterns: []string{"email"}, Mask: "***"},
+			jwt.MapClaims{"email": "a@example.com"},
+			jwt.MapClaims{"email": "***"},
+		},
+		{
+			"struct claims",
+			jwt.NewRedactor("sub"),
+			&jwt.StandardClaims{Subject: "user", Issuer: "issuer"},
+			jwt.MapClaims{"sub": jwt.RedactedValue, "iss": "issuer"},
+		},
+		{
+			"nil redactor",
+			nil,
+			jwt.MapClaims{"email": "a@example.com"},
+			jwt.MapClaims{"email": "a@example.com"},
+		},
+		{
+			"default",
+			jwt.DefaultRedactor,
+			jwt.MapClaims{"sub": "user", "email": "a@example.com", "preferred_username": "ann"},
+			jwt.MapClaims{"sub": "user", "email": jwt.RedactedValue, "preferred_username": jwt.RedactedValue},
+		},
+	}
+
+	for _, data := range tests {
+		redacted, err := data.redactor.Redact(data.claims)
+		if err != nil {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(redacted, data.expected) {
+			t.Errorf("[%v] Expected %v, got %v", data.name, data.expected, redacted)
+		}
+	}
+}
+
+func TestRedactorCopies(t *testing.T) {
+	claims := jwt.MapClaims{"email": "a@example.com", "address": map[string]interface{}{"country": "NZ"}}
+	redacted, _ := jwt.NewRedactor("email", "address.country").Redact(claims)
+	if claims["email"] != "a@example.com" || claims["address"].(map[string]interface{})["country"] != "NZ" {
+		t.Errorf("Redact modified the claims: %v", claims)
+	}
+	if redacted["email"] != jwt.RedactedValue {
+		t.Errorf("Unexpected redacted claims: %v", redacted)
+	}
+}
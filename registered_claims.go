@@ -0,0 +1,94 @@
+package jwt
+
+import (
+	"context"
+)
+
+// The registered claims of RFC 7519 section 4.1, designed to be embedded in
+// application claims types:
+//
+//	type UserClaims struct {
+//		jwt.RegisteredClaims
+//		Email string `json:"email"`
+//	}
+//
+// Unlike StandardClaims, aud may hold several audiences.  When a Parser
+// without Validators parses claims embedding RegisteredClaims, exp, iat and
+// nbf are checked as the claims encode, before the type's own Valid method
+// runs.  So they are still checked if the embedding type defines its own
+// Valid without calling RegisteredClaims.Valid, or shadows a registered claim
+// with a field of the same json name, such as an exp of another type.
+type RegisteredClaims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  ClaimStrings `json:"aud,omitempty"`
+	ExpiresAt int64        `json:"exp,omitempty"`
+	NotBefore int64        `json:"nbf,omitempty"`
+	IssuedAt  int64        `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+// Validates the time based claims exp, iat and nbf, as StandardClaims.Valid
+// does.  Claims that are not set are not checked.
+func (c RegisteredClaims) Valid() error {
+	return StandardClaims{ExpiresAt: c.ExpiresAt, IssuedAt: c.IssuedAt, NotBefore: c.NotBefore}.Valid()
+}
+
+// Reports whether cmp is one of the audiences.  If required is false, this
+// method will return true if no audience is set.
+func (c *RegisteredClaims) VerifyAudience(cmp string, req bool) bool {
+	return c.Audience.Verify(cmp, req)
+}
+
+// Compares the exp claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c *RegisteredClaims) VerifyExpiresAt(cmp int64, req bool) bool {
+	return verifyExp(c.ExpiresAt, cmp, req)
+}
+
+// Compares the iat claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c *RegisteredClaims) VerifyIssuedAt(cmp int64, req bool) bool {
+	return verifyIat(c.IssuedAt, cmp, req)
+}
+
+// Compares the iss claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c *RegisteredClaims) VerifyIssuer(cmp string, req bool) bool {
+	return verifyIss(c.Issuer, cmp, req)
+}
+
+// Compares the nbf claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c *RegisteredClaims) VerifyNotBefore(cmp int64, req bool) bool {
+	return verifyNbf(c.NotBefore, cmp, req)
+}
+
+// Promoted to types embedding RegisteredClaims, even those that define their
+// own Valid, so the parser can find them
+func (c *RegisteredClaims) registeredClaims() *RegisteredClaims {
+	return c
+}
+
+type registeredClaimsHolder interface {
+	registeredClaims() *RegisteredClaims
+}
+
+// Check exp, iat and nbf of claims embedding RegisteredClaims as they encode,
+// so fields shadowing the registered claims are honored
+func validateRegisteredClaims(ctx context.Context, token *Token) error {
+	switch token.Claims.(type) {
+	case *RegisteredClaims:
+		return runValidators(ctx, token, defaultValidators)
+	case registeredClaimsHolder:
+		// Encode the claims once, rather than in each validator
+		m, err := claimsToMap(token.Claims)
+		if err != nil {
+			return err
+		}
+		encoded := *token
+		encoded.Claims = m
+		return runValidators(ctx, &encoded, defaultValidators)
+	}
+	return nil
+}
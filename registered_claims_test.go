@@ -0,0 +1,93 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type embeddingClaims struct {
+	jwt.RegisteredClaims
+	Email string `json:"email"`
+}
+
+// Overrides Valid without calling RegisteredClaims.Valid
+type overridingClaims struct {
+	jwt.RegisteredClaims
+	Email string `json:"email"`
+}
+
+func (c *overridingClaims) Valid() error {
+	if c.Email == "" {
+		return errors.New("email is required")
+	}
+	return nil
+}
+
+// Shadows exp with a field of another type
+type shadowingClaims struct {
+	jwt.RegisteredClaims
+	ExpiresAt float64 `json:"exp"`
+}
+
+type validatingClaims struct {
+	jwt.RegisteredClaims
+}
+
+func (c *validatingClaims) Validate(ctx context.Context) error {
+	if c.Subject != "user" {
+		return errors.New("unexpected subject")
+	}
+	return nil
+}
+
+func TestRegisteredClaims(t *testing.T) {
+	now := time.Now().Unix()
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	valid := signHS256(jwt.MapClaims{"sub": "user", "aud": []string{"a", "b"}, "email": "u@example.com", "exp": now + 60})
+	expired := signHS256(jwt.MapClaims{"sub": "user", "email": "u@example.com", "exp": now - 60})
+	noEmail := signHS256(jwt.MapClaims{"sub": "user", "exp": now + 60})
+
+	var tests = []struct {
+		name   string
+		token  string
+		claims jwt.Claims
+		errors uint32
+	}{
+		{"registered", valid, &jwt.RegisteredClaims{}, 0},
+		{"registered expired", expired, &jwt.RegisteredClaims{}, jwt.ValidationErrorExpired},
+		{"embedded", valid, &embeddingClaims{}, 0},
+		{"embedded expired", expired, &embeddingClaims{}, jwt.ValidationErrorExpired},
+		{"overriding", valid, &overridingClaims{}, 0},
+		{"overriding expired", expired, &overridingClaims{}, jwt.ValidationErrorExpired},
+		{"overriding own check", noEmail, &overridingClaims{}, jwt.ValidationErrorClaimsInvalid},
+		{"shadowing", valid, &shadowingClaims{}, 0},
+		{"shadowing expired", expired, &shadowingClaims{}, jwt.ValidationErrorExpired},
+		{"validating", valid, &validatingClaims{}, 0},
+		{"validating expired", expired, &validatingClaims{}, jwt.ValidationErrorExpired},
+	}
+
+	for _, test := range tests {
+		_, err := jwt.ParseWithClaims(test.token, test.claims, keyFunc)
+		if test.errors == 0 {
+			if err != nil {
+				t.Errorf("[%v] Unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&test.errors == 0 {
+			t.Errorf("[%v] Expected error flags %v, got %v", test.name, test.errors, err)
+		}
+	}
+
+	claims := &embeddingClaims{}
+	if _, err := jwt.ParseWithClaims(valid, claims, keyFunc); err != nil {
+		t.Fatal(err)
+	}
+	if !claims.VerifyAudience("b", true) || claims.VerifyAudience("c", false) || claims.Email != "u@example.com" {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+}
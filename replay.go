@@ -0,0 +1,93 @@
+package jwt
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrTokenReplayed = errors.New("token has already been used")
+	ErrMissingJTI    = errors.New("one-time token has no jti claim")
+	ErrMissingExp    = errors.New("one-time token has no exp claim")
+)
+
+// Records consumed token IDs so each token is accepted only once.  Consume
+// returns ErrTokenReplayed if id was already consumed; it may forget ids
+// after expiresAt, when the token would be rejected as expired anyway.
+// Implementations backed by a shared store make one-time tokens safe across
+// instances.
+type ReplayGuard interface {
+	Consume(id string, expiresAt time.Time) error
+}
+
+// Create an in-memory ReplayGuard
+func NewMemoryReplayGuard() *MemoryReplayGuard {
+	return &MemoryReplayGuard{seen: make(map[string]time.Time)}
+}
+
+// A ReplayGuard held in process memory, remembering each id until it
+// expires.  Safe for concurrent use.
+type MemoryReplayGuard struct {
+	mu       sync.Mutex
+	seen     map[string]time.Time
+	consumed int // Since expired ids were last pruned
+}
+
+func (g *MemoryReplayGuard) Consume(id string, expiresAt time.Time) error {
+	now := TimeFunc()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if exp, ok := g.seen[id]; ok && now.Before(exp) {
+		return ErrTokenReplayed
+	}
+	g.seen[id] = expiresAt
+	// Prune once the map has had as many ids added as it held at the last prune
+	if g.consumed++; g.consumed >= len(g.seen)/2+16 {
+		for k, exp := range g.seen {
+			if !now.Before(exp) {
+				delete(g.seen, k)
+			}
+		}
+		g.consumed = 0
+	}
+	return nil
+}
+
+// The number of ids remembered
+func (g *MemoryReplayGuard) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.seen)
+}
+
+// Parse and validate a one-time token, such as an email verification or
+// password reset link, then consume its jti with guard so it is rejected if
+// presented again.  The token must have jti and exp claims.  The jti is only
+// consumed once the token is otherwise valid, so forged or expired tokens
+// cannot use up a real token's ID.
+func (p *Parser) ParseOnce(tokenString string, claims Claims, keyFunc Keyfunc, guard ReplayGuard) (*Token, error) {
+	token, err := p.ParseWithClaims(tokenString, claims, keyFunc)
+	if err != nil {
+		return token, err
+	}
+	token.Valid = false
+
+	m, err := claimsToMap(token.Claims)
+	if err != nil {
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorClaimsInvalid}
+	}
+	id, _ := m["jti"].(string)
+	if id == "" {
+		return token, &ValidationError{Inner: ErrMissingJTI, Errors: ValidationErrorId}
+	}
+	exp, ok := token.ExpiresAt()
+	if !ok {
+		return token, &ValidationError{Inner: ErrMissingExp, Errors: ValidationErrorExpired}
+	}
+	if err := guard.Consume(id, exp); err != nil {
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorId}
+	}
+	token.Valid = true
+	return token, nil
+}
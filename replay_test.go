@@ -0,0 +1,84 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestParseOnce(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	sign := func(claims jwt.MapClaims) string {
+		s, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(hmacTestKey)
+		return s
+	}
+	exp := float64(now.Unix() + 60)
+	guard := jwt.NewMemoryReplayGuard()
+	parser := jwt.NewParser()
+
+	var tests = []struct {
+		name   string
+		token  string
+		errors uint32
+	}{
+		{"first use", sign(jwt.MapClaims{"jti": "a", "exp": exp}), 0},
+		{"replayed", sign(jwt.MapClaims{"jti": "a", "exp": exp, "extra": true}), jwt.ValidationErrorId},
+		{"other id", sign(jwt.MapClaims{"jti": "b", "exp": exp}), 0},
+		{"no jti", sign(jwt.MapClaims{"exp": exp}), jwt.ValidationErrorId},
+		{"no exp", sign(jwt.MapClaims{"jti": "c"}), jwt.ValidationErrorExpired},
+		{"expired", sign(jwt.MapClaims{"jti": "d", "exp": float64(now.Unix() - 1)}), jwt.ValidationErrorExpired},
+		{"malformed", sign(jwt.MapClaims{"jti": "e", "exp": exp})[:10], jwt.ValidationErrorMalformed},
+	}
+
+	for _, data := range tests {
+		token, err := parser.ParseOnce(data.token, jwt.MapClaims{}, keyFunc, guard)
+		if data.errors == 0 {
+			if err != nil || !token.Valid {
+				t.Errorf("[%v] Unexpected error: %v", data.name, err)
+			}
+			continue
+		}
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&data.errors == 0 {
+			t.Errorf("[%v] Expected error flags %v, got %v", data.name, data.errors, err)
+		}
+		if token != nil && token.Valid {
+			t.Errorf("[%v] Token should not be valid", data.name)
+		}
+	}
+
+	// Rejected tokens don't consume their id
+	if _, err := parser.ParseOnce(sign(jwt.MapClaims{"jti": "d", "exp": exp}), jwt.MapClaims{}, keyFunc, guard); err != nil {
+		t.Errorf("Unexpected error reusing the id of a rejected token: %v", err)
+	}
+}
+
+func TestMemoryReplayGuard(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	guard := jwt.NewMemoryReplayGuard()
+	if err := guard.Consume("a", now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := guard.Consume("a", now.Add(time.Minute)); err != jwt.ErrTokenReplayed {
+		t.Errorf("Expected ErrTokenReplayed, got %v", err)
+	}
+
+	// Expired ids may be reused, and are eventually forgotten
+	now = now.Add(2 * time.Minute)
+	if err := guard.Consume("a", now.Add(time.Minute)); err != nil {
+		t.Errorf("Unexpected error consuming an expired id: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		guard.Consume(string(rune('A'+i)), now)
+	}
+	if n := guard.Len(); n > 60 {
+		t.Errorf("Expected expired ids to be pruned, %v remain", n)
+	}
+}
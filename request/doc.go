@@ -4,4 +4,15 @@
 // The main function is ParseFromRequest and it's WithClaims variant.
 // See examples for how to use the various Extractor implementations
 // or roll your own.
+//
+// AuthorizationHeaderExtractor strips a "Bearer " prefix if there is one.  To
+// accept other schemes, such as "JWT" or "Token", and reject the rest, use
+// SchemeExtractor.
+//
+// ParseFromRequestWithLocation also reports where the token was found, and
+// WithAllowedLocations rejects tokens found elsewhere, such as in the query
+// string.
+//
+// For WebSocket upgrades, where browsers cannot set an Authorization header,
+// use WebSocketExtractor.
 package request
@@ -61,6 +61,22 @@ var extractorTestData = []struct {
 		token:     extractorTestTokenA,
 		err:       nil,
 	},
+	{
+		name:      "scheme",
+		extractor: NewSchemeExtractor("Bearer", "JWT"),
+		headers:   map[string]string{"Authorization": "jwt \t " + extractorTestTokenA},
+		query:     nil,
+		token:     extractorTestTokenA,
+		err:       nil,
+	},
+	{
+		name:      "unsupported scheme",
+		extractor: NewSchemeExtractor("Bearer", "JWT"),
+		headers:   map[string]string{"Authorization": "Basic " + extractorTestTokenA},
+		query:     nil,
+		token:     "",
+		err:       ErrUnsupportedScheme,
+	},
 }
 
 func TestExtractor(t *testing.T) {
@@ -89,3 +105,32 @@ func makeExampleRequest(method, path string, headers map[string]string, urlArgs
 	}
 	return r
 }
+
+func TestSchemeExtractor(t *testing.T) {
+	var tests = []struct {
+		value  string
+		token  string
+		scheme string
+		err    error
+	}{
+		{"Bearer A", "A", "Bearer", nil},
+		{"BEARER   A ", "A", "Bearer", nil},
+		{"Token A", "A", "Token", nil},
+		{"BearerA", "", "", ErrUnsupportedScheme},
+		{"Bearer", "", "", ErrUnsupportedScheme},
+		{"A", "", "", ErrUnsupportedScheme},
+		{"  ", "", "", ErrNoTokenInRequest},
+	}
+	e := NewSchemeExtractor("Bearer", "Token")
+	for _, test := range tests {
+		r := makeExampleRequest("GET", "/", map[string]string{"Authorization": test.value}, nil)
+		token, scheme, err := e.ExtractTokenAndScheme(r)
+		if token != test.token || scheme != test.scheme || err != test.err {
+			t.Errorf("[%q] Expected %q %q %v, got %q %q %v", test.value, test.token, test.scheme, test.err, token, scheme, err)
+		}
+	}
+
+	if token, err := (&SchemeExtractor{}).ExtractToken(makeExampleRequest("GET", "/", map[string]string{"Authorization": "JWT A"}, nil)); err != ErrUnsupportedScheme {
+		t.Errorf("Expected only Bearer by default, got %q %v", token, err)
+	}
+}
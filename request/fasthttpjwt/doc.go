@@ -0,0 +1,8 @@
+// Token extraction and parsing for fasthttp servers, using the extractors
+// from the request package:
+//
+//	token, err := fasthttpjwt.ParseFromFastHTTPRequest(ctx, request.OAuth2Extractor, keyFunc)
+//
+// This package depends on github.com/valyala/fasthttp and is only built with
+// the fasthttp build tag.
+package fasthttpjwt
@@ -0,0 +1,33 @@
+//go:build fasthttp
+
+package fasthttpjwt
+
+import (
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/request"
+	"github.com/valyala/fasthttp"
+)
+
+// Extract and parse a JWT token from a fasthttp request.  This behaves the
+// same as request.ParseFromRequest.
+func ParseFromFastHTTPRequest(ctx *fasthttp.RequestCtx, extractor request.ValuesExtractor, keyFunc jwt.Keyfunc, options ...request.ParseFromRequestOption) (*jwt.Token, error) {
+	return request.ParseFromValues(Values(ctx), extractor, keyFunc, options...)
+}
+
+// Returns request.Values backed by ctx.  Arguments are looked up in the query
+// string, then the form body.
+func Values(ctx *fasthttp.RequestCtx) request.Values {
+	return values{ctx}
+}
+
+type values struct {
+	ctx *fasthttp.RequestCtx
+}
+
+func (v values) Header(name string) string {
+	return string(v.ctx.Request.Header.Peek(name))
+}
+
+func (v values) Argument(name string) string {
+	return string(v.ctx.FormValue(name))
+}
@@ -0,0 +1,29 @@
+//go:build fasthttp
+
+package fasthttpjwt
+
+import (
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/request"
+	"github.com/valyala/fasthttp"
+)
+
+func TestParseFromFastHTTPRequest(t *testing.T) {
+	key := []byte("secret")
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(key)
+
+	var header fasthttp.RequestCtx
+	header.Request.Header.Set("Authorization", "Bearer "+tokenString)
+	var query fasthttp.RequestCtx
+	query.Request.SetRequestURI("/orders?access_token=" + tokenString)
+
+	for name, ctx := range map[string]*fasthttp.RequestCtx{"header": &header, "query": &query} {
+		token, err := ParseFromFastHTTPRequest(ctx, request.OAuth2Extractor, keyFunc)
+		if err != nil || !token.Valid {
+			t.Errorf("[%v] Unexpected result: %v %v", name, token, err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Errors
+var (
+	ErrLocationNotAllowed = errors.New("token was found in a location that is not allowed")
+)
+
+// Where in a request a token was found
+type Location string
+
+const (
+	LocationUnknown           Location = ""                   // The extractor does not report locations
+	LocationHeader            Location = "header"             // A request header, such as Authorization
+	LocationCookie            Location = "cookie"             // A cookie
+	LocationQuery             Location = "query"              // The URL query string
+	LocationForm              Location = "form"               // A POSTed form
+	LocationWebSocketProtocol Location = "websocket_protocol" // A Sec-WebSocket-Protocol entry
+)
+
+// A token extracted from a request, and where it was found
+type Extraction struct {
+	Token    string
+	Location Location
+	Name     string // The name of the header, cookie or argument
+	Scheme   string // The Authorization scheme, if a SchemeExtractor found the token
+}
+
+// Implemented by extractors that can report where they found a token.  All
+// the extractors in this package do, as long as the extractors they wrap do
+// too.
+type LocatingExtractor interface {
+	Extractor
+	ExtractTokenLocation(*http.Request) (*Extraction, error)
+}
+
+// Extract a token from req and report where it was found.  The location is
+// LocationUnknown if extractor is not a LocatingExtractor.
+func Extract(req *http.Request, extractor Extractor) (*Extraction, error) {
+	if e, ok := extractor.(LocatingExtractor); ok {
+		return e.ExtractTokenLocation(req)
+	}
+	tok, err := extractor.ExtractToken(req)
+	if err != nil {
+		return nil, err
+	}
+	return &Extraction{Token: tok}, nil
+}
+
+func (e HeaderExtractor) ExtractTokenLocation(req *http.Request) (*Extraction, error) {
+	for _, header := range e {
+		if ah := req.Header.Get(header); ah != "" {
+			return &Extraction{Token: ah, Location: LocationHeader, Name: header}, nil
+		}
+	}
+	return nil, ErrNoTokenInRequest
+}
+
+func (e ArgumentExtractor) ExtractTokenLocation(req *http.Request) (*Extraction, error) {
+	// Make sure form is parsed
+	req.ParseMultipartForm(10e6)
+
+	// Form values take precedence over the query string, as in req.Form
+	for _, arg := range e {
+		if ah := req.PostForm.Get(arg); ah != "" {
+			return &Extraction{Token: ah, Location: LocationForm, Name: arg}, nil
+		}
+		if ah := req.Form.Get(arg); ah != "" {
+			return &Extraction{Token: ah, Location: LocationQuery, Name: arg}, nil
+		}
+	}
+	return nil, ErrNoTokenInRequest
+}
+
+func (e MultiExtractor) ExtractTokenLocation(req *http.Request) (*Extraction, error) {
+	for _, extractor := range e {
+		if x, err := Extract(req, extractor); err == nil && x.Token != "" {
+			return x, nil
+		} else if err != nil && err != ErrNoTokenInRequest {
+			return nil, err
+		}
+	}
+	return nil, ErrNoTokenInRequest
+}
+
+func (e *PostExtractionFilter) ExtractTokenLocation(req *http.Request) (*Extraction, error) {
+	x, err := Extract(req, e.Extractor)
+	if err != nil {
+		return nil, err
+	}
+	if x.Token == "" {
+		return nil, ErrNoTokenInRequest
+	}
+	if x.Token, err = e.Filter(x.Token); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (e *SchemeExtractor) ExtractTokenLocation(req *http.Request) (*Extraction, error) {
+	tok, scheme, err := e.ExtractTokenAndScheme(req)
+	if err != nil {
+		return nil, err
+	}
+	return &Extraction{Token: tok, Location: LocationHeader, Name: e.header(), Scheme: scheme}, nil
+}
+
+// Extractor for finding a token in a cookie.  Looks at each named cookie in
+// order until there's a match.
+type CookieExtractor []string
+
+func (e CookieExtractor) ExtractToken(req *http.Request) (string, error) {
+	x, err := e.ExtractTokenLocation(req)
+	if err != nil {
+		return "", err
+	}
+	return x.Token, nil
+}
+
+func (e CookieExtractor) ExtractTokenLocation(req *http.Request) (*Extraction, error) {
+	for _, name := range e {
+		if c, err := req.Cookie(name); err == nil && c.Value != "" {
+			return &Extraction{Token: c.Value, Location: LocationCookie, Name: name}, nil
+		}
+	}
+	return nil, ErrNoTokenInRequest
+}
+
+// Reject tokens found anywhere but locations, such as LocationHeader in
+// production, while a handler for signed download links also allows
+// LocationQuery.  Tokens from extractors that do not report their location
+// are rejected.
+func WithAllowedLocations(locations ...Location) ParseFromRequestOption {
+	return func(p *fromRequestParser) {
+		p.checkLocation = func(x *Extraction) error {
+			for _, l := range locations {
+				if x.Location == l && l != LocationUnknown {
+					return nil
+				}
+			}
+			return ErrLocationNotAllowed
+		}
+	}
+}
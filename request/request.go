@@ -12,8 +12,16 @@ import (
 //
 // You can provide options to modify parsing behavior
 func ParseFromRequest(req *http.Request, extractor Extractor, keyFunc jwt.Keyfunc, options ...ParseFromRequestOption) (token *jwt.Token, err error) {
+	token, _, err = ParseFromRequestWithLocation(req, extractor, keyFunc, options...)
+	return token, err
+}
+
+// ParseFromRequest, also reporting where in the request the token was found,
+// as Extract does.  The Extraction is returned whenever a token was found,
+// even if it fails to parse.
+func ParseFromRequestWithLocation(req *http.Request, extractor Extractor, keyFunc jwt.Keyfunc, options ...ParseFromRequestOption) (*jwt.Token, *Extraction, error) {
 	// Create basic parser struct
-	p := &fromRequestParser{req, extractor, nil, nil}
+	p := &fromRequestParser{req: req, extractor: extractor}
 
 	// Handle options
 	for _, option := range options {
@@ -25,16 +33,49 @@ func ParseFromRequest(req *http.Request, extractor Extractor, keyFunc jwt.Keyfun
 		p.claims = jwt.MapClaims{}
 	}
 	if p.parser == nil {
-		p.parser = &jwt.Parser{}
+		p.parser = jwt.NewParser()
 	}
 
 	// perform extract
-	tokenString, err := p.extractor.ExtractToken(req)
+	x, err := Extract(req, p.extractor)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if p.checkLocation != nil {
+		if err := p.checkLocation(x); err != nil {
+			return nil, x, err
+		}
 	}
 
 	// perform parse
+	token, err := p.parser.ParseWithClaims(x.Token, p.claims, keyFunc)
+	return token, x, err
+}
+
+// Extract and parse a JWT token from request values.  This behaves the same as
+// ParseFromRequest, for HTTP stacks that don't use *http.Request.
+func ParseFromValues(values Values, extractor ValuesExtractor, keyFunc jwt.Keyfunc, options ...ParseFromRequestOption) (token *jwt.Token, err error) {
+	p := &fromRequestParser{}
+	for _, option := range options {
+		option(p)
+	}
+	if p.claims == nil {
+		p.claims = jwt.MapClaims{}
+	}
+	if p.parser == nil {
+		p.parser = jwt.NewParser()
+	}
+
+	tokenString, err := extractor.ExtractTokenFromValues(values)
+	if err != nil {
+		return nil, err
+	}
+	// Values extractors do not report locations
+	if p.checkLocation != nil {
+		if err := p.checkLocation(&Extraction{Token: tokenString}); err != nil {
+			return nil, err
+		}
+	}
 	return p.parser.ParseWithClaims(tokenString, p.claims, keyFunc)
 }
 
@@ -49,6 +90,8 @@ type fromRequestParser struct {
 	extractor Extractor
 	claims    jwt.Claims
 	parser    *jwt.Parser
+
+	checkLocation func(*Extraction) error // Set by WithAllowedLocations
 }
 
 type ParseFromRequestOption func(*fromRequestParser)
@@ -60,7 +103,7 @@ func WithClaims(claims jwt.Claims) ParseFromRequestOption {
 	}
 }
 
-// Parse using a custom parser
+// Parse using a custom parser.  Defaults to jwt.NewParser().
 func WithParser(parser *jwt.Parser) ParseFromRequestOption {
 	return func(p *fromRequestParser) {
 		p.parser = parser
@@ -101,3 +101,61 @@ func TestParseRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFromRequestWithLocation(t *testing.T) {
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyfunc := func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil }
+	extractor := MultiExtractor{
+		NewSchemeExtractor("Bearer", "JWT"),
+		CookieExtractor{"token"},
+		ArgumentExtractor{"access_token"},
+	}
+
+	var tests = []struct {
+		name     string
+		request  func() *http.Request
+		location Location
+		argName  string
+		scheme   string
+	}{
+		{"header", func() *http.Request {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "JWT "+tokenString)
+			return r
+		}, LocationHeader, "Authorization", "JWT"},
+		{"cookie", func() *http.Request {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.AddCookie(&http.Cookie{Name: "token", Value: tokenString})
+			return r
+		}, LocationCookie, "token", ""},
+		{"query", func() *http.Request {
+			r, _ := http.NewRequest("GET", "/?access_token="+tokenString, nil)
+			return r
+		}, LocationQuery, "access_token", ""},
+		{"form", func() *http.Request {
+			r, _ := http.NewRequest("POST", "/?access_token=other", strings.NewReader("access_token="+tokenString))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return r
+		}, LocationForm, "access_token", ""},
+	}
+	for _, test := range tests {
+		token, x, err := ParseFromRequestWithLocation(test.request(), extractor, keyfunc)
+		if err != nil || !token.Valid {
+			t.Errorf("[%v] Unexpected error: %v", test.name, err)
+			continue
+		}
+		if x.Location != test.location || x.Name != test.argName || x.Scheme != test.scheme || x.Token != tokenString {
+			t.Errorf("[%v] Unexpected extraction: %+v", test.name, x)
+		}
+
+		_, err = ParseFromRequest(test.request(), extractor, keyfunc, WithAllowedLocations(LocationHeader, LocationCookie))
+		if allowed := test.location == LocationHeader || test.location == LocationCookie; allowed != (err == nil) {
+			t.Errorf("[%v] Unexpected error with allowed locations: %v", test.name, err)
+		} else if !allowed && err != ErrLocationNotAllowed {
+			t.Errorf("[%v] Expected ErrLocationNotAllowed, got %v", test.name, err)
+		}
+	}
+}
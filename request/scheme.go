@@ -0,0 +1,84 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Errors
+var (
+	ErrUnsupportedScheme = errors.New("authorization scheme is not supported")
+)
+
+// Extractor for tokens in an Authorization header sent with one of a set of
+// schemes, such as "Bearer" or "JWT".  Schemes are matched case-insensitively,
+// as RFC 7235 requires, and any run of spaces or tabs may separate the scheme
+// from the token.
+//
+// A header with any other scheme, or with no scheme, fails with
+// ErrUnsupportedScheme rather than being passed on as a token.
+type SchemeExtractor struct {
+	Schemes []string // Accepted schemes.  Defaults to "Bearer"
+	Header  string   // Defaults to "Authorization"
+}
+
+// Create a SchemeExtractor accepting the given Authorization header schemes,
+// such as NewSchemeExtractor("Bearer", "JWT")
+func NewSchemeExtractor(schemes ...string) *SchemeExtractor {
+	return &SchemeExtractor{Schemes: schemes}
+}
+
+func (e *SchemeExtractor) ExtractToken(req *http.Request) (string, error) {
+	tok, _, err := e.ExtractTokenAndScheme(req)
+	return tok, err
+}
+
+// Like ExtractToken, also returning the scheme the token was sent with, as
+// listed in Schemes
+func (e *SchemeExtractor) ExtractTokenAndScheme(req *http.Request) (token, scheme string, err error) {
+	return e.match(req.Header.Get(e.header()))
+}
+
+func (e *SchemeExtractor) ExtractTokenFromValues(v Values) (string, error) {
+	tok, _, err := e.match(v.Header(e.header()))
+	return tok, err
+}
+
+func (e *SchemeExtractor) match(value string) (token, scheme string, err error) {
+	if strings.TrimSpace(value) == "" {
+		return "", "", ErrNoTokenInRequest
+	}
+	schemes := e.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"Bearer"}
+	}
+	for _, scheme := range schemes {
+		if token, ok := MatchScheme(value, scheme); ok {
+			return token, scheme, nil
+		}
+	}
+	return "", "", ErrUnsupportedScheme
+}
+
+func (e *SchemeExtractor) header() string {
+	if e.Header == "" {
+		return "Authorization"
+	}
+	return e.Header
+}
+
+// Returns the credentials of an Authorization header value if it uses
+// scheme, which is matched case-insensitively.  Leading and trailing
+// whitespace, and any spaces or tabs between the scheme and the credentials,
+// are ignored.
+func MatchScheme(value, scheme string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) <= len(scheme) || !strings.EqualFold(value[:len(scheme)], scheme) {
+		return "", false
+	}
+	if c := value[len(scheme)]; c != ' ' && c != '\t' {
+		return "", false
+	}
+	return strings.TrimLeft(value[len(scheme):], " \t"), true
+}
@@ -0,0 +1,70 @@
+package request
+
+import (
+	"errors"
+)
+
+// Errors
+var (
+	ErrValuesUnsupported = errors.New("extractor does not support request values")
+)
+
+// Header and argument lookup over a request type other than *http.Request,
+// such as a fasthttp.RequestCtx, so the standard extractors can be shared
+// across HTTP stacks
+type Values interface {
+	Header(name string) string
+	Argument(name string) string // A query string or form argument
+}
+
+// Implemented by extractors that can work with Values.  HeaderExtractor,
+// ArgumentExtractor, MultiExtractor and PostExtractionFilter all do, as long
+// as the extractors they wrap do too, so AuthorizationHeaderExtractor and
+// OAuth2Extractor can be used directly.
+type ValuesExtractor interface {
+	ExtractTokenFromValues(Values) (string, error)
+}
+
+func (e HeaderExtractor) ExtractTokenFromValues(v Values) (string, error) {
+	for _, header := range e {
+		if ah := v.Header(header); ah != "" {
+			return ah, nil
+		}
+	}
+	return "", ErrNoTokenInRequest
+}
+
+func (e ArgumentExtractor) ExtractTokenFromValues(v Values) (string, error) {
+	for _, arg := range e {
+		if ah := v.Argument(arg); ah != "" {
+			return ah, nil
+		}
+	}
+	return "", ErrNoTokenInRequest
+}
+
+func (e MultiExtractor) ExtractTokenFromValues(v Values) (string, error) {
+	for _, extractor := range e {
+		if tok, err := extractFromValues(extractor, v); tok != "" {
+			return tok, nil
+		} else if err != ErrNoTokenInRequest {
+			return "", err
+		}
+	}
+	return "", ErrNoTokenInRequest
+}
+
+func (e *PostExtractionFilter) ExtractTokenFromValues(v Values) (string, error) {
+	if tok, err := extractFromValues(e.Extractor, v); tok != "" {
+		return e.Filter(tok)
+	} else {
+		return "", err
+	}
+}
+
+func extractFromValues(extractor Extractor, v Values) (string, error) {
+	if e, ok := extractor.(ValuesExtractor); ok {
+		return e.ExtractTokenFromValues(v)
+	}
+	return "", ErrValuesUnsupported
+}
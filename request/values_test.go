@@ -0,0 +1,68 @@
+package request
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type testValues struct {
+	headers map[string]string
+	args    map[string]string
+}
+
+func (v testValues) Header(name string) string   { return v.headers[name] }
+func (v testValues) Argument(name string) string { return v.args[name] }
+
+func TestValuesExtractor(t *testing.T) {
+	var tests = []struct {
+		name      string
+		extractor ValuesExtractor
+		values    testValues
+		token     string
+		err       error
+	}{
+		{"header", HeaderExtractor{"Foo"}, testValues{headers: map[string]string{"Foo": "A"}}, "A", nil},
+		{"argument", ArgumentExtractor{"token"}, testValues{args: map[string]string{"token": "A"}}, "A", nil},
+		{"bearer", AuthorizationHeaderExtractor, testValues{headers: map[string]string{"Authorization": "Bearer A"}}, "A", nil},
+		{"oauth2 argument", OAuth2Extractor, testValues{args: map[string]string{"access_token": "A"}}, "A", nil},
+		{"missing", OAuth2Extractor, testValues{}, "", ErrNoTokenInRequest},
+		{"unsupported", MultiExtractor{&WebSocketExtractor{}}, testValues{}, "", ErrValuesUnsupported},
+	}
+
+	for _, data := range tests {
+		token, err := data.extractor.ExtractTokenFromValues(data.values)
+		if token != data.token || err != data.err {
+			t.Errorf("[%v] Expected %q, %v, got %q, %v", data.name, data.token, data.err, token, err)
+		}
+	}
+}
+
+func TestParseFromValues(t *testing.T) {
+	key := []byte("secret")
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(key)
+	values := testValues{headers: map[string]string{"Authorization": "Bearer " + tokenString}}
+
+	token, err := ParseFromValues(values, OAuth2Extractor, func(*jwt.Token) (interface{}, error) { return key, nil })
+	if err != nil || !token.Valid || token.Claims.(jwt.MapClaims)["sub"] != "user" {
+		t.Errorf("Unexpected result: %v %v", token, err)
+	}
+}
+
+func TestParseDefaultParser(t *testing.T) {
+	key := []byte("secret")
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(key)
+
+	// The default parser is NewParser's, which rejects padded segments
+	values := testValues{headers: map[string]string{"Authorization": "Bearer " + tokenString + "="}}
+	if _, err := ParseFromValues(values, OAuth2Extractor, keyFunc); err == nil {
+		t.Errorf("Expected ParseFromValues to reject the padded token")
+	}
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+tokenString+"=")
+	if _, err := ParseFromRequest(r, OAuth2Extractor, keyFunc); err == nil {
+		t.Errorf("Expected ParseFromRequest to reject the padded token")
+	}
+}
@@ -0,0 +1,113 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Errors
+var (
+	ErrNotWebSocketHandshake = errors.New("request is not a WebSocket handshake")
+	ErrQueryTokenNotAllowed  = errors.New("token in query string is not allowed")
+)
+
+// Default marker for a token carried in Sec-WebSocket-Protocol.  Browsers
+// cannot set headers on WebSocket connections, so clients offer the token as
+// an extra subprotocol, e.g. new WebSocket(url, ["chat", "access_token." + jwt]).
+const WebSocketProtocolPrefix = "access_token."
+
+// Extractor for tokens presented during a WebSocket upgrade.  Looks in the
+// Authorization header, then for a Sec-WebSocket-Protocol entry starting
+// with ProtocolPrefix.
+//
+// Tokens in the query string end up in access logs and browser history, so
+// they are only read if QueryParameter is set.  Otherwise a request with an
+// access_token query parameter fails with ErrQueryTokenNotAllowed, rather
+// than falling back to it silently.
+type WebSocketExtractor struct {
+	ProtocolPrefix string // Defaults to WebSocketProtocolPrefix
+	QueryParameter string // Query parameter to read the token from, if any
+}
+
+func (e *WebSocketExtractor) ExtractToken(req *http.Request) (string, error) {
+	x, err := e.ExtractTokenLocation(req)
+	if err != nil {
+		return "", err
+	}
+	return x.Token, nil
+}
+
+func (e *WebSocketExtractor) ExtractTokenLocation(req *http.Request) (*Extraction, error) {
+	if !IsWebSocketHandshake(req) {
+		return nil, ErrNotWebSocketHandshake
+	}
+	if x, _ := Extract(req, AuthorizationHeaderExtractor); x != nil && x.Token != "" {
+		return x, nil
+	}
+	prefix := e.prefix()
+	for _, protocol := range webSocketProtocols(req) {
+		if strings.HasPrefix(protocol, prefix) && len(protocol) > len(prefix) {
+			return &Extraction{Token: protocol[len(prefix):], Location: LocationWebSocketProtocol, Name: "Sec-WebSocket-Protocol"}, nil
+		}
+	}
+	query := req.URL.Query()
+	if e.QueryParameter != "" {
+		if tok := query.Get(e.QueryParameter); tok != "" {
+			return &Extraction{Token: tok, Location: LocationQuery, Name: e.QueryParameter}, nil
+		}
+	} else if query.Get("access_token") != "" {
+		return nil, ErrQueryTokenNotAllowed
+	}
+	return nil, ErrNoTokenInRequest
+}
+
+// The subprotocols offered by the client, without the one carrying the token.
+// The server must select one of these in its handshake response, and must not
+// echo the token back.
+func (e *WebSocketExtractor) Protocols(req *http.Request) []string {
+	prefix := e.prefix()
+	var protocols []string
+	for _, protocol := range webSocketProtocols(req) {
+		if !strings.HasPrefix(protocol, prefix) {
+			protocols = append(protocols, protocol)
+		}
+	}
+	return protocols
+}
+
+func (e *WebSocketExtractor) prefix() string {
+	if e.ProtocolPrefix != "" {
+		return e.ProtocolPrefix
+	}
+	return WebSocketProtocolPrefix
+}
+
+// Reports whether req asks to upgrade to the WebSocket protocol
+func IsWebSocketHandshake(req *http.Request) bool {
+	return req.Method == "GET" && headerContainsToken(req.Header, "Connection", "upgrade") &&
+		headerContainsToken(req.Header, "Upgrade", "websocket")
+}
+
+func webSocketProtocols(req *http.Request) []string {
+	var protocols []string
+	for _, v := range req.Header[http.CanonicalHeaderKey("Sec-WebSocket-Protocol")] {
+		for _, protocol := range strings.Split(v, ",") {
+			if protocol = strings.TrimSpace(protocol); protocol != "" {
+				protocols = append(protocols, protocol)
+			}
+		}
+	}
+	return protocols
+}
+
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, v := range h[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
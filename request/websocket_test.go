@@ -0,0 +1,45 @@
+package request
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWebSocketExtractor(t *testing.T) {
+	var tests = []struct {
+		name      string
+		extractor *WebSocketExtractor
+		url       string
+		headers   map[string]string
+		token     string
+		err       error
+		protocols []string
+	}{
+		{"protocol", &WebSocketExtractor{}, "/ws", map[string]string{"Sec-WebSocket-Protocol": "chat, access_token.A"}, "A", nil, []string{"chat"}},
+		{"custom prefix", &WebSocketExtractor{ProtocolPrefix: "jwt."}, "/ws", map[string]string{"Sec-WebSocket-Protocol": "jwt.A"}, "A", nil, nil},
+		{"authorization header", &WebSocketExtractor{}, "/ws", map[string]string{"Authorization": "Bearer A"}, "A", nil, nil},
+		{"query forbidden", &WebSocketExtractor{}, "/ws?access_token=A", nil, "", ErrQueryTokenNotAllowed, nil},
+		{"query enabled", &WebSocketExtractor{QueryParameter: "token"}, "/ws?token=A", nil, "A", nil, nil},
+		{"protocol before query", &WebSocketExtractor{QueryParameter: "token"}, "/ws?token=B", map[string]string{"Sec-WebSocket-Protocol": "access_token.A"}, "A", nil, nil},
+		{"missing", &WebSocketExtractor{}, "/ws", map[string]string{"Sec-WebSocket-Protocol": "chat"}, "", ErrNoTokenInRequest, []string{"chat"}},
+		{"not an upgrade", &WebSocketExtractor{}, "/ws", map[string]string{"Upgrade": "", "Sec-WebSocket-Protocol": "access_token.A"}, "", ErrNotWebSocketHandshake, nil},
+	}
+
+	for _, data := range tests {
+		r := httptest.NewRequest("GET", data.url, nil)
+		r.Header.Set("Connection", "keep-alive, Upgrade")
+		r.Header.Set("Upgrade", "websocket")
+		for k, v := range data.headers {
+			r.Header.Set(k, v)
+		}
+
+		token, err := data.extractor.ExtractToken(r)
+		if token != data.token || err != data.err {
+			t.Errorf("[%v] Expected %q, %v, got %q, %v", data.name, data.token, data.err, token, err)
+		}
+		if data.protocols != nil && !reflect.DeepEqual(data.extractor.Protocols(r), data.protocols) {
+			t.Errorf("[%v] Expected protocols %v, got %v", data.name, data.protocols, data.extractor.Protocols(r))
+		}
+	}
+}
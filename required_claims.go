@@ -0,0 +1,27 @@
+package jwt
+
+// Fail validation for tokens missing any of the named claims.  Standard
+// claims such as exp are otherwise only checked when present, so a token
+// without exp never expires.  For struct claims, a claim is missing when its
+// field encodes to nothing, such as a zero value tagged omitempty.
+func WithRequiredClaims(names ...string) ParserOption {
+	return func(p *Parser) {
+		p.validators = append(p.validators, func(token *Token) error {
+			claims, err := claimsToMap(token.Claims)
+			if err != nil {
+				return &ValidationError{Inner: err, Errors: ValidationErrorClaimsInvalid}
+			}
+			return requireClaims(claims, names, "token")
+		})
+	}
+}
+
+// Returns an error naming the first of names absent from claims
+func requireClaims(claims MapClaims, names []string, kind string) error {
+	for _, name := range names {
+		if v, ok := claims[name]; !ok || v == nil {
+			return NewValidationError(kind+" is missing the "+name+" claim", ValidationErrorClaimsInvalid)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestRequiredClaims(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	var tests = []struct {
+		name   string
+		claims jwt.Claims
+		valid  bool
+	}{
+		{"all present", jwt.MapClaims{"exp": exp, "sub": "user", "jti": "abc"}, true},
+		{"missing exp", jwt.MapClaims{"sub": "user", "jti": "abc"}, false},
+		{"null jti", jwt.MapClaims{"exp": exp, "sub": "user", "jti": nil}, false},
+		{"struct", &jwt.StandardClaims{ExpiresAt: exp, Subject: "user", Id: "abc"}, true},
+		{"struct zero exp", &jwt.StandardClaims{Subject: "user", Id: "abc"}, false},
+	}
+
+	parser := jwt.NewParser(jwt.WithRequiredClaims("exp", "sub", "jti"))
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	for _, data := range tests {
+		tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).SignedString(hmacTestKey)
+		_, err := parser.Parse(tokenString, keyFunc)
+		if data.valid && err != nil {
+			t.Errorf("[%v] Error while verifying token: %v", data.name, err)
+		}
+		if !data.valid {
+			if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors != jwt.ValidationErrorClaimsInvalid {
+				t.Errorf("[%v] Expected ValidationErrorClaimsInvalid, got %v", data.name, err)
+			}
+		}
+	}
+}
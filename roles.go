@@ -0,0 +1,85 @@
+package jwt
+
+// Extracts a normalized list of roles from a claims set, for identity providers
+// that each lay out roles differently
+type RoleMapper func(claims MapClaims) []string
+
+// Returns the roles in claims, as found by mapper
+func Roles(claims Claims, mapper RoleMapper) []string {
+	m, err := claimsToMap(claims)
+	if err != nil {
+		return nil
+	}
+	return mapper(m)
+}
+
+// Reports whether claims include all of roles, as found by mapper
+func HasRoles(claims Claims, mapper RoleMapper, roles ...string) bool {
+	granted := Roles(claims, mapper)
+	for _, want := range roles {
+		found := false
+		for _, r := range granted {
+			if r == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Reads Keycloak realm roles (realm_access.roles) and the client roles
+// (resource_access.<client>.roles) of each of clients.  Client roles are
+// returned as "client:role".
+func KeycloakRoles(clients ...string) RoleMapper {
+	return func(claims MapClaims) []string {
+		var roles []string
+		if realm, ok := claims["realm_access"].(map[string]interface{}); ok {
+			roles = append(roles, stringsClaim(realm["roles"])...)
+		}
+		resources, _ := claims["resource_access"].(map[string]interface{})
+		for _, client := range clients {
+			if resource, ok := resources[client].(map[string]interface{}); ok {
+				for _, role := range stringsClaim(resource["roles"]) {
+					roles = append(roles, client+":"+role)
+				}
+			}
+		}
+		return roles
+	}
+}
+
+// Reads roles from an Auth0 namespaced custom claim, such as
+// "https://example.com/roles"
+func Auth0Roles(claim string) RoleMapper {
+	return func(claims MapClaims) []string {
+		return stringsClaim(claims[claim])
+	}
+}
+
+// Reads app roles from the Azure AD roles claim
+func AzureADRoles(claims MapClaims) []string {
+	return stringsClaim(claims["roles"])
+}
+
+// Returns a claim holding a string or an array of strings as a []string
+func stringsClaim(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		var values []string
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
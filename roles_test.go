@@ -0,0 +1,56 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestRoleMappers(t *testing.T) {
+	var tests = []struct {
+		name   string
+		claims string
+		mapper jwt.RoleMapper
+		roles  []string
+	}{
+		{
+			"keycloak",
+			`{"realm_access":{"roles":["admin"]},"resource_access":{"orders":{"roles":["write"]},"billing":{"roles":["read"]}}}`,
+			jwt.KeycloakRoles("orders"),
+			[]string{"admin", "orders:write"},
+		},
+		{
+			"auth0",
+			`{"https://example.com/roles":["editor","viewer"]}`,
+			jwt.Auth0Roles("https://example.com/roles"),
+			[]string{"editor", "viewer"},
+		},
+		{
+			"azure ad",
+			`{"roles":["Task.Write"]}`,
+			jwt.AzureADRoles,
+			[]string{"Task.Write"},
+		},
+		{
+			"missing",
+			`{"sub":"user"}`,
+			jwt.KeycloakRoles("orders"),
+			nil,
+		},
+	}
+
+	for _, data := range tests {
+		claims := jwt.MapClaims{}
+		json.Unmarshal([]byte(data.claims), &claims)
+		if roles := jwt.Roles(claims, data.mapper); !reflect.DeepEqual(roles, data.roles) {
+			t.Errorf("[%v] Unexpected roles: %v", data.name, roles)
+		}
+	}
+
+	claims := jwt.MapClaims{"roles": []string{"Task.Read", "Task.Write"}}
+	if !jwt.HasRoles(claims, jwt.AzureADRoles, "Task.Write") || jwt.HasRoles(claims, jwt.AzureADRoles, "Admin") {
+		t.Errorf("HasRoles mismatch")
+	}
+}
@@ -74,14 +74,21 @@ func (m *SigningMethodRSA) Verify(signingString, signature string, key interface
 }
 
 // Implements the Sign method from SigningMethod
-// For this signing method, must be an *rsa.PrivateKey structure.
+// For this signing method, must be an *rsa.PrivateKey structure or a
+// crypto.Signer backed by an RSA key, such as an HSM or KMS client.
 func (m *SigningMethodRSA) Sign(signingString string, key interface{}) (string, error) {
 	var rsaKey *rsa.PrivateKey
+	var signer crypto.Signer
 	var ok bool
 
 	// Validate type of key
 	if rsaKey, ok = key.(*rsa.PrivateKey); !ok {
-		return "", ErrInvalidKey
+		if signer, ok = key.(crypto.Signer); !ok {
+			return "", ErrInvalidKey
+		}
+		if _, ok = signer.Public().(*rsa.PublicKey); !ok {
+			return "", ErrInvalidKeyType
+		}
 	}
 
 	// Create the hasher
@@ -92,6 +99,15 @@ func (m *SigningMethodRSA) Sign(signingString string, key interface{}) (string,
 	hasher := m.Hash.New()
 	hasher.Write([]byte(signingString))
 
+	// Keys that never leave their device sign the digest themselves
+	if signer != nil {
+		if sigBytes, err := signer.Sign(rand.Reader, hasher.Sum(nil), m.Hash); err == nil {
+			return EncodeSegment(sigBytes), nil
+		} else {
+			return "", err
+		}
+	}
+
 	// Sign the string and return the encoded bytes
 	if sigBytes, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, m.Hash, hasher.Sum(nil)); err == nil {
 		return EncodeSegment(sigBytes), nil
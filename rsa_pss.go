@@ -1,3 +1,4 @@
+//go:build go1.4
 // +build go1.4
 
 package jwt
@@ -114,13 +115,20 @@ func (m *SigningMethodRSAPSS) Verify(signingString, signature string, key interf
 }
 
 // Implements the Sign method from SigningMethod
-// For this signing method, key must be an rsa.PrivateKey struct
+// For this signing method, key must be an rsa.PrivateKey struct or a
+// crypto.Signer backed by an RSA key, such as an HSM or KMS client.
 func (m *SigningMethodRSAPSS) Sign(signingString string, key interface{}) (string, error) {
 	var rsaKey *rsa.PrivateKey
+	var signer crypto.Signer
 
 	switch k := key.(type) {
 	case *rsa.PrivateKey:
 		rsaKey = k
+	case crypto.Signer:
+		if _, ok := k.Public().(*rsa.PublicKey); !ok {
+			return "", ErrInvalidKeyType
+		}
+		signer = k
 	default:
 		return "", ErrInvalidKeyType
 	}
@@ -133,6 +141,17 @@ func (m *SigningMethodRSAPSS) Sign(signingString string, key interface{}) (strin
 	hasher := m.Hash.New()
 	hasher.Write([]byte(signingString))
 
+	// Keys that never leave their device sign the digest themselves.
+	// crypto.Signer selects PSS when given *rsa.PSSOptions.
+	if signer != nil {
+		opts := &rsa.PSSOptions{SaltLength: m.Options.SaltLength, Hash: m.Hash}
+		if sigBytes, err := signer.Sign(rand.Reader, hasher.Sum(nil), opts); err == nil {
+			return EncodeSegment(sigBytes), nil
+		} else {
+			return "", err
+		}
+	}
+
 	// Sign the string and return the encoded bytes
 	if sigBytes, err := rsa.SignPSS(rand.Reader, rsaKey, m.Hash, hasher.Sum(nil), m.Options); err == nil {
 		return EncodeSegment(sigBytes), nil
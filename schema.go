@@ -0,0 +1,269 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// A subset of JSON Schema for describing the shape of a claims set, so that
+// tokens which are signed but malformed, such as those from a misconfigured
+// issuer, are rejected with an error naming each offending claim.  It can be
+// built in Go or loaded from a JSON Schema document with ParseSchema; the
+// keywords below are supported and others are ignored.
+type Schema struct {
+	Type                 SchemaTypes        `json:"type,omitempty"` // Any of "string", "number", "integer", "boolean", "array", "object" and "null"
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"` // If false, only Properties are allowed
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// The types a Schema allows.  Decodes from a single type name or a list.
+type SchemaTypes []string
+
+func (t *SchemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaTypes{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// Decode a JSON Schema document and compile its patterns
+func ParseSchema(data []byte) (*Schema, error) {
+	s := new(Schema)
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if err := s.Compile(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Compile the patterns of s and its subschemas.  Validate compiles them on
+// first use; calling Compile up front reports bad patterns early and makes s
+// safe for concurrent use.
+func (s *Schema) Compile() error {
+	if s.Pattern != "" && s.pattern == nil {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("schema pattern %q: %v", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	for _, sub := range s.Properties {
+		if err := sub.Compile(); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.Compile()
+	}
+	return nil
+}
+
+// One way a claims set fails a Schema
+type SchemaViolation struct {
+	Path    string // The claim, such as "roles[1]" or "address.country"
+	Message string
+}
+
+// Every way a claims set fails a Schema
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Path + ": " + v.Message
+	}
+	return "claims do not match schema: " + strings.Join(msgs, "; ")
+}
+
+// Check claims against s.  Returns a *SchemaError listing every violation.
+func (s *Schema) Validate(claims Claims) error {
+	if err := s.Compile(); err != nil {
+		return err
+	}
+	m, err := claimsToMap(claims)
+	if err != nil {
+		return err
+	}
+	e := new(SchemaError)
+	s.validate(e, "", map[string]interface{}(m))
+	if len(e.Violations) > 0 {
+		return e
+	}
+	return nil
+}
+
+// A validation step checking claims against schema, named "schema".  The
+// schema's patterns are compiled up front; a bad pattern fails every token.
+func SchemaValidator(schema *Schema) Validator {
+	schema.Compile()
+	return Validator{Name: "schema", Validate: func(ctx context.Context, token *Token) error {
+		if err := schema.Validate(token.Claims); err != nil {
+			return &ValidationError{Inner: err, Errors: ValidationErrorClaimsInvalid}
+		}
+		return nil
+	}}
+}
+
+func (s *Schema) validate(e *SchemaError, path string, v interface{}) {
+	fail := func(format string, args ...interface{}) {
+		p := path
+		if p == "" {
+			p = "(claims)"
+		}
+		e.Violations = append(e.Violations, SchemaViolation{Path: p, Message: fmt.Sprintf(format, args...)})
+	}
+
+	typ, num := schemaType(v)
+	if len(s.Type) > 0 && !s.Type.allows(typ, num) {
+		fail("must be %s, not %s", strings.Join(s.Type, " or "), typ)
+		return
+	}
+	if len(s.Enum) > 0 && !schemaEnumContains(s.Enum, v) {
+		fail("must be one of %v", s.Enum)
+	}
+
+	switch typ {
+	case "string":
+		str := v.(string)
+		n := utf8.RuneCountInString(str)
+		if s.MinLength != nil && n < *s.MinLength {
+			fail("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			fail("must be at most %d characters", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(str) {
+			fail("must match %s", s.Pattern)
+		}
+	case "number":
+		if s.Minimum != nil && num < *s.Minimum {
+			fail("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && num > *s.Maximum {
+			fail("must be at most %v", *s.Maximum)
+		}
+	case "array":
+		items := v.([]interface{})
+		if s.MinItems != nil && len(items) < *s.MinItems {
+			fail("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(items) > *s.MaxItems {
+			fail("must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				s.Items.validate(e, fmt.Sprintf("%s[%d]", path, i), item)
+			}
+		}
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			obj = v.(MapClaims)
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				e.Violations = append(e.Violations, SchemaViolation{Path: schemaPath(path, name), Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if sub, ok := s.Properties[name]; ok {
+				sub.validate(e, schemaPath(path, name), obj[name])
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				e.Violations = append(e.Violations, SchemaViolation{Path: schemaPath(path, name), Message: "is not allowed"})
+			}
+		}
+	}
+}
+
+func (t SchemaTypes) allows(typ string, num float64) bool {
+	for _, want := range t {
+		if want == typ || want == "integer" && typ == "number" && num == math.Trunc(num) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the JSON type of a decoded claim value, and its value if it is a
+// number
+func schemaType(v interface{}) (string, float64) {
+	switch v := v.(type) {
+	case nil:
+		return "null", 0
+	case string:
+		return "string", 0
+	case bool:
+		return "boolean", 0
+	case float64:
+		return "number", v
+	case json.Number:
+		f, _ := v.Float64()
+		return "number", f
+	case int:
+		return "number", float64(v)
+	case int64:
+		return "number", float64(v)
+	case []interface{}:
+		return "array", 0
+	case map[string]interface{}:
+		return "object", 0
+	case MapClaims:
+		return "object", 0
+	}
+	return fmt.Sprintf("%T", v), 0
+}
+
+func schemaEnumContains(values []interface{}, v interface{}) bool {
+	typ, num := schemaType(v)
+	for _, value := range values {
+		vtyp, vnum := schemaType(value)
+		if vtyp != typ {
+			continue
+		}
+		if typ == "number" && num == vnum || typ != "number" && fmt.Sprint(value) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func schemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,79 @@
+package jwt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["sub", "email"],
+	"properties": {
+		"sub": {"type": "string", "minLength": 1},
+		"email": {"type": "string", "pattern": "^[^@]+@[^@]+$"},
+		"age": {"type": "integer", "minimum": 0},
+		"roles": {"type": "array", "items": {"enum": ["admin", "user"]}, "maxItems": 2},
+		"address": {"type": "object", "properties": {"country": {"type": "string", "maxLength": 2}}}
+	}
+}`
+
+func TestSchema(t *testing.T) {
+	schema, err := jwt.ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name       string
+		claims     jwt.Claims
+		violations []string
+	}{
+		{"valid", jwt.MapClaims{"sub": "u", "email": "u@example.com", "age": float64(30), "roles": []interface{}{"admin"}}, nil},
+		{"struct claims", &jwt.StandardClaims{Subject: "u"}, []string{"email: is required"}},
+		{"wrong types", jwt.MapClaims{"sub": float64(1), "email": "u@example.com", "age": 1.5}, []string{"age: must be integer, not number", "sub: must be string, not number"}},
+		{"nested", jwt.MapClaims{"sub": "u", "email": "nope", "roles": []interface{}{"admin", "root"}, "address": map[string]interface{}{"country": "USA"}},
+			[]string{"address.country: must be at most 2 characters", "email: must match ^[^@]+@[^@]+$", "roles[1]: must be one of [admin user]"}},
+	}
+
+	for _, test := range tests {
+		err := schema.Validate(test.claims)
+		if test.violations == nil {
+			if err != nil {
+				t.Errorf("[%v] Unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		se, ok := err.(*jwt.SchemaError)
+		if !ok {
+			t.Errorf("[%v] Expected a SchemaError, got %v", test.name, err)
+			continue
+		}
+		var got []string
+		for _, v := range se.Violations {
+			got = append(got, v.Path+": "+v.Message)
+		}
+		if strings.Join(got, "\n") != strings.Join(test.violations, "\n") {
+			t.Errorf("[%v] Expected violations:\n%s\ngot:\n%s", test.name, strings.Join(test.violations, "\n"), strings.Join(got, "\n"))
+		}
+	}
+
+	if _, err := jwt.ParseSchema([]byte(`{"pattern": "("}`)); err == nil {
+		t.Errorf("Expected an invalid pattern to be rejected")
+	}
+}
+
+func TestSchemaValidator(t *testing.T) {
+	schema, _ := jwt.ParseSchema([]byte(testSchema))
+	parser := jwt.NewParser(jwt.WithValidator(jwt.SchemaValidator(schema)))
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	if _, err := parser.Parse(signHS256(jwt.MapClaims{"sub": "u", "email": "u@example.com"}), keyFunc); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	_, err := parser.Parse(signHS256(jwt.MapClaims{"sub": "u"}), keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorClaimsInvalid == 0 || !strings.Contains(err.Error(), "email: is required") {
+		t.Errorf("Expected a schema error, got %v", err)
+	}
+}
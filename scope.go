@@ -0,0 +1,80 @@
+package jwt
+
+import (
+	"strings"
+)
+
+// Returns the scopes granted by claims.  Both the space-delimited "scope"
+// claim (RFC 8693 section 4.2) and the "scp" array used by some providers are
+// read; duplicates are removed.
+func Scopes(claims Claims) []string {
+	m, err := claimsToMap(claims)
+	if err != nil {
+		return nil
+	}
+
+	var scopes []string
+	seen := map[string]bool{}
+	add := func(s string) {
+		if s != "" && !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+
+	if scope, ok := m["scope"].(string); ok {
+		for _, s := range ParseScope(scope) {
+			add(s)
+		}
+	}
+	switch scp := m["scp"].(type) {
+	case string:
+		for _, s := range ParseScope(scp) {
+			add(s)
+		}
+	case []interface{}:
+		for _, s := range scp {
+			if s, ok := s.(string); ok {
+				add(s)
+			}
+		}
+	case []string:
+		for _, s := range scp {
+			add(s)
+		}
+	}
+	return scopes
+}
+
+// Reports whether the token's claims grant scope
+func HasScope(token *Token, scope string) bool {
+	return HasScopes(token, scope)
+}
+
+// Reports whether the token's claims grant all of scopes
+func HasScopes(token *Token, scopes ...string) bool {
+	granted := Scopes(token.Claims)
+	for _, want := range scopes {
+		found := false
+		for _, s := range granted {
+			if s == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Split a space-delimited scope claim into its scopes
+func ParseScope(scope string) []string {
+	return strings.Fields(scope)
+}
+
+// Join scopes into a space-delimited scope claim, as for the "scope" claim
+func FormatScope(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
@@ -0,0 +1,38 @@
+package jwt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestScopes(t *testing.T) {
+	var tests = []struct {
+		name   string
+		claims jwt.Claims
+		scopes []string
+	}{
+		{"scope string", jwt.MapClaims{"scope": "orders:read  orders:write"}, []string{"orders:read", "orders:write"}},
+		{"scp array", jwt.MapClaims{"scp": []interface{}{"orders:read", "orders:write"}}, []string{"orders:read", "orders:write"}},
+		{"both, deduplicated", jwt.MapClaims{"scope": "orders:read", "scp": []interface{}{"orders:read", "admin"}}, []string{"orders:read", "admin"}},
+		{"none", jwt.StandardClaims{Subject: "user"}, nil},
+	}
+
+	for _, data := range tests {
+		if scopes := jwt.Scopes(data.claims); !reflect.DeepEqual(scopes, data.scopes) {
+			t.Errorf("[%v] Unexpected scopes: %v", data.name, scopes)
+		}
+	}
+
+	token := &jwt.Token{Claims: jwt.MapClaims{"scope": "orders:read orders:write"}}
+	if !jwt.HasScope(token, "orders:write") || jwt.HasScope(token, "admin") {
+		t.Errorf("HasScope mismatch")
+	}
+	if !jwt.HasScopes(token, "orders:read", "orders:write") || jwt.HasScopes(token, "orders:read", "admin") {
+		t.Errorf("HasScopes mismatch")
+	}
+	if s := jwt.FormatScope(jwt.ParseScope(" a  b ")); s != "a b" {
+		t.Errorf("Unexpected normalized scope: %q", s)
+	}
+}
@@ -0,0 +1,99 @@
+package sdjwt
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// The _sd_alg value for SHA-256, the only supported digest algorithm
+const DigestAlgorithm = "sha-256"
+
+// Errors
+var (
+	ErrInvalidDisclosure      = errors.New("SD-JWT disclosure is malformed")
+	ErrUnreferencedDisclosure = errors.New("SD-JWT disclosure does not match any digest in the token")
+	ErrDuplicateDigest        = errors.New("SD-JWT digest appears more than once")
+	ErrDigestAlgorithm        = errors.New("SD-JWT _sd_alg is not supported")
+	ErrClaimConflict          = errors.New("SD-JWT disclosure names a claim that is already present")
+)
+
+// A salted claim, or an array element if Name is empty
+type Disclosure struct {
+	Salt    string
+	Name    string
+	Value   interface{}
+	Encoded string // The base64url-encoded JSON array, as presented
+}
+
+// Create a disclosure for the claim name, with a random salt
+func NewDisclosure(name string, value interface{}) (*Disclosure, error) {
+	return newDisclosure(name, value, true)
+}
+
+// Create a disclosure for an array element, with a random salt.  Put
+// ArrayElement(d.Digest()) in the array in its place.
+func NewArrayElementDisclosure(value interface{}) (*Disclosure, error) {
+	return newDisclosure("", value, false)
+}
+
+func newDisclosure(name string, value interface{}, named bool) (*Disclosure, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	d := &Disclosure{Salt: jwt.EncodeSegment(salt), Name: name, Value: value}
+	array := []interface{}{d.Salt, value}
+	if named {
+		array = []interface{}{d.Salt, name, value}
+	}
+	data, err := json.Marshal(array)
+	if err != nil {
+		return nil, err
+	}
+	d.Encoded = jwt.EncodeSegment(data)
+	return d, nil
+}
+
+// Decode a presented disclosure
+func ParseDisclosure(encoded string) (*Disclosure, error) {
+	data, err := jwt.DecodeSegment(encoded)
+	if err != nil {
+		return nil, ErrInvalidDisclosure
+	}
+	var array []interface{}
+	if err := json.Unmarshal(data, &array); err != nil {
+		return nil, ErrInvalidDisclosure
+	}
+	d := &Disclosure{Encoded: encoded}
+	var ok bool
+	switch len(array) {
+	case 2:
+		d.Value = array[1]
+	case 3:
+		if d.Name, ok = array[1].(string); !ok || d.Name == "_sd" || d.Name == "..." {
+			return nil, ErrInvalidDisclosure
+		}
+		d.Value = array[2]
+	default:
+		return nil, ErrInvalidDisclosure
+	}
+	if d.Salt, ok = array[0].(string); !ok {
+		return nil, ErrInvalidDisclosure
+	}
+	return d, nil
+}
+
+// The digest the issuer puts in the token in place of the claim
+func (d *Disclosure) Digest() string {
+	sum := sha256.Sum256([]byte(d.Encoded))
+	return jwt.EncodeSegment(sum[:])
+}
+
+// The array element standing in for an array element disclosure
+func ArrayElement(digest string) map[string]interface{} {
+	return map[string]interface{}{"...": digest}
+}
@@ -0,0 +1,16 @@
+// Selective Disclosure for JWTs (SD-JWT).
+//
+// The issuer replaces chosen claims with digests in an _sd array and hands
+// the holder a disclosure for each: the salted claim the digest was computed
+// from.  The holder presents the token with only the disclosures it wants to
+// reveal, and the verifier checks each against the signed digests.
+//
+//	issued, err := sdjwt.Issue(jwt.SigningMethodES256, key, claims, "email", "birthdate")
+//	presentation := issued.Present("email")
+//
+//	p, err := sdjwt.Verify(presentation, keyFunc)
+//	email := p.Claims["email"]
+//
+// Only SHA-256 digests are supported.  A key binding JWT, if the holder
+// appends one, is returned in Presentation.KeyBinding but not verified.
+package sdjwt
@@ -0,0 +1,81 @@
+package sdjwt
+
+import (
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// An issued SD-JWT: the signed token and every disclosure for it
+type SDJWT struct {
+	Token       string
+	Disclosures []*Disclosure
+}
+
+// Sign claims, replacing the top-level claims named in selective with
+// digests.  claims is not modified.  Nested claims can be made selectively
+// disclosable by building their _sd arrays with NewDisclosure, and the
+// resulting disclosures appended to the returned SDJWT.
+func Issue(method jwt.SigningMethod, key interface{}, claims jwt.MapClaims, selective ...string) (*SDJWT, error) {
+	payload := jwt.MapClaims{}
+	for k, v := range claims {
+		payload[k] = v
+	}
+
+	issued := &SDJWT{}
+	var digests []interface{}
+	for _, name := range selective {
+		value, ok := payload[name]
+		if !ok {
+			continue
+		}
+		d, err := NewDisclosure(name, value)
+		if err != nil {
+			return nil, err
+		}
+		delete(payload, name)
+		issued.Disclosures = append(issued.Disclosures, d)
+		digests = append(digests, d.Digest())
+	}
+	if len(digests) > 0 {
+		payload["_sd"] = digests
+		payload["_sd_alg"] = DigestAlgorithm
+	}
+
+	var err error
+	if issued.Token, err = jwt.NewWithClaims(method, payload).SignedString(key); err != nil {
+		return nil, err
+	}
+	return issued, nil
+}
+
+// The token with every disclosure, as the issuer sends it to the holder
+func (s *SDJWT) String() string {
+	return s.serialize(s.Disclosures)
+}
+
+// The token with only the disclosures for the named claims, as a holder
+// presents it.  Array element disclosures are never included.
+func (s *SDJWT) Present(names ...string) string {
+	var chosen []*Disclosure
+	for _, d := range s.Disclosures {
+		for _, name := range names {
+			if d.Name != "" && d.Name == name {
+				chosen = append(chosen, d)
+				break
+			}
+		}
+	}
+	return s.serialize(chosen)
+}
+
+func (s *SDJWT) serialize(disclosures []*Disclosure) string {
+	var b strings.Builder
+	b.WriteString(s.Token)
+	b.WriteByte('~')
+	for _, d := range disclosures {
+		b.WriteString(d.Encoded)
+		b.WriteByte('~')
+	}
+	return b.String()
+}
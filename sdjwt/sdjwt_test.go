@@ -0,0 +1,113 @@
+package sdjwt
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var testKey = []byte("secret")
+
+func testKeyFunc(*jwt.Token) (interface{}, error) { return testKey, nil }
+
+func TestIssueAndVerify(t *testing.T) {
+	claims := jwt.MapClaims{"iss": "https://issuer.example.com", "sub": "alice", "email": "alice@example.com", "birthdate": "1990-01-01"}
+	issued, err := Issue(jwt.SigningMethodHS256, testKey, claims, "email", "birthdate")
+	if err != nil {
+		t.Fatalf("Error issuing SD-JWT: %v", err)
+	}
+	if len(issued.Disclosures) != 2 || len(claims) != 4 {
+		t.Fatalf("Unexpected disclosures %v, or claims modified", issued.Disclosures)
+	}
+
+	signed := jwt.MapClaims{}
+	jwt.ParseWithClaims(issued.Token, signed, testKeyFunc)
+	if _, ok := signed["email"]; ok || signed["_sd_alg"] != DigestAlgorithm {
+		t.Errorf("Expected email to be replaced by a digest: %v", signed)
+	}
+
+	var tests = []struct {
+		name         string
+		presentation string
+		want         jwt.MapClaims
+		err          error
+	}{
+		{"all", issued.String(), claims, nil},
+		{"email only", issued.Present("email"), jwt.MapClaims{"iss": "https://issuer.example.com", "sub": "alice", "email": "alice@example.com"}, nil},
+		{"none", issued.Present(), jwt.MapClaims{"iss": "https://issuer.example.com", "sub": "alice"}, nil},
+		{"key binding", issued.Present("email") + "kb.jwt.sig", jwt.MapClaims{"iss": "https://issuer.example.com", "sub": "alice", "email": "alice@example.com"}, nil},
+		{"forged disclosure", issued.Present() + mustDisclosure(t, "email", "mallory@example.com").Encoded + "~", nil, ErrUnreferencedDisclosure},
+		{"repeated disclosure", issued.Present("email") + issued.Disclosures[0].Encoded + "~", nil, ErrDuplicateDigest},
+		{"malformed disclosure", issued.Present() + "!!~", nil, ErrInvalidDisclosure},
+		{"no separator", issued.Token, nil, errAny},
+	}
+
+	for _, data := range tests {
+		p, err := Verify(data.presentation, testKeyFunc)
+		if data.err == errAny {
+			if err == nil {
+				t.Errorf("[%v] Expected an error", data.name)
+			}
+			continue
+		}
+		if err != data.err {
+			t.Errorf("[%v] Expected error %v, got %v", data.name, data.err, err)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(p.Claims, data.want) {
+			t.Errorf("[%v] Expected claims %v, got %v", data.name, data.want, p.Claims)
+		}
+		if data.name == "key binding" && p.KeyBinding != "kb.jwt.sig" {
+			t.Errorf("[%v] Unexpected key binding %q", data.name, p.KeyBinding)
+		}
+	}
+}
+
+func TestVerifyNested(t *testing.T) {
+	street := mustDisclosure(t, "street", "Main St 1")
+	nationality, _ := NewArrayElementDisclosure("DE")
+	conflict := mustDisclosure(t, "sub", "mallory")
+
+	claims := jwt.MapClaims{
+		"sub":           "alice",
+		"address":       map[string]interface{}{"country": "DE", "_sd": []string{street.Digest()}},
+		"nationalities": []interface{}{"US", ArrayElement(nationality.Digest()), ArrayElement("decoy")},
+		"_sd":           []string{conflict.Digest()},
+	}
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testKey)
+	issued := &SDJWT{Token: token, Disclosures: []*Disclosure{street, nationality}}
+
+	p, err := Verify(issued.String(), testKeyFunc)
+	if err != nil {
+		t.Fatalf("Error verifying: %v", err)
+	}
+	want := jwt.MapClaims{
+		"sub":           "alice",
+		"address":       map[string]interface{}{"country": "DE", "street": "Main St 1"},
+		"nationalities": []interface{}{"US", "DE"},
+	}
+	if !reflect.DeepEqual(p.Claims, want) {
+		t.Errorf("Expected %v, got %v", want, p.Claims)
+	}
+
+	if _, err := Verify(token+"~"+conflict.Encoded+"~", testKeyFunc); err != ErrClaimConflict {
+		t.Errorf("Expected ErrClaimConflict, got %v", err)
+	}
+	if !strings.HasSuffix(issued.Present("street"), street.Encoded+"~") {
+		t.Errorf("Expected nested disclosure to be presentable by name")
+	}
+}
+
+// Matches any non-nil error in test tables
+var errAny = errors.New("any error")
+
+func mustDisclosure(t *testing.T, name string, value interface{}) *Disclosure {
+	d, err := NewDisclosure(name, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
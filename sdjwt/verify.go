@@ -0,0 +1,150 @@
+package sdjwt
+
+import (
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// A verified SD-JWT presentation
+type Presentation struct {
+	Token       *jwt.Token
+	Claims      jwt.MapClaims // The signed claims with the presented disclosures applied
+	Disclosures []*Disclosure
+	KeyBinding  string // The key binding JWT, if any.  Not verified.
+}
+
+// Verify the issuer-signed token in presentation with keyFunc and options,
+// then check each disclosure against its digests.  Claims whose disclosures
+// were not presented are absent from Presentation.Claims.
+func Verify(presentation string, keyFunc jwt.Keyfunc, options ...jwt.ParserOption) (*Presentation, error) {
+	parts := strings.Split(presentation, "~")
+	if len(parts) < 2 {
+		return nil, jwt.NewValidationError("SD-JWT has no ~ separator", jwt.ValidationErrorMalformed)
+	}
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.NewParser(options...).ParseWithClaims(parts[0], claims, keyFunc)
+	if err != nil {
+		return nil, err
+	}
+	if alg, ok := claims["_sd_alg"]; ok && alg != DigestAlgorithm {
+		return nil, ErrDigestAlgorithm
+	}
+
+	p := &Presentation{Token: token, KeyBinding: parts[len(parts)-1]}
+	byDigest := make(map[string]*Disclosure)
+	for _, encoded := range parts[1 : len(parts)-1] {
+		d, err := ParseDisclosure(encoded)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := byDigest[d.Digest()]; ok {
+			return nil, ErrDuplicateDigest
+		}
+		byDigest[d.Digest()] = d
+		p.Disclosures = append(p.Disclosures, d)
+	}
+
+	r := &resolver{disclosures: byDigest, seen: make(map[string]bool)}
+	resolved, err := r.object(claims)
+	if err != nil {
+		return nil, err
+	}
+	delete(resolved, "_sd_alg")
+	for digest := range byDigest {
+		if !r.seen[digest] {
+			return nil, ErrUnreferencedDisclosure
+		}
+	}
+	p.Claims = jwt.MapClaims(resolved)
+	return p, nil
+}
+
+// Replaces digests with their disclosed values, recursively
+type resolver struct {
+	disclosures map[string]*Disclosure
+	seen        map[string]bool
+}
+
+// Record digest as found, returning its disclosure if it was presented
+func (r *resolver) use(digest string) (*Disclosure, error) {
+	if r.seen[digest] {
+		return nil, ErrDuplicateDigest
+	}
+	r.seen[digest] = true
+	return r.disclosures[digest], nil
+}
+
+func (r *resolver) object(in map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		if k == "_sd" {
+			continue
+		}
+		resolved, err := r.value(v)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = resolved
+	}
+
+	digests, _ := in["_sd"].([]interface{})
+	for _, v := range digests {
+		digest, ok := v.(string)
+		if !ok {
+			return nil, ErrInvalidDisclosure
+		}
+		d, err := r.use(digest)
+		if err != nil {
+			return nil, err
+		}
+		if d == nil {
+			// Not presented, or a decoy
+			continue
+		}
+		if d.Name == "" {
+			return nil, ErrInvalidDisclosure
+		}
+		if _, ok := out[d.Name]; ok {
+			return nil, ErrClaimConflict
+		}
+		if out[d.Name], err = r.value(d.Value); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (r *resolver) value(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return r.object(v)
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, element := range v {
+			if m, ok := element.(map[string]interface{}); ok && len(m) == 1 {
+				if digest, ok := m["..."].(string); ok {
+					d, err := r.use(digest)
+					if err != nil {
+						return nil, err
+					}
+					if d == nil {
+						continue
+					}
+					if d.Name != "" {
+						return nil, ErrInvalidDisclosure
+					}
+					element = d.Value
+				}
+			}
+			resolved, err := r.value(element)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, resolved)
+		}
+		return out, nil
+	}
+	return v, nil
+}
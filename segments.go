@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	ErrNonCanonicalSegment = errors.New("segment is not canonical unpadded base64url")
+)
+
+// The three encoded segments of a compact token
+type TokenSegments struct {
+	Header    string
+	Payload   string
+	Signature string
+}
+
+// Split a compact token into its segments.  The token must contain exactly two
+// periods, and the header, signature and (unless the header sets b64 to false)
+// payload must be unpadded base64url in canonical form.  Nothing is verified.
+func SplitToken(tokenString string) (*TokenSegments, error) {
+	header, payload, signature, ok := splitToken(tokenString)
+	if !ok {
+		return nil, NewValidationError("token contains an invalid number of segments", ValidationErrorMalformed)
+	}
+	s := &TokenSegments{Header: header, Payload: payload, Signature: signature}
+
+	headerBytes, err := s.DecodeHeader()
+	if err != nil {
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	var h map[string]interface{}
+	if err := json.Unmarshal(headerBytes, &h); err != nil {
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	if unencoded, err := parseUnencodedPayloadHeader(h); err != nil {
+		return nil, err
+	} else if !unencoded && !isCanonicalSegment(payload) {
+		return nil, &ValidationError{Inner: ErrNonCanonicalSegment, Errors: ValidationErrorMalformed}
+	}
+	if !isCanonicalSegment(signature) {
+		return nil, &ValidationError{Inner: ErrNonCanonicalSegment, Errors: ValidationErrorMalformed}
+	}
+	return s, nil
+}
+
+// The input to the signing method: the header and payload segments joined by a period
+func (s *TokenSegments) SigningString() string {
+	return s.Header + "." + s.Payload
+}
+
+// Decode the header segment, rejecting padding and non-canonical encodings
+func (s *TokenSegments) DecodeHeader() ([]byte, error) {
+	return decodeStrictSegment(s.Header)
+}
+
+// Decode the payload segment, rejecting padding and non-canonical encodings.
+// For unencoded (b64=false) payloads, use Payload directly.
+func (s *TokenSegments) DecodePayload() ([]byte, error) {
+	return decodeStrictSegment(s.Payload)
+}
+
+// Decode the signature segment, rejecting padding and non-canonical encodings
+func (s *TokenSegments) DecodeSignature() ([]byte, error) {
+	return decodeStrictSegment(s.Signature)
+}
+
+func decodeStrictSegment(seg string) ([]byte, error) {
+	if !isCanonicalSegment(seg) {
+		return nil, ErrNonCanonicalSegment
+	}
+	return base64.RawURLEncoding.DecodeString(seg)
+}
@@ -0,0 +1,52 @@
+package jwt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestSplitToken(t *testing.T) {
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"}).SignedString(hmacTestKey)
+	parts := strings.Split(tokenString, ".")
+
+	segments, err := jwt.SplitToken(tokenString)
+	if err != nil {
+		t.Fatalf("Error splitting token: %v", err)
+	}
+	if segments.Header != parts[0] || segments.Payload != parts[1] || segments.Signature != parts[2] {
+		t.Errorf("Segments mismatch: %+v", segments)
+	}
+	if segments.SigningString() != parts[0]+"."+parts[1] {
+		t.Errorf("SigningString mismatch: %v", segments.SigningString())
+	}
+	if payload, err := segments.DecodePayload(); err != nil || string(payload) != `{"foo":"bar"}` {
+		t.Errorf("Unexpected payload %s: %v", payload, err)
+	}
+
+	var tests = []struct {
+		name        string
+		tokenString string
+	}{
+		{"too few segments", parts[0] + "." + parts[1]},
+		{"too many segments", tokenString + ".extra"},
+		{"padded header", parts[0] + "=." + parts[1] + "." + parts[2]},
+		{"standard alphabet", parts[0] + "." + parts[1] + ".ab+c"},
+		{"newline in payload", parts[0] + "." + parts[1][:4] + "\n" + parts[1][4:] + "." + parts[2]},
+	}
+	for _, data := range tests {
+		_, err := jwt.SplitToken(data.tokenString)
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorMalformed == 0 {
+			t.Errorf("[%v] Expected malformed error, got %v", data.name, err)
+		}
+	}
+
+	// Unencoded payloads are not base64
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"})
+	token.SetUnencodedPayload()
+	tokenString, _ = token.SignedString(hmacTestKey)
+	if segments, err := jwt.SplitToken(tokenString); err != nil || segments.Payload != `{"foo":"bar"}` {
+		t.Errorf("Unexpected result for unencoded payload %+v: %v", segments, err)
+	}
+}
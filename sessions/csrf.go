@@ -0,0 +1,130 @@
+package sessions
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	DefaultCSRFCookieName = "csrf_token"
+	DefaultCSRFHeader     = "X-CSRF-Token"
+	DefaultCSRFFormField  = "csrf_token"
+)
+
+var (
+	ErrCSRFMissing  = errors.New("request has no CSRF token")
+	ErrCSRFMismatch = errors.New("CSRF token does not match the session")
+)
+
+// Protects cookie sessions from cross-site request forgery with the
+// double-submit pattern: the CSRF token is set in a cookie scripts can read,
+// and unsafe requests must repeat it in a header or form field.  The token is
+// an HMAC of the session's jti, so it is only valid alongside the session it
+// was issued for and cannot be planted by an attacker who can set cookies on
+// a sibling domain.  Configure it before use; it is then safe for concurrent
+// use.
+type CSRF struct {
+	Sessions *Manager
+	Key      []byte // Derives tokens from session IDs.  Must be secret, and differ from the session key
+
+	CookieName string // Defaults to DefaultCSRFCookieName
+	Header     string // Defaults to DefaultCSRFHeader
+	FormField  string // Defaults to DefaultCSRFFormField
+
+	// Responds to rejected requests.  Defaults to 403 Forbidden.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// Create a CSRF deriving tokens for sessions with key
+func NewCSRF(sessions *Manager, key []byte) *CSRF {
+	return &CSRF{Sessions: sessions, Key: key}
+}
+
+// Returns the CSRF token for the session with the given jti
+func (c *CSRF) Token(sessionID string) string {
+	mac := hmac.New(sha256.New, c.Key)
+	mac.Write([]byte(sessionID))
+	return jwt.EncodeSegment(mac.Sum(nil))
+}
+
+// Write the CSRF token for the session with the given jti to the CSRF
+// cookie, with the session cookie's attributes except HttpOnly.  Call it
+// whenever a session is saved.
+func (c *CSRF) SetCookie(w http.ResponseWriter, sessionID string) {
+	cookie := c.Sessions.cookie(c.Token(sessionID))
+	cookie.Name = c.cookieName()
+	cookie.HttpOnly = false
+	http.SetCookie(w, cookie)
+}
+
+// Check r carries the CSRF token of its session in both the CSRF cookie and
+// the header or form field.  Requests with safe methods (GET, HEAD, OPTIONS
+// and TRACE) are not checked.
+func (c *CSRF) Validate(r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return nil
+	}
+
+	claims := &Claims{}
+	if _, err := c.Sessions.Load(r, claims); err != nil {
+		return err
+	}
+	cookie, err := r.Cookie(c.cookieName())
+	if err != nil {
+		return ErrCSRFMissing
+	}
+	submitted := r.Header.Get(c.header())
+	if submitted == "" {
+		submitted = r.PostFormValue(c.formField())
+	}
+	if submitted == "" {
+		return ErrCSRFMissing
+	}
+
+	expected := []byte(c.Token(claims.Id))
+	if !hmac.Equal([]byte(cookie.Value), expected) || !hmac.Equal([]byte(submitted), expected) {
+		return ErrCSRFMismatch
+	}
+	return nil
+}
+
+// Wrap next, rejecting unsafe requests that fail Validate
+func (c *CSRF) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := c.Validate(r); err != nil {
+			if c.ErrorHandler != nil {
+				c.ErrorHandler(w, r, err)
+			} else {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			}
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *CSRF) cookieName() string {
+	if c.CookieName == "" {
+		return DefaultCSRFCookieName
+	}
+	return c.CookieName
+}
+
+func (c *CSRF) header() string {
+	if c.Header == "" {
+		return DefaultCSRFHeader
+	}
+	return c.Header
+}
+
+func (c *CSRF) formField() string {
+	if c.FormField == "" {
+		return DefaultCSRFFormField
+	}
+	return c.FormField
+}
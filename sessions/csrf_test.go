@@ -0,0 +1,79 @@
+package sessions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestCSRF(t *testing.T) {
+	m := New(jwt.SigningMethodHS256, testKey)
+	csrf := NewCSRF(m, []byte("csrf secret"))
+
+	claims := &Claims{}
+	w := httptest.NewRecorder()
+	if err := m.Save(w, claims); err != nil {
+		t.Fatal(err)
+	}
+	csrf.SetCookie(w, claims.Id)
+	token := csrf.Token(claims.Id)
+
+	var cookies []*http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == DefaultCSRFCookieName && c.HttpOnly {
+			t.Errorf("CSRF cookie must be readable by scripts")
+		}
+		cookies = append(cookies, c)
+	}
+	newRequest := func(method, header, form string, withCookies bool) *http.Request {
+		var r *http.Request
+		if form != "" {
+			r = httptest.NewRequest(method, "/", strings.NewReader(url.Values{DefaultCSRFFormField: {form}}.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		} else {
+			r = httptest.NewRequest(method, "/", nil)
+		}
+		if header != "" {
+			r.Header.Set(DefaultCSRFHeader, header)
+		}
+		if withCookies {
+			for _, c := range cookies {
+				r.AddCookie(c)
+			}
+		}
+		return r
+	}
+
+	handler := csrf.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	var tests = []struct {
+		name   string
+		r      *http.Request
+		status int
+	}{
+		{"safe method", newRequest("GET", "", "", false), http.StatusOK},
+		{"header", newRequest("POST", token, "", true), http.StatusOK},
+		{"form field", newRequest("POST", "", token, true), http.StatusOK},
+		{"missing token", newRequest("POST", "", "", true), http.StatusForbidden},
+		{"wrong token", newRequest("DELETE", csrf.Token("other session"), "", true), http.StatusForbidden},
+		{"no session", newRequest("POST", token, "", false), http.StatusForbidden},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, test.r)
+		if w.Code != test.status {
+			t.Errorf("[%v] Expected status %v, got %v", test.name, test.status, w.Code)
+		}
+	}
+
+	// A token for another session is rejected even when cookie and header agree
+	r := newRequest("POST", csrf.Token("other session"), "", false)
+	r.AddCookie(cookies[0])
+	r.AddCookie(&http.Cookie{Name: DefaultCSRFCookieName, Value: csrf.Token("other session")})
+	if err := csrf.Validate(r); err != ErrCSRFMismatch {
+		t.Errorf("Expected ErrCSRFMismatch, got %v", err)
+	}
+}
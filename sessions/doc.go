@@ -0,0 +1,38 @@
+// Stateless sessions kept in a signed cookie.
+//
+// A Manager writes the session's claims as a JWT into an HttpOnly cookie,
+// Secure and SameSite=Lax by default, and reads them back, rejecting cookies
+// that are forged, tampered with or expired.  With an EncryptionKey the token
+// is also encrypted, as a Fernet token, so the claims cannot be read by the
+// browser or anyone the cookie leaks to:
+//
+//	manager := sessions.New(jwt.SigningMethodHS256, key)
+//	manager.EncryptionKey = encryptionKey
+//
+//	// on login
+//	claims := &sessions.Claims{}
+//	claims.Subject = user.ID
+//	err := manager.Save(w, claims)
+//
+//	// on each request
+//	claims := &sessions.Claims{}
+//	token, err := manager.Load(r, claims)
+//	if err == nil {
+//		manager.Rotate(w, token)
+//	}
+//
+// Rotate re-issues the cookie with a later exp once the session is close to
+// expiry, so active users stay signed in while idle sessions lapse.  Since
+// the session lives only in the cookie, Clear signs a user out of this
+// browser but cannot revoke copies of the cookie; pair the Manager with a
+// tokenstore.TokenStore when sessions must be revocable.
+//
+// Cookie sessions are sent with cross-site requests, so forms and APIs that
+// change state need CSRF protection.  CSRF derives a token from the session's
+// jti, which pages read from a cookie and send back in a header or form
+// field:
+//
+//	csrf := sessions.NewCSRF(manager, csrfKey)
+//	csrf.SetCookie(w, claims.Id) // after manager.Save
+//	http.Handle("/account", csrf.Handler(accountHandler))
+package sessions
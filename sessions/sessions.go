@@ -0,0 +1,199 @@
+package sessions
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/fernet"
+)
+
+const (
+	DefaultCookieName = "session"
+	DefaultLifetime   = 24 * time.Hour
+
+	// Browsers may drop cookies larger than this, name and attributes included
+	maxCookieSize = 4096
+)
+
+var (
+	ErrNoSession      = errors.New("request has no session cookie")
+	ErrCookieTooLarge = errors.New("session cookie exceeds 4096 bytes")
+)
+
+// The claims of every session.  Embed it to keep more in the session.
+type Claims struct {
+	jwt.StandardClaims
+}
+
+func (c *Claims) sessionClaims() *Claims {
+	return c
+}
+
+// Claims types accepted by Save and Load: Claims, or a struct embedding it
+type SessionClaims interface {
+	jwt.Claims
+	sessionClaims() *Claims
+}
+
+// Reads and writes sessions in a cookie.  Configure it before use; it is then
+// safe for concurrent use.
+type Manager struct {
+	Method        jwt.SigningMethod
+	Key           interface{} // Signs sessions, and verifies them unless VerifyKey is set
+	VerifyKey     interface{} // Verifies sessions, for asymmetric methods
+	EncryptionKey []byte      // If set, a 32-byte Fernet key the signed token is encrypted with
+
+	Lifetime time.Duration         // Lifetime of new sessions.  Defaults to DefaultLifetime
+	Refresh  jwt.SlidingExpiration // When and how far Rotate extends sessions
+	Parser   *jwt.Parser           // Options for checking sessions.  Defaults to NewParser(); exp is always required
+
+	CookieName string        // Defaults to DefaultCookieName
+	Path       string        // Defaults to "/"
+	Domain     string        // If empty, the cookie is sent only to the host that set it
+	SameSite   http.SameSite // Defaults to http.SameSiteLaxMode
+	Insecure   bool          // Omit the Secure attribute, for development over plain HTTP only
+}
+
+// Create a Manager signing sessions with method and key
+func New(method jwt.SigningMethod, key interface{}) *Manager {
+	return &Manager{Method: method, Key: key}
+}
+
+// Sign claims and write them to the session cookie.  A missing jti, iat or
+// exp is filled in, with exp Lifetime from now.
+func (m *Manager) Save(w http.ResponseWriter, claims SessionClaims) error {
+	c := claims.sessionClaims()
+	now := jwt.TimeFunc()
+	if c.Id == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return err
+		}
+		c.Id = jwt.EncodeSegment(id)
+	}
+	if c.IssuedAt == 0 {
+		c.IssuedAt = now.Unix()
+	}
+	if c.ExpiresAt == 0 {
+		lifetime := m.Lifetime
+		if lifetime == 0 {
+			lifetime = DefaultLifetime
+		}
+		c.ExpiresAt = now.Add(lifetime).Unix()
+	}
+
+	tokenString, err := jwt.NewWithClaims(m.Method, claims).SignedString(m.Key)
+	if err != nil {
+		return err
+	}
+	return m.writeCookie(w, tokenString, time.Unix(c.ExpiresAt, 0))
+}
+
+// Read and verify the session cookie into claims.  Returns ErrNoSession if
+// the request has no session cookie.
+func (m *Manager) Load(r *http.Request, claims SessionClaims) (*jwt.Token, error) {
+	cookie, err := r.Cookie(m.cookieName())
+	if err != nil {
+		return nil, ErrNoSession
+	}
+	tokenString := cookie.Value
+	if m.EncryptionKey != nil {
+		plaintext, _, err := fernet.Decrypt(m.EncryptionKey, tokenString)
+		if err != nil {
+			return nil, err
+		}
+		tokenString = string(plaintext)
+	}
+
+	parser := jwt.NewParser()
+	if m.Parser != nil {
+		copied := *m.Parser
+		parser = &copied
+	}
+	validators := parser.Validators
+	if validators == nil {
+		validators = jwt.DefaultValidators()
+	}
+	parser.Validators = append(validators[:len(validators):len(validators)], jwt.ExpirationValidator(true))
+	parser.ValidMethods = []string{m.Method.Alg()}
+	return parser.ParseWithClaims(tokenString, claims, m.keyFunc)
+}
+
+// Re-issue the session cookie with a later exp if token, returned by Load,
+// is close to expiry, as Refresh describes.  Reports whether the cookie was
+// re-issued.
+func (m *Manager) Rotate(w http.ResponseWriter, token *jwt.Token) (bool, error) {
+	tokenString, refreshed, err := m.Refresh.Refresh(token, m.Key)
+	if err != nil || !refreshed {
+		return false, err
+	}
+	next, err := jwt.ParseUnverified(tokenString)
+	if err != nil {
+		return false, err
+	}
+	exp, _ := next.ExpiresAt()
+	if err := m.writeCookie(w, tokenString, exp); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete the session cookie, signing the user out of this browser
+func (m *Manager) Clear(w http.ResponseWriter) {
+	cookie := m.cookie("")
+	cookie.MaxAge = -1
+	http.SetCookie(w, cookie)
+}
+
+func (m *Manager) writeCookie(w http.ResponseWriter, tokenString string, exp time.Time) error {
+	value := tokenString
+	if m.EncryptionKey != nil {
+		var err error
+		if value, err = fernet.Encrypt(m.EncryptionKey, []byte(tokenString)); err != nil {
+			return err
+		}
+	}
+	cookie := m.cookie(value)
+	cookie.Expires = exp
+	if len(cookie.String()) > maxCookieSize {
+		return ErrCookieTooLarge
+	}
+	http.SetCookie(w, cookie)
+	return nil
+}
+
+func (m *Manager) cookie(value string) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     m.cookieName(),
+		Value:    value,
+		Path:     m.Path,
+		Domain:   m.Domain,
+		Secure:   !m.Insecure,
+		HttpOnly: true,
+		SameSite: m.SameSite,
+	}
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+	return cookie
+}
+
+func (m *Manager) cookieName() string {
+	if m.CookieName == "" {
+		return DefaultCookieName
+	}
+	return m.CookieName
+}
+
+func (m *Manager) keyFunc(*jwt.Token) (interface{}, error) {
+	if m.VerifyKey != nil {
+		return m.VerifyKey, nil
+	}
+	return m.Key, nil
+}
@@ -0,0 +1,144 @@
+package sessions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var testKey = []byte("secret")
+
+type userClaims struct {
+	Claims
+	Name string `json:"name"`
+}
+
+// Returns a request carrying the cookies set on w
+func nextRequest(w *httptest.ResponseRecorder) *http.Request {
+	r := httptest.NewRequest("GET", "/", nil)
+	for _, c := range w.Result().Cookies() {
+		r.AddCookie(c)
+	}
+	return r
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	for _, encrypted := range []bool{false, true} {
+		m := New(jwt.SigningMethodHS256, testKey)
+		if encrypted {
+			m.EncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+		}
+		claims := &userClaims{Name: "Alice"}
+		claims.Subject = "alice"
+		w := httptest.NewRecorder()
+		if err := m.Save(w, claims); err != nil {
+			t.Fatal(err)
+		}
+
+		cookie := w.Result().Cookies()[0]
+		if cookie.Name != DefaultCookieName || !cookie.Secure || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/" {
+			t.Errorf("Unexpected cookie attributes: %+v", cookie)
+		}
+		if hidden := !strings.HasPrefix(cookie.Value, "eyJ"); hidden != encrypted {
+			t.Errorf("Expected encrypted=%v, got cookie %q", encrypted, cookie.Value)
+		}
+
+		loaded := &userClaims{}
+		if _, err := m.Load(nextRequest(w), loaded); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if loaded.Subject != "alice" || loaded.Name != "Alice" || loaded.Id == "" || loaded.ExpiresAt != now.Add(DefaultLifetime).Unix() {
+			t.Errorf("Unexpected claims: %+v", loaded)
+		}
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	m := New(jwt.SigningMethodHS256, testKey)
+	if _, err := m.Load(httptest.NewRequest("GET", "/", nil), &Claims{}); err != ErrNoSession {
+		t.Errorf("Expected ErrNoSession, got %v", err)
+	}
+
+	var tests = []struct {
+		name  string
+		value string
+	}{
+		{"forged", mustSign(t, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()}, []byte("other"))},
+		{"no exp", mustSign(t, jwt.MapClaims{"sub": "alice"}, testKey)},
+		{"expired", mustSign(t, jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}, testKey)},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: test.value})
+		if _, err := m.Load(r, &Claims{}); err == nil {
+			t.Errorf("[%v] Expected the session to be rejected", test.name)
+		}
+	}
+}
+
+func TestRotate(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	m := New(jwt.SigningMethodHS256, testKey)
+	m.Lifetime = time.Hour
+	m.EncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+	w := httptest.NewRecorder()
+	if err := m.Save(w, &Claims{}); err != nil {
+		t.Fatal(err)
+	}
+	r := nextRequest(w)
+
+	token, err := m.Load(r, &Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated, err := m.Rotate(httptest.NewRecorder(), token); rotated || err != nil {
+		t.Errorf("Expected a fresh session to be kept: %v %v", rotated, err)
+	}
+
+	now = now.Add(55 * time.Minute)
+	claims := &Claims{}
+	if token, err = m.Load(r, claims); err != nil {
+		t.Fatal(err)
+	}
+	w = httptest.NewRecorder()
+	if rotated, err := m.Rotate(w, token); !rotated || err != nil {
+		t.Fatalf("Expected the session to be rotated: %v %v", rotated, err)
+	}
+	rotated := &Claims{}
+	if _, err := m.Load(nextRequest(w), rotated); err != nil {
+		t.Fatal(err)
+	}
+	if rotated.Id != claims.Id || rotated.ExpiresAt != now.Add(time.Hour).Unix() {
+		t.Errorf("Unexpected rotated claims: %+v", rotated)
+	}
+}
+
+func TestClear(t *testing.T) {
+	m := New(jwt.SigningMethodHS256, testKey)
+	m.CookieName = "sid"
+	w := httptest.NewRecorder()
+	m.Clear(w)
+	cookie := w.Result().Cookies()[0]
+	if cookie.Name != "sid" || cookie.MaxAge >= 0 || cookie.Value != "" {
+		t.Errorf("Unexpected cookie: %+v", cookie)
+	}
+}
+
+func mustSign(t *testing.T, claims jwt.Claims, key []byte) string {
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -0,0 +1,20 @@
+// Pre-signed URLs for downloads and uploads, in the manner of S3 presigned
+// URLs.
+//
+// A Signer adds a short-lived token to a URL, in the sig query parameter.
+// The token names the HTTP method and path it grants, and optionally a hash
+// of the rest of the query string, so the link cannot be pointed at another
+// file or reused to upload:
+//
+//	signer := signedurl.NewSigner(jwt.SigningMethodHS256, key)
+//	link, err := signer.Sign("GET", &url.URL{Path: "/files/report.pdf"}, 10*time.Minute)
+//
+// Verifier checks the token on incoming requests, and its Handler rejects
+// requests without a valid one:
+//
+//	verifier := signedurl.NewVerifier(keyFunc)
+//	http.Handle("/files/", verifier.Handler(filesHandler))
+//
+// Tokens have the typ "url+jwt", so access tokens and other JWTs signed with
+// the same key are not accepted as signed URLs.
+package signedurl
@@ -0,0 +1,163 @@
+package signedurl
+
+import (
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	DefaultParameter = "sig"
+	DefaultLifetime  = 15 * time.Minute
+
+	// The typ header of signed URL tokens
+	TokenType = "url+jwt"
+)
+
+var (
+	ErrNoSignature   = errors.New("URL is not signed")
+	ErrURLMismatch   = errors.New("signed URL does not match the request")
+	ErrMissingMethod = errors.New("signed URL method is not set")
+)
+
+// The claims of a signed URL token
+type Claims struct {
+	jwt.StandardClaims
+	Method    string `json:"htm"`          // The HTTP method the URL may be used with
+	Path      string `json:"htu"`          // The escaped path the URL grants
+	QueryHash string `json:"qh,omitempty"` // SHA-256 of the canonical query string, if it is bound
+}
+
+// Signs URLs.  Configure it before use; it is then safe for concurrent use.
+type Signer struct {
+	Method    jwt.SigningMethod
+	Key       interface{}
+	Parameter string // Query parameter carrying the token.  Defaults to DefaultParameter
+
+	// Leave the query string unsigned, so parameters can be added to the URL
+	// after it is signed.  By default the whole query string is bound.
+	UnsignedQuery bool
+}
+
+// Create a Signer signing URLs with method and key
+func NewSigner(method jwt.SigningMethod, key interface{}) *Signer {
+	return &Signer{Method: method, Key: key}
+}
+
+// Returns a copy of u, a URL or a path with query string, signed for use
+// with method until lifetime from now.  A lifetime of zero means
+// DefaultLifetime.
+func (s *Signer) Sign(method string, u *url.URL, lifetime time.Duration) (*url.URL, error) {
+	if method == "" {
+		return nil, ErrMissingMethod
+	}
+	if lifetime == 0 {
+		lifetime = DefaultLifetime
+	}
+	now := jwt.TimeFunc()
+	claims := &Claims{Method: strings.ToUpper(method), Path: u.EscapedPath()}
+	claims.IssuedAt = now.Unix()
+	claims.ExpiresAt = now.Add(lifetime).Unix()
+
+	query := u.Query()
+	query.Del(parameter(s.Parameter))
+	if !s.UnsignedQuery {
+		claims.QueryHash = queryHash(query)
+	}
+
+	token := jwt.NewWithClaims(s.Method, claims)
+	token.SetType(TokenType)
+	tokenString, err := token.SignedString(s.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := *u
+	query.Set(parameter(s.Parameter), tokenString)
+	signed.RawQuery = query.Encode()
+	return &signed, nil
+}
+
+// Checks signed URLs on incoming requests.  Configure it before use; it is
+// then safe for concurrent use.
+type Verifier struct {
+	KeyFunc   jwt.Keyfunc
+	Parameter string      // Query parameter carrying the token.  Defaults to DefaultParameter
+	Parser    *jwt.Parser // Options for checking tokens.  Defaults to NewParser(); exp and typ are always required
+
+	// Responds to rejected requests.  Defaults to 403 Forbidden.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// Create a Verifier checking tokens with the keys keyFunc returns
+func NewVerifier(keyFunc jwt.Keyfunc) *Verifier {
+	return &Verifier{KeyFunc: keyFunc}
+}
+
+// Check r was made with a valid signed URL for its method, path and, if it
+// was signed, query string.  Returns the token's claims.
+func (v *Verifier) Verify(r *http.Request) (*Claims, error) {
+	query := r.URL.Query()
+	tokenString := query.Get(parameter(v.Parameter))
+	if tokenString == "" {
+		return nil, ErrNoSignature
+	}
+
+	parser := jwt.NewParser()
+	if v.Parser != nil {
+		copied := *v.Parser
+		parser = &copied
+	}
+	validators := parser.Validators
+	if validators == nil {
+		validators = jwt.DefaultValidators()
+	}
+	parser.Validators = append(validators[:len(validators):len(validators)], jwt.ExpirationValidator(true))
+	parser.RequiredType = TokenType
+
+	claims := &Claims{}
+	if _, err := parser.ParseWithClaims(tokenString, claims, v.KeyFunc); err != nil {
+		return nil, err
+	}
+
+	query.Del(parameter(v.Parameter))
+	if claims.Method != r.Method || claims.Path != r.URL.EscapedPath() ||
+		(claims.QueryHash != "" && claims.QueryHash != queryHash(query)) {
+		return nil, ErrURLMismatch
+	}
+	return claims, nil
+}
+
+// Wrap next, rejecting requests that fail Verify
+func (v *Verifier) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := v.Verify(r); err != nil {
+			if v.ErrorHandler != nil {
+				v.ErrorHandler(w, r, err)
+			} else {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			}
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Hash the query string in a canonical form, with keys sorted, so reordering
+// parameters does not invalidate the URL
+func queryHash(query url.Values) string {
+	sum := sha256.Sum256([]byte(query.Encode()))
+	return jwt.EncodeSegment(sum[:])
+}
+
+func parameter(name string) string {
+	if name == "" {
+		return DefaultParameter
+	}
+	return name
+}
@@ -0,0 +1,83 @@
+package signedurl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var testKey = []byte("secret")
+
+func keyFunc(*jwt.Token) (interface{}, error) { return testKey, nil }
+
+func TestSignAndVerify(t *testing.T) {
+	signer := NewSigner(jwt.SigningMethodHS256, testKey)
+	link, err := signer.Sign("get", &url.URL{Path: "/files/a b.pdf", RawQuery: "version=2&download=1"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unbound := NewSigner(jwt.SigningMethodHS256, testKey)
+	unbound.UnsignedQuery = true
+	unboundLink, err := unbound.Sign("PUT", &url.URL{Path: "/uploads/x"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := link.Query().Get(DefaultParameter)
+	accessToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{Method: "GET", Path: "/files/a%20b.pdf"}).SignedString(testKey)
+
+	var tests = []struct {
+		name   string
+		method string
+		target string
+		err    bool
+	}{
+		{"valid", "GET", link.String(), false},
+		{"reordered query", "GET", "/files/a%20b.pdf?download=1&sig=" + sig + "&version=2", false},
+		{"unsigned query", "PUT", unboundLink.String() + "&part=3", false},
+		{"wrong method", "POST", link.String(), true},
+		{"wrong path", "GET", "/files/other.pdf?download=1&version=2&sig=" + sig, true},
+		{"changed query", "GET", "/files/a%20b.pdf?download=1&version=3&sig=" + sig, true},
+		{"extra query", "GET", link.String() + "&x=1", true},
+		{"unsigned", "GET", "/files/a%20b.pdf", true},
+		{"not a URL token", "GET", "/files/a%20b.pdf?sig=" + accessToken, true},
+	}
+	verifier := NewVerifier(keyFunc)
+	handler := verifier.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(test.method, test.target, nil))
+		if rejected := w.Code == http.StatusForbidden; rejected != test.err {
+			t.Errorf("[%v] Expected rejected=%v, got status %v", test.name, test.err, w.Code)
+		}
+	}
+
+	claims, err := verifier.Verify(httptest.NewRequest("GET", link.String(), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Method != "GET" || claims.Path != "/files/a%20b.pdf" || claims.ExpiresAt-claims.IssuedAt != 60 {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	link, err := NewSigner(jwt.SigningMethodHS256, testKey).Sign("GET", &url.URL{Path: "/files/a"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(DefaultLifetime + time.Second)
+	if _, err := NewVerifier(keyFunc).Verify(httptest.NewRequest("GET", link.String(), nil)); err == nil {
+		t.Errorf("Expected an expired link to be rejected")
+	}
+	if _, err := NewSigner(jwt.SigningMethodHS256, testKey).Sign("", &url.URL{Path: "/"}, 0); err != ErrMissingMethod {
+		t.Errorf("Expected ErrMissingMethod, got %v", err)
+	}
+}
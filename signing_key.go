@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"errors"
+)
+
+var (
+	ErrSigningKeyMethodMismatch = errors.New("key may not be used with this signing method")
+)
+
+// A key together with its metadata.  When a *SigningKey is passed to
+// SignedString, the kid header is set from ID and Key is handed to the signing
+// method.  A Keyfunc may also return a *SigningKey, in which case Method (if set)
+// must match the token's signing method.
+type SigningKey struct {
+	Key    interface{}   // The key material, as expected by the signing method
+	ID     string        // Key ID, used as the kid header
+	Method SigningMethod // If set, the only signing method this key may be used with
+}
+
+// Check the key may be used with method
+func (k *SigningKey) allows(method SigningMethod) bool {
+	return k.Method == nil || (method != nil && k.Method.Alg() == method.Alg())
+}
+
+// If key is a SigningKey, set the kid header and return the underlying key
+func (t *Token) applySigningKey(key interface{}) (interface{}, error) {
+	sk, ok := signingKey(key)
+	if !ok {
+		return key, nil
+	}
+	if !sk.allows(t.Method) {
+		return nil, ErrSigningKeyMethodMismatch
+	}
+	if sk.ID != "" {
+		if t.Header == nil {
+			t.Header = map[string]interface{}{}
+		}
+		t.Header["kid"] = sk.ID
+	}
+	return sk.Key, nil
+}
+
+// If key is a SigningKey, check it may be used with method and return the underlying key
+func unwrapSigningKey(method SigningMethod, key interface{}) (interface{}, error) {
+	sk, ok := signingKey(key)
+	if !ok {
+		return key, nil
+	}
+	if !sk.allows(method) {
+		return nil, ErrSigningKeyMethodMismatch
+	}
+	return sk.Key, nil
+}
+
+func signingKey(key interface{}) (*SigningKey, bool) {
+	switch k := key.(type) {
+	case *SigningKey:
+		return k, k != nil
+	case SigningKey:
+		return &k, true
+	}
+	return nil, false
+}
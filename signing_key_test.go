@@ -0,0 +1,43 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func TestSigningKey(t *testing.T) {
+	privateKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+	key := &jwt.SigningKey{Key: privateKey, ID: "2024-key", Method: jwt.SigningMethodRS256}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"foo": "bar"})
+	tokenString, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Error signing with SigningKey: %v", err)
+	}
+
+	parsed, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return &jwt.SigningKey{Key: jwtTestDefaultKey, ID: "2024-key", Method: jwt.SigningMethodRS256}, nil
+	})
+	if err != nil || !parsed.Valid {
+		t.Fatalf("Error verifying with SigningKey: %v", err)
+	}
+	if parsed.Header["kid"] != "2024-key" {
+		t.Errorf("kid header not set: %v", parsed.Header)
+	}
+}
+
+func TestSigningKeyMethodMismatch(t *testing.T) {
+	key := &jwt.SigningKey{Key: hmacTestKey, ID: "hmac", Method: jwt.SigningMethodHS512}
+
+	if _, err := jwt.New(jwt.SigningMethodHS256).SignedString(key); err != jwt.ErrSigningKeyMethodMismatch {
+		t.Errorf("Expected ErrSigningKeyMethodMismatch, got %v", err)
+	}
+
+	tokenString, _ := jwt.New(jwt.SigningMethodHS256).SignedString(hmacTestKey)
+	_, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return key, nil })
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Inner != jwt.ErrSigningKeyMethodMismatch {
+		t.Errorf("Expected ErrSigningKeyMethodMismatch, got %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 )
 
@@ -16,11 +19,38 @@ type SigningMethod interface {
 
 // Register the "alg" name and a factory function for signing method.
 // This is typically done during init() in the method's implementation
+//
+// Registering the same type of method for an alg twice is a no-op, so
+// factories may return a new instance on each call.  Registering a method of
+// a different type for an alg that is already registered panics, so two
+// packages can't silently replace each other's methods; use
+// UnregisterSigningMethod first to replace one deliberately.
 func RegisterSigningMethod(alg string, f func() SigningMethod) {
+	method := f()
+
+	signingMethodLock.Lock()
+	existing, ok := signingMethods[alg]
+	if !ok {
+		signingMethods[alg] = f
+	}
+	signingMethodLock.Unlock()
+
+	// Factories are called without the lock, as they may register methods too
+	if ok {
+		if reflect.TypeOf(existing()) != reflect.TypeOf(method) {
+			panic(fmt.Sprintf("jwt: a different signing method is already registered for alg %v", alg))
+		}
+	}
+}
+
+// Remove the signing method registered for alg.  Tokens using this alg will
+// no longer be parsed.  This can be used to globally disable algorithms
+// your application should never accept.
+func UnregisterSigningMethod(alg string) {
 	signingMethodLock.Lock()
 	defer signingMethodLock.Unlock()
 
-	signingMethods[alg] = f
+	delete(signingMethods, alg)
 }
 
 // Get a signing method from an "alg" string
@@ -33,3 +63,15 @@ func GetSigningMethod(alg string) (method SigningMethod) {
 	}
 	return
 }
+
+// Returns the "alg" names of all registered signing methods, in sorted order
+func GetAlgorithms() (algs []string) {
+	signingMethodLock.RLock()
+	defer signingMethodLock.RUnlock()
+
+	for alg := range signingMethods {
+		algs = append(algs, alg)
+	}
+	sort.Strings(algs)
+	return
+}
@@ -0,0 +1,81 @@
+package jwt_test
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestGetAlgorithms(t *testing.T) {
+	algs := jwt.GetAlgorithms()
+	for _, expected := range []string{"ES256", "HS256", "PS256", "RS256", "none"} {
+		var found bool
+		for _, alg := range algs {
+			if alg == expected {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected %v to be registered: %v", expected, algs)
+		}
+	}
+}
+
+// An HMAC method of its own type, to conflict with SigningMethodHMAC
+type otherHMAC struct {
+	*jwt.SigningMethodHMAC
+}
+
+func TestRegisterSigningMethod(t *testing.T) {
+	// Re-registering the same type of method is fine, even a new instance
+	jwt.RegisterSigningMethod("HS256", func() jwt.SigningMethod { return jwt.SigningMethodHS256 })
+	jwt.RegisterSigningMethod("HS256", func() jwt.SigningMethod {
+		return &jwt.SigningMethodHMAC{Name: "HS256", Hash: crypto.SHA256}
+	})
+	if jwt.GetSigningMethod("HS256") != jwt.SigningMethodHS256 {
+		t.Errorf("Re-registration replaced HS256")
+	}
+
+	// A factory returning a new instance on each call may be registered twice
+	newMethod := func() jwt.SigningMethod { return &jwt.SigningMethodHMAC{Name: "HS-new", Hash: crypto.SHA256} }
+	jwt.RegisterSigningMethod("HS-new", newMethod)
+	defer jwt.UnregisterSigningMethod("HS-new")
+	jwt.RegisterSigningMethod("HS-new", newMethod)
+
+	// A different type of method is a conflict
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected conflicting registration to panic")
+			}
+		}()
+		jwt.RegisterSigningMethod("HS256", func() jwt.SigningMethod { return otherHMAC{jwt.SigningMethodHS256} })
+	}()
+	if jwt.GetSigningMethod("HS256") != jwt.SigningMethodHS256 {
+		t.Errorf("Conflicting registration replaced HS256")
+	}
+
+	// Factories may register methods themselves
+	jwt.RegisterSigningMethod("HS-nested", func() jwt.SigningMethod {
+		jwt.RegisterSigningMethod("HS256", func() jwt.SigningMethod { return jwt.SigningMethodHS256 })
+		return &jwt.SigningMethodHMAC{Name: "HS-nested", Hash: crypto.SHA256}
+	})
+	jwt.UnregisterSigningMethod("HS-nested")
+}
+
+func TestUnregisterSigningMethod(t *testing.T) {
+	method := &jwt.SigningMethodHMAC{Name: "HS-test", Hash: crypto.SHA256}
+	jwt.RegisterSigningMethod(method.Alg(), func() jwt.SigningMethod { return method })
+	tokenString, _ := jwt.New(method).SignedString(hmacTestKey)
+
+	jwt.UnregisterSigningMethod(method.Alg())
+	if jwt.GetSigningMethod(method.Alg()) != nil {
+		t.Errorf("Signing method still registered")
+	}
+
+	_, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors != jwt.ValidationErrorUnverifiable {
+		t.Errorf("Expected unverifiable error, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrTokenNotValid = errors.New("token has not been validated")
+	ErrNoLifetime    = errors.New("token lifetime is unknown: set Lifetime or include iat")
+	ErrNoIssuedAt    = errors.New("token has no iat to bound its session by MaxLifetime")
+)
+
+// Refresh policy for sliding sessions.  A validated token that is close to
+// expiry is re-signed with a later exp; its other claims, including the
+// original iat, are kept as they are.
+type SlidingExpiration struct {
+	// Lifetime of refreshed tokens, counted from the refresh.  If zero, the
+	// token's own lifetime (exp - iat) is used.
+	Lifetime time.Duration
+
+	// Refresh once less than this fraction of the token's lifetime remains.
+	// Defaults to 0.2.
+	RefreshFraction float64
+
+	// If non-zero, exp is never extended beyond iat + MaxLifetime, bounding
+	// how long a session can be kept alive.  Tokens without iat are then
+	// not refreshed.
+	MaxLifetime time.Duration
+}
+
+// Reports whether token is unexpired but within the refresh window
+func (s SlidingExpiration) NeedsRefresh(token *Token) bool {
+	exp, ok := token.ExpiresAt()
+	if !ok {
+		return false
+	}
+	now := TimeFunc()
+	if !now.Before(exp) {
+		return false
+	}
+	lifetime, err := s.tokenLifetime(token, exp)
+	if err != nil {
+		return false
+	}
+	fraction := s.RefreshFraction
+	if fraction == 0 {
+		fraction = 0.2
+	}
+	return exp.Sub(now) < time.Duration(float64(lifetime)*fraction)
+}
+
+// Re-sign token with an extended exp if it needs a refresh.  The token must
+// have been validated by a Parser.  Otherwise, or if MaxLifetime leaves no
+// room to extend it, token.Raw is returned with refreshed false.  With a
+// MaxLifetime, tokens without iat fail with ErrNoIssuedAt.
+func (s SlidingExpiration) Refresh(token *Token, key interface{}) (tokenString string, refreshed bool, err error) {
+	if !token.Valid {
+		return "", false, ErrTokenNotValid
+	}
+	if !s.NeedsRefresh(token) {
+		return token.Raw, false, nil
+	}
+
+	exp, _ := token.ExpiresAt()
+	lifetime := s.Lifetime
+	if lifetime == 0 {
+		if lifetime, err = s.tokenLifetime(token, exp); err != nil {
+			return "", false, err
+		}
+	}
+	newExp := TimeFunc().Add(lifetime)
+	if s.MaxLifetime != 0 {
+		iat, ok, _ := timeClaim(token.Claims, "iat")
+		if !ok {
+			return "", false, ErrNoIssuedAt
+		}
+		if limit := time.Unix(iat, 0).Add(s.MaxLifetime); newExp.After(limit) {
+			newExp = limit
+		}
+	}
+	if newExp.Unix() <= exp.Unix() {
+		return token.Raw, false, nil
+	}
+
+	src, err := claimsToMap(token.Claims)
+	if err != nil {
+		return "", false, err
+	}
+	claims := make(MapClaims, len(src))
+	for k, v := range src {
+		claims[k] = v
+	}
+	claims["exp"] = newExp.Unix()
+
+	header := make(map[string]interface{}, len(token.Header))
+	for k, v := range token.Header {
+		header[k] = v
+	}
+	next := &Token{Header: header, Claims: claims, Method: token.Method, JSONCodec: token.JSONCodec}
+	if tokenString, err = next.SignedString(key); err != nil {
+		return "", false, err
+	}
+	return tokenString, true, nil
+}
+
+// The lifetime the refresh window is measured against: exp - iat, or
+// Lifetime if the token has no iat
+func (s SlidingExpiration) tokenLifetime(token *Token, exp time.Time) (time.Duration, error) {
+	if iat, ok, _ := timeClaim(token.Claims, "iat"); ok && iat < exp.Unix() {
+		return exp.Sub(time.Unix(iat, 0)), nil
+	}
+	if s.Lifetime > 0 {
+		return s.Lifetime, nil
+	}
+	return 0, ErrNoLifetime
+}
@@ -0,0 +1,90 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestSlidingExpiration(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	var tests = []struct {
+		name      string
+		policy    jwt.SlidingExpiration
+		iat, exp  int64
+		refreshed bool
+		newExp    int64
+	}{
+		{"fresh", jwt.SlidingExpiration{}, -600, 3000, false, 0},
+		{"in window", jwt.SlidingExpiration{}, -3000, 600, true, 3600},
+		{"custom fraction", jwt.SlidingExpiration{RefreshFraction: 0.5}, -1800, 1800, false, 0},
+		{"fixed lifetime", jwt.SlidingExpiration{Lifetime: time.Hour}, -3000, 600, true, 3600},
+		{"capped", jwt.SlidingExpiration{MaxLifetime: 2 * time.Hour}, -6000, 600, true, 1200},
+		{"cap reached", jwt.SlidingExpiration{MaxLifetime: time.Hour}, -3000, 600, false, 0},
+	}
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	for _, data := range tests {
+		claims := jwt.MapClaims{"sub": "user", "iat": now.Unix() + data.iat, "exp": now.Unix() + data.exp}
+		tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(hmacTestKey)
+		token, err := jwt.Parse(tokenString, keyFunc)
+		if err != nil {
+			t.Fatalf("[%v] Error while parsing token: %v", data.name, err)
+		}
+
+		refreshedString, refreshed, err := data.policy.Refresh(token, hmacTestKey)
+		if err != nil {
+			t.Errorf("[%v] Error while refreshing token: %v", data.name, err)
+			continue
+		}
+		if refreshed != data.refreshed {
+			t.Errorf("[%v] Expected refreshed %v, got %v", data.name, data.refreshed, refreshed)
+			continue
+		}
+		if !refreshed {
+			if refreshedString != tokenString {
+				t.Errorf("[%v] Expected the original token back", data.name)
+			}
+			continue
+		}
+
+		next, err := jwt.Parse(refreshedString, keyFunc)
+		if err != nil {
+			t.Errorf("[%v] Error while parsing refreshed token: %v", data.name, err)
+			continue
+		}
+		c := next.Claims.(jwt.MapClaims)
+		if exp, _ := next.ExpiresAt(); exp.Unix() != now.Unix()+data.newExp {
+			t.Errorf("[%v] Expected exp %v, got %v", data.name, now.Unix()+data.newExp, exp.Unix())
+		}
+		if c["iat"].(float64) != float64(now.Unix()+data.iat) || c["sub"] != "user" {
+			t.Errorf("[%v] Refresh changed other claims: %v", data.name, c)
+		}
+	}
+
+	// Without iat, MaxLifetime can't bound the session
+	claims := jwt.MapClaims{"sub": "user", "exp": now.Unix() + 600}
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(hmacTestKey)
+	token, err := jwt.Parse(tokenString, keyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := jwt.SlidingExpiration{Lifetime: time.Hour, MaxLifetime: 2 * time.Hour}
+	if _, refreshed, err := policy.Refresh(token, hmacTestKey); refreshed || err != jwt.ErrNoIssuedAt {
+		t.Errorf("Expected ErrNoIssuedAt, got %v %v", refreshed, err)
+	}
+	policy.MaxLifetime = 0
+	if _, refreshed, err := policy.Refresh(token, hmacTestKey); !refreshed || err != nil {
+		t.Errorf("Expected a refresh without MaxLifetime, got %v %v", refreshed, err)
+	}
+
+	// Unvalidated tokens are refused
+	token = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": now.Unix() + 1})
+	if _, _, err := (jwt.SlidingExpiration{}).Refresh(token, hmacTestKey); err != jwt.ErrTokenNotValid {
+		t.Errorf("Expected ErrTokenNotValid, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrMissingTenant = errors.New("token does not name a tenant")
+	ErrUnknownTenant = errors.New("token tenant is unknown")
+)
+
+// Chooses verification keys by tenant, for multi-tenant services where each
+// tenant has its own keys.  The tenant is read from a claim of the unverified
+// token, or from the prefix of its kid, and Resolve supplies a Keyfunc for
+// that tenant, such as one backed by the tenant's JWK Set.  Resolved tenants
+// are cached for TTL.  Safe for concurrent use.
+type TenantKeyResolver struct {
+	// The claim naming the tenant, such as "tid".  If empty, the tenant is the
+	// part of the kid header before KeyIDSeparator.
+	Claim string
+
+	// Separates the tenant from the rest of the kid.  Defaults to ":".
+	KeyIDSeparator string
+
+	// Returns the Keyfunc for tenant.  Return ErrUnknownTenant for tenants
+	// that don't exist.  Required
+	Resolve func(ctx context.Context, tenant string) (KeyfuncContext, error)
+
+	// How long a resolved tenant is cached.  Defaults to 5 minutes; negative
+	// disables caching.
+	TTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]tenantEntry
+}
+
+type tenantEntry struct {
+	keyFunc KeyfuncContext
+	expires time.Time
+}
+
+// Returns the tenant named by the unverified token
+func (r *TenantKeyResolver) Tenant(token *Token) (string, error) {
+	if r.Claim != "" {
+		claims, err := claimsToMap(token.Claims)
+		if err != nil {
+			return "", err
+		}
+		tenant, _ := claims[r.Claim].(string)
+		if tenant == "" {
+			return "", ErrMissingTenant
+		}
+		return tenant, nil
+	}
+	sep := r.KeyIDSeparator
+	if sep == "" {
+		sep = ":"
+	}
+	kid, _ := token.Header["kid"].(string)
+	i := strings.Index(kid, sep)
+	if i <= 0 {
+		return "", ErrMissingTenant
+	}
+	return kid[:i], nil
+}
+
+// A Keyfunc returning the key from the token's tenant's Keyfunc
+func (r *TenantKeyResolver) Keyfunc(token *Token) (interface{}, error) {
+	return r.KeyfuncContext(context.Background(), token)
+}
+
+// Like Keyfunc, passing ctx to Resolve and the tenant's Keyfunc
+func (r *TenantKeyResolver) KeyfuncContext(ctx context.Context, token *Token) (interface{}, error) {
+	tenant, err := r.Tenant(token)
+	if err != nil {
+		return nil, err
+	}
+	keyFunc, err := r.keyfunc(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return keyFunc(ctx, token)
+}
+
+func (r *TenantKeyResolver) keyfunc(ctx context.Context, tenant string) (KeyfuncContext, error) {
+	now := TimeFunc()
+	r.mu.Lock()
+	entry, ok := r.cache[tenant]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.keyFunc, nil
+	}
+
+	keyFunc, err := r.Resolve(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	if keyFunc == nil {
+		return nil, ErrUnknownTenant
+	}
+	ttl := r.TTL
+	if ttl == 0 {
+		ttl = 5 * time.Minute
+	}
+	if ttl > 0 {
+		r.mu.Lock()
+		if r.cache == nil {
+			r.cache = make(map[string]tenantEntry)
+		}
+		// Drop expired tenants so the cache only grows with active ones
+		for k, e := range r.cache {
+			if !now.Before(e.expires) {
+				delete(r.cache, k)
+			}
+		}
+		r.cache[tenant] = tenantEntry{keyFunc, now.Add(ttl)}
+		r.mu.Unlock()
+	}
+	return keyFunc, nil
+}
+
+// Forget the cached Keyfunc for tenant, for example after its keys change
+func (r *TenantKeyResolver) Invalidate(tenant string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cache, tenant)
+}
@@ -0,0 +1,90 @@
+package jwt_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestTenantKeyResolver(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	keys := map[string][]byte{"acme": []byte("acme-secret"), "globex": []byte("globex-secret")}
+	resolved := map[string]int{}
+	resolve := func(ctx context.Context, tenant string) (jwt.KeyfuncContext, error) {
+		resolved[tenant]++
+		key, ok := keys[tenant]
+		if !ok {
+			return nil, jwt.ErrUnknownTenant
+		}
+		return func(context.Context, *jwt.Token) (interface{}, error) { return key, nil }, nil
+	}
+
+	sign := func(key []byte, kid string, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		s, _ := token.SignedString(key)
+		return s
+	}
+
+	var tests = []struct {
+		name     string
+		resolver *jwt.TenantKeyResolver
+		token    string
+		valid    bool
+	}{
+		{"claim", &jwt.TenantKeyResolver{Claim: "tid", Resolve: resolve}, sign(keys["acme"], "", jwt.MapClaims{"tid": "acme"}), true},
+		{"claim with another tenant's key", &jwt.TenantKeyResolver{Claim: "tid", Resolve: resolve}, sign(keys["acme"], "", jwt.MapClaims{"tid": "globex"}), false},
+		{"missing claim", &jwt.TenantKeyResolver{Claim: "tid", Resolve: resolve}, sign(keys["acme"], "", jwt.MapClaims{}), false},
+		{"unknown tenant", &jwt.TenantKeyResolver{Claim: "tid", Resolve: resolve}, sign(keys["acme"], "", jwt.MapClaims{"tid": "initech"}), false},
+		{"kid prefix", &jwt.TenantKeyResolver{Resolve: resolve}, sign(keys["globex"], "globex:2024", jwt.MapClaims{}), true},
+		{"kid separator", &jwt.TenantKeyResolver{KeyIDSeparator: "/", Resolve: resolve}, sign(keys["globex"], "globex/2024", jwt.MapClaims{}), true},
+		{"kid without tenant", &jwt.TenantKeyResolver{Resolve: resolve}, sign(keys["globex"], "2024", jwt.MapClaims{}), false},
+	}
+	for _, data := range tests {
+		_, err := jwt.Parse(data.token, data.resolver.Keyfunc)
+		if (err == nil) != data.valid {
+			t.Errorf("[%v] Unexpected result: %v", data.name, err)
+		}
+	}
+}
+
+func TestTenantKeyResolverCache(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	calls := 0
+	resolver := &jwt.TenantKeyResolver{Claim: "tid", TTL: time.Minute, Resolve: func(ctx context.Context, tenant string) (jwt.KeyfuncContext, error) {
+		calls++
+		return func(context.Context, *jwt.Token) (interface{}, error) { return hmacTestKey, nil }, nil
+	}}
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"tid": "acme"}).SignedString(hmacTestKey)
+
+	parse := func() {
+		if _, err := jwt.Parse(tokenString, resolver.Keyfunc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	parse()
+	parse()
+	if calls != 1 {
+		t.Errorf("Expected the tenant to be cached, resolved %v times", calls)
+	}
+	now = now.Add(2 * time.Minute)
+	parse()
+	if calls != 2 {
+		t.Errorf("Expected the cache to expire, resolved %v times", calls)
+	}
+	resolver.Invalidate("acme")
+	parse()
+	if calls != 3 {
+		t.Errorf("Expected Invalidate to clear the cache, resolved %v times", calls)
+	}
+}
@@ -0,0 +1,27 @@
+package jwt_test
+
+import (
+	"go/build"
+	"testing"
+)
+
+// The core package must not pull in packages that WebAssembly and TinyGo
+// builds can't use
+func TestPortableImports(t *testing.T) {
+	forbidden := map[string]bool{"net/http": true, "crypto/tls": true, "net/mail": true, "os/exec": true}
+
+	for _, tags := range [][]string{nil, {"tinygo"}} {
+		ctx := build.Default
+		ctx.GOOS, ctx.GOARCH = "js", "wasm"
+		ctx.BuildTags = tags
+		pkg, err := ctx.ImportDir(".", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, imp := range pkg.Imports {
+			if imp == "net/http" || tags != nil && forbidden[imp] {
+				t.Errorf("[%v] Package imports %v", tags, imp)
+			}
+		}
+	}
+}
@@ -1,9 +1,9 @@
 package jwt
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
-	"encoding/json"
-	"strings"
 	"time"
 )
 
@@ -27,6 +27,10 @@ type Token struct {
 	Claims    Claims                 // The second segment of the token
 	Signature string                 // The third segment of the token.  Populated when you Parse a token
 	Valid     bool                   // Is the token valid?  Populated when you Parse/Verify a token
+	JSONCodec JSONCodec              // JSON implementation used for signing.  Defaults to DefaultJSONCodec
+	RawHeader []byte                 // The decoded JSON of the first segment.  Populated when you Parse a token
+	RawClaims []byte                 // The decoded JSON of the second segment.  Populated when you Parse a token
+	MaxSize   int                    // If non-zero, signing fails with a *TokenSizeError when the token would be longer
 }
 
 // Create a new Token.  Takes a signing method
@@ -45,17 +49,10 @@ func NewWithClaims(method SigningMethod, claims Claims) *Token {
 	}
 }
 
-// Get the complete, signed token
+// Get the complete, signed token.  key may be a *SigningKey, in which
+// case the kid header is set from the key's ID.
 func (t *Token) SignedString(key interface{}) (string, error) {
-	var sig, sstr string
-	var err error
-	if sstr, err = t.SigningString(); err != nil {
-		return "", err
-	}
-	if sig, err = t.Method.Sign(sstr, key); err != nil {
-		return "", err
-	}
-	return strings.Join([]string{sstr, sig}, "."), nil
+	return t.SignedStringWithContext(context.Background(), key)
 }
 
 // Generate the signing string.  This is the
@@ -63,23 +60,34 @@ func (t *Token) SignedString(key interface{}) (string, error) {
 // need this for something special, just go straight for
 // the SignedString.
 func (t *Token) SigningString() (string, error) {
-	var err error
-	parts := make([]string, 2)
-	for i, _ := range parts {
-		var jsonValue []byte
-		if i == 0 {
-			if jsonValue, err = json.Marshal(t.Header); err != nil {
-				return "", err
-			}
-		} else {
-			if jsonValue, err = json.Marshal(t.Claims); err != nil {
-				return "", err
-			}
-		}
+	header, err := t.jsonCodec().Marshal(t.Header)
+	if err != nil {
+		return "", err
+	}
+	claims, err := t.jsonCodec().Marshal(t.Claims)
+	if err != nil {
+		return "", err
+	}
+	if claims, err = t.compressPayload(claims); err != nil {
+		return "", err
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
 
-		parts[i] = EncodeSegment(jsonValue)
+	b := appendEncodeSegment((*buf)[:0], header)
+	b = append(b, '.')
+	// RFC 7797: sign over the raw payload when b64 is false
+	if t.isUnencodedPayload() {
+		if bytes.IndexByte(claims, '.') != -1 {
+			return "", ErrUnencodedPayloadContainsPeriod
+		}
+		b = append(b, claims...)
+	} else {
+		b = appendEncodeSegment(b, claims)
 	}
-	return strings.Join(parts, "."), nil
+	*buf = b
+	return string(b), nil
 }
 
 // Parse, validate, and return a token.
@@ -93,16 +101,53 @@ func ParseWithClaims(tokenString string, claims Claims, keyFunc Keyfunc) (*Token
 	return new(Parser).ParseWithClaims(tokenString, claims, keyFunc)
 }
 
+// WARNING: Don't use this function unless you know what you're doing
+//
+// Decode the header and claims of a token without verifying its signature or
+// validating its claims.  Useful for routing decisions, such as picking the
+// tenant or issuer whose keys should verify the token, before a real Parse.
+// Nothing in the returned token can be trusted; token.Valid is always false.
+func ParseUnverified(tokenString string) (*Token, error) {
+	return ParseUnverifiedWithClaims(tokenString, MapClaims{})
+}
+
+// ParseUnverified, decoding the claims into claims
+func ParseUnverifiedWithClaims(tokenString string, claims Claims) (*Token, error) {
+	token, _, err := new(Parser).ParseUnverified(tokenString, claims)
+	return token, err
+}
+
 // Encode JWT specific base64url encoding with padding stripped
 func EncodeSegment(seg []byte) string {
-	return strings.TrimRight(base64.URLEncoding.EncodeToString(seg), "=")
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	*buf = appendEncodeSegment((*buf)[:0], seg)
+	return string(*buf)
 }
 
 // Decode JWT specific base64url encoding with padding stripped
 func DecodeSegment(seg string) ([]byte, error) {
-	if l := len(seg) % 4; l > 0 {
-		seg += strings.Repeat("=", 4-l)
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	// Restore the padding in the scratch buffer rather than concatenating.
+	// A single leftover character can never be valid, padded or not.
+	b := append((*buf)[:0], seg...)
+	switch len(seg) % 4 {
+	case 1:
+		return nil, base64.CorruptInputError(len(seg) - 1)
+	case 2:
+		b = append(b, "=="...)
+	case 3:
+		b = append(b, '=')
 	}
+	*buf = b
 
-	return base64.URLEncoding.DecodeString(seg)
+	decoded := make([]byte, base64.URLEncoding.DecodedLen(len(b)))
+	n, err := base64.URLEncoding.Decode(decoded, b)
+	if err != nil {
+		return nil, err
+	}
+	return decoded[:n], nil
 }
@@ -0,0 +1,60 @@
+package jwt
+
+import (
+	"errors"
+)
+
+const tokenBinaryVersion = 1
+
+var (
+	ErrTokenNotEncoded  = errors.New("token has no raw form; sign or parse it before marshaling")
+	ErrTokenBinaryValue = errors.New("invalid binary token encoding")
+)
+
+// Encode the parsed token and whether it was valid, so a verified token can be
+// cached in a store such as Redis and restored with UnmarshalBinary without
+// verifying it again.  Only the raw token and the Valid flag are stored; the
+// header and claims are decoded again on restore.
+func (t *Token) MarshalBinary() ([]byte, error) {
+	if t.Raw == "" {
+		return nil, ErrTokenNotEncoded
+	}
+	data := make([]byte, 2, 2+len(t.Raw))
+	data[0] = tokenBinaryVersion
+	if t.Valid {
+		data[1] = 1
+	}
+	return append(data, t.Raw...), nil
+}
+
+// Restore a token encoded by MarshalBinary.  The claims are decoded into
+// t.Claims if it is set, and into a MapClaims otherwise.  A token that was
+// valid stays valid only until its exp claim: once it has expired, Valid is
+// false and the expiry is returned as a *ValidationError.
+//
+// The signature is not checked again, so the encoded value must only come
+// from a store that untrusted parties cannot write to.
+func (t *Token) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != tokenBinaryVersion || data[1] > 1 {
+		return ErrTokenBinaryValue
+	}
+	claims := t.Claims
+	if claims == nil {
+		claims = MapClaims{}
+	}
+	token, parts, err := new(Parser).ParseUnverified(string(data[2:]), claims)
+	if err != nil {
+		return err
+	}
+	token.Signature = parts[2]
+	token.JSONCodec = t.JSONCodec
+	*t = *token
+
+	if data[1] == 1 {
+		if t.IsExpired(0) {
+			return NewValidationError("token is expired", ValidationErrorExpired)
+		}
+		t.Valid = true
+	}
+	return nil
+}
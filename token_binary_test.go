@@ -0,0 +1,93 @@
+package jwt_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestTokenBinary(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user", "exp": float64(now.Unix() + 60)}).SignedString(hmacTestKey)
+	parsed, err := jwt.Parse(tokenString, keyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := parsed.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &jwt.Token{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !restored.Valid || restored.Raw != tokenString || restored.Method != jwt.SigningMethodHS256 || restored.Signature != parsed.Signature {
+		t.Errorf("Unexpected restored token: %+v", restored)
+	}
+	if restored.Claims.(jwt.MapClaims)["sub"] != "user" {
+		t.Errorf("Unexpected claims: %v", restored.Claims)
+	}
+
+	// Decoding into caller-provided claims
+	standard := &jwt.Token{Claims: &jwt.StandardClaims{}}
+	if err := standard.UnmarshalBinary(data); err != nil || standard.Claims.(*jwt.StandardClaims).Subject != "user" {
+		t.Errorf("Unexpected struct claims: %v %v", standard.Claims, err)
+	}
+
+	// Invalid tokens stay invalid
+	invalid, _ := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return []byte("wrong"), nil })
+	data, _ = invalid.MarshalBinary()
+	restored = &jwt.Token{}
+	if err := restored.UnmarshalBinary(data); err != nil || restored.Valid {
+		t.Errorf("Expected an invalid token, got %v %v", restored.Valid, err)
+	}
+
+	// Expired on restore
+	data, _ = parsed.MarshalBinary()
+	now = now.Add(time.Hour)
+	restored = &jwt.Token{}
+	err = restored.UnmarshalBinary(data)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorExpired == 0 || restored.Valid {
+		t.Errorf("Expected an expiry error, got %v %v", restored.Valid, err)
+	}
+}
+
+func TestTokenBinaryErrors(t *testing.T) {
+	if _, err := jwt.New(jwt.SigningMethodHS256).MarshalBinary(); err != jwt.ErrTokenNotEncoded {
+		t.Errorf("Expected ErrTokenNotEncoded, got %v", err)
+	}
+	for _, data := range [][]byte{nil, {2, 0}, {1, 2}} {
+		if err := new(jwt.Token).UnmarshalBinary(data); err != jwt.ErrTokenBinaryValue {
+			t.Errorf("Expected ErrTokenBinaryValue for %v, got %v", data, err)
+		}
+	}
+	if err := new(jwt.Token).UnmarshalBinary(append([]byte{1, 1}, "not.a token"...)); err == nil {
+		t.Errorf("Expected an error for a malformed token")
+	}
+}
+
+func TestTokenGob(t *testing.T) {
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString(hmacTestKey)
+	parsed, _ := jwt.Parse(tokenString, keyFunc)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(parsed); err != nil {
+		t.Fatal(err)
+	}
+	var restored jwt.Token
+	if err := gob.NewDecoder(&buf).Decode(&restored); err != nil {
+		t.Fatal(err)
+	}
+	if !restored.Valid || restored.Raw != tokenString {
+		t.Errorf("Unexpected restored token: %+v", restored)
+	}
+}
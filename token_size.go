@@ -0,0 +1,132 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Signature size assumed for RSA methods when the key is not known, that of
+// a 2048 bit key
+const defaultRSASignatureSize = 256
+
+// A token would be longer than its MaxSize
+type TokenSizeError struct {
+	Size   int         // Length of the token
+	Max    int         // The budget it exceeds
+	Claims []ClaimSize // The claims, largest first
+}
+
+// The space one claim takes in the encoded payload
+type ClaimSize struct {
+	Name string
+	Size int
+}
+
+func (e *TokenSizeError) Error() string {
+	largest := make([]string, 0, 3)
+	for i := 0; i < len(e.Claims) && i < 3; i++ {
+		largest = append(largest, fmt.Sprintf("%v (%v bytes)", e.Claims[i].Name, e.Claims[i].Size))
+	}
+	msg := fmt.Sprintf("token is %v bytes, over the budget of %v", e.Size, e.Max)
+	if len(largest) > 0 {
+		msg += "; largest claims: " + strings.Join(largest, ", ")
+	}
+	return msg
+}
+
+// Estimate the length of a token with claims signed with method, with the
+// header NewWithClaims sets.  The header and payload are encoded exactly;
+// RSA signatures are assumed to be from a 2048 bit key.
+func EstimateSize(claims Claims, method SigningMethod) (int, error) {
+	return NewWithClaims(method, claims).EstimateSize(nil)
+}
+
+// Estimate the length of the token once signed with key, which may be nil.
+// The header and payload are encoded exactly, including a kid set from a
+// *SigningKey; the signature size comes from the method and, for RSA, key.
+func (t *Token) EstimateSize(key interface{}) (int, error) {
+	estimate := *t
+	estimate.Header = make(map[string]interface{}, len(t.Header))
+	for k, v := range t.Header {
+		estimate.Header[k] = v
+	}
+	key, err := estimate.applySigningKey(key)
+	if err != nil {
+		return 0, err
+	}
+	sstr, err := estimate.SigningString()
+	if err != nil {
+		return 0, err
+	}
+	return estimatedLength(t.Method, sstr, key), nil
+}
+
+// Length of sstr once signed by method with key
+func estimatedLength(method SigningMethod, sstr string, key interface{}) int {
+	return len(sstr) + 1 + base64.RawURLEncoding.EncodedLen(signatureSize(method, key))
+}
+
+// Size in bytes of a signature by method with key
+func signatureSize(method SigningMethod, key interface{}) int {
+	switch m := method.(type) {
+	case *SigningMethodHMAC:
+		return m.Hash.Size()
+	case *SigningMethodECDSA:
+		return 2 * m.KeySize
+	case *SigningMethodRSA, *SigningMethodRSAPSS:
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			return k.Size()
+		case crypto.Signer:
+			if pub, ok := k.Public().(*rsa.PublicKey); ok {
+				return pub.Size()
+			}
+		}
+		return defaultRSASignatureSize
+	case *signingMethodNone:
+		return 0
+	}
+	return defaultRSASignatureSize
+}
+
+// Check the token fits its MaxSize before it is signed
+func (t *Token) checkSize(sstr string, key interface{}) error {
+	if t.MaxSize <= 0 {
+		return nil
+	}
+	size := estimatedLength(t.Method, sstr, key)
+	if size <= t.MaxSize {
+		return nil
+	}
+	return &TokenSizeError{Size: size, Max: t.MaxSize, Claims: claimSizes(t.Claims)}
+}
+
+// Returns the encoded size of each claim, largest first
+func claimSizes(claims Claims) []ClaimSize {
+	m, err := claimsToMap(claims)
+	if err != nil {
+		return nil
+	}
+	sizes := make([]ClaimSize, 0, len(m))
+	for name, v := range m {
+		value, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		// "name":value, and a comma, as base64url
+		n := len(name) + 3 + len(value) + 1
+		sizes = append(sizes, ClaimSize{Name: name, Size: base64.RawURLEncoding.EncodedLen(n)})
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].Size != sizes[j].Size {
+			return sizes[i].Size > sizes[j].Size
+		}
+		return sizes[i].Name < sizes[j].Name
+	})
+	return sizes
+}
@@ -0,0 +1,59 @@
+package jwt_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/test"
+)
+
+func TestEstimateSize(t *testing.T) {
+	claims := jwt.MapClaims{"sub": "user", "groups": []string{"a", "b"}}
+	rsaKey := test.LoadRSAPrivateKeyFromDisk("test/sample_key")
+
+	for _, method := range []jwt.SigningMethod{jwt.SigningMethodHS256, jwt.SigningMethodHS512, jwt.SigningMethodRS256} {
+		var key interface{} = hmacTestKey
+		if method == jwt.SigningMethodRS256 {
+			key = rsaKey
+		}
+		token := jwt.NewWithClaims(method, claims)
+		tokenString, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size, err := token.EstimateSize(key)
+		if err != nil || size != len(tokenString) {
+			t.Errorf("[%v] Expected %v bytes, estimated %v %v", method.Alg(), len(tokenString), size, err)
+		}
+	}
+
+	size, err := jwt.EstimateSize(claims, jwt.SigningMethodHS256)
+	if tokenString := signHS256(claims); err != nil || size != len(tokenString) {
+		t.Errorf("Expected %v bytes, estimated %v %v", len(tokenString), size, err)
+	}
+	withKid, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).EstimateSize(&jwt.SigningKey{Key: hmacTestKey, ID: "2024-key"})
+	if withKid <= size {
+		t.Errorf("Expected the kid header to be counted")
+	}
+}
+
+func TestMaxSize(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user", "groups": strings.Repeat("g", 9000)})
+	token.MaxSize = 8192
+
+	_, err := token.SignedString(hmacTestKey)
+	var sizeErr *jwt.TokenSizeError
+	if !errors.As(err, &sizeErr) || sizeErr.Max != 8192 || sizeErr.Size <= 8192 || sizeErr.Claims[0].Name != "groups" {
+		t.Fatalf("Expected a TokenSizeError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "largest claims: groups") {
+		t.Errorf("Unexpected message: %v", err)
+	}
+
+	token.Claims = jwt.MapClaims{"sub": "user"}
+	if _, err := token.SignedString(hmacTestKey); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestSegmentRoundTrip(t *testing.T) {
+	for n := 0; n < 70; n++ {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(i * 37)
+		}
+		seg := jwt.EncodeSegment(data)
+		decoded, err := jwt.DecodeSegment(seg)
+		if err != nil || string(decoded) != string(data) {
+			t.Errorf("[%v] Round trip mismatch for %q: %v", n, seg, err)
+		}
+	}
+}
+
+func BenchmarkEncodeSegment(b *testing.B) {
+	data := []byte(`{"sub":"1234567890","name":"John Doe","admin":true,"iat":1516239022}`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		jwt.EncodeSegment(data)
+	}
+}
+
+func BenchmarkDecodeSegment(b *testing.B) {
+	seg := jwt.EncodeSegment([]byte(`{"sub":"1234567890","name":"John Doe","admin":true,"iat":1516239022}`))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := jwt.DecodeSegment(seg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSigningString(b *testing.B) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{Subject: "1234567890", Issuer: "issuer", IssuedAt: 1516239022})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := token.SigningString(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+// Server-side bookkeeping for issued tokens.
+//
+// A TokenStore records a Session for each token an auth service issues,
+// keyed by its jti, so the service can list a user's active sessions and
+// revoke one or all of them.  Validator rejects tokens whose session has been
+// revoked:
+//
+//	store := tokenstore.NewMemoryStore()
+//	session, _ := tokenstore.NewSession(token, r.UserAgent())
+//	store.Save(ctx, session)
+//
+//	parser := jwt.NewParser(jwt.WithValidator(tokenstore.Validator(store)))
+//
+// MemoryStore suits a single process.  RedisStore shares sessions between
+// instances; it depends on github.com/redis/go-redis/v9 and is only built
+// with the redis build tag.
+package tokenstore
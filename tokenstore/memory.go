@@ -0,0 +1,117 @@
+package tokenstore
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Create an empty in-memory TokenStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions:  make(map[string]*Session),
+		bySubject: make(map[string]map[string]bool),
+	}
+}
+
+// A TokenStore held in process memory.  Expired sessions are dropped as they
+// are encountered.  Safe for concurrent use.
+type MemoryStore struct {
+	mu        sync.Mutex
+	sessions  map[string]*Session
+	bySubject map[string]map[string]bool
+}
+
+func (s *MemoryStore) Save(ctx context.Context, session *Session) error {
+	copied := *session
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(session.ID)
+	s.sessions[session.ID] = &copied
+	ids := s.bySubject[session.Subject]
+	if ids == nil {
+		ids = make(map[string]bool)
+		s.bySubject[session.Subject] = ids
+	}
+	ids[session.ID] = true
+	return nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, id string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if session.Expired(jwt.TimeFunc()) {
+		s.remove(id)
+		return nil, ErrNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (s *MemoryStore) Sessions(ctx context.Context, subject string) ([]*Session, error) {
+	now := jwt.TimeFunc()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var sessions []*Session
+	for id := range s.bySubject[subject] {
+		session := s.sessions[id]
+		if session.Expired(now) {
+			s.remove(id)
+			continue
+		}
+		copied := *session
+		sessions = append(sessions, &copied)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].IssuedAt.Before(sessions[j].IssuedAt) })
+	return sessions, nil
+}
+
+func (s *MemoryStore) Revoke(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(id)
+	return nil
+}
+
+func (s *MemoryStore) RevokeAll(ctx context.Context, subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.bySubject[subject] {
+		delete(s.sessions, id)
+	}
+	delete(s.bySubject, subject)
+	return nil
+}
+
+// Drop sessions that have expired.  Call it periodically to bound memory use
+// when many sessions are never looked up again.
+func (s *MemoryStore) Prune() {
+	now := jwt.TimeFunc()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, session := range s.sessions {
+		if session.Expired(now) {
+			s.remove(id)
+		}
+	}
+}
+
+func (s *MemoryStore) remove(id string) {
+	session, ok := s.sessions[id]
+	if !ok {
+		return
+	}
+	delete(s.sessions, id)
+	if ids := s.bySubject[session.Subject]; ids != nil {
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(s.bySubject, session.Subject)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+//go:build redis
+
+package tokenstore
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// Create a TokenStore keeping sessions in Redis through client
+func NewRedisStore(client redis.Cmdable) *RedisStore {
+	return &RedisStore{Client: client, Prefix: "jwt:session:"}
+}
+
+// A TokenStore backed by Redis, for sharing sessions between instances.
+// Each session is a JSON string that Redis expires with the session, and each
+// subject has a sorted set of its session IDs scored by expiry.
+type RedisStore struct {
+	Client redis.Cmdable
+	Prefix string // Prepended to every key
+}
+
+func (s *RedisStore) sessionKey(id string) string {
+	return s.Prefix + "id:" + id
+}
+
+func (s *RedisStore) subjectKey(subject string) string {
+	return s.Prefix + "sub:" + subject
+}
+
+func (s *RedisStore) Save(ctx context.Context, session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	score := math.Inf(1)
+	var ttl time.Duration
+	if !session.ExpiresAt.IsZero() {
+		if ttl = session.ExpiresAt.Sub(jwt.TimeFunc()); ttl <= 0 {
+			return s.Revoke(ctx, session.ID)
+		}
+		score = float64(session.ExpiresAt.Unix())
+	}
+	_, err = s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.sessionKey(session.ID), data, ttl)
+		pipe.ZAdd(ctx, s.subjectKey(session.Subject), redis.Z{Score: score, Member: session.ID})
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) Get(ctx context.Context, id string) (*Session, error) {
+	data, err := s.Client.Get(ctx, s.sessionKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	session := &Session{}
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, err
+	}
+	if session.Expired(jwt.TimeFunc()) {
+		return nil, ErrNotFound
+	}
+	return session, nil
+}
+
+func (s *RedisStore) Sessions(ctx context.Context, subject string) ([]*Session, error) {
+	now := jwt.TimeFunc()
+	key := s.subjectKey(subject)
+	if err := s.Client.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(now.Unix(), 10)).Err(); err != nil {
+		return nil, err
+	}
+	ids, err := s.Client.ZRange(ctx, key, 0, -1).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = s.sessionKey(id)
+	}
+	values, err := s.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []*Session
+	for _, v := range values {
+		data, ok := v.(string)
+		if !ok {
+			continue // Revoked or expired since it was listed
+		}
+		session := &Session{}
+		if err := json.Unmarshal([]byte(data), session); err != nil {
+			return nil, err
+		}
+		if !session.Expired(now) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].IssuedAt.Before(sessions[j].IssuedAt) })
+	return sessions, nil
+}
+
+func (s *RedisStore) Revoke(ctx context.Context, id string) error {
+	session, err := s.Get(ctx, id)
+	if err == ErrNotFound {
+		return s.Client.Del(ctx, s.sessionKey(id)).Err()
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.sessionKey(id))
+		pipe.ZRem(ctx, s.subjectKey(session.Subject), id)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) RevokeAll(ctx context.Context, subject string) error {
+	key := s.subjectKey(subject)
+	ids, err := s.Client.ZRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	keys := []string{key}
+	for _, id := range ids {
+		keys = append(keys, s.sessionKey(id))
+	}
+	return s.Client.Del(ctx, keys...).Err()
+}
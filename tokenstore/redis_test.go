@@ -0,0 +1,23 @@
+//go:build redis
+
+package tokenstore
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Runs against the server at REDIS_ADDR, if set
+func TestRedisStore(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR is not set")
+	}
+	store := NewRedisStore(redis.NewClient(&redis.Options{Addr: addr}))
+	store.Prefix = "jwt-test:" + strconv.FormatInt(time.Now().UnixNano(), 10) + ":"
+	testStore(t, store)
+}
@@ -0,0 +1,113 @@
+package tokenstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var (
+	ErrNotFound  = errors.New("session not found or revoked")
+	ErrMissingID = errors.New("token has no jti claim")
+)
+
+// What a TokenStore records about an issued token
+type Session struct {
+	ID        string            `json:"jti"`
+	Subject   string            `json:"sub"`
+	Device    string            `json:"device,omitempty"` // Describes the client, such as its user agent
+	IssuedAt  time.Time         `json:"iat"`
+	ExpiresAt time.Time         `json:"exp"` // The session is forgotten after this.  Zero means never
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// Reports whether the session has expired at now
+func (s *Session) Expired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && !now.Before(s.ExpiresAt)
+}
+
+// Persists sessions for issued tokens.  Implementations must be safe for
+// concurrent use, and must not return sessions that have expired.
+type TokenStore interface {
+	// Record a session, replacing any with the same ID
+	Save(ctx context.Context, session *Session) error
+	// Returns the session with the given ID, or ErrNotFound
+	Get(ctx context.Context, id string) (*Session, error)
+	// Returns the subject's active sessions, oldest first
+	Sessions(ctx context.Context, subject string) ([]*Session, error)
+	// Remove a session.  Revoking an unknown session is not an error.
+	Revoke(ctx context.Context, id string) error
+	// Remove all of the subject's sessions
+	RevokeAll(ctx context.Context, subject string) error
+}
+
+// Describe a token as a Session, from its jti, sub, iat and exp claims
+func NewSession(token *jwt.Token, device string) (*Session, error) {
+	claims, err := mapClaims(token.Claims)
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{Device: device}
+	s.ID, _ = claims["jti"].(string)
+	if s.ID == "" {
+		return nil, ErrMissingID
+	}
+	s.Subject, _ = claims["sub"].(string)
+	s.IssuedAt = numericDate(claims["iat"])
+	if exp, ok := token.ExpiresAt(); ok {
+		s.ExpiresAt = exp
+	}
+	if s.IssuedAt.IsZero() {
+		s.IssuedAt = jwt.TimeFunc()
+	}
+	return s, nil
+}
+
+// A validation step rejecting tokens whose jti has no active session in
+// store, because it was revoked or never recorded
+func Validator(store TokenStore) jwt.Validator {
+	return jwt.Validator{Name: "session", Validate: func(ctx context.Context, token *jwt.Token) error {
+		claims, err := mapClaims(token.Claims)
+		if err != nil {
+			return err
+		}
+		id, _ := claims["jti"].(string)
+		if id == "" {
+			return &jwt.ValidationError{Inner: ErrMissingID, Errors: jwt.ValidationErrorId}
+		}
+		if _, err := store.Get(ctx, id); err != nil {
+			return &jwt.ValidationError{Inner: err, Errors: jwt.ValidationErrorId}
+		}
+		return nil
+	}}
+}
+
+func mapClaims(claims jwt.Claims) (jwt.MapClaims, error) {
+	if m, ok := claims.(jwt.MapClaims); ok {
+		return m, nil
+	}
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	m := jwt.MapClaims{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return m, dec.Decode(&m)
+}
+
+func numericDate(v interface{}) time.Time {
+	switch v := v.(type) {
+	case float64:
+		return time.Unix(int64(v), 0)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return time.Unix(n, 0)
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,110 @@
+package tokenstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var testKey = []byte("secret")
+
+// Exercises a TokenStore; shared by the implementations' tests
+func testStore(t *testing.T, store TokenStore) {
+	ctx := context.Background()
+	now := time.Unix(jwt.TimeFunc().Unix(), 0)
+	sessions := []*Session{
+		{ID: "a", Subject: "alice", Device: "phone", IssuedAt: now.Add(-2 * time.Minute), ExpiresAt: now.Add(time.Hour)},
+		{ID: "b", Subject: "alice", Device: "laptop", IssuedAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour)},
+		{ID: "c", Subject: "bob", IssuedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{ID: "d", Subject: "alice", IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)},
+	}
+	for _, s := range sessions {
+		if err := store.Save(ctx, s); err != nil {
+			t.Fatalf("Error saving %v: %v", s.ID, err)
+		}
+	}
+
+	if s, err := store.Get(ctx, "b"); err != nil || s.Device != "laptop" || !s.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Unexpected session: %+v %v", s, err)
+	}
+	if _, err := store.Get(ctx, "d"); err != ErrNotFound {
+		t.Errorf("Expected expired session to be missing, got %v", err)
+	}
+	if active, err := store.Sessions(ctx, "alice"); err != nil || len(active) != 2 || active[0].ID != "a" || active[1].ID != "b" {
+		t.Errorf("Unexpected sessions: %+v %v", active, err)
+	}
+
+	if err := store.Revoke(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Revoke(ctx, "unknown"); err != nil {
+		t.Errorf("Unexpected error revoking an unknown session: %v", err)
+	}
+	if active, _ := store.Sessions(ctx, "alice"); len(active) != 1 || active[0].ID != "b" {
+		t.Errorf("Unexpected sessions after Revoke: %+v", active)
+	}
+
+	if err := store.RevokeAll(ctx, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if active, _ := store.Sessions(ctx, "alice"); len(active) != 0 {
+		t.Errorf("Unexpected sessions after RevokeAll: %+v", active)
+	}
+	if _, err := store.Get(ctx, "b"); err != ErrNotFound {
+		t.Errorf("Expected revoked session to be missing, got %v", err)
+	}
+	if _, err := store.Get(ctx, "c"); err != nil {
+		t.Errorf("RevokeAll removed another subject's session: %v", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	testStore(t, store)
+
+	store.Prune()
+	if len(store.sessions) != 1 || len(store.bySubject) != 1 {
+		t.Errorf("Unexpected entries after Prune: %v %v", store.sessions, store.bySubject)
+	}
+}
+
+func TestNewSession(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{Id: "id1", Subject: "alice", IssuedAt: 1500000000, ExpiresAt: 1500003600})
+	s, err := NewSession(token, "phone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ID != "id1" || s.Subject != "alice" || s.Device != "phone" || s.IssuedAt.Unix() != 1500000000 || s.ExpiresAt.Unix() != 1500003600 {
+		t.Errorf("Unexpected session: %+v", s)
+	}
+
+	if _, err := NewSession(jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice"}), ""); err != ErrMissingID {
+		t.Errorf("Expected ErrMissingID, got %v", err)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	store := NewMemoryStore()
+	parser := jwt.NewParser(jwt.WithValidator(Validator(store)))
+	keyFunc := func(*jwt.Token) (interface{}, error) { return testKey, nil }
+
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"jti": "id1", "sub": "alice"}).SignedString(testKey)
+	if _, err := parser.Parse(tokenString, keyFunc); err == nil {
+		t.Errorf("Expected an unrecorded session to be rejected")
+	}
+
+	token, _ := jwt.ParseUnverified(tokenString)
+	session, _ := NewSession(token, "")
+	store.Save(context.Background(), session)
+	if _, err := parser.Parse(tokenString, keyFunc); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	store.Revoke(context.Background(), "id1")
+	_, err := parser.Parse(tokenString, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorId == 0 || ve.Error() != ErrNotFound.Error() {
+		t.Errorf("Expected a revoked session error, got %v", err)
+	}
+}
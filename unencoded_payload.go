@@ -0,0 +1,45 @@
+package jwt
+
+import (
+	"errors"
+)
+
+// Unencoded payload errors
+var (
+	ErrUnencodedPayloadContainsPeriod = errors.New("unencoded payload must not contain a '.' character")
+)
+
+// Configure the token to be signed over its raw, unencoded payload as described
+// in RFC 7797.  This sets the "b64" header to false and lists "b64" in the "crit"
+// header, as required by the spec.
+//
+// The payload is placed in the token as-is, so it must not contain any '.' characters.
+func (t *Token) SetUnencodedPayload() {
+	t.Header["b64"] = false
+	if !headerIsCritical(t.Header, "b64") {
+		t.Header["crit"] = append(criticalHeaders(t.Header), "b64")
+	}
+}
+
+// Reports whether the token header indicates an unencoded payload (b64=false)
+func (t *Token) isUnencodedPayload() bool {
+	b64, ok := t.Header["b64"].(bool)
+	return ok && !b64
+}
+
+// Checks the "b64" header of a parsed token.  Returns true if the payload is
+// unencoded.  Per RFC 7797 section 6, "b64" must be listed in "crit" when present.
+func parseUnencodedPayloadHeader(header map[string]interface{}) (bool, error) {
+	v, ok := header["b64"]
+	if !ok {
+		return false, nil
+	}
+	b64, ok := v.(bool)
+	if !ok {
+		return false, NewValidationError("b64 header must be a boolean", ValidationErrorMalformed)
+	}
+	if !headerIsCritical(header, "b64") {
+		return false, NewValidationError("b64 header must be listed in crit", ValidationErrorMalformed)
+	}
+	return !b64, nil
+}
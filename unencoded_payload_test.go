@@ -0,0 +1,65 @@
+package jwt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestUnencodedPayload(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"})
+	token.SetUnencodedPayload()
+
+	tokenString, err := token.SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+
+	parts := strings.Split(tokenString, ".")
+	if parts[1] != `{"foo":"bar"}` {
+		t.Errorf("Payload was encoded: %v", parts[1])
+	}
+
+	parsed, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+	if err != nil || !parsed.Valid {
+		t.Fatalf("Error parsing token: %v", err)
+	}
+	if claims := parsed.Claims.(jwt.MapClaims); claims["foo"] != "bar" {
+		t.Errorf("Claims mismatch: %v", claims)
+	}
+}
+
+func TestUnencodedPayloadRejections(t *testing.T) {
+	var tests = []struct {
+		name   string
+		header string
+		claims string
+		errors uint32
+	}{
+		{"missing crit", `{"alg":"HS256","b64":false}`, `{"foo":"bar"}`, jwt.ValidationErrorMalformed},
+		{"non-boolean b64", `{"alg":"HS256","b64":"false","crit":["b64"]}`, `{"foo":"bar"}`, jwt.ValidationErrorMalformed},
+		{"tampered payload", `{"alg":"HS256","b64":false,"crit":["b64"]}`, `{"foo":"baz"}`, jwt.ValidationErrorSignatureInvalid},
+	}
+
+	for _, data := range tests {
+		header := jwt.EncodeSegment([]byte(data.header))
+		sig, _ := jwt.SigningMethodHS256.Sign(header+`.{"foo":"bar"}`, hmacTestKey)
+		tokenString := strings.Join([]string{header, data.claims, sig}, ".")
+
+		_, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+		if err == nil {
+			t.Errorf("[%v] Invalid token passed validation", data.name)
+			continue
+		}
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&data.errors == 0 {
+			t.Errorf("[%v] Unexpected error: %v", data.name, err)
+		}
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "b.ar"})
+	token.SetUnencodedPayload()
+	if _, err := token.SignedString(hmacTestKey); err != jwt.ErrUnencodedPayloadContainsPeriod {
+		t.Errorf("Expected ErrUnencodedPayloadContainsPeriod, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// A named step in claims validation.  When a Parser has Validators, they run
+// in order in place of the claims' own Valid method, so checks can be added,
+// removed or reordered per parser.
+type Validator struct {
+	Name     string
+	Validate func(ctx context.Context, token *Token) error
+}
+
+// The steps equivalent to StandardClaims.Valid: exp, iat and nbf, each
+// checked only when present
+func DefaultValidators() []Validator {
+	return []Validator{
+		ExpirationValidator(false),
+		IssuedAtValidator(false),
+		NotBeforeValidator(false),
+	}
+}
+
+// DefaultValidators, shared by the parses that run them
+var defaultValidators = DefaultValidators()
+
+// Check the exp claim has not passed.  If required, tokens without exp fail.
+func ExpirationValidator(required bool) Validator {
+	return timeClaimValidator("exp", required, ValidationErrorExpired, func(exp, now int64) bool { return now <= exp })
+}
+
+// Check the iat claim is not in the future.  If required, tokens without iat fail.
+func IssuedAtValidator(required bool) Validator {
+	return timeClaimValidator("iat", required, ValidationErrorIssuedAt, func(iat, now int64) bool { return now >= iat })
+}
+
+// Check the nbf claim has passed.  If required, tokens without nbf fail.
+func NotBeforeValidator(required bool) Validator {
+	return timeClaimValidator("nbf", required, ValidationErrorNotValidYet, func(nbf, now int64) bool { return now >= nbf })
+}
+
+// Require the aud claim to contain audience
+func AudienceValidator(audience string) Validator {
+	return Validator{Name: "aud", Validate: func(ctx context.Context, token *Token) error {
+		claims, err := claimsToMap(token.Claims)
+		if err != nil {
+			return err
+		}
+		if !claims.VerifyAudience(audience, true) {
+			return NewValidationError("token was not issued for "+audience, ValidationErrorAudience)
+		}
+		return nil
+	}}
+}
+
+// Require the iss claim to equal issuer
+func IssuerValidator(issuer string) Validator {
+	return Validator{Name: "iss", Validate: func(ctx context.Context, token *Token) error {
+		claims, err := claimsToMap(token.Claims)
+		if err != nil {
+			return err
+		}
+		if !claims.VerifyIssuer(issuer, true) {
+			return NewValidationError("token was not issued by "+issuer, ValidationErrorIssuer)
+		}
+		return nil
+	}}
+}
+
+func timeClaimValidator(name string, required bool, flag uint32, ok func(value, now int64) bool) Validator {
+	return Validator{Name: name, Validate: func(ctx context.Context, token *Token) error {
+		value, present, err := timeClaim(token.Claims, name)
+		if err != nil {
+			return err
+		}
+		if !present {
+			if required {
+				return NewValidationError(fmt.Sprintf("token is missing the %v claim", name), flag)
+			}
+			return nil
+		}
+		if !ok(value, TimeFunc().Unix()) {
+			return NewValidationError(fmt.Sprintf("%v claim check failed: %v", name, time.Unix(value, 0).UTC()), flag)
+		}
+		return nil
+	}}
+}
+
+// Returns a NumericDate claim as Unix seconds
+func timeClaim(claims Claims, name string) (int64, bool, error) {
+	// Avoid re-encoding the common claims types
+	var m MapClaims
+	switch c := claims.(type) {
+	case *StandardClaims:
+		return timeField(name, c.ExpiresAt, c.IssuedAt, c.NotBefore)
+	case StandardClaims:
+		return timeField(name, c.ExpiresAt, c.IssuedAt, c.NotBefore)
+	case *RegisteredClaims:
+		return timeField(name, c.ExpiresAt, c.IssuedAt, c.NotBefore)
+	case RegisteredClaims:
+		return timeField(name, c.ExpiresAt, c.IssuedAt, c.NotBefore)
+	case MapClaims:
+		m = c
+	default:
+		var err error
+		if m, err = claimsToMap(claims); err != nil {
+			return 0, false, err
+		}
+	}
+	switch v := m[name].(type) {
+	case float64:
+		return int64(v), true, nil
+	case json.Number:
+		return numberToInt64(v), true, nil
+	case int64:
+		return v, true, nil
+	case int:
+		return int64(v), true, nil
+	case nil:
+		return 0, false, nil
+	}
+	return 0, false, NewValidationError(name+" claim must be a number", ValidationErrorClaimsInvalid)
+}
+
+// Picks the named time claim from a struct's fields, where zero means unset
+func timeField(name string, exp, iat, nbf int64) (int64, bool, error) {
+	var v int64
+	switch name {
+	case "exp":
+		v = exp
+	case "iat":
+		v = iat
+	case "nbf":
+		v = nbf
+	}
+	return v, v != 0, nil
+}
+
+// Run validators in order, combining their errors as Claims.Valid does
+func runValidators(ctx context.Context, token *Token, validators []Validator) error {
+	vErr := new(ValidationError)
+	for _, v := range validators {
+		err := v.Validate(ctx, token)
+		if err == nil {
+			continue
+		}
+		if e, ok := err.(*ValidationError); ok {
+			vErr.Inner = e
+			vErr.Errors |= e.Errors
+		} else {
+			vErr.Inner = err
+			vErr.Errors |= ValidationErrorClaimsInvalid
+		}
+	}
+	if vErr.valid() {
+		return nil
+	}
+	return vErr
+}
+
+// Returns validators with any named name removed
+func withoutValidator(validators []Validator, name string) []Validator {
+	kept := make([]Validator, 0, len(validators))
+	for _, v := range validators {
+		if v.Name != name {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
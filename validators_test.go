@@ -0,0 +1,62 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestValidators(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour).Unix()
+	future := now.Add(time.Hour).Unix()
+	errCustom := errors.New("custom check failed")
+	custom := jwt.Validator{Name: "custom", Validate: func(ctx context.Context, token *jwt.Token) error {
+		if token.Claims.(jwt.MapClaims)["sub"] == "blocked" {
+			return errCustom
+		}
+		return nil
+	}}
+
+	var tests = []struct {
+		name   string
+		claims jwt.MapClaims
+		parser *jwt.Parser
+		errors uint32
+	}{
+		{"defaults match Valid", jwt.MapClaims{"exp": past}, jwt.NewParser(jwt.WithValidators(jwt.DefaultValidators()...)), jwt.ValidationErrorExpired},
+		{"exp and nbf combined", jwt.MapClaims{"exp": past, "nbf": future}, jwt.NewParser(jwt.WithValidators(jwt.DefaultValidators()...)), jwt.ValidationErrorExpired | jwt.ValidationErrorNotValidYet},
+		{"exp removed", jwt.MapClaims{"exp": past}, jwt.NewParser(jwt.WithoutValidator("exp")), 0},
+		{"iss required", jwt.MapClaims{"sub": "user"}, jwt.NewParser(jwt.WithValidator(jwt.IssuerValidator("https://auth.example.com"))), jwt.ValidationErrorIssuer},
+		{"iss matches", jwt.MapClaims{"iss": "https://auth.example.com"}, jwt.NewParser(jwt.WithValidator(jwt.IssuerValidator("https://auth.example.com"))), 0},
+		{"aud required", jwt.MapClaims{"aud": []string{"web"}}, jwt.NewParser(jwt.WithValidator(jwt.AudienceValidator("api"))), jwt.ValidationErrorAudience},
+		{"exp required", jwt.MapClaims{"sub": "user"}, jwt.NewParser(jwt.WithValidators(jwt.ExpirationValidator(true))), jwt.ValidationErrorExpired},
+		{"custom", jwt.MapClaims{"sub": "blocked"}, jwt.NewParser(jwt.WithValidator(custom)), jwt.ValidationErrorClaimsInvalid},
+		{"non-numeric exp", jwt.MapClaims{"exp": "tomorrow"}, jwt.NewParser(jwt.WithValidators(jwt.DefaultValidators()...)), jwt.ValidationErrorClaimsInvalid},
+	}
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	for _, data := range tests {
+		tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data.claims).SignedString(hmacTestKey)
+		_, err := data.parser.Parse(tokenString, keyFunc)
+		if data.errors == 0 {
+			if err != nil {
+				t.Errorf("[%v] Error while verifying token: %v", data.name, err)
+			}
+			continue
+		}
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors != data.errors {
+			t.Errorf("[%v] Expected errors %v, got %v", data.name, data.errors, err)
+		}
+	}
+
+	// Typed claims are supported too
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{ExpiresAt: past}).SignedString(hmacTestKey)
+	parser := jwt.NewParser(jwt.WithValidators(jwt.DefaultValidators()...))
+	if _, err := parser.ParseWithClaims(tokenString, &jwt.StandardClaims{}, keyFunc); err == nil {
+		t.Errorf("Expired typed claims passed validation")
+	}
+}
@@ -0,0 +1,119 @@
+package jwt
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Remembers tokens whose signatures have been verified, so a parser can skip
+// signature verification when the same token is presented again.  Claims are
+// still decoded and validated on every parse, and the key is still looked up.
+//
+// Entries are keyed by the raw token string.  Implementations backed by a
+// shared store such as Redis should hash the key rather than store tokens.
+// A cache must only be shared by parsers that accept the same keys.
+type VerificationCache interface {
+	// Reports whether tokenString was recorded as verified and has not expired
+	Contains(tokenString string) bool
+	// Record tokenString as verified until expiresAt
+	Add(tokenString string, expiresAt time.Time)
+}
+
+// Create an in-memory VerificationCache holding up to size tokens.  Tokens
+// stay cached for at most ttl, and never past their exp claim.
+func NewLRUVerificationCache(size int, ttl time.Duration) *LRUVerificationCache {
+	return &LRUVerificationCache{
+		size:    size,
+		ttl:     ttl,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// In-memory least-recently-used VerificationCache.  Safe for concurrent use.
+type LRUVerificationCache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+func (c *LRUVerificationCache) Contains(tokenString string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[tokenString]
+	if !ok {
+		return false
+	}
+	if !TimeFunc().Before(el.Value.(*lruEntry).expiresAt) {
+		c.remove(el)
+		return false
+	}
+	c.ll.MoveToFront(el)
+	return true
+}
+
+func (c *LRUVerificationCache) Add(tokenString string, expiresAt time.Time) {
+	if limit := TimeFunc().Add(c.ttl); expiresAt.IsZero() || expiresAt.After(limit) {
+		expiresAt = limit
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[tokenString]; ok {
+		el.Value.(*lruEntry).expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.entries[tokenString] = c.ll.PushFront(&lruEntry{tokenString, expiresAt})
+	for c.ll.Len() > c.size {
+		c.remove(c.ll.Back())
+	}
+}
+
+// Number of cached tokens, including any that have expired but not yet been evicted
+func (c *LRUVerificationCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// callers must hold c.mu
+func (c *LRUVerificationCache) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry).key)
+}
+
+// Verify the token's signature, consulting and updating the parser's cache
+func (p *Parser) verifySignature(ctx context.Context, token *Token, signingString string, key interface{}) error {
+	if p.VerificationCache != nil && p.VerificationCache.Contains(token.Raw) {
+		return nil
+	}
+	if err := verifySignatureContext(ctx, token.Method, signingString, token.Signature, key, p.StrictDecoding); err != nil {
+		return err
+	}
+	if p.VerificationCache != nil {
+		p.VerificationCache.Add(token.Raw, expiresAt(token.Claims))
+	}
+	return nil
+}
+
+// Returns the exp claim, or the zero time if there is none
+func expiresAt(claims Claims) time.Time {
+	exp, ok, err := timeClaim(claims, "exp")
+	if !ok || err != nil || exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(exp, 0)
+}
@@ -0,0 +1,69 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestVerificationCache(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	cache := jwt.NewLRUVerificationCache(2, time.Minute)
+	parser := jwt.NewParser(jwt.WithVerificationCache(cache))
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	wrongKeyFunc := func(*jwt.Token) (interface{}, error) { return []byte("wrong"), nil }
+
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": now.Add(time.Hour).Unix()}).SignedString(hmacTestKey)
+
+	if _, err := parser.Parse(tokenString, wrongKeyFunc); err == nil {
+		t.Fatalf("Token with bad signature passed validation")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Token with bad signature was cached")
+	}
+
+	if _, err := parser.Parse(tokenString, keyFunc); err != nil {
+		t.Fatalf("Error parsing token: %v", err)
+	}
+	// Verification is skipped while the token is cached
+	if _, err := parser.Parse(tokenString, wrongKeyFunc); err != nil {
+		t.Errorf("Cached token was verified again: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := parser.Parse(tokenString, wrongKeyFunc); err == nil {
+		t.Errorf("Cache entry outlived its TTL")
+	}
+}
+
+func TestVerificationCacheBoundedByExpiry(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	jwt.TimeFunc = func() time.Time { return now }
+	defer func() { jwt.TimeFunc = time.Now }()
+
+	cache := jwt.NewLRUVerificationCache(2, time.Hour)
+	parser := jwt.NewParser(jwt.WithVerificationCache(cache), jwt.WithoutClaimsValidation())
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	wrongKeyFunc := func(*jwt.Token) (interface{}, error) { return []byte("wrong"), nil }
+
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{ExpiresAt: now.Add(time.Minute).Unix()}).SignedString(hmacTestKey)
+	parser.ParseWithClaims(tokenString, &jwt.StandardClaims{}, keyFunc)
+
+	now = now.Add(2 * time.Minute)
+	if _, err := parser.ParseWithClaims(tokenString, &jwt.StandardClaims{}, wrongKeyFunc); err == nil {
+		t.Errorf("Cache entry outlived the token's exp")
+	}
+
+	// The least recently used token is evicted
+	for i := 0; i < 3; i++ {
+		s, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"n": i}).SignedString(hmacTestKey)
+		parser.Parse(s, keyFunc)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 cached tokens, got %v", cache.Len())
+	}
+}
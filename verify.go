@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"context"
+)
+
+// Verify signature using method and key.  This is the single code path the
+// parser uses to check signatures, so it is the one place to review when
+// auditing how signatures are compared:
+//
+//   - The method must be non-nil and is never inferred from the key.
+//   - The signature must be canonical, unpadded base64url.  Alternate
+//     encodings of the same bytes (padding, non-zero trailing bits) are
+//     rejected so a token has exactly one valid signature string.  Parsers
+//     only require this when StrictDecoding is set, as by NewParser.
+//   - HMAC signatures are compared with hmac.Equal, which is constant time
+//     with respect to the signature contents.  RSA, RSA-PSS and ECDSA
+//     signatures are checked by crypto/rsa and crypto/ecdsa, which do not
+//     compare secrets.
+//
+// Returns nil if the signature is valid.
+func VerifySignature(method SigningMethod, signingString, signature string, key interface{}) error {
+	return VerifySignatureContext(context.Background(), method, signingString, signature, key)
+}
+
+// Checks shared by every signing method.  See VerifySignature.
+func checkSignature(method SigningMethod, signature string, strict bool) error {
+	if method == nil {
+		return NewValidationError("signing method is unspecified", ValidationErrorUnverifiable)
+	}
+
+	if strict && signature != "" && !isCanonicalSegment(signature) {
+		if _, err := DecodeSegment(signature); err != nil {
+			return err
+		}
+		return ErrSignatureInvalid
+	}
+
+	return nil
+}
+
+// Reports whether seg is exactly what EncodeSegment produces for some input:
+// unpadded base64url with no stray bits in its final character
+func isCanonicalSegment(seg string) bool {
+	var last byte
+	for i := 0; i < len(seg); i++ {
+		c := seg[i]
+		switch {
+		case c >= 'A' && c <= 'Z':
+			last = c - 'A'
+		case c >= 'a' && c <= 'z':
+			last = c - 'a' + 26
+		case c >= '0' && c <= '9':
+			last = c - '0' + 52
+		case c == '-':
+			last = 62
+		case c == '_':
+			last = 63
+		default:
+			return false
+		}
+	}
+	switch len(seg) % 4 {
+	case 1:
+		return false
+	case 2:
+		return last&0x0f == 0
+	case 3:
+		return last&0x03 == 0
+	}
+	return true
+}
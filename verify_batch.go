@@ -0,0 +1,106 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+)
+
+// Parse and verify many tokens, such as those carried by a batch of queued
+// events, returning one result per token in the same order.  Tokens with the
+// same header and issuer share one call to keyFunc.
+func VerifyBatch(tokens []string, keyFunc Keyfunc) []ParseResult {
+	return new(Parser).VerifyBatch(context.Background(), tokens, keyFunc, 1)
+}
+
+// Parse and verify many tokens into MapClaims, returning one result per
+// token in the same order.  Up to workers tokens are verified at once; one or
+// less verifies them in turn on the calling goroutine.
+//
+// keyFunc is called once for each distinct combination of raw header and iss
+// claim, and its key or error is reused for the other tokens of the batch.
+// Key functions that choose keys by other claims should not be batched.
+func (p *Parser) VerifyBatch(ctx context.Context, tokens []string, keyFunc Keyfunc, workers int) []ParseResult {
+	return p.VerifyBatchContext(ctx, tokens, keyfuncWithContext(keyFunc), workers)
+}
+
+// VerifyBatch, passing ctx to keyFunc and the signing method
+func (p *Parser) VerifyBatchContext(ctx context.Context, tokens []string, keyFunc KeyfuncContext, workers int) []ParseResult {
+	results := make([]ParseResult, len(tokens))
+	if keyFunc != nil {
+		keyFunc = (&batchKeys{keyFunc: keyFunc}).lookup
+	}
+	parse := func(i int) {
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			return
+		}
+		results[i].Token, results[i].Err = p.ParseWithClaimsContext(ctx, tokens[i], MapClaims{}, keyFunc)
+	}
+
+	if workers <= 1 {
+		for i := range tokens {
+			parse(i)
+		}
+		return results
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(tokens); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				parse(i)
+			}
+		}()
+	}
+	for i := range tokens {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// Memoizes a Keyfunc for the tokens of a batch
+type batchKeys struct {
+	keyFunc KeyfuncContext
+
+	mu      sync.Mutex
+	entries map[batchKeyID]*batchKey
+}
+
+type batchKeyID struct {
+	header string
+	issuer string
+}
+
+type batchKey struct {
+	once sync.Once
+	key  interface{}
+	err  error
+}
+
+func (b *batchKeys) lookup(ctx context.Context, token *Token) (interface{}, error) {
+	id := batchKeyID{header: string(token.RawHeader)}
+	if claims, ok := token.Claims.(MapClaims); ok {
+		id.issuer, _ = claims["iss"].(string)
+	}
+
+	b.mu.Lock()
+	if b.entries == nil {
+		b.entries = make(map[batchKeyID]*batchKey)
+	}
+	entry, ok := b.entries[id]
+	if !ok {
+		entry = &batchKey{}
+		b.entries[id] = entry
+	}
+	b.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.key, entry.err = b.keyFunc(ctx, token)
+	})
+	return entry.key, entry.err
+}
@@ -0,0 +1,46 @@
+package jwt_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestVerifyBatch(t *testing.T) {
+	tokens := []string{
+		signHS256(jwt.MapClaims{"iss": "a", "n": 1}),
+		signHS256(jwt.MapClaims{"iss": "a", "n": 2}),
+		signHS256(jwt.MapClaims{"iss": "b", "n": 3}),
+		signHS256(jwt.MapClaims{"iss": "a", "n": 4}) + "x",
+		"not a token",
+	}
+
+	for _, workers := range []int{1, 4} {
+		var lookups int32
+		keyFunc := func(*jwt.Token) (interface{}, error) {
+			atomic.AddInt32(&lookups, 1)
+			return hmacTestKey, nil
+		}
+		results := new(jwt.Parser).VerifyBatch(context.Background(), tokens, keyFunc, workers)
+		if len(results) != len(tokens) {
+			t.Fatalf("Expected %v results, got %v", len(tokens), len(results))
+		}
+		for i, result := range results[:3] {
+			if result.Err != nil || result.Token.Claims.(jwt.MapClaims)["n"] != float64(i+1) {
+				t.Errorf("[%v] Unexpected result %v: %v", workers, i, result.Err)
+			}
+		}
+		if results[3].Err == nil || results[4].Err == nil {
+			t.Errorf("[%v] Expected invalid tokens to fail", workers)
+		}
+		if lookups != 2 {
+			t.Errorf("[%v] Expected one key lookup per issuer, got %v", workers, lookups)
+		}
+	}
+
+	if results := jwt.VerifyBatch(tokens[:1], func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }); results[0].Err != nil {
+		t.Errorf("Unexpected error: %v", results[0].Err)
+	}
+}
@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// One step of VerifyCompact, named after what it checks: "decode", "alg",
+// "crit", "typ", "signature", then the name of each claims Validator, such as
+// "exp", and "claims" for checks without a name
+type VerifyCheck struct {
+	Name string
+	Err  error // Nil if the check passed
+}
+
+// Reports whether the check passed
+func (c VerifyCheck) Passed() bool {
+	return c.Err == nil
+}
+
+// Encodes Err as its message
+func (c VerifyCheck) MarshalJSON() ([]byte, error) {
+	v := struct {
+		Name   string `json:"name"`
+		Passed bool   `json:"passed"`
+		Error  string `json:"error,omitempty"`
+	}{Name: c.Name, Passed: c.Passed()}
+	if c.Err != nil {
+		v.Error = c.Err.Error()
+	}
+	return json.Marshal(v)
+}
+
+// The outcome of VerifyCompact: the decoded token and every check run against
+// it.  Header and Claims are set whenever they could be decoded, even if the
+// token is invalid.  The claims are not redacted; pass them through a
+// Redactor before showing them to anyone but the token's owner.
+type VerifyResult struct {
+	Header map[string]interface{} `json:"header,omitempty"`
+	Claims MapClaims              `json:"claims,omitempty"`
+	Checks []VerifyCheck          `json:"checks"`
+	Valid  bool                   `json:"valid"`
+}
+
+// Returns the first check that failed, or nil if the token is valid
+func (r *VerifyResult) Failed() *VerifyCheck {
+	for i := range r.Checks {
+		if !r.Checks[i].Passed() {
+			return &r.Checks[i]
+		}
+	}
+	return nil
+}
+
+// Returns the check with the given name, or nil if it did not run
+func (r *VerifyResult) Check(name string) *VerifyCheck {
+	for i := range r.Checks {
+		if r.Checks[i].Name == name {
+			return &r.Checks[i]
+		}
+	}
+	return nil
+}
+
+// Reports whether the signature was checked and is valid, whatever the
+// claims say, as jwt.io's "Signature Verified"
+func (r *VerifyResult) SignatureValid() bool {
+	c := r.Check("signature")
+	return c != nil && c.Passed()
+}
+
+// Renders the checks on one line, such as "decode=ok alg=ok signature=ok exp=failed"
+func (r *VerifyResult) String() string {
+	parts := make([]string, len(r.Checks))
+	for i, c := range r.Checks {
+		status := "ok"
+		if !c.Passed() {
+			status = "failed"
+		}
+		parts[i] = c.Name + "=" + status
+	}
+	return strings.Join(parts, " ")
+}
+
+// Decode and verify a pasted token with key the way jwt.io does, for admin
+// and debugging endpoints: rather than stopping at the first error, as Parse
+// does, it runs every check it can and reports each one, so a caller can tell
+// an expired token from a bad signature from the wrong key type.  The options
+// configure the checks as they would a Parser, e.g. WithValidMethods or
+// WithValidator.
+//
+// Checks that depend on an earlier one are skipped when it fails: nothing is
+// checked after "decode" fails, and the signature is not checked with an
+// unknown alg.  Use Parse to authenticate requests; a VerifyResult is a
+// report, not an authorization decision.
+func VerifyCompact(tokenString string, key interface{}, options ...ParserOption) *VerifyResult {
+	p := NewParser(options...)
+	r := new(VerifyResult)
+	ctx := context.Background()
+
+	token, parts, err := p.parseHeader(tokenString)
+	if err == nil {
+		token.Claims = MapClaims{}
+		err = p.decodeTokenClaims(token, parts[1], p.decodeNewSegment)
+	}
+	if r.record("decode", err); err != nil {
+		if token != nil {
+			r.Header = token.Header
+		}
+		return r
+	}
+	r.Header = token.Header
+	r.Claims = token.Claims.(MapClaims)
+
+	if err = lookupSigningMethod(token); err == nil && p.ValidMethods != nil && !containsString(p.ValidMethods, token.Method.Alg()) {
+		err = NewValidationError(fmt.Sprintf("signing method %v is invalid", token.Method.Alg()), ValidationErrorSignatureInvalid)
+	}
+	r.record("alg", err)
+	r.record("crit", validateCriticalHeaders(token.Header))
+	r.record("typ", p.validateTypeHeader(token.Header))
+
+	if token.Method != nil {
+		token.Signature = parts[2]
+		if err = p.checkKey(key); err == nil {
+			err = p.verifySignature(ctx, token, parts[0]+"."+parts[1], key)
+		}
+		r.record("signature", err)
+	}
+
+	if !p.SkipClaimsValidation {
+		validators := p.Validators
+		if validators == nil {
+			validators = DefaultValidators()
+		}
+		for _, v := range validators {
+			r.record(v.Name, v.Validate(ctx, token))
+		}
+		for _, validate := range p.validators {
+			r.record("claims", validate(token))
+		}
+	}
+
+	r.Valid = r.Failed() == nil
+	return r
+}
+
+func (r *VerifyResult) record(name string, err error) {
+	r.Checks = append(r.Checks, VerifyCheck{Name: name, Err: err})
+}
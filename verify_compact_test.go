@@ -0,0 +1,98 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestVerifyCompact(t *testing.T) {
+	now := time.Now().Unix()
+	valid := signHS256(jwt.MapClaims{"sub": "user", "exp": now + 60})
+	expired := signHS256(jwt.MapClaims{"sub": "user", "exp": now - 60})
+
+	var tests = []struct {
+		name       string
+		token      string
+		key        interface{}
+		options    []jwt.ParserOption
+		failed     string
+		signature  bool
+		hasClaims  bool
+		checkNames string
+	}{
+		{"valid", valid, hmacTestKey, nil, "", true, true, "decode alg crit typ signature exp iat nbf"},
+		{"expired", expired, hmacTestKey, nil, "exp", true, true, "decode alg crit typ signature exp iat nbf"},
+		{"wrong key", expired, []byte("wrong"), nil, "signature", false, true, "decode alg crit typ signature exp iat nbf"},
+		{"alg not allowed", valid, hmacTestKey, []jwt.ParserOption{jwt.WithValidMethods([]string{"RS256"})}, "alg", true, true, "decode alg crit typ signature exp iat nbf"},
+		{"audience", valid, hmacTestKey, []jwt.ParserOption{jwt.WithValidator(jwt.AudienceValidator("api"))}, "aud", true, true, "decode alg crit typ signature exp iat nbf aud"},
+		{"no claims validation", expired, hmacTestKey, []jwt.ParserOption{jwt.WithoutClaimsValidation()}, "", true, true, "decode alg crit typ signature"},
+		{"malformed", "not a token", hmacTestKey, nil, "decode", false, false, "decode"},
+	}
+
+	for _, test := range tests {
+		r := jwt.VerifyCompact(test.token, test.key, test.options...)
+		var failed string
+		if c := r.Failed(); c != nil {
+			failed = c.Name
+		}
+		if failed != test.failed || r.Valid != (test.failed == "") {
+			t.Errorf("[%v] Expected %q to fail, got %q (valid=%v)", test.name, test.failed, failed, r.Valid)
+		}
+		if r.SignatureValid() != test.signature {
+			t.Errorf("[%v] Expected signature valid %v", test.name, test.signature)
+		}
+		if (r.Claims["sub"] == "user") != test.hasClaims {
+			t.Errorf("[%v] Unexpected claims: %v", test.name, r.Claims)
+		}
+		var names []string
+		for _, c := range r.Checks {
+			names = append(names, c.Name)
+		}
+		if got := strings.Join(names, " "); got != test.checkNames {
+			t.Errorf("[%v] Expected checks %q, got %q", test.name, test.checkNames, got)
+		}
+	}
+}
+
+func TestVerifyCompactJSON(t *testing.T) {
+	token := signHS256(jwt.MapClaims{"sub": "user", "exp": float64(1)})
+	r := jwt.VerifyCompact(token, hmacTestKey)
+	if r.String() != "decode=ok alg=ok crit=ok typ=ok signature=ok exp=failed iat=ok nbf=ok" {
+		t.Errorf("Unexpected summary: %q", r)
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Header map[string]interface{}
+		Checks []struct {
+			Name   string
+			Passed bool
+			Error  string
+		}
+		Valid bool
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Valid || decoded.Header["alg"] != "HS256" || len(decoded.Checks) != 8 {
+		t.Fatalf("Unexpected JSON: %s", data)
+	}
+	if exp := decoded.Checks[5]; exp.Name != "exp" || exp.Passed || !strings.Contains(exp.Error, "exp claim check failed") {
+		t.Errorf("Unexpected exp check: %+v", exp)
+	}
+}
+
+func signHS256(claims jwt.Claims) string {
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(hmacTestKey)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
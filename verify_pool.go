@@ -0,0 +1,127 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+var ErrPoolClosed = errors.New("verification pool is closed")
+
+// The outcome of a parse run by a VerifyPool
+type ParseResult struct {
+	Token *Token
+	Err   error
+}
+
+// Runs token parsing and signature verification on a fixed number of worker
+// goroutines, so CPU heavy verification, such as RSA at high request rates,
+// is bounded rather than run on every I/O goroutine at once.  Requests wait
+// in a queue of limited size; when it is full, submitting blocks until there
+// is room or the request's context is done.
+//
+// A VerifyPool is safe for concurrent use.  Close it to stop its workers.
+type VerifyPool struct {
+	parser *Parser
+	jobs   chan func()
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// Create a VerifyPool parsing with parser, or NewParser() if nil.
+// workers defaults to GOMAXPROCS, and queue may be zero for no queueing.
+func NewVerifyPool(parser *Parser, workers, queue int) *VerifyPool {
+	if parser == nil {
+		parser = NewParser()
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	vp := &VerifyPool{parser: parser, jobs: make(chan func(), queue)}
+	vp.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go vp.work()
+	}
+	return vp
+}
+
+func (vp *VerifyPool) work() {
+	defer vp.wg.Done()
+	for job := range vp.jobs {
+		job()
+	}
+}
+
+// Parse tokenString with the pool's parser on a worker.  The result is sent
+// on the returned channel, which is buffered so it need not be read.
+func (vp *VerifyPool) ParseAsync(ctx context.Context, tokenString string, claims Claims, keyFunc KeyfuncContext) <-chan ParseResult {
+	results := make(chan ParseResult, 1)
+	vp.ParseFunc(ctx, tokenString, claims, keyFunc, func(token *Token, err error) {
+		results <- ParseResult{Token: token, Err: err}
+	})
+	return results
+}
+
+// Parse tokenString with the pool's parser on a worker, then call callback
+// with the result.  The callback runs on the worker, so it should not block.
+// If the pool is closed, or ctx is done before a worker is free, callback is
+// called with the error before ParseFunc returns.
+func (vp *VerifyPool) ParseFunc(ctx context.Context, tokenString string, claims Claims, keyFunc KeyfuncContext, callback func(*Token, error)) {
+	err := vp.submit(ctx, func() {
+		if err := ctx.Err(); err != nil {
+			callback(nil, err)
+			return
+		}
+		callback(vp.parser.ParseWithClaimsContext(ctx, tokenString, claims, keyFunc))
+	})
+	if err != nil {
+		callback(nil, err)
+	}
+}
+
+// Verify a signature on a worker, as VerifySignatureContext does.  The
+// result is sent on the returned channel, which is buffered so it need not
+// be read.
+func (vp *VerifyPool) VerifyAsync(ctx context.Context, method SigningMethod, signingString, signature string, key interface{}) <-chan error {
+	results := make(chan error, 1)
+	err := vp.submit(ctx, func() {
+		if err := ctx.Err(); err != nil {
+			results <- err
+			return
+		}
+		results <- VerifySignatureContext(ctx, method, signingString, signature, key)
+	})
+	if err != nil {
+		results <- err
+	}
+	return results
+}
+
+// Stop accepting work and wait for queued work to finish
+func (vp *VerifyPool) Close() {
+	vp.mu.Lock()
+	if !vp.closed {
+		vp.closed = true
+		close(vp.jobs)
+	}
+	vp.mu.Unlock()
+	vp.wg.Wait()
+}
+
+// Queue job, waiting for room unless ctx is done first
+func (vp *VerifyPool) submit(ctx context.Context, job func()) error {
+	vp.mu.RLock()
+	defer vp.mu.RUnlock()
+	if vp.closed {
+		return ErrPoolClosed
+	}
+	select {
+	case vp.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,65 @@
+package jwt_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestVerifyPool(t *testing.T) {
+	pool := jwt.NewVerifyPool(jwt.NewParser(), 2, 4)
+	keyFunc := func(context.Context, *jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	tokenString := signHS256(jwt.MapClaims{"sub": "user"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := <-pool.ParseAsync(context.Background(), tokenString, jwt.MapClaims{}, keyFunc)
+			if result.Err != nil || !result.Token.Valid {
+				t.Errorf("Unexpected result: %v", result.Err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	done := make(chan error, 1)
+	pool.ParseFunc(context.Background(), tokenString+"x", jwt.MapClaims{}, keyFunc, func(token *jwt.Token, err error) {
+		done <- err
+	})
+	if err := <-done; err == nil {
+		t.Errorf("Expected an invalid signature to be rejected")
+	}
+
+	parts := strings.Split(tokenString, ".")
+	if err := <-pool.VerifyAsync(context.Background(), jwt.SigningMethodHS256, parts[0]+"."+parts[1], parts[2], hmacTestKey); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if result := <-pool.ParseAsync(ctx, tokenString, jwt.MapClaims{}, keyFunc); result.Err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", result.Err)
+	}
+
+	pool.Close()
+	if result := <-pool.ParseAsync(context.Background(), tokenString, jwt.MapClaims{}, keyFunc); result.Err != jwt.ErrPoolClosed {
+		t.Errorf("Expected ErrPoolClosed, got %v", result.Err)
+	}
+}
+
+func TestVerifyPoolDefaultParser(t *testing.T) {
+	pool := jwt.NewVerifyPool(nil, 1, 0)
+	defer pool.Close()
+	keyFunc := func(context.Context, *jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	// The default parser is NewParser's, which rejects padded segments
+	padded := signHS256(jwt.MapClaims{"sub": "user"}) + "="
+	if result := <-pool.ParseAsync(context.Background(), padded, jwt.MapClaims{}, keyFunc); result.Err == nil {
+		t.Errorf("Expected the padded token to be rejected")
+	}
+}
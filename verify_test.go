@@ -0,0 +1,37 @@
+package jwt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestVerifySignature(t *testing.T) {
+	data := hmacTestData[0]
+	parts := strings.Split(data.tokenString, ".")
+	signingString := strings.Join(parts[0:2], ".")
+
+	var tests = []struct {
+		name      string
+		method    jwt.SigningMethod
+		signature string
+		valid     bool
+	}{
+		{"valid", jwt.SigningMethodHS256, parts[2], true},
+		{"nil method", nil, parts[2], false},
+		{"padded", jwt.SigningMethodHS256, parts[2] + "=", false},
+		{"non-canonical trailing bits", jwt.SigningMethodHS256, parts[2][:len(parts[2])-1] + "l", false},
+		{"tampered", jwt.SigningMethodHS256, "A" + parts[2][1:], false},
+	}
+
+	for _, test := range tests {
+		err := jwt.VerifySignature(test.method, signingString, test.signature, hmacTestKey)
+		if test.valid && err != nil {
+			t.Errorf("[%v] Error while verifying signature: %v", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("[%v] Invalid signature passed validation", test.name)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+var ErrUnknownClaimsVersion = errors.New("token claims version is not supported")
+
+// A registry of claims types by version, so the format of a token's claims
+// can evolve without breaking consumers of older tokens.  Each token carries
+// its version in a claim, "ver" by default, and is parsed into a new value
+// of the claims type registered for that version:
+//
+//	versions := jwt.NewVersionedClaims(nil)
+//	versions.Register("1", func() jwt.Claims { return &ClaimsV1{} })
+//	versions.Register("2", func() jwt.Claims { return &ClaimsV2{} })
+//	token, err := versions.Parse(tokenString, keyFunc)
+//	switch claims := token.Claims.(type) { ... }
+//
+// The version may be a JSON string or a number, which is matched as written,
+// so 2 and 2.0 are different versions.  Configure the registry before use; it
+// is then safe for concurrent use.
+type VersionedClaims struct {
+	Claim string // Name of the version claim.  Defaults to "ver"
+
+	// Version assumed for tokens without the version claim, such as those
+	// issued before versioning was introduced.  If empty, they are rejected.
+	Default string
+
+	// Options used to parse tokens.  Defaults to NewParser().
+	Parser *Parser
+
+	versions map[string]func() Claims
+}
+
+// Create a VersionedClaims parsing with parser, which may be nil
+func NewVersionedClaims(parser *Parser) *VersionedClaims {
+	return &VersionedClaims{Parser: parser, versions: make(map[string]func() Claims)}
+}
+
+// Parse tokens of version into claims returned by newClaims
+func (v *VersionedClaims) Register(version string, newClaims func() Claims) {
+	if v.versions == nil {
+		v.versions = make(map[string]func() Claims)
+	}
+	v.versions[version] = newClaims
+}
+
+// Parse and validate a token into the claims type registered for its version
+func (v *VersionedClaims) Parse(tokenString string, keyFunc Keyfunc) (*Token, error) {
+	return v.ParseContext(context.Background(), tokenString, keyfuncWithContext(keyFunc))
+}
+
+// Like Parse, passing ctx to keyFunc and the signing method
+func (v *VersionedClaims) ParseContext(ctx context.Context, tokenString string, keyFunc KeyfuncContext) (*Token, error) {
+	parser := v.Parser
+	if parser == nil {
+		parser = NewParser()
+	}
+	claims, err := v.claimsFor(parser, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	return parser.ParseWithClaimsContext(ctx, tokenString, claims, keyFunc)
+}
+
+// Returns new claims of the type registered for the token's version
+func (v *VersionedClaims) claimsFor(p *Parser, tokenString string) (Claims, error) {
+	token, parts, err := p.parseHeader(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := p.decodePayload(token.Header, parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	name := v.Claim
+	if name == "" {
+		name = "ver"
+	}
+	version := v.Default
+	raw, err := RawClaim(payload, name)
+	switch {
+	case err == ErrClaimNotFound:
+	case err != nil:
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	case raw[0] == '"':
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+		}
+	default:
+		version = string(raw)
+	}
+
+	newClaims, ok := v.versions[version]
+	if !ok || version == "" {
+		return nil, &ValidationError{Inner: ErrUnknownClaimsVersion, Errors: ValidationErrorClaimsInvalid}
+	}
+	return newClaims(), nil
+}
@@ -0,0 +1,54 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type claimsV1 struct {
+	jwt.StandardClaims
+	Name string `json:"name"`
+}
+
+type claimsV2 struct {
+	jwt.StandardClaims
+	Version   int    `json:"ver"`
+	GivenName string `json:"given_name"`
+}
+
+func TestVersionedClaims(t *testing.T) {
+	versions := jwt.NewVersionedClaims(nil)
+	versions.Register("1", func() jwt.Claims { return &claimsV1{} })
+	versions.Register("2", func() jwt.Claims { return &claimsV2{} })
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	token, err := versions.Parse(signHS256(jwt.MapClaims{"ver": 2, "given_name": "Alex"}), keyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims, ok := token.Claims.(*claimsV2); !ok || claims.GivenName != "Alex" {
+		t.Errorf("Unexpected claims: %#v", token.Claims)
+	}
+	token, err = versions.Parse(signHS256(jwt.MapClaims{"ver": "1", "name": "Alex"}), keyFunc)
+	if claims, ok := token.Claims.(*claimsV1); err != nil || !ok || claims.Name != "Alex" {
+		t.Errorf("Unexpected claims: %#v %v", token.Claims, err)
+	}
+
+	unversioned := signHS256(jwt.MapClaims{"name": "Alex"})
+	for _, tokenString := range []string{unversioned, signHS256(jwt.MapClaims{"ver": 3})} {
+		_, err = versions.Parse(tokenString, keyFunc)
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Inner != jwt.ErrUnknownClaimsVersion {
+			t.Errorf("Expected ErrUnknownClaimsVersion, got %v", err)
+		}
+	}
+	versions.Default = "1"
+	if token, err = versions.Parse(unversioned, keyFunc); err != nil || token.Claims.(*claimsV1).Name != "Alex" {
+		t.Errorf("Unexpected result for the default version: %v", err)
+	}
+
+	// Tokens are still verified
+	if _, err = versions.Parse(signHS256(jwt.MapClaims{"ver": 2})+"x", keyFunc); err == nil {
+		t.Errorf("Expected an invalid signature to be rejected")
+	}
+}
@@ -0,0 +1,135 @@
+package jwt
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+)
+
+// Certificate chain errors
+var (
+	ErrMissingCertificateChain = errors.New("token has no x5c certificate chain")
+	ErrInvalidCertificateChain = errors.New("x5c header is not a valid certificate chain")
+	ErrCertificateThumbprint   = errors.New("x5t thumbprint does not match the x5c leaf certificate")
+	ErrMissingRoots            = errors.New("x5c chain can't be verified without trusted roots")
+)
+
+// Embed an X.509 certificate chain in the x5c header, along with the x5t#S256
+// thumbprint of the leaf.  The certificate containing the key used to sign the
+// token must come first, followed by the certificates that certify it.
+func (t *Token) SetCertificateChain(chain []*x509.Certificate) {
+	encoded := make([]string, len(chain))
+	for i, cert := range chain {
+		// x5c uses standard base64, not base64url (RFC 7515 section 4.1.6)
+		encoded[i] = base64.StdEncoding.EncodeToString(cert.Raw)
+	}
+	t.Header["x5c"] = encoded
+	if len(chain) > 0 {
+		t.Header["x5t#S256"] = CertificateThumbprint(chain[0])
+	}
+}
+
+// Decode the x5c header into a certificate chain, leaf first.  If x5t or x5t#S256
+// headers are present, they must match the leaf certificate.
+// The chain is not verified; use X5CKeyfunc for that.
+func (t *Token) CertificateChain() ([]*x509.Certificate, error) {
+	var encoded []string
+	switch x5c := t.Header["x5c"].(type) {
+	case nil:
+		return nil, ErrMissingCertificateChain
+	case []string:
+		encoded = x5c
+	case []interface{}:
+		for _, v := range x5c {
+			s, ok := v.(string)
+			if !ok {
+				return nil, ErrInvalidCertificateChain
+			}
+			encoded = append(encoded, s)
+		}
+	default:
+		return nil, ErrInvalidCertificateChain
+	}
+	if len(encoded) == 0 {
+		return nil, ErrMissingCertificateChain
+	}
+
+	chain := make([]*x509.Certificate, len(encoded))
+	for i, s := range encoded {
+		der, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, ErrInvalidCertificateChain
+		}
+		if chain[i], err = x509.ParseCertificate(der); err != nil {
+			return nil, err
+		}
+	}
+
+	if x5t, ok := t.Header["x5t#S256"]; ok {
+		if x5t != CertificateThumbprint(chain[0]) {
+			return nil, ErrCertificateThumbprint
+		}
+	}
+	if x5t, ok := t.Header["x5t"]; ok {
+		sum := sha1.Sum(chain[0].Raw)
+		if x5t != EncodeSegment(sum[:]) {
+			return nil, ErrCertificateThumbprint
+		}
+	}
+
+	return chain, nil
+}
+
+// The x5t#S256 thumbprint of cert: the base64url-encoded SHA-256 hash of its DER encoding
+func CertificateThumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return EncodeSegment(sum[:])
+}
+
+// Returns a Keyfunc that verifies tokens with the public key of the x5c leaf
+// certificate, once the chain is verified against opts.Roots, with the
+// remaining x5c certificates used as intermediates.  Set opts.KeyUsages if
+// your signing certificates aren't issued for server authentication.
+//
+// opts and opts.Roots are required; tokens are rejected with ErrMissingRoots
+// without them.  See UnsafeX5CKeyfunc to skip verifying the chain.
+func X5CKeyfunc(opts *x509.VerifyOptions) Keyfunc {
+	return func(token *Token) (interface{}, error) {
+		if opts == nil || opts.Roots == nil {
+			return nil, ErrMissingRoots
+		}
+		chain, err := token.CertificateChain()
+		if err != nil {
+			return nil, err
+		}
+
+		verifyOpts := *opts
+		verifyOpts.Intermediates = x509.NewCertPool()
+		for _, cert := range chain[1:] {
+			verifyOpts.Intermediates.AddCert(cert)
+		}
+		if verifyOpts.CurrentTime.IsZero() {
+			verifyOpts.CurrentTime = TimeFunc()
+		}
+		if _, err = chain[0].Verify(verifyOpts); err != nil {
+			return nil, err
+		}
+		return chain[0].PublicKey, nil
+	}
+}
+
+// Returns a Keyfunc that verifies tokens with the public key of the x5c leaf
+// certificate without verifying the chain.  It trusts whatever certificate the
+// token carries, so it is only safe if the token's origin has been established
+// in some other way.
+func UnsafeX5CKeyfunc() Keyfunc {
+	return func(token *Token) (interface{}, error) {
+		chain, err := token.CertificateChain()
+		if err != nil {
+			return nil, err
+		}
+		return chain[0].PublicKey, nil
+	}
+}
@@ -0,0 +1,92 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func makeTestCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestX5CKeyfunc(t *testing.T) {
+	root, rootKey := makeTestCertificate(t, "root", nil, nil)
+	leaf, leafKey := makeTestCertificate(t, "leaf", root, rootKey)
+	other, otherKey := makeTestCertificate(t, "other", nil, nil)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"foo": "bar"})
+	token.SetCertificateChain([]*x509.Certificate{leaf, root})
+	tokenString, err := token.SignedString(leafKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	if _, err := jwt.Parse(tokenString, jwt.X5CKeyfunc(&x509.VerifyOptions{Roots: roots})); err != nil {
+		t.Errorf("Error verifying token with trusted chain: %v", err)
+	}
+
+	untrusted := x509.NewCertPool()
+	untrusted.AddCert(other)
+	if _, err := jwt.Parse(tokenString, jwt.X5CKeyfunc(&x509.VerifyOptions{Roots: untrusted})); err == nil {
+		t.Errorf("Token with untrusted chain passed validation")
+	}
+
+	// A self-signed certificate is only trusted without verification
+	selfSigned := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"foo": "bar"})
+	selfSigned.SetCertificateChain([]*x509.Certificate{other})
+	selfSignedString, _ := selfSigned.SignedString(otherKey)
+	for _, opts := range []*x509.VerifyOptions{nil, {}} {
+		_, err := jwt.Parse(selfSignedString, jwt.X5CKeyfunc(opts))
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Inner != jwt.ErrMissingRoots {
+			t.Errorf("Expected ErrMissingRoots, got %v", err)
+		}
+	}
+	if _, err := jwt.Parse(selfSignedString, jwt.X5CKeyfunc(&x509.VerifyOptions{Roots: roots})); err == nil {
+		t.Errorf("Token with self-signed certificate passed validation")
+	}
+	if _, err := jwt.Parse(selfSignedString, jwt.UnsafeX5CKeyfunc()); err != nil {
+		t.Errorf("Error verifying token without verifying the chain: %v", err)
+	}
+
+	// A thumbprint that doesn't match the leaf is rejected
+	token.Header["x5t#S256"] = "bogus"
+	tokenString, _ = token.SignedString(leafKey)
+	if _, err := jwt.Parse(tokenString, jwt.UnsafeX5CKeyfunc()); err == nil {
+		t.Errorf("Token with mismatched thumbprint passed validation")
+	}
+}
@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// The zip header value for DEFLATE compressed payloads (RFC 1951)
+const CompressionDeflate = "DEF"
+
+// The most a compressed payload may expand to when the parser's
+// MaxDecompressedSize is zero
+const DefaultMaxDecompressedSize = 1 << 20
+
+var (
+	ErrCompressedUnencodedPayload = errors.New("an unencoded payload can't be compressed")
+	ErrDecompressedSizeExceeded   = errors.New("decompressed payload exceeds the size limit")
+)
+
+// Compress the token's claims with DEFLATE when it is signed, by setting the
+// zip header to "DEF".  This shrinks large claim sets, such as those sent in
+// size limited HTTP headers.  Only parsers that understand zip, such as this
+// package's, can read the claims.
+func (t *Token) SetCompression() {
+	if t.Header == nil {
+		t.Header = map[string]interface{}{}
+	}
+	t.Header["zip"] = CompressionDeflate
+}
+
+// Returns the compression named by the zip header, or "" if there is none
+func compression(header map[string]interface{}) (string, error) {
+	v, ok := header["zip"]
+	if !ok {
+		return "", nil
+	}
+	if zip, ok := v.(string); ok && zip == CompressionDeflate {
+		return zip, nil
+	}
+	return "", NewValidationError(fmt.Sprintf("zip header %v is not supported", v), ValidationErrorMalformed)
+}
+
+// Compress the encoded claims of a token being signed, if its header asks
+func (t *Token) compressPayload(claims []byte) ([]byte, error) {
+	zip, err := compression(t.Header)
+	if err != nil || zip == "" {
+		return claims, err
+	}
+	if t.isUnencodedPayload() {
+		return nil, ErrCompressedUnencodedPayload
+	}
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(claims); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress a parsed token's payload if its zip header asks, reading no more
+// than the parser's MaxDecompressedSize so small tokens can't expand into
+// huge claim sets
+func (p *Parser) decompressPayload(header map[string]interface{}, payload []byte) ([]byte, error) {
+	zip, err := compression(header)
+	if err != nil || zip == "" {
+		return payload, err
+	}
+	if unencoded, _ := parseUnencodedPayloadHeader(header); unencoded {
+		return nil, NewValidationError(ErrCompressedUnencodedPayload.Error(), ValidationErrorMalformed)
+	}
+
+	limit := p.MaxDecompressedSize
+	if limit <= 0 {
+		limit = DefaultMaxDecompressedSize
+	}
+	r := flate.NewReader(bytes.NewReader(payload))
+	defer r.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	if len(data) > limit {
+		return nil, &ValidationError{Inner: ErrDecompressedSizeExceeded, Errors: ValidationErrorMalformed}
+	}
+	return data, nil
+}
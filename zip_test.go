@@ -0,0 +1,64 @@
+package jwt_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestCompression(t *testing.T) {
+	groups := make([]string, 500)
+	for i := range groups {
+		groups[i] = fmt.Sprintf("group-%d", i)
+	}
+	claims := jwt.MapClaims{"sub": "user", "groups": groups}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.SetCompression()
+	compressed, err := token.SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := signHS256(claims)
+	if len(compressed) >= len(plain)/2 {
+		t.Errorf("Expected compression, got %v bytes for %v", len(compressed), len(plain))
+	}
+
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	parsed, err := jwt.Parse(compressed, keyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Claims.(jwt.MapClaims)["sub"] != "user" || len(parsed.Claims.(jwt.MapClaims)["groups"].([]interface{})) != 500 {
+		t.Errorf("Unexpected claims: %v", parsed.Claims)
+	}
+	if err := jwt.NewParser().ParseInto(&jwt.Token{}, compressed, jwt.MapClaims{}, keyFunc); err != nil {
+		t.Errorf("Unexpected error from ParseInto: %v", err)
+	}
+
+	// Decompression is bounded
+	_, err = jwt.NewParser(jwt.WithMaxDecompressedSize(1024)).Parse(compressed, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Inner != jwt.ErrDecompressedSizeExceeded {
+		t.Errorf("Expected ErrDecompressedSizeExceeded, got %v", err)
+	}
+	var bomb bytes.Buffer
+	w, _ := flate.NewWriter(&bomb, flate.BestCompression)
+	w.Write([]byte(`{"pad":"` + strings.Repeat("a", 2<<20) + `"}`))
+	w.Close()
+	header := jwt.EncodeSegment([]byte(`{"alg":"HS256","zip":"DEF"}`))
+	signing := header + "." + jwt.EncodeSegment(bomb.Bytes())
+	signature, _ := jwt.SigningMethodHS256.Sign(signing, hmacTestKey)
+	_, err = jwt.Parse(signing+"."+signature, keyFunc)
+	if ve, ok := err.(*jwt.ValidationError); !ok || ve.Inner != jwt.ErrDecompressedSizeExceeded {
+		t.Errorf("Expected ErrDecompressedSizeExceeded, got %v", err)
+	}
+
+	token.Header["zip"] = "GZIP"
+	if _, err := token.SignedString(hmacTestKey); err == nil {
+		t.Errorf("Expected an unsupported zip to be rejected")
+	}
+}